@@ -0,0 +1,82 @@
+// Package accel is the extension point of hardware accelerated proving: an Accelerator (e.g.
+// bindings to a GPU library such as icicle) proves BN254 groth16 statements on a device. Prove uses
+// the registered accelerator and falls back to gnark's CPU prover when there is none, when it fails,
+// or when its proof doesn't verify.
+//
+// gnark v0.5 has no hook inside groth16.Prove to offload its MSMs and FFTs: an accelerator proves
+// on its own, from the R1CS, proving key and witness.
+package accel
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrUnsupported is returned by accelerators for the proofs they can't run (e.g. too large for the
+// device memory): Prove falls back to the CPU without logging
+var ErrUnsupported = errors.New("unsupported by the accelerator")
+
+// Accelerator proves BN254 groth16 statements on a device
+type Accelerator interface {
+	// Name identifies the accelerator in logs and benchmarks
+	Name() string
+	// Prove proves like groth16.Prove, on the device
+	Prove(r1cs frontend.CompiledConstraintSystem, pk groth16.ProvingKey, witness frontend.Circuit) (groth16.Proof, error)
+}
+
+// Plugin is the environment variable naming the Go plugin (go build -buildmode=plugin) providing the
+// accelerator, as an exported variable Accelerator implementing Accelerator; it is loaded by
+// binaries built with -tags accelplugin
+const Plugin = "GNARK_WORKSHOP_ACCELERATOR"
+
+// Disable is the environment variable turning acceleration off, e.g. GNARK_WORKSHOP_ACCEL=off
+const Disable = "GNARK_WORKSHOP_ACCEL"
+
+var (
+	lock        sync.RWMutex
+	accelerator Accelerator
+)
+
+// Register makes a the accelerator of Prove; accelerators register from an init function, in
+// files behind a build tag (see plugin.go)
+func Register(a Accelerator) {
+	lock.Lock()
+	defer lock.Unlock()
+	accelerator = a
+}
+
+// Get returns the registered accelerator, nil if there is none or it is disabled
+func Get() Accelerator {
+	if os.Getenv(Disable) == "off" {
+		return nil
+	}
+	lock.RLock()
+	defer lock.RUnlock()
+	return accelerator
+}
+
+// Prove proves the full assignment witness on the registered accelerator, or with groth16.Prove if
+// there is none, the proof is not on BN254, or the accelerator fails. The accelerator proofs are
+// checked with vk: an invalid one (e.g. from a faulty device) is logged and proven again on the CPU.
+func Prove(r1cs frontend.CompiledConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey, witness frontend.Circuit) (groth16.Proof, error) {
+	if a := Get(); a != nil && pk.CurveID() == ecc.BN254 {
+		proof, err := a.Prove(r1cs, pk, witness)
+		if err == nil {
+			if err = groth16.Verify(proof, vk, witness); err == nil {
+				return proof, nil
+			}
+			err = fmt.Errorf("invalid proof: %w", err)
+		}
+		if !errors.Is(err, ErrUnsupported) {
+			log.Printf("%s accelerator failed, proving on the CPU: %v", a.Name(), err)
+		}
+	}
+	return groth16.Prove(r1cs, pk, witness)
+}
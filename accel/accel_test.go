@@ -0,0 +1,102 @@
+package accel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// cubeCircuit proves the knowledge of the cube root of a public Y
+type cubeCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubeCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+func cubeWitness(x, y int) *cubeCircuit {
+	var w cubeCircuit
+	w.X.Assign(x)
+	w.Y.Assign(y)
+	return &w
+}
+
+// fake is an accelerator returning proof and err, counting its calls
+type fake struct {
+	proof groth16.Proof
+	err   error
+	calls int
+}
+
+func (f *fake) Name() string {
+	return "fake"
+}
+
+func (f *fake) Prove(frontend.CompiledConstraintSystem, groth16.ProvingKey, frontend.Circuit) (groth16.Proof, error) {
+	f.calls++
+	return f.proof, f.err
+}
+
+func TestProve(t *testing.T) {
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &cubeCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness := cubeWitness(3, 27)
+	valid, err := groth16.Prove(r1cs, pk, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a valid proof of another statement, as a faulty device could return
+	other, err := groth16.Prove(r1cs, pk, cubeWitness(2, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Register(nil) })
+
+	for name, tc := range map[string]struct {
+		accelerator *fake
+		disabled    bool
+		// calls is the number of proofs the accelerator is asked for
+		calls int
+	}{
+		"valid proof":    {accelerator: &fake{proof: valid}, calls: 1},
+		"invalid proof":  {accelerator: &fake{proof: other}, calls: 1},
+		"failure":        {accelerator: &fake{err: errors.New("device lost")}, calls: 1},
+		"unsupported":    {accelerator: &fake{err: ErrUnsupported}, calls: 1},
+		"disabled":       {accelerator: &fake{proof: other}, disabled: true},
+		"no accelerator": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.disabled {
+				t.Setenv(Disable, "off")
+			}
+			if tc.accelerator != nil {
+				Register(tc.accelerator)
+			} else {
+				Register(nil)
+			}
+			proof, err := Prove(r1cs, pk, vk, witness)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := groth16.Verify(proof, vk, witness); err != nil {
+				t.Fatalf("Prove returned an invalid proof: %v", err)
+			}
+			if tc.accelerator != nil && tc.accelerator.calls != tc.calls {
+				t.Fatalf("the accelerator proved %d times, expected %d", tc.accelerator.calls, tc.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+//go:build accelplugin
+// +build accelplugin
+
+package accel
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"plugin"
+)
+
+// built with -tags accelplugin, the binary loads the accelerator plugin at startup; without the
+// variable, or if the plugin doesn't load (e.g. no device), proofs run on the CPU
+func init() {
+	fileName := os.Getenv(Plugin)
+	if fileName == "" {
+		return
+	}
+	a, err := loadPlugin(fileName)
+	if err != nil {
+		log.Printf("%s: %v, proving on the CPU", fileName, err)
+		return
+	}
+	Register(a)
+}
+
+func loadPlugin(fileName string) (Accelerator, error) {
+	p, err := plugin.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := p.Lookup("Accelerator")
+	if err != nil {
+		return nil, err
+	}
+	switch a := symbol.(type) {
+	case *Accelerator:
+		return *a, nil
+	case Accelerator:
+		return a, nil
+	}
+	return nil, fmt.Errorf("Accelerator is a %T, expected an accel.Accelerator", symbol)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/accumulator"
+	"github.com/gbotrel/gnark-workshop/circuit"
+)
+
+// runAccumulator runs the accumulate → aggregate → settle cycle on the simulated backend
+// 1. circuit.BatchSize commitments are queued on-chain (one storage write each)
+// 2. a single circuit.Batch proof covering all of them is created off-chain
+// 3. the proof is submitted once, settling every pending commitment
+func runAccumulator() {
+	// compile the aggregation circuit and run its (toy) trusted setup in memory
+	var batch circuit.Batch
+	log.Println("compiling batch circuit")
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &batch)
+	assertNoError(err)
+
+	log.Println("running groth16.Setup")
+	pk, vk, err := groth16.Setup(r1cs)
+	assertNoError(err)
+
+	auth, chain, err := newBackend()
+	assertNoError(err)
+
+	log.Println("deploying batch verifier and accumulator contracts on chain")
+	acc, err := accumulator.Deploy(auth, chain, vk)
+	assertNoError(err)
+	chain.Commit()
+
+	var result accumulatorResult
+
+	// accumulate
+	secrets := make([][]byte, circuit.BatchSize)
+	for i := range secrets {
+		secrets[i] = []byte(fmt.Sprintf("secret-%d", i))
+		commitment, err := accumulator.Commitment(secrets[i])
+		assertNoError(err)
+		tx, err := acc.Accumulate(auth, commitment)
+		assertNoError(err)
+		chain.Commit()
+		receipt, err := chain.TransactionReceipt(context.Background(), tx.Hash())
+		assertNoError(err)
+		log.Printf("accumulated commitment %d (gas used: %d)", i, receipt.GasUsed)
+		result.Accumulate = append(result.Accumulate, newTxResult(receipt))
+	}
+
+	// aggregate
+	log.Println("creating aggregated proof")
+	proof, err := accumulator.Aggregate(r1cs, pk, secrets)
+	assertNoError(err)
+
+	// settle
+	tx, err := acc.Settle(auth, proof)
+	assertNoError(err)
+	chain.Commit()
+	receipt, err := chain.TransactionReceipt(context.Background(), tx.Hash())
+	assertNoError(err)
+	if receipt.Status != 1 {
+		log.Fatal("settlement transaction reverted, but shouldn't have")
+	}
+	log.Printf("settled %d commitments with one proof (gas used: %d)", circuit.BatchSize, receipt.GasUsed)
+	result.Settle = newTxResult(receipt)
+
+	for _, secret := range secrets {
+		commitment, err := accumulator.Commitment(secret)
+		assertNoError(err)
+		settled, err := acc.Settled(nil, commitment)
+		assertNoError(err)
+		if !settled {
+			log.Fatal("commitment not marked as settled on chain")
+		}
+	}
+	log.Println("successfully settled all commitments on-chain")
+	emit("accumulator", result)
+}
+
+// accumulatorResult is the JSON output of -accumulator
+type accumulatorResult struct {
+	Accumulate []*txResult `json:"accumulate"`
+	Settle     *txResult   `json:"settle"`
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+pragma solidity ^0.8.0;
+
+// IBatchVerifier is the gnark exported verifier of the circuit.Batch circuit
+interface IBatchVerifier {
+    function verifyProof(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c,
+        uint256[4] memory input
+    ) external view returns (bool r);
+}
+
+// Accumulator stores pending commitments (the public hashes of pre-image
+// statements) for the price of a storage write, and settles them all at once
+// with a single aggregated proof.
+// Only the aggregator (the deployer) queues commitments: it proves the batch, so
+// it holds every pre-image, and commitments nobody can open would otherwise fill
+// the batch and block every later settlement.
+contract Accumulator {
+    uint256 public constant BATCH_SIZE = 4;
+
+    IBatchVerifier public immutable verifier;
+    address public immutable aggregator;
+
+    uint256[] private pending;
+    mapping(uint256 => bool) public settled;
+
+    event Accumulated(uint256 indexed commitment, uint256 position);
+    event Settled(uint256[4] commitments);
+
+    constructor(IBatchVerifier _verifier) {
+        verifier = _verifier;
+        aggregator = msg.sender;
+    }
+
+    // accumulate queues a commitment until the next settlement
+    function accumulate(uint256 commitment) external {
+        require(msg.sender == aggregator, "accumulator-not-aggregator");
+        require(pending.length < BATCH_SIZE, "accumulator-full");
+        require(!settled[commitment], "accumulator-already-settled");
+        pending.push(commitment);
+        emit Accumulated(commitment, pending.length - 1);
+    }
+
+    function pendingCommitments() external view returns (uint256[] memory) {
+        return pending;
+    }
+
+    // settle verifies one proof covering all the pending commitments, in the
+    // order they were accumulated, and marks them as settled
+    function settle(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c
+    ) external {
+        require(pending.length == BATCH_SIZE, "accumulator-not-full");
+
+        uint256[4] memory input;
+        for (uint256 i = 0; i < BATCH_SIZE; i++) {
+            input[i] = pending[i];
+        }
+        require(verifier.verifyProof(a, b, c, input), "accumulator-invalid-proof");
+
+        for (uint256 i = 0; i < BATCH_SIZE; i++) {
+            settled[input[i]] = true;
+        }
+        delete pending;
+        emit Settled(input);
+    }
+}
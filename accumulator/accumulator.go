@@ -0,0 +1,115 @@
+// Package accumulator drives the accumulate → aggregate → settle cycle of the
+// Accumulator contract: pre-image statements are queued on-chain as cheap
+// commitments, then settled together by a single circuit.Batch proof.
+package accumulator
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+//go:embed Accumulator.sol
+var accumulatorSol string
+
+// ErrBatchSize is returned when aggregating a number of secrets different from circuit.BatchSize
+var ErrBatchSize = fmt.Errorf("aggregation expects exactly %d secrets", circuit.BatchSize)
+
+// Accumulator is a handle on a deployed Accumulator contract
+type Accumulator struct {
+	Address  common.Address
+	Verifier common.Address
+	contract *bind.BoundContract
+}
+
+// Deploy exports vk (a circuit.Batch verifying key) to solidity, deploys it,
+// then deploys an Accumulator contract settling against it, whose aggregator is auth.From.
+// Requires solc in PATH; caller is responsible for committing / mining the transactions.
+func Deploy(auth *bind.TransactOpts, backend bind.ContractBackend, vk groth16.VerifyingKey) (*Accumulator, error) {
+	var buf bytes.Buffer
+	if err := vk.ExportSolidity(&buf); err != nil {
+		return nil, err
+	}
+	verifierAddress, _, err := ethereum.DeployContract(auth, backend, buf.String(), "Verifier")
+	if err != nil {
+		return nil, err
+	}
+
+	address, contract, err := ethereum.DeployContract(auth, backend, accumulatorSol, "Accumulator", verifierAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &Accumulator{Address: address, Verifier: verifierAddress, contract: contract}, nil
+}
+
+// Accumulate queues a commitment (the public hash of a pre-image statement) on-chain; auth must be
+// the aggregator's
+func (a *Accumulator) Accumulate(auth *bind.TransactOpts, commitment *big.Int) (*types.Transaction, error) {
+	return a.contract.Transact(auth, "accumulate", commitment)
+}
+
+// Pending returns the commitments waiting for settlement, in accumulation order
+func (a *Accumulator) Pending(opts *bind.CallOpts) ([]*big.Int, error) {
+	var out []interface{}
+	if err := a.contract.Call(opts, &out, "pendingCommitments"); err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new([]*big.Int)).(*[]*big.Int), nil
+}
+
+// Settled returns true if commitment was covered by a settled aggregated proof
+func (a *Accumulator) Settled(opts *bind.CallOpts, commitment *big.Int) (bool, error) {
+	var out []interface{}
+	if err := a.contract.Call(opts, &out, "settled", commitment); err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// Settle submits the aggregated proof covering all pending commitments
+func (a *Accumulator) Settle(auth *bind.TransactOpts, proof groth16.Proof) (*types.Transaction, error) {
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, nil)
+	if err != nil {
+		return nil, err
+	}
+	return a.contract.Transact(auth, "settle", solidityInputs.A, solidityInputs.B, solidityInputs.C)
+}
+
+// Commitment returns mimc(secret), the value accumulated on-chain for secret
+func Commitment(secret []byte) (*big.Int, error) {
+	hash, err := circuit.Hash(secret)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(hash), nil
+}
+
+// Aggregate creates a single circuit.Batch proof of knowledge of the pre-images
+// of all the commitments; secrets must be ordered as their commitments were accumulated.
+func Aggregate(r1cs frontend.CompiledConstraintSystem, pk groth16.ProvingKey, secrets [][]byte) (groth16.Proof, error) {
+	if len(secrets) != circuit.BatchSize {
+		return nil, ErrBatchSize
+	}
+
+	var witness circuit.Batch
+	for i, secret := range secrets {
+		hash, err := circuit.Hash(secret)
+		if err != nil {
+			return nil, err
+		}
+		witness.Secrets[i].Assign(secret)
+		witness.Hashes[i].Assign(hash)
+	}
+
+	return groth16.Prove(r1cs, pk, &witness)
+}
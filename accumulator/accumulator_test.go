@@ -0,0 +1,115 @@
+package accumulator
+
+import (
+	"fmt"
+	"math/big"
+	"os/exec"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/testchain"
+)
+
+func TestAggregateBatchSize(t *testing.T) {
+	if _, err := Aggregate(nil, nil, make([][]byte, circuit.BatchSize-1)); err != ErrBatchSize {
+		t.Fatalf("got %v, expected ErrBatchSize", err)
+	}
+}
+
+// TestAccumulatorGriefing fills the batch with commitments nobody can open: the contract only
+// takes them from the aggregator, which then settles its own batch
+func TestAccumulatorGriefing(t *testing.T) {
+	if _, err := exec.LookPath(ethereum.Solc); err != nil {
+		t.Skip("requires solc:", err)
+	}
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit.Batch{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := testchain.New(testchain.Genesis{Accounts: []string{"aggregator", "mallory"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+	acc, err := Deploy(chain.Transactor("aggregator"), chain, vk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Commit()
+
+	// the gas limit is set as estimating a reverting call fails
+	accumulate := func(account string, commitment *big.Int) uint64 {
+		t.Helper()
+		opts := chain.Transactor(account)
+		opts.GasLimit = 1000000
+		tx, err := acc.Accumulate(opts, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		receipt, err := chain.Mine(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return receipt.Status
+	}
+
+	for i := 0; i < circuit.BatchSize; i++ {
+		if status := accumulate("mallory", big.NewInt(int64(i+1))); status != types.ReceiptStatusFailed {
+			t.Fatalf("mallory queued junk commitment %d", i)
+		}
+	}
+	pending, err := acc.Pending(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("%d pending commitments, expected none", len(pending))
+	}
+
+	secrets := make([][]byte, circuit.BatchSize)
+	for i := range secrets {
+		secrets[i] = []byte(fmt.Sprintf("secret-%d", i))
+		commitment, err := Commitment(secrets[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := accumulate("aggregator", commitment); status != types.ReceiptStatusSuccessful {
+			t.Fatalf("the aggregator couldn't queue commitment %d", i)
+		}
+	}
+	proof, err := Aggregate(r1cs, pk, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := acc.Settle(chain.Transactor("aggregator"), proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := chain.Mine(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("settlement reverted")
+	}
+	for _, secret := range secrets {
+		commitment, err := Commitment(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if settled, err := acc.Settled(&bind.CallOpts{}, commitment); err != nil || !settled {
+			t.Fatalf("commitment not settled: %v", err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"io/ioutil"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// setAffinity restricts the process to cpus, e.g. the CPUs of a NUMA node
+// Affinity is per thread: it is set on every thread of the process, the threads the Go runtime
+// starts later inherit it.
+func setAffinity(cpus []int) error {
+	var mask [maxCPUs / 64]uint64
+	for _, cpu := range cpus {
+		mask[cpu/64] |= 1 << (uint(cpu) % 64)
+	}
+	tasks, err := ioutil.ReadDir("/proc/self/task")
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, uintptr(tid), unsafe.Sizeof(mask), uintptr(unsafe.Pointer(&mask)))
+		if errno != 0 && errno != syscall.ESRCH { // ESRCH: the thread exited
+			return errno
+		}
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import "errors"
+
+// setAffinity is only implemented on linux
+func setAffinity(cpus []int) error {
+	return errors.New("-cpus is only supported on linux")
+}
@@ -0,0 +1,192 @@
+package artifacts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ChunkIndex lists the chunks a file was split into by Split, in order
+// Reading the chunks one after the other gives back the file: its hash (HashFile) is unchanged.
+type ChunkIndex struct {
+	Size      int64   `json:"size"`
+	ChunkSize int64   `json:"chunkSize"`
+	Chunks    []Chunk `json:"chunks"`
+}
+
+// Chunk is a segment of a split file, stored next to its index
+type Chunk struct {
+	File string `json:"file"`
+	Size int64  `json:"size"`
+	Hash string `json:"hash"`
+}
+
+// IndexFile returns the name of the chunk index of fileName
+func IndexFile(fileName string) string {
+	return fileName + ".chunks.json"
+}
+
+// Split splits fileName in chunks of chunkSize bytes, fileName.000, fileName.001, ..., and writes
+// their index; fileName is left in place
+func Split(fileName string, chunkSize int64) (*ChunkIndex, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size %d", chunkSize)
+	}
+	in, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	index := &ChunkIndex{ChunkSize: chunkSize}
+	for {
+		chunk := Chunk{File: fmt.Sprintf("%s.%03d", filepath.Base(fileName), len(index.Chunks))}
+		out, err := os.Create(filepath.Join(filepath.Dir(fileName), chunk.File))
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		chunk.Size, err = io.Copy(io.MultiWriter(out, h), io.LimitReader(in, chunkSize))
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+		if chunk.Size == 0 && len(index.Chunks) != 0 {
+			// the previous chunk ended the file
+			if err := os.Remove(filepath.Join(filepath.Dir(fileName), chunk.File)); err != nil {
+				return nil, err
+			}
+			break
+		}
+		chunk.Hash = hex.EncodeToString(h.Sum(nil))
+		index.Chunks = append(index.Chunks, chunk)
+		index.Size += chunk.Size
+		if chunk.Size < chunkSize {
+			break
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return index, ioutil.WriteFile(IndexFile(fileName), append(data, '\n'), 0644)
+}
+
+// ReadIndex reads the chunk index of fileName, nil if it was not split
+func ReadIndex(fileName string) (*ChunkIndex, error) {
+	data, err := ioutil.ReadFile(IndexFile(fileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var index ChunkIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("%s: %w", IndexFile(fileName), err)
+	}
+	return &index, nil
+}
+
+// Open opens fileName, or, if it was split and removed, its chunks: they are opened one at a time
+// as the reader reaches them, and each is checked against the hash in the index once read, so that
+// at most one chunk file is open and a corrupted chunk fails the read with ErrCorrupted
+// Encrypted artifacts (see Encrypt) are decrypted with the current key source.
+func Open(fileName string) (io.ReadCloser, error) {
+	f, err := OpenRaw(fileName)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Decrypt(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, f}, nil
+}
+
+// OpenRaw opens fileName or its chunks, as Open, without decrypting them, e.g. to copy them as stored
+func OpenRaw(fileName string) (io.ReadCloser, error) {
+	f, err := os.Open(fileName)
+	if !os.IsNotExist(err) {
+		return f, err
+	}
+	index, ierr := ReadIndex(fileName)
+	if ierr != nil {
+		return nil, ierr
+	}
+	if index == nil {
+		return nil, err
+	}
+	return &chunkReader{dir: filepath.Dir(fileName), chunks: index.Chunks}, nil
+}
+
+// FileSize returns the size of fileName, or of its chunks if it was split and removed
+func FileSize(fileName string) (int64, error) {
+	info, err := os.Stat(fileName)
+	if err == nil {
+		return info.Size(), nil
+	}
+	index, ierr := ReadIndex(fileName)
+	if ierr != nil || index == nil {
+		return 0, err
+	}
+	return index.Size, nil
+}
+
+// chunkReader reads the chunks of a split file in order
+type chunkReader struct {
+	dir    string
+	chunks []Chunk
+	f      *os.File
+	h      hash.Hash
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.f == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+			f, err := os.Open(filepath.Join(r.dir, r.chunks[0].File))
+			if err != nil {
+				return 0, err
+			}
+			r.f, r.h = f, sha256.New()
+		}
+		n, err := r.f.Read(p)
+		r.h.Write(p[:n])
+		if err == io.EOF {
+			chunk := r.chunks[0]
+			r.f.Close()
+			r.f, r.chunks = nil, r.chunks[1:]
+			if hex.EncodeToString(r.h.Sum(nil)) != chunk.Hash {
+				return n, fmt.Errorf("%s: %w", chunk.File, ErrCorrupted)
+			}
+			if n == 0 {
+				continue
+			}
+			return n, nil
+		}
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.f == nil {
+		return nil
+	}
+	return r.f.Close()
+}
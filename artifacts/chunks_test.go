@@ -0,0 +1,88 @@
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestSplitOpen splits files and reads them back from their chunks, once the file is removed:
+// their hash and size are unchanged
+func TestSplitOpen(t *testing.T) {
+	const chunkSize = 1000
+	for _, size := range []int{0, 1, chunkSize, 2*chunkSize + chunkSize/2} {
+		size := size
+		t.Run(fmt.Sprintf("%d bytes", size), func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "circuit.pk")
+			data := make([]byte, size)
+			for i := range data {
+				data[i] = byte(i % 251)
+			}
+			if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			hash, err := HashFile(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if index, err := ReadIndex(fileName); err != nil || index != nil {
+				t.Fatalf("index of a file not split: %v, %v", index, err)
+			}
+
+			index, err := Split(fileName, chunkSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nbChunks := (size + chunkSize - 1) / chunkSize
+			if nbChunks == 0 {
+				nbChunks = 1
+			}
+			if len(index.Chunks) != nbChunks || index.Size != int64(size) {
+				t.Fatalf("%d chunks of %d bytes, expected %d of %d", len(index.Chunks), index.Size, nbChunks, size)
+			}
+			read, err := ReadIndex(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(read, index) {
+				t.Fatalf("read index %+v, expected %+v", read, index)
+			}
+
+			// the chunks are read once the file is gone
+			if err := os.Remove(fileName); err != nil {
+				t.Fatal(err)
+			}
+			if h, err := HashFile(fileName); err != nil || h != hash {
+				t.Fatalf("hash of the chunks %s, %v, expected %s", h, err, hash)
+			}
+			if n, err := FileSize(fileName); err != nil || n != int64(size) {
+				t.Fatalf("size of the chunks %d, %v, expected %d", n, err, size)
+			}
+		})
+	}
+}
+
+func TestOpenCorruptedChunk(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "circuit.pk")
+	if err := ioutil.WriteFile(fileName, make([]byte, 2500), 0644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := Split(fileName, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(fileName); err != nil {
+		t.Fatal(err)
+	}
+	chunk := filepath.Join(filepath.Dir(fileName), index.Chunks[1].File)
+	if err := ioutil.WriteFile(chunk, append(make([]byte, 999), 1), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := HashFile(fileName); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("got %v, expected ErrCorrupted", err)
+	}
+}
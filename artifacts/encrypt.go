@@ -0,0 +1,355 @@
+package artifacts
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// EncryptedMagic starts the artifacts written by Encrypt
+const EncryptedMagic = "GNWE"
+
+// PassphraseEnv is the environment variable holding the passphrase of encrypted artifacts: if set,
+// Open decrypts them with it (see SetKeySource for a KMS)
+const PassphraseEnv = "GNARK_WORKSHOP_KEY_PASSPHRASE"
+
+const (
+	encryptionVersion = 1
+	// segmentSize is the size of the plaintext segments, each sealed on its own so that keys of
+	// several GiB are encrypted and decrypted as streams
+	segmentSize = 64 << 10
+	// noncePrefixSize is the random part of the nonces, followed by the segment counter and the
+	// last segment flag (STREAM construction): segments can't be reordered, dropped or truncated
+	noncePrefixSize = 7
+)
+
+var (
+	// ErrEncrypted is returned when reading an encrypted artifact without a key source
+	ErrEncrypted = errors.New("artifact is encrypted, set " + PassphraseEnv + " or a key source")
+	// ErrDecryption is returned when an encrypted artifact doesn't authenticate
+	ErrDecryption = errors.New("decryption failed: wrong key, or corrupted artifact")
+)
+
+// KeySource provides the AES-256 keys of encrypted artifacts: a Passphrase, or a KMS generating
+// data keys and unwrapping them (envelope encryption)
+type KeySource interface {
+	// NewKey returns a new 32 bytes key, and the blob stored in the header of the artifact to
+	// get it back, e.g. a salt or a wrapped data key
+	NewKey() (key, blob []byte, err error)
+	// Key returns the key of blob
+	Key(blob []byte) ([]byte, error)
+}
+
+// Passphrase is a KeySource deriving the keys from a passphrase and a random salt, with scrypt
+type Passphrase string
+
+// NewKey returns a key derived with a new salt, the blob
+func (p Passphrase) NewKey() ([]byte, []byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	key, err := p.Key(salt)
+	return key, salt, err
+}
+
+// Key derives the key of salt
+func (p Passphrase) Key(salt []byte) ([]byte, error) {
+	if p == "" {
+		return nil, errors.New("empty passphrase")
+	}
+	return scrypt.Key([]byte(p), salt, 1<<15, 8, 1, 32)
+}
+
+var (
+	keySourceLock sync.RWMutex
+	keySource     KeySource
+)
+
+func init() {
+	if p, ok := os.LookupEnv(PassphraseEnv); ok {
+		SetKeySource(Passphrase(p))
+	}
+}
+
+// SetKeySource sets the KeySource Open and Decrypt decrypt artifacts with, e.g. a KMS client from
+// an init function; nil refuses encrypted artifacts
+func SetKeySource(ks KeySource) {
+	keySourceLock.Lock()
+	defer keySourceLock.Unlock()
+	keySource = ks
+}
+
+// CurrentKeySource returns the KeySource set by SetKeySource, or from PassphraseEnv, nil if none
+func CurrentKeySource() KeySource {
+	keySourceLock.RLock()
+	defer keySourceLock.RUnlock()
+	return keySource
+}
+
+// Encrypt returns a writer encrypting to w with a new key of ks, with AES-GCM; Close writes the
+// last segment, and doesn't close w
+// Encoding: EncryptedMagic, version (1 byte), blob (big endian uint16 length, then the bytes), nonce
+// prefix, then the sealed segments of segmentSize bytes, the last one shorter (possibly empty).
+func Encrypt(w io.Writer, ks KeySource) (io.WriteCloser, error) {
+	key, blob, err := ks.NewKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) > 0xffff {
+		return nil, errors.New("key blob too long")
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, len(EncryptedMagic)+3+len(blob)+noncePrefixSize)
+	header = append(header, EncryptedMagic...)
+	header = append(header, encryptionVersion, byte(len(blob)>>8), byte(len(blob)))
+	header = append(header, blob...)
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	header = append(header, prefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, aead: aead, prefix: prefix, header: header, buf: make([]byte, 0, segmentSize)}, nil
+}
+
+// Decrypt returns a reader decrypting r with the current key source if r is encrypted (see Encrypt),
+// r itself, buffered, if it's not
+func Decrypt(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if !isEncrypted(br) {
+		return br, nil
+	}
+	ks := CurrentKeySource()
+	if ks == nil {
+		return nil, ErrEncrypted
+	}
+	return decrypt(br, ks)
+}
+
+// IsEncrypted reports whether fileName (or its chunks) was written by Encrypt
+func IsEncrypted(fileName string) (bool, error) {
+	f, err := OpenRaw(fileName)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	return isEncrypted(bufio.NewReader(f)), nil
+}
+
+// EncryptFile encrypts fileName in place with a new key of ks
+func EncryptFile(fileName string, ks KeySource) error {
+	if encrypted, err := IsEncrypted(fileName); err != nil || encrypted {
+		if encrypted {
+			err = fmt.Errorf("%s is already encrypted", fileName)
+		}
+		return err
+	}
+	return rewrite(fileName, func(dst io.Writer, src io.Reader) error {
+		w, err := Encrypt(dst, ks)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, src); err != nil {
+			return err
+		}
+		return w.Close()
+	})
+}
+
+// DecryptFile decrypts fileName in place, with the current key source
+func DecryptFile(fileName string) error {
+	return rewrite(fileName, func(dst io.Writer, src io.Reader) error {
+		_, err := io.Copy(dst, src)
+		return err
+	})
+}
+
+// rewrite replaces fileName, which must not be split (see Split), by what transform writes from
+// its decrypted content
+func rewrite(fileName string, transform func(dst io.Writer, src io.Reader) error) error {
+	if index, err := ReadIndex(fileName); err != nil || index != nil {
+		if index != nil {
+			err = fmt.Errorf("%s is split in chunks: encrypt or decrypt it before chunk-pk", fileName)
+		}
+		return err
+	}
+	src, err := Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	tmp, err := ioutil.TempFile(filepath.Dir(fileName), filepath.Base(fileName)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := transform(tmp, src); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fileName)
+}
+
+func isEncrypted(br *bufio.Reader) bool {
+	prefix, err := br.Peek(len(EncryptedMagic))
+	return err == nil && string(prefix) == EncryptedMagic
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// nonce returns the nonce of the segment i
+func nonce(prefix []byte, i uint32, last bool) []byte {
+	n := make([]byte, 12)
+	copy(n, prefix)
+	binary.BigEndian.PutUint32(n[noncePrefixSize:], i)
+	if last {
+		n[11] = 1
+	}
+	return n
+}
+
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix []byte
+	// header is authenticated with every segment
+	header  []byte
+	buf     []byte
+	segment uint32
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// a full segment is sealed once more data follows: the last one is sealed by Close
+		if len(e.buf) == segmentSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(e.buf[len(e.buf):segmentSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	if e.segment == ^uint32(0) {
+		return errors.New("encrypted artifact too large")
+	}
+	sealed := e.aead.Seal(nil, nonce(e.prefix, e.segment, last), e.buf, e.header)
+	e.segment++
+	e.buf = e.buf[:0]
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+// decrypt reads the header of an encrypted artifact from br, and returns the reader of its plaintext
+func decrypt(br *bufio.Reader, ks KeySource) (io.Reader, error) {
+	var fixed [len(EncryptedMagic) + 3]byte
+	if _, err := io.ReadFull(br, fixed[:]); err != nil {
+		return nil, fmt.Errorf("reading encryption header: %w", err)
+	}
+	if fixed[len(EncryptedMagic)] != encryptionVersion {
+		return nil, fmt.Errorf("unsupported encryption version %d", fixed[len(EncryptedMagic)])
+	}
+	blob := make([]byte, binary.BigEndian.Uint16(fixed[len(EncryptedMagic)+1:]))
+	prefix := make([]byte, noncePrefixSize)
+	for _, b := range [][]byte{blob, prefix} {
+		if _, err := io.ReadFull(br, b); err != nil {
+			return nil, fmt.Errorf("reading encryption header: %w", err)
+		}
+	}
+	key, err := ks.Key(blob)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := append(append(append([]byte(nil), fixed[:]...), blob...), prefix...)
+	return &decryptReader{r: br, aead: aead, prefix: prefix, header: header, sealed: make([]byte, segmentSize+aead.Overhead())}, nil
+}
+
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	header  []byte
+	sealed  []byte
+	plain   []byte
+	segment uint32
+	done    bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+// open reads and opens the next segment; the last one is shorter than a full segment, or
+// followed by nothing
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.sealed)
+	switch {
+	case err == io.EOF:
+		// the last segment, even empty, has a tag
+		return fmt.Errorf("%w: truncated", ErrDecryption)
+	case err == io.ErrUnexpectedEOF:
+		d.done = true
+	case err != nil:
+		return err
+	default:
+		_, err := d.r.Peek(1)
+		d.done = err == io.EOF
+	}
+	plain, err := d.aead.Open(d.sealed[:0], nonce(d.prefix, d.segment, d.done), d.sealed[:n], d.header)
+	if err != nil {
+		return ErrDecryption
+	}
+	d.segment++
+	d.plain = plain
+	return nil
+}
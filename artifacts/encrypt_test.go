@@ -0,0 +1,230 @@
+package artifacts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// staticKey is a KeySource with a single key, named by its blob: scrypt is slow
+type staticKey struct {
+	key  [32]byte
+	blob string
+}
+
+func (k staticKey) NewKey() ([]byte, []byte, error) {
+	return k.key[:], []byte(k.blob), nil
+}
+
+func (k staticKey) Key(blob []byte) ([]byte, error) {
+	if string(blob) != k.blob {
+		return nil, fmt.Errorf("unknown key %q", blob)
+	}
+	return k.key[:], nil
+}
+
+// setKeySource sets ks for the test
+func setKeySource(t *testing.T, ks KeySource) {
+	t.Helper()
+	previous := CurrentKeySource()
+	SetKeySource(ks)
+	t.Cleanup(func() { SetKeySource(previous) })
+}
+
+func plaintext(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func encrypt(t *testing.T, data []byte, ks KeySource) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := Encrypt(&buf, ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decryptAll(encrypted []byte) ([]byte, error) {
+	r, err := Decrypt(bytes.NewReader(encrypted))
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func TestEncrypt(t *testing.T) {
+	ks := staticKey{key: [32]byte{1}, blob: "k1"}
+	setKeySource(t, ks)
+	for _, size := range []int{0, 1, segmentSize - 1, segmentSize, segmentSize + 1, 3*segmentSize + 7} {
+		data := plaintext(size)
+		encrypted := encrypt(t, data, ks)
+		if bytes.Contains(encrypted, data[:size/2]) && size > 16 {
+			t.Fatalf("%d bytes: the plaintext is in the ciphertext", size)
+		}
+		decrypted, err := decryptAll(encrypted)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", size, err)
+		}
+		if !bytes.Equal(decrypted, data) {
+			t.Fatalf("%d bytes: decrypted %d different bytes", size, len(decrypted))
+		}
+	}
+
+	// the nonce prefix is random: encrypting twice gives two ciphertexts
+	if bytes.Equal(encrypt(t, plaintext(10), ks), encrypt(t, plaintext(10), ks)) {
+		t.Fatal("the same plaintext encrypted twice gave the same ciphertext")
+	}
+
+	// artifacts that aren't encrypted are read as is
+	if decrypted, err := decryptAll([]byte("plain artifact")); err != nil || string(decrypted) != "plain artifact" {
+		t.Fatalf("got %q, %v", decrypted, err)
+	}
+}
+
+func TestDecryptTampered(t *testing.T) {
+	ks := staticKey{key: [32]byte{1}, blob: "k1"}
+	setKeySource(t, ks)
+	// two full segments, the second one sealed as the last
+	encrypted := encrypt(t, plaintext(2*segmentSize), ks)
+	headerSize := len(EncryptedMagic) + 3 + len(ks.blob) + noncePrefixSize
+	sealedSize := segmentSize + 16
+	if len(encrypted) != headerSize+2*sealedSize {
+		t.Fatalf("%d bytes encrypted", len(encrypted))
+	}
+	segment := func(i int) []byte {
+		return encrypted[headerSize+i*sealedSize : headerSize+(i+1)*sealedSize]
+	}
+	flip := func(i int) []byte {
+		tampered := append([]byte(nil), encrypted...)
+		tampered[i] ^= 1
+		return tampered
+	}
+
+	for name, tc := range map[string]struct {
+		encrypted []byte
+		ks        KeySource
+		expected  error
+	}{
+		"ciphertext":        {encrypted: flip(headerSize + 10), expected: ErrDecryption},
+		"tag":               {encrypted: flip(len(encrypted) - 1), expected: ErrDecryption},
+		"nonce prefix":      {encrypted: flip(headerSize - 1), expected: ErrDecryption},
+		"last segment gone": {encrypted: encrypted[:headerSize+sealedSize], expected: ErrDecryption},
+		"truncated":         {encrypted: encrypted[:headerSize+sealedSize+100], expected: ErrDecryption},
+		"segments swapped": {
+			encrypted: append(append(append([]byte(nil), encrypted[:headerSize]...), segment(1)...), segment(0)...),
+			expected:  ErrDecryption,
+		},
+		"another key": {encrypted: encrypted, ks: staticKey{key: [32]byte{2}, blob: "k1"}, expected: ErrDecryption},
+		"no key":      {encrypted: encrypted, expected: ErrEncrypted},
+	} {
+		switch {
+		case name == "no key":
+			SetKeySource(nil)
+		case tc.ks != nil:
+			SetKeySource(tc.ks)
+		default:
+			SetKeySource(ks)
+		}
+		if _, err := decryptAll(tc.encrypted); !errors.Is(err, tc.expected) {
+			t.Errorf("%s: got %v, expected %v", name, err, tc.expected)
+		}
+	}
+
+	SetKeySource(ks)
+	unsupported := append([]byte(nil), encrypted...)
+	unsupported[len(EncryptedMagic)] = encryptionVersion + 1
+	if _, err := decryptAll(unsupported); err == nil {
+		t.Error("read an unsupported encryption version")
+	}
+	if _, err := decryptAll(encrypted[:len(EncryptedMagic)+2]); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated header: got %v", err)
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	p := Passphrase("correct horse battery staple")
+	key, salt, err := p.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 32 {
+		t.Fatalf("%d bytes key", len(key))
+	}
+	if again, err := p.Key(salt); err != nil || !bytes.Equal(again, key) {
+		t.Fatalf("the salt gives another key: %v", err)
+	}
+	if other, err := Passphrase("another passphrase").Key(salt); err != nil || bytes.Equal(other, key) {
+		t.Fatalf("another passphrase gives the same key: %v", err)
+	}
+	if _, _, err := Passphrase("").NewKey(); err == nil {
+		t.Fatal("derived a key from an empty passphrase")
+	}
+
+	setKeySource(t, p)
+	if decrypted, err := decryptAll(encrypt(t, []byte("proving key"), p)); err != nil || string(decrypted) != "proving key" {
+		t.Fatalf("got %q, %v", decrypted, err)
+	}
+}
+
+func TestEncryptFile(t *testing.T) {
+	ks := staticKey{key: [32]byte{1}, blob: "k1"}
+	setKeySource(t, ks)
+	fileName := filepath.Join(t.TempDir(), PKFile)
+	data := plaintext(segmentSize + 10)
+	if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EncryptFile(fileName, ks); err != nil {
+		t.Fatal(err)
+	}
+	if encrypted, err := IsEncrypted(fileName); err != nil || !encrypted {
+		t.Fatalf("not encrypted: %v", err)
+	}
+	if err := EncryptFile(fileName, ks); err == nil {
+		t.Fatal("encrypted twice")
+	}
+	// Open decrypts
+	f, err := Open(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil || !bytes.Equal(read, data) {
+		t.Fatalf("read %d bytes through Open, %v", len(read), err)
+	}
+
+	if err := DecryptFile(fileName); err != nil {
+		t.Fatal(err)
+	}
+	if read, err := ioutil.ReadFile(fileName); err != nil || !bytes.Equal(read, data) {
+		t.Fatalf("decrypted %d bytes, %v", len(read), err)
+	}
+	if encrypted, err := IsEncrypted(fileName); err != nil || encrypted {
+		t.Fatalf("still encrypted: %v", err)
+	}
+
+	// split files are refused, chunk-pk comes after encryption
+	if _, err := Split(fileName, segmentSize/2); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptFile(fileName, ks); err == nil {
+		t.Fatal("encrypted a split file")
+	}
+}
@@ -0,0 +1,102 @@
+package artifacts
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+)
+
+// Magic starts the serialized objects written by Write
+const Magic = "GNWS"
+
+// headerVersion is the version of the header encoding
+const headerVersion = 1
+
+// Header is the envelope of a serialized gnark object (R1CS, key or proof): it says what the
+// bytes that follow were generated with
+type Header struct {
+	GnarkVersion string
+	Curve        ecc.ID
+	Backend      backend.ID
+}
+
+func (h Header) String() string {
+	curve := fmt.Sprintf("curve %d", h.Curve)
+	for _, id := range ecc.Implemented() {
+		if id == h.Curve {
+			curve = id.String()
+		}
+	}
+	return fmt.Sprintf("gnark %s for %s (%s)", h.GnarkVersion, curve, h.Backend)
+}
+
+// Write writes the header h, then o
+// Encoding: Magic, version (1 byte), curve and backend IDs (big endian uint16), gnark version
+// (1 byte length, then the string).
+func Write(w io.Writer, h Header, o io.WriterTo) (int64, error) {
+	if len(h.GnarkVersion) > 255 {
+		return 0, errors.New("gnark version too long")
+	}
+	buf := make([]byte, 0, len(Magic)+6+len(h.GnarkVersion))
+	buf = append(buf, Magic...)
+	buf = append(buf, headerVersion)
+	buf = append(buf, byte(h.Curve>>8), byte(h.Curve), byte(h.Backend>>8), byte(h.Backend))
+	buf = append(buf, byte(len(h.GnarkVersion)))
+	buf = append(buf, h.GnarkVersion...)
+	n, err := w.Write(buf)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := o.WriteTo(w)
+	return int64(n) + m, err
+}
+
+// Read reads into o an object written by Write, refusing one generated for another curve or
+// backend than expected's; objects serialized without a header are read as is
+func Read(r io.Reader, expected Header, o io.ReaderFrom) (int64, error) {
+	br := bufio.NewReader(r)
+	prefix, err := br.Peek(len(Magic))
+	if err != nil || string(prefix) != Magic {
+		// no header (or too short to have one, o will say)
+		n, err := o.ReadFrom(br)
+		return n, err
+	}
+
+	h, n, err := readHeader(br)
+	if err != nil {
+		return n, err
+	}
+	if h.Curve != expected.Curve || h.Backend != expected.Backend {
+		return n, fmt.Errorf("this object was generated with %s, expected %s", h, expected)
+	}
+	m, err := o.ReadFrom(br)
+	if err != nil && h.GnarkVersion != expected.GnarkVersion {
+		return n + m, fmt.Errorf("this object was generated with %s, this binary uses gnark %s: %w", h, expected.GnarkVersion, err)
+	}
+	return n + m, err
+}
+
+func readHeader(r io.Reader) (Header, int64, error) {
+	var fixed [len(Magic) + 6]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return Header{}, 0, fmt.Errorf("reading header: %w", err)
+	}
+	if fixed[len(Magic)] != headerVersion {
+		return Header{}, int64(len(fixed)), fmt.Errorf("unsupported header version %d", fixed[len(Magic)])
+	}
+	h := Header{
+		Curve:   ecc.ID(binary.BigEndian.Uint16(fixed[len(Magic)+1:])),
+		Backend: backend.ID(binary.BigEndian.Uint16(fixed[len(Magic)+3:])),
+	}
+	version := make([]byte, fixed[len(Magic)+5])
+	if _, err := io.ReadFull(r, version); err != nil {
+		return Header{}, int64(len(fixed)), fmt.Errorf("reading header: %w", err)
+	}
+	h.GnarkVersion = string(version)
+	return h, int64(len(fixed) + len(version)), nil
+}
@@ -0,0 +1,82 @@
+package artifacts
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+)
+
+// object is a serialized gnark object of a fixed size
+type object [8]byte
+
+func (o *object) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(o[:])
+	return int64(n), err
+}
+
+func (o *object) ReadFrom(r io.Reader) (int64, error) {
+	n, err := io.ReadFull(r, o[:])
+	return int64(n), err
+}
+
+func TestHeader(t *testing.T) {
+	expected := Header{GnarkVersion: "v0.5.0", Curve: ecc.BN254, Backend: backend.GROTH16}
+	written := object{1, 2, 3, 4, 5, 6, 7, 8}
+	write := func(h Header) []byte {
+		var buf bytes.Buffer
+		n, err := Write(&buf, h, &written)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(buf.Len()) {
+			t.Fatalf("Write returned %d for %d bytes", n, buf.Len())
+		}
+		return buf.Bytes()
+	}
+
+	for name, tc := range map[string]struct {
+		data []byte
+		// err is a part of the expected error, none if empty
+		err string
+	}{
+		"header":           {data: write(expected)},
+		"no header":        {data: written[:]},
+		"another gnark":    {data: write(Header{GnarkVersion: "v0.6.0", Curve: ecc.BN254, Backend: backend.GROTH16})},
+		"another curve":    {data: write(Header{GnarkVersion: "v0.5.0", Curve: ecc.BLS12_381, Backend: backend.GROTH16}), err: "bls12_381"},
+		"another backend":  {data: write(Header{GnarkVersion: "v0.5.0", Curve: ecc.BN254, Backend: backend.PLONK}), err: "plonk"},
+		"truncated":        {data: write(expected)[:len(Magic)+3], err: "reading header"},
+		"truncated object": {data: write(Header{GnarkVersion: "v0.6.0", Curve: ecc.BN254, Backend: backend.GROTH16})[:len(Magic)+6+len("v0.6.0")+4], err: "this binary uses gnark v0.5.0"},
+		"version": {
+			data: func() []byte {
+				data := write(expected)
+				data[len(Magic)] = headerVersion + 1
+				return data
+			}(),
+			err: "unsupported header version",
+		},
+	} {
+		var read object
+		n, err := Read(bytes.NewReader(tc.data), expected, &read)
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s: %v", name, err)
+		case tc.err == "" && (read != written || n != int64(len(tc.data))):
+			t.Errorf("%s: read %v, %d bytes", name, read, n)
+		case tc.err != "" && (err == nil || !strings.Contains(strings.ToLower(err.Error()), tc.err)):
+			t.Errorf("%s: got %v, expected %q", name, err, tc.err)
+		}
+	}
+
+	if _, err := Write(io.Discard, Header{GnarkVersion: strings.Repeat("v", 256)}, &written); err == nil {
+		t.Error("wrote a gnark version too long for the header")
+	}
+	var read object
+	if _, err := Read(bytes.NewReader(nil), expected, &read); !errors.Is(err, io.EOF) {
+		t.Errorf("empty: got %v", err)
+	}
+}
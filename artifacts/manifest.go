@@ -0,0 +1,80 @@
+// Package artifacts describes the files produced by the circuit setup.
+package artifacts
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/gbotrel/gnark-workshop/features"
+)
+
+// Manifest records how a set of artifacts (R1CS, proving and verifying keys) was built
+type Manifest struct {
+	// Circuit is the name of the circuit, the name of its instance for parameterized circuits
+	Circuit string `json:"circuit"`
+	// Params are the parameters of the instance, defaults included
+	Params       map[string]int  `json:"params,omitempty"`
+	Curve        string          `json:"curve"`
+	Backend      string          `json:"backend"`
+	Features     []features.Flag `json:"features,omitempty"`
+	GnarkVersion string          `json:"gnarkVersion,omitempty"`
+	// CircuitHash is the Hash of the compiled circuit the keys were set up for
+	CircuitHash string `json:"circuitHash,omitempty"`
+	// PKHash and VKHash are the HashFile of the key files
+	PKHash string `json:"pkHash,omitempty"`
+	VKHash string `json:"vkHash,omitempty"`
+	// DeterministicSetup is set for keys derived from a public seed, which must not be deployed
+	DeterministicSetup bool `json:"deterministicSetup,omitempty"`
+	// Beacon is set if a public random beacon was mixed into the setup, last
+	Beacon *Beacon `json:"beacon,omitempty"`
+	// Version is the version of the setup in the history of the circuit (see Store.History), 0 for
+	// artifacts built before versions existed
+	Version int `json:"version,omitempty"`
+	// Digests are the hashes of the other artifacts, checked before they are used
+	Digests Digests `json:"digests,omitempty"`
+	// Signature is set if the manifest was signed (see Sign), last
+	Signature *Signature `json:"signature,omitempty"`
+}
+
+// Beacon records the public random beacon the last contribution to the keys was derived from (see
+// ceremony.ContributeBeacon), so that anyone can check it was used
+type Beacon struct {
+	// Value is the beacon as given, e.g. a block hash or the randomness of a drand round
+	Value string `json:"value"`
+	// X1 is the hex [x]₁ of the contribution, and PrevVK the sha256 of the verifying key before it,
+	// as in ceremony.Contribution
+	X1     string `json:"x1"`
+	PrevVK string `json:"prevVk"`
+}
+
+// ReadManifest reads a manifest file
+// Artifacts built before manifests existed have none: they get an empty manifest, requiring no feature.
+func ReadManifest(fileName string) (*Manifest, error) {
+	var m Manifest
+	data, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return &m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Save writes the manifest as indented JSON
+func (m *Manifest) Save(fileName string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, append(data, '\n'), 0644)
+}
+
+// CheckFeatures returns an error if the artifacts need a feature this binary doesn't support
+func (m *Manifest) CheckFeatures() error {
+	return features.Check(m.Features)
+}
@@ -0,0 +1,138 @@
+package artifacts
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// TrustedKeyEnv is the environment variable holding the hex ed25519 public key manifests must be
+// signed with, for the artifacts to be trusted (see Manifest.Verify)
+const TrustedKeyEnv = "GNARK_WORKSHOP_TRUSTED_KEY"
+
+var (
+	// ErrUnsigned is returned when a trusted key is set and a manifest is not signed
+	ErrUnsigned = errors.New("manifest is not signed")
+	// ErrBadSignature is returned when a manifest signature doesn't verify, or is not made with the
+	// trusted key
+	ErrBadSignature = errors.New("manifest signature doesn't verify")
+)
+
+// Signature is the ed25519 signature of a manifest, by PublicKey
+type Signature struct {
+	PublicKey string `json:"publicKey"`
+	Signature string `json:"signature"`
+}
+
+// Digests are the HashFile of the artifacts derived from the keys, by name: R1CSFile and the
+// verifier files (verifier.sol, verifier.bin, verifier.abi)
+type Digests map[string]string
+
+// signedBytes returns the JSON encoding of m without its signature, as signed
+func (m *Manifest) signedBytes() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	return json.Marshal(&unsigned)
+}
+
+// Sign signs m with key, replacing any previous signature; m must not change afterwards
+func (m *Manifest) Sign(key ed25519.PrivateKey) error {
+	data, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	m.Signature = &Signature{
+		PublicKey: hex.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Signature: hex.EncodeToString(ed25519.Sign(key, data)),
+	}
+	return nil
+}
+
+// Verify checks the signature of m, made with trusted if set: a manifest without signature passes
+// if trusted is nil, and fails with ErrUnsigned otherwise
+func (m *Manifest) Verify(trusted ed25519.PublicKey) error {
+	if m.Signature == nil {
+		if trusted != nil {
+			return fmt.Errorf("%s: %w", m.Circuit, ErrUnsigned)
+		}
+		return nil
+	}
+	publicKey, err := hex.DecodeString(m.Signature.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("%s: %w: invalid public key", m.Circuit, ErrBadSignature)
+	}
+	if trusted != nil && !trusted.Equal(ed25519.PublicKey(publicKey)) {
+		return fmt.Errorf("%s: %w: signed by %s, not the trusted key", m.Circuit, ErrBadSignature, m.Signature.PublicKey)
+	}
+	signature, err := hex.DecodeString(m.Signature.Signature)
+	if err != nil {
+		return fmt.Errorf("%s: %w", m.Circuit, ErrBadSignature)
+	}
+	data, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, data, signature) {
+		return fmt.Errorf("%s: %w", m.Circuit, ErrBadSignature)
+	}
+	return nil
+}
+
+// CheckDigests returns ErrCorrupted if a file of files, by artifact name, doesn't match its digest;
+// artifacts without digest, or without file, are not checked
+func (m *Manifest) CheckDigests(files map[string]string) error {
+	for name, fileName := range files {
+		expected, ok := m.Digests[name]
+		if !ok || fileName == "" {
+			continue
+		}
+		h, err := HashFile(fileName)
+		if err != nil {
+			return err
+		}
+		if h != expected {
+			return fmt.Errorf("%s: %w", fileName, ErrCorrupted)
+		}
+	}
+	return nil
+}
+
+// TrustedKey returns the public key of TrustedKeyEnv, nil if not set
+func TrustedKey() (ed25519.PublicKey, error) {
+	value := strings.TrimSpace(os.Getenv(TrustedKeyEnv))
+	if value == "" {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%s: expected a hex ed25519 public key", TrustedKeyEnv)
+	}
+	return key, nil
+}
+
+// ReadSigningKey reads the hex ed25519 seed of fileName; with generate, a new key is written to
+// fileName first if it doesn't exist
+func ReadSigningKey(fileName string, generate bool) (ed25519.PrivateKey, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) && generate {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return key, ioutil.WriteFile(fileName, []byte(hex.EncodeToString(key.Seed())+"\n"), 0600)
+	}
+	if err != nil {
+		return nil, err
+	}
+	seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("%s: expected a hex ed25519 seed", fileName)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
@@ -0,0 +1,199 @@
+package artifacts
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func signedManifest(t *testing.T, key ed25519.PrivateKey) *Manifest {
+	t.Helper()
+	m := &Manifest{
+		Circuit: "mimc", Curve: "bn254", CircuitHash: "c", PKHash: "p", VKHash: "v", Version: 1,
+		Digests: Digests{R1CSFile: "r", "verifier.sol": "s"},
+	}
+	if err := m.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestSignVerify(t *testing.T) {
+	key, other := newKey(t), newKey(t)
+	trusted := key.Public().(ed25519.PublicKey)
+
+	for name, tc := range map[string]struct {
+		manifest func() *Manifest
+		trusted  ed25519.PublicKey
+		expected error
+	}{
+		"signed":             {manifest: func() *Manifest { return signedManifest(t, key) }},
+		"signed and trusted": {manifest: func() *Manifest { return signedManifest(t, key) }, trusted: trusted},
+		"unsigned":           {manifest: func() *Manifest { return &Manifest{Circuit: "mimc"} }},
+		"unsigned, trusted key set": {
+			manifest: func() *Manifest { return &Manifest{Circuit: "mimc"} },
+			trusted:  trusted,
+			expected: ErrUnsigned,
+		},
+		"another key": {manifest: func() *Manifest { return signedManifest(t, other) }, trusted: trusted, expected: ErrBadSignature},
+		"pk hash changed": {
+			manifest: func() *Manifest {
+				m := signedManifest(t, key)
+				m.PKHash = "q"
+				return m
+			},
+			expected: ErrBadSignature,
+		},
+		"digest changed": {
+			manifest: func() *Manifest {
+				m := signedManifest(t, key)
+				m.Digests["verifier.sol"] = "t"
+				return m
+			},
+			expected: ErrBadSignature,
+		},
+		// the signer's key replaced along with the content: only a trusted key catches it
+		"re-signed": {
+			manifest: func() *Manifest {
+				m := signedManifest(t, key)
+				m.VKHash = "w"
+				if err := m.Sign(other); err != nil {
+					t.Fatal(err)
+				}
+				return m
+			},
+			trusted:  trusted,
+			expected: ErrBadSignature,
+		},
+		"invalid public key": {
+			manifest: func() *Manifest {
+				m := signedManifest(t, key)
+				m.Signature.PublicKey = "00"
+				return m
+			},
+			expected: ErrBadSignature,
+		},
+		"invalid signature": {
+			manifest: func() *Manifest {
+				m := signedManifest(t, key)
+				m.Signature.Signature = "zz"
+				return m
+			},
+			expected: ErrBadSignature,
+		},
+	} {
+		if err := tc.manifest().Verify(tc.trusted); !errors.Is(err, tc.expected) {
+			t.Errorf("%s: got %v, expected %v", name, err, tc.expected)
+		}
+	}
+
+	// the signature survives Save and ReadManifest
+	fileName := filepath.Join(t.TempDir(), ManifestFile)
+	if err := signedManifest(t, key).Save(fileName); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadManifest(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Verify(trusted); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckDigests(t *testing.T) {
+	dir := t.TempDir()
+	files := make(map[string]string)
+	m := &Manifest{Digests: make(Digests)}
+	for _, name := range []string{R1CSFile, "verifier.sol"} {
+		files[name] = filepath.Join(dir, name)
+		if err := ioutil.WriteFile(files[name], []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		h, err := HashFile(files[name])
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Digests[name] = h
+	}
+	if err := m.CheckDigests(files); err != nil {
+		t.Fatal(err)
+	}
+	// artifacts without digest, or without file, are skipped
+	if err := m.CheckDigests(map[string]string{"verifier.abi": filepath.Join(dir, "missing"), R1CSFile: ""}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(files["verifier.sol"], []byte("contract Verifier {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CheckDigests(files); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("got %v, expected ErrCorrupted", err)
+	}
+	if err := os.Remove(files["verifier.sol"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CheckDigests(files); err == nil {
+		t.Fatal("a missing artifact with a digest passed")
+	}
+}
+
+func TestTrustedKey(t *testing.T) {
+	key := newKey(t).Public().(ed25519.PublicKey)
+	for value, expected := range map[string]ed25519.PublicKey{
+		"":                                    nil,
+		hex.EncodeToString(key):               key,
+		"0x" + hex.EncodeToString(key) + "\n": key,
+		" " + hex.EncodeToString(key) + " ":   key,
+	} {
+		t.Setenv(TrustedKeyEnv, value)
+		got, err := TrustedKey()
+		if err != nil || !got.Equal(expected) {
+			t.Errorf("%q: got %x, %v", value, got, err)
+		}
+	}
+	for _, value := range []string{"zz", hex.EncodeToString(key[:16])} {
+		t.Setenv(TrustedKeyEnv, value)
+		if _, err := TrustedKey(); err == nil {
+			t.Errorf("%q: accepted", value)
+		}
+	}
+}
+
+func TestReadSigningKey(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "signing.key")
+	if _, err := ReadSigningKey(fileName, false); !os.IsNotExist(err) {
+		t.Fatalf("got %v, expected a missing file", err)
+	}
+	key, err := ReadSigningKey(fileName, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(fileName); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("key file: %v, %v", info.Mode(), err)
+	}
+	again, err := ReadSigningKey(fileName, true)
+	if err != nil || !again.Equal(key) {
+		t.Fatalf("the key changed: %v", err)
+	}
+	if err := ioutil.WriteFile(fileName, []byte("not a seed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSigningKey(fileName, true); err == nil {
+		t.Fatal("read an invalid seed")
+	}
+}
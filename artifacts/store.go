@@ -0,0 +1,143 @@
+package artifacts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+)
+
+// Names of the artifacts in a store directory
+const (
+	R1CSFile     = "circuit.r1cs"
+	PKFile       = "circuit.pk"
+	VKFile       = "circuit.vk"
+	ManifestFile = "manifest.json"
+)
+
+var (
+	// ErrCircuitMismatch is returned when the artifacts were not built from the compiled circuit
+	ErrCircuitMismatch = errors.New("artifacts were built for another version of the circuit, run -init again")
+	// ErrCorrupted is returned when a key file doesn't match the hash recorded in the manifest
+	ErrCorrupted = errors.New("artifact doesn't match the hash recorded in its manifest")
+)
+
+// Store is a content-addressed artifact store: each setup is kept in its own directory, named
+// after the ID of its manifest, and Root/<circuit>.current names the setup in use for a circuit
+type Store struct {
+	Root string
+}
+
+// Dir returns the directory of the setup id
+func (s Store) Dir(id string) string {
+	return filepath.Join(s.Root, id)
+}
+
+// Current returns the ID of the setup in use for circuit; it is empty if there is none
+func (s Store) Current(circuit string) (string, error) {
+	data, err := ioutil.ReadFile(s.currentFile(circuit))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SetCurrent makes the setup id the one in use for circuit
+func (s Store) SetCurrent(circuit, id string) error {
+	return ioutil.WriteFile(s.currentFile(circuit), []byte(id+"\n"), 0644)
+}
+
+func (s Store) currentFile(circuit string) string {
+	return filepath.Join(s.Root, circuit+".current")
+}
+
+// ID returns the store ID of the artifacts described by m: a hash of the circuit name, the curve,
+// the gnark version, the circuit hash, the version and the hashes of the keys
+// Every setup has its own ID, and the artifacts under an ID never change: a second setup of the same
+// circuit version is stored under a new ID, and the version history points to it.
+func ID(m *Manifest) string {
+	h := sha256.New()
+	for _, s := range []string{m.Circuit, m.Curve, m.GnarkVersion, m.CircuitHash, m.PKHash, m.VKHash} {
+		fmt.Fprintf(h, "%d:%s", len(s), s)
+	}
+	fmt.Fprintf(h, "version:%d", m.Version)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// GnarkVersion returns the version of the gnark module this binary is built with
+func GnarkVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/consensys/gnark" {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// Hash returns the hex encoded sha256 of the serialization of o
+// With o a compiled constraint system, it is the circuit hash of the manifests.
+func Hash(o io.WriterTo) (string, error) {
+	h := sha256.New()
+	if _, err := o.WriteTo(h); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashFile returns the hex encoded sha256 of the content of fileName, or of its chunks (see Split)
+func HashFile(fileName string) (string, error) {
+	f, err := Open(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CheckCircuit returns ErrCircuitMismatch if circuitHash (the Hash of the freshly compiled
+// circuit) is not the one the artifacts were built from
+// Manifests written before circuit hashes were recorded pass.
+func (m *Manifest) CheckCircuit(circuitHash string) error {
+	if m.CircuitHash != "" && m.CircuitHash != circuitHash {
+		return fmt.Errorf("%s: %w", m.Circuit, ErrCircuitMismatch)
+	}
+	return nil
+}
+
+// CheckKeys returns ErrCorrupted if the proving or verifying key file doesn't match its
+// recorded hash; an empty file name skips the corresponding check
+func (m *Manifest) CheckKeys(pkFile, vkFile string) error {
+	for _, k := range []struct{ fileName, hash string }{{pkFile, m.PKHash}, {vkFile, m.VKHash}} {
+		if k.fileName == "" || k.hash == "" {
+			continue
+		}
+		h, err := HashFile(k.fileName)
+		if err != nil {
+			return err
+		}
+		if h != k.hash {
+			return fmt.Errorf("%s: %w", k.fileName, ErrCorrupted)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package artifacts
+
+import "testing"
+
+func TestID(t *testing.T) {
+	m := Manifest{Circuit: "mimc", Curve: "bn254", GnarkVersion: "v0.5.1", CircuitHash: "c", PKHash: "p", VKHash: "v", Version: 1}
+	id := ID(&m)
+	if again := m; ID(&again) != id {
+		t.Fatal("the ID of the same manifest changed")
+	}
+	// fields that aren't part of the setup don't change the ID
+	signed := m
+	signed.Signature = &Signature{}
+	signed.Digests = Digests{R1CSFile: "r"}
+	if ID(&signed) != id {
+		t.Fatal("the ID depends on the signature or the digests")
+	}
+
+	for name, change := range map[string]func(m *Manifest){
+		"circuit":       func(m *Manifest) { m.Circuit = "password" },
+		"circuit hash":  func(m *Manifest) { m.CircuitHash = "d" },
+		"version":       func(m *Manifest) { m.Version = 2 },
+		"proving key":   func(m *Manifest) { m.PKHash = "q" },
+		"verifying key": func(m *Manifest) { m.VKHash = "w" },
+		// the fields are length prefixed: moving a character between them is another ID
+		"boundary": func(m *Manifest) { m.PKHash, m.VKHash = "pv", "" },
+	} {
+		other := m
+		change(&other)
+		if ID(&other) == id {
+			t.Errorf("%s: same ID", name)
+		}
+	}
+}
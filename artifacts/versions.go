@@ -0,0 +1,86 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Version is a setup of a circuit, as recorded in the version history of the store
+// Proofs made with the keys of a version are verified with its verifying key, in the store
+// directory ID, or on chain by its verifiers.
+type Version struct {
+	Version     int    `json:"version"`
+	ID          string `json:"id"`
+	CircuitHash string `json:"circuitHash"`
+	VKHash      string `json:"vkHash,omitempty"`
+	// Previous is the version this one was migrated from, 0 for the first one
+	Previous int `json:"previous,omitempty"`
+	// Verifiers are the addresses of the verifiers of this version, by (decimal) chain ID
+	Verifiers map[string]string `json:"verifiers,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// History is the list of the versions of a circuit, oldest first
+type History []Version
+
+// History returns the version history of circuit; it is empty for circuits set up before versions existed
+func (s Store) History(circuit string) (History, error) {
+	var h History
+	data, err := ioutil.ReadFile(s.historyFile(circuit))
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// SaveHistory writes the version history of circuit
+func (s Store) SaveHistory(circuit string, h History) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Root, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.historyFile(circuit), append(data, '\n'), 0644)
+}
+
+func (s Store) historyFile(circuit string) string {
+	return filepath.Join(s.Root, circuit+".versions.json")
+}
+
+// Latest returns the last version, nil if there is none
+func (h History) Latest() *Version {
+	if len(h) == 0 {
+		return nil
+	}
+	return &h[len(h)-1]
+}
+
+// Get returns the version number version
+func (h History) Get(version int) (*Version, bool) {
+	for i := range h {
+		if h[i].Version == version {
+			return &h[i], true
+		}
+	}
+	return nil, false
+}
+
+// Set records v, replacing the version with the same number if any
+func (h History) Set(v Version) History {
+	if existing, ok := h.Get(v.Version); ok {
+		*existing = v
+		return h
+	}
+	return append(h, v)
+}
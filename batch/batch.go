@@ -0,0 +1,183 @@
+// Package batch verifies several Groth16 proofs (BN254) of the same circuit at once, with a
+// single randomized pairing check.
+//
+// Each Groth16 check is e(A, B) = e(α, β)·e(L, γ)·e(C, δ), L being the public inputs combination
+// of the verifying key. With random r_i, the n checks hold (but with negligible probability)
+// iff
+//
+//	∏ e(r_i·A_i, B_i) = e((Σr_i)·α, β)·e(Σr_i·L_i, γ)·e(Σr_i·C_i, δ)
+//
+// that is n+3 pairings (and one final exponentiation) instead of 4n.
+package batch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+var (
+	// ErrInvalidBatch is returned when at least one proof of the batch is invalid
+	ErrInvalidBatch = errors.New("batch verification failed: at least one proof is invalid")
+	// ErrEmptyBatch is returned when verifying a batch without proofs
+	ErrEmptyBatch = errors.New("empty batch")
+)
+
+type verifyingKey struct {
+	alpha              bn254.G1Affine
+	beta, gamma, delta bn254.G2Affine
+	k                  []bn254.G1Affine
+}
+
+type proof struct {
+	ar, krs bn254.G1Affine
+	bs      bn254.G2Affine
+}
+
+// Batch collects proofs of the same circuit and their public witnesses
+type Batch struct {
+	vk      verifyingKey
+	proofs  []proof
+	publics [][]fr.Element
+}
+
+// New returns an empty batch of proofs verified against vk
+func New(vk groth16.VerifyingKey) (*Batch, error) {
+	var buf bytes.Buffer
+	if _, err := vk.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	// G1.Alpha, G1.Beta, G2.Beta, G2.Gamma, G1.Delta, G2.Delta, G1.K
+	var (
+		b       Batch
+		g1Beta  bn254.G1Affine
+		g1Delta bn254.G1Affine
+	)
+	dec := bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&b.vk.alpha, &g1Beta, &b.vk.beta, &b.vk.gamma, &g1Delta, &b.vk.delta, &b.vk.k} {
+		if err := dec.Decode(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(b.vk.k) == 0 {
+		return nil, errors.New("invalid verifying key: empty K")
+	}
+	return &b, nil
+}
+
+// Add adds a proof and its public witness to the batch
+func (b *Batch) Add(p groth16.Proof, publicWitness []fr.Element) error {
+	if len(publicWitness) != len(b.vk.k)-1 {
+		return fmt.Errorf("proof %d: got %d public inputs, expected %d", len(b.proofs), len(publicWitness), len(b.vk.k)-1)
+	}
+	var buf bytes.Buffer
+	if _, err := p.WriteTo(&buf); err != nil {
+		return err
+	}
+	var decoded proof
+	dec := bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&decoded.ar, &decoded.bs, &decoded.krs} {
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+	}
+	b.proofs = append(b.proofs, decoded)
+	b.publics = append(b.publics, publicWitness)
+	return nil
+}
+
+// Len returns the number of proofs in the batch
+func (b *Batch) Len() int {
+	return len(b.proofs)
+}
+
+// Verify checks all the proofs of the batch at once; it returns ErrInvalidBatch without telling
+// which proof is invalid (verify them one by one to find out)
+func (b *Batch) Verify() error {
+	if len(b.proofs) == 0 {
+		return ErrEmptyBatch
+	}
+	n := len(b.proofs)
+
+	// fresh randomness on every call: a prover knowing r_i could forge a batch
+	r := make([]fr.Element, n)
+	for i := range r {
+		if _, err := r[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	P := make([]bn254.G1Affine, 0, n+3)
+	Q := make([]bn254.G2Affine, 0, n+3)
+
+	// e(-r_i·A_i, B_i)
+	var sumR fr.Element
+	for i, p := range b.proofs {
+		sumR.Add(&sumR, &r[i])
+		var a bn254.G1Affine
+		a.ScalarMultiplication(&p.ar, toBig(r[i]))
+		a.Neg(&a)
+		P = append(P, a)
+		Q = append(Q, p.bs)
+	}
+
+	// e((Σr_i)·α, β)
+	var alpha bn254.G1Affine
+	alpha.ScalarMultiplication(&b.vk.alpha, toBig(sumR))
+	P = append(P, alpha)
+	Q = append(Q, b.vk.beta)
+
+	// e(Σr_i·L_i, γ), with Σr_i·L_i = (Σr_i)·K_0 + Σ_j (Σ_i r_i·x_ij)·K_j
+	scalars := make([]fr.Element, len(b.vk.k))
+	scalars[0] = sumR
+	for i := range b.publics {
+		for j, x := range b.publics[i] {
+			var t fr.Element
+			t.Mul(&r[i], &x)
+			scalars[j+1].Add(&scalars[j+1], &t)
+		}
+	}
+	P = append(P, linearCombination(b.vk.k, scalars))
+	Q = append(Q, b.vk.gamma)
+
+	// e(Σr_i·C_i, δ)
+	krs := make([]bn254.G1Affine, n)
+	for i, p := range b.proofs {
+		krs[i] = p.krs
+	}
+	P = append(P, linearCombination(krs, r))
+	Q = append(Q, b.vk.delta)
+
+	ok, err := bn254.PairingCheck(P, Q)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidBatch
+	}
+	return nil
+}
+
+// linearCombination returns Σ scalars[i]·points[i]
+func linearCombination(points []bn254.G1Affine, scalars []fr.Element) bn254.G1Affine {
+	var acc bn254.G1Jac
+	for i := range points {
+		var t bn254.G1Jac
+		t.FromAffine(&points[i])
+		t.ScalarMultiplication(&t, toBig(scalars[i]))
+		acc.AddAssign(&t)
+	}
+	var res bn254.G1Affine
+	res.FromJacobian(&acc)
+	return res
+}
+
+func toBig(e fr.Element) *big.Int {
+	return e.ToBigIntRegular(new(big.Int))
+}
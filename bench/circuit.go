@@ -0,0 +1,49 @@
+// Package bench measures the costs of the workshop verifiers, with synthetic circuits whose
+// shape (e.g. the number of public inputs) can be varied.
+package bench
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+)
+
+// PublicInputs defines a circuit with len(Inputs) public inputs
+// sum(public inputs) = secret sum
+type PublicInputs struct {
+	Inputs []frontend.Variable `gnark:",public"`
+	Sum    frontend.Variable
+}
+
+// NewPublicInputs returns a PublicInputs circuit definition with n public inputs
+func NewPublicInputs(n int) *PublicInputs {
+	return &PublicInputs{Inputs: make([]frontend.Variable, n)}
+}
+
+// Define declares the circuit's constraints
+func (c *PublicInputs) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	sum := cs.Constant(0)
+	for _, input := range c.Inputs {
+		sum = cs.Add(sum, input)
+	}
+	cs.AssertIsEqual(sum, c.Sum)
+	return nil
+}
+
+// PublicInputsWitness returns a valid PublicInputs assignment with n public inputs, 1 to n
+// Inputs are large field elements, so that the calldata doesn't benefit from zero bytes pricing.
+func PublicInputsWitness(n int) *PublicInputs {
+	witness := NewPublicInputs(n)
+	sum := new(big.Int)
+	for i := range witness.Inputs {
+		// 2^250 + i
+		v := new(big.Int).Lsh(big.NewInt(1), 250)
+		v.Add(v, big.NewInt(int64(i+1)))
+		witness.Inputs[i].Assign(v)
+		sum.Add(sum, v)
+	}
+	witness.Sum.Assign(sum.Mod(sum, fr.Modulus()))
+	return witness
+}
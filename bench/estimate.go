@@ -0,0 +1,124 @@
+package bench
+
+import (
+	"math"
+	"math/bits"
+	"runtime"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+)
+
+// Calibration is the speed of the groth16 prover primitives on this machine, measured by Calibrate
+type Calibration struct {
+	LogSize int `json:"logSize"`
+	Procs   int `json:"procs"`
+	// MSM is the time of a BN254 G1 multi-exponentiation of 2^LogSize points
+	MSM time.Duration `json:"msm"`
+	// FFT is the time of an FFT of 2^LogSize elements
+	FFT time.Duration `json:"fft"`
+}
+
+// Calibrate measures the G1 MSM and the FFT of 2^logSize elements, the best of 3 runs, on the
+// GOMAXPROCS cores; 2^16 takes well under a second on a laptop
+func Calibrate(logSize int) (Calibration, error) {
+	points, scalars := msmInputs(1 << logSize)
+	cal := Calibration{LogSize: logSize, Procs: runtime.GOMAXPROCS(0)}
+	best := func(f func() error) (time.Duration, error) {
+		min := time.Duration(math.MaxInt64)
+		for i := 0; i < 3; i++ {
+			start := time.Now()
+			if err := f(); err != nil {
+				return 0, err
+			}
+			if d := time.Since(start); d < min {
+				min = d
+			}
+		}
+		return min, nil
+	}
+	var err error
+	if cal.MSM, err = best(func() error {
+		var r bn254.G1Affine
+		_, err := r.MultiExp(points, scalars, ecc.MultiExpConfig{ScalarsMont: true})
+		return err
+	}); err != nil {
+		return cal, err
+	}
+	values := append([]fr.Element(nil), scalars...)
+	domain := fft.NewDomain(uint64(len(values)), 0, false)
+	cal.FFT, err = best(func() error {
+		domain.FFT(values, fft.DIF, 0)
+		fft.BitReverse(values)
+		return nil
+	})
+	return cal, err
+}
+
+// ProofEstimate is the expected cost of a groth16 proof on BN254
+type ProofEstimate struct {
+	// Domain is the size of the FFT domain, the number of constraints rounded up to a power of 2
+	Domain int           `json:"domain"`
+	Time   time.Duration `json:"time"`
+	// Memory is the peak heap: proving key and R1CS in memory, plus the prover vectors
+	Memory int64 `json:"memory"`
+}
+
+// EstimateProof extrapolates cal to the proof of a circuit of constraints constraints, wires wires
+// and public public inputs (the constant wire excluded), as gnark v0.5 computes it: 4 G1 MSMs (A and B
+// over the wires, K over the private wires, Z over the domain), 1 G2 MSM over the wires, about 3 times
+// as slow, and 7 FFTs of the domain. An MSM of n points costs n/log(n) times a constant (Pippenger),
+// an FFT n·log(n). The estimate is rough, ±50%: use it to know what to expect, not as a benchmark.
+func EstimateProof(cal Calibration, constraints, wires, public int) ProofEstimate {
+	// the domain holds the constraints and a constraint per public wire
+	domain := 1 << bits.Len(uint(constraints+public))
+	calSize := float64(int(1) << cal.LogSize)
+	msm := func(n int) float64 {
+		if n < 2 {
+			return 0
+		}
+		return float64(cal.MSM) * float64(n) / calSize * math.Log2(calSize) / math.Log2(float64(n))
+	}
+	fft := func(n int) float64 {
+		if n < 2 {
+			return 0
+		}
+		return float64(cal.FFT) * float64(n) * math.Log2(float64(n)) / (calSize * math.Log2(calSize))
+	}
+	private := wires - public - 1
+	ns := 2*msm(wires) + msm(private) + msm(domain) + 3*msm(wires) + 7*fft(domain)
+
+	const (
+		g1, g2, element = 64, 128, 32
+		// 3 linear expressions of a few terms, and their slice headers, per constraint
+		constraint = 120
+	)
+	pk := int64(g1*(2*wires+private+domain) + g2*wires)
+	r1cs := int64(constraint * constraints)
+	// the wire values, then a, b, c and their coset evaluations
+	vectors := int64(element*wires + 2*3*element*domain)
+	return ProofEstimate{Domain: domain, Time: time.Duration(ns), Memory: pk + r1cs + vectors}
+}
+
+// msmInputs returns n distinct points, multiples of the generator, and n full size scalars
+func msmInputs(n int) ([]bn254.G1Affine, []fr.Element) {
+	_, _, g, _ := bn254.Generators()
+	points := make([]bn254.G1Affine, n)
+	scalars := make([]fr.Element, n)
+	var acc bn254.G1Jac
+	acc.FromAffine(&g)
+	var c fr.Element
+	c.SetString("8444461749428370424248824938781546531375899335154063827935233455917409239041")
+	scalars[0].SetUint64(7)
+	for i := range points {
+		points[i].FromJacobian(&acc)
+		acc.AddMixed(&g)
+		if i > 0 {
+			scalars[i].Mul(&scalars[i-1], &c)
+		}
+	}
+	return points, scalars
+}
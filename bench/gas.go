@@ -0,0 +1,111 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// Gas is the on-chain cost of the verifier of a circuit with PublicInputs public inputs
+type Gas struct {
+	PublicInputs int `json:"publicInputs"`
+	// Deploy is the gas used by the verifier creation transaction
+	Deploy uint64 `json:"deploy"`
+	// Verify is the gas used by a verifyProof transaction, including the 21000 base cost
+	// and the calldata
+	Verify uint64 `json:"verify"`
+	// Calldata is the size of the verifyProof calldata, in bytes
+	Calldata int `json:"calldata"`
+	// Bytecode is the size of the deployed verifier creation code, in bytes
+	Bytecode int `json:"bytecode"`
+}
+
+// MeasureGas sets up a PublicInputs circuit with n public inputs, deploys its verifier on chain
+// (the simulated backend, or a dev node) and sends a verifyProof transaction with a valid proof
+// Requires solc in PATH.
+func MeasureGas(auth *bind.TransactOpts, chain ethereum.Backend, n int) (Gas, error) {
+	result := Gas{PublicInputs: n}
+	ctx := context.Background()
+
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, NewPublicInputs(n))
+	if err != nil {
+		return result, err
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		return result, err
+	}
+
+	// deploy the verifier
+	var buf bytes.Buffer
+	if err := vk.ExportSolidity(&buf); err != nil {
+		return result, err
+	}
+	contracts, err := ethereum.CompileSolidity(buf.String())
+	if err != nil {
+		return result, err
+	}
+	_, bytecode, err := ethereum.Artifact(contracts, "Verifier")
+	if err != nil {
+		return result, err
+	}
+	result.Bytecode = len(bytecode)
+	address, tx, err := ethereum.DeployRaw(ctx, auth, chain, bytecode)
+	if err != nil {
+		return result, err
+	}
+	chain.Commit()
+	receipt, err := chain.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return result, err
+	}
+	if receipt.Status != 1 {
+		return result, fmt.Errorf("verifier deployment with %d public inputs reverted", n)
+	}
+	result.Deploy = receipt.GasUsed
+
+	// verify a proof in a transaction
+	witness := PublicInputsWitness(n)
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	if err != nil {
+		return result, err
+	}
+	publicWitness, err := ethereum.PublicWitness(witness)
+	if err != nil {
+		return result, err
+	}
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	if err != nil {
+		return result, err
+	}
+	calldata, err := solidityInputs.Calldata()
+	if err != nil {
+		return result, err
+	}
+	result.Calldata = len(calldata)
+	parsed, err := ethereum.VerifierABI(n)
+	if err != nil {
+		return result, err
+	}
+	tx, err = bind.NewBoundContract(address, parsed, chain, chain, chain).RawTransact(auth, calldata)
+	if err != nil {
+		return result, err
+	}
+	chain.Commit()
+	receipt, err = chain.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return result, err
+	}
+	if receipt.Status != 1 {
+		return result, fmt.Errorf("verifyProof with %d public inputs reverted", n)
+	}
+	result.Verify = receipt.GasUsed
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package bench
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/accel"
+)
+
+// Result is the measure of one stage of the proving flow of a circuit on a curve
+type Result struct {
+	// Name is the benchmark name, Benchmark<Stage>/<circuit>/<curve>-<GOMAXPROCS> as go test names them
+	Name string
+	testing.BenchmarkResult
+}
+
+// String formats r as a line of `go test -bench` output, which benchstat reads
+func (r Result) String() string {
+	return fmt.Sprintf("%s\t%s\t%s", r.Name, r.BenchmarkResult.String(), r.MemString())
+}
+
+// Prover benchmarks compile, setup, witness solving, prove and verify of c on curveID, calling
+// report after each stage; proofs are also measured on the registered accelerator, if any (ProveAccel)
+// witness may be nil, or not solve on curveID (e.g. its hash was computed on another curve):
+// the solve, prove and verify stages are then skipped and the returned error says why.
+// testing.Init must have been called, and the test.benchtime flag set, before.
+func Prover(name string, curveID ecc.ID, c, witness frontend.Circuit, report func(Result)) error {
+	run := func(stage string, f func(b *testing.B)) {
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			f(b)
+		})
+		report(Result{Name: fmt.Sprintf("Benchmark%s/%s/%s-%d", stage, name, curveID, runtime.GOMAXPROCS(0)), BenchmarkResult: r})
+	}
+
+	// the measured stages must succeed, check them once outside of the benchmarks
+	r1cs, err := frontend.Compile(curveID, backend.GROTH16, c)
+	if err != nil {
+		return err
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		return err
+	}
+
+	run("Compile", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = frontend.Compile(curveID, backend.GROTH16, c)
+		}
+	})
+	run("Setup", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = groth16.Setup(r1cs)
+		}
+	})
+
+	if witness == nil {
+		return fmt.Errorf("%s: no example witness, skipping solve, prove and verify", name)
+	}
+	if err := groth16.IsSolved(r1cs, witness); err != nil {
+		return fmt.Errorf("%s: example witness doesn't solve on %s (%v), skipping solve, prove and verify", name, curveID, err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	if err != nil {
+		return err
+	}
+
+	run("Solve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = groth16.IsSolved(r1cs, witness)
+		}
+	})
+	run("Prove", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = groth16.Prove(r1cs, pk, witness)
+		}
+	})
+	if a := accel.Get(); a != nil && curveID == ecc.BN254 {
+		run("ProveAccel", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = accel.Prove(r1cs, pk, vk, witness)
+			}
+		})
+	}
+	run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = groth16.Verify(proof, vk, witness)
+		}
+	})
+	return nil
+}
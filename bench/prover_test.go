@@ -0,0 +1,150 @@
+package bench
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	_ "github.com/gbotrel/gnark-workshop/circuits/all"
+)
+
+// the stages of the registered circuits on BN254, named as the bench command names them, so that
+// benchstat compares go test -bench and go run . bench runs:
+//
+//	go test -run xxx -bench 'Prove/mimc' -count 5 ./bench
+
+func BenchmarkCompile(b *testing.B) {
+	benchmarkStage(b, func(b *testing.B, s *stage) {
+		for i := 0; i < b.N; i++ {
+			_, _ = frontend.Compile(ecc.BN254, backend.GROTH16, s.circuit)
+		}
+	})
+}
+
+func BenchmarkSetup(b *testing.B) {
+	benchmarkStage(b, func(b *testing.B, s *stage) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = groth16.Setup(s.r1cs)
+		}
+	})
+}
+
+func BenchmarkSolve(b *testing.B) {
+	benchmarkStage(b, func(b *testing.B, s *stage) {
+		s.prove(b)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = groth16.IsSolved(s.r1cs, s.witness)
+		}
+	})
+}
+
+func BenchmarkProve(b *testing.B) {
+	benchmarkStage(b, func(b *testing.B, s *stage) {
+		s.prove(b)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = groth16.Prove(s.r1cs, s.pk, s.witness)
+		}
+	})
+}
+
+func BenchmarkVerify(b *testing.B) {
+	benchmarkStage(b, func(b *testing.B, s *stage) {
+		s.prove(b)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = groth16.Verify(s.proof, s.vk, s.witness)
+		}
+	})
+}
+
+// stage holds what the benchmark of a stage of a circuit starts from
+type stage struct {
+	name    string
+	circuit frontend.Circuit
+	r1cs    frontend.CompiledConstraintSystem
+	pk      groth16.ProvingKey
+	vk      groth16.VerifyingKey
+	witness frontend.Circuit
+	proof   groth16.Proof
+}
+
+// prove sets the example witness of the circuit and its proof, skipping the benchmark if the
+// circuit has no example
+func (s *stage) prove(b *testing.B) {
+	b.Helper()
+	witness, err := circuits.Example(s.name)
+	if err != nil {
+		b.Skip(err)
+	}
+	if s.pk, s.vk, err = groth16.Setup(s.r1cs); err != nil {
+		b.Fatal(err)
+	}
+	if s.proof, err = groth16.Prove(s.r1cs, s.pk, witness); err != nil {
+		b.Fatal(err)
+	}
+	s.witness = witness
+}
+
+// benchmarkStage runs f for each registered circuit, compiled, as the sub-benchmark <circuit>/bn254
+func benchmarkStage(b *testing.B, f func(b *testing.B, s *stage)) {
+	for _, name := range circuits.Names() {
+		name := name
+		b.Run(name+"/"+ecc.BN254.String(), func(b *testing.B) {
+			c, err := circuits.Get(name)
+			if err != nil {
+				b.Fatal(err)
+			}
+			r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, c)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			f(b, &stage{name: name, circuit: c, r1cs: r1cs})
+		})
+	}
+}
+
+func TestProver(t *testing.T) {
+	benchtime := flag.Lookup("test.benchtime")
+	saved := benchtime.Value.String()
+	if err := flag.Set("test.benchtime", "1x"); err != nil {
+		t.Fatal(err)
+	}
+	defer flag.Set("test.benchtime", saved)
+
+	var names []string
+	report := func(r Result) {
+		names = append(names, r.Name)
+		if r.N != 1 {
+			t.Errorf("%s: %d iterations with -benchtime 1x", r.Name, r.N)
+		}
+		if fields := strings.Split(r.String(), "\t"); fields[0] != r.Name || !strings.HasSuffix(r.String(), "allocs/op") {
+			t.Errorf("%q is not a go test -bench line", r.String())
+		}
+	}
+	if err := Prover("sum", ecc.BN254, NewPublicInputs(2), PublicInputsWitness(2), report); err != nil {
+		t.Fatal(err)
+	}
+	for i, stage := range []string{"Compile", "Setup", "Solve", "Prove", "Verify"} {
+		if i >= len(names) || !strings.HasPrefix(names[i], "Benchmark"+stage+"/sum/bn254-") {
+			t.Fatalf("got %v, expected the %s stage at index %d", names, stage, i)
+		}
+	}
+
+	// without a witness, only compile and setup are measured
+	names = nil
+	if err := Prover("sum", ecc.BN254, NewPublicInputs(2), nil, report); err == nil {
+		t.Fatal("no error without a witness")
+	}
+	if len(names) != 2 {
+		t.Fatalf("got %v, expected compile and setup", names)
+	}
+}
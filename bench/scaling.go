@@ -0,0 +1,47 @@
+package bench
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Scaling measures the proofs of the circuits of each size of sizes on BN254, as built by build,
+// calling report after each measure
+// Results are named BenchmarkScaling/<name>/n=<size>-<GOMAXPROCS>, and report the number of
+// constraints of the circuit as an extra metric, next to ns/op: plotting both against the size
+// shows how the circuit grows, and how the prover follows.
+func Scaling(name string, sizes []int, build func(size int) (c, witness frontend.Circuit, err error), report func(Result)) error {
+	for _, size := range sizes {
+		c, witness, err := build(size)
+		if err != nil {
+			return err
+		}
+		r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, c)
+		if err != nil {
+			return err
+		}
+		pk, _, err := groth16.Setup(r1cs)
+		if err != nil {
+			return err
+		}
+		if _, err := groth16.Prove(r1cs, pk, witness); err != nil {
+			return fmt.Errorf("%s n=%d: %w", name, size, err)
+		}
+
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = groth16.Prove(r1cs, pk, witness)
+			}
+			b.ReportMetric(float64(r1cs.GetNbConstraints()), "constraints")
+		})
+		report(Result{Name: fmt.Sprintf("BenchmarkScaling/%s/n=%d-%d", name, size, runtime.GOMAXPROCS(0)), BenchmarkResult: r})
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/prover"
+)
+
+// Throughput measures the proofs of c on BN254 with each number of concurrent proofs of workers,
+// on a prover.Pool as serve-prover runs them, calling report after each measure
+// An iteration is a proof: ns/op is the wall time per proof, its inverse the throughput. Results
+// are named BenchmarkThroughput/<circuit>/workers=<n>-<GOMAXPROCS>.
+func Throughput(name string, c, witness frontend.Circuit, workers []int, report func(Result)) error {
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, c)
+	if err != nil {
+		return err
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		return err
+	}
+	if _, err := groth16.Prove(r1cs, pk, witness); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	for _, n := range workers {
+		pool := prover.NewPool(r1cs, pk, vk, n, n)
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			next := make(chan struct{})
+			var wg sync.WaitGroup
+			for w := 0; w < n; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range next {
+						_, _ = pool.Prove(context.Background(), witness)
+					}
+				}()
+			}
+			for i := 0; i < b.N; i++ {
+				next <- struct{}{}
+			}
+			close(next)
+			wg.Wait()
+		})
+		pool.Close()
+		report(Result{Name: fmt.Sprintf("BenchmarkThroughput/%s/workers=%d-%d", name, n, runtime.GOMAXPROCS(0)), BenchmarkResult: r})
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gbotrel/gnark-workshop/bench"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runBenchGas measures the deployment and verifyProof gas of verifiers with varying numbers
+// of public inputs, on the simulated backend
+func runBenchGas(args []string) {
+	fs := flag.NewFlagSet("bench-gas", flag.ExitOnError)
+	fInputs := fs.String("inputs", "1,2,4,8,16", "comma separated numbers of public inputs")
+	fReport := fs.String("report", "", "also write the results to this file, as CSV if it ends with .csv, else as JSON")
+	assertNoError(fs.Parse(args))
+	if _, err := exec.LookPath(ethereum.Solc); err != nil {
+		log.Fatal("please install solc", err)
+	}
+
+	var counts []int
+	for _, s := range strings.Split(*fInputs, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 1 {
+			log.Fatalf("bench-gas: invalid number of public inputs %q", s)
+		}
+		counts = append(counts, n)
+	}
+
+	auth, chain, err := newBackend()
+	assertNoError(err)
+	results := make([]bench.Gas, 0, len(counts))
+	for _, n := range counts {
+		log.Printf("measuring verifier with %d public inputs", n)
+		gas, err := bench.MeasureGas(auth, chain, n)
+		assertNoError(err)
+		results = append(results, gas)
+	}
+
+	if *fReport != "" {
+		assertNoError(writeGasReport(*fReport, results))
+		log.Println("report written to", *fReport)
+	}
+	if jsonOutput() {
+		emit("bench-gas", results)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "public inputs\tdeploy gas\tverifyProof gas\tcalldata bytes\tbytecode bytes\t")
+	for _, r := range results {
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t\n", r.PublicInputs, r.Deploy, r.Verify, r.Calldata, r.Bytecode)
+	}
+	assertNoError(w.Flush())
+}
+
+func writeGasReport(fileName string, results []bench.Gas) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if filepath.Ext(fileName) != ".csv" {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+		return f.Close()
+	}
+
+	w := csv.NewWriter(f)
+	records := [][]string{{"publicInputs", "deploy", "verify", "calldata", "bytecode"}}
+	for _, r := range results {
+		records = append(records, []string{
+			strconv.Itoa(r.PublicInputs),
+			strconv.FormatUint(r.Deploy, 10),
+			strconv.FormatUint(r.Verify, 10),
+			strconv.Itoa(r.Calldata),
+			strconv.Itoa(r.Bytecode),
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+	return f.Close()
+}
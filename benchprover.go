@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/bench"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	hashchain "github.com/gbotrel/gnark-workshop/circuits/hash-chain"
+)
+
+// benchCurves are the curves the bench command accepts, by name
+var benchCurves = map[string]ecc.ID{
+	ecc.BN254.String():     ecc.BN254,
+	ecc.BLS12_381.String(): ecc.BLS12_381,
+	ecc.BLS12_377.String(): ecc.BLS12_377,
+	ecc.BW6_761.String():   ecc.BW6_761,
+}
+
+// runBench measures compile, setup, solve, prove and verify of the registered circuits, and
+// prints the results on stdout in the `go test -bench` format, so that runs can be compared with
+// benchstat:
+//
+//	go run . bench -count 5 > old.txt
+//	go run . bench -count 5 > new.txt
+//	benchstat old.txt new.txt
+//
+// -procs and -workers measure the scaling: the stages are run with each GOMAXPROCS of -procs (the
+// -N suffix of the benchmark names), and the throughput of each number of concurrent proofs of
+// -workers, on a prover.Pool as serve-prover runs them.
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	fCircuits := fs.String("circuits", strings.Join(circuits.Names(), ","), "comma separated registered circuits")
+	fCurves := fs.String("curves", ecc.BN254.String(), "comma separated curves (bn254, bls12_381, bls12_377, bw6_761)")
+	fBenchtime := fs.String("benchtime", "1s", "run each stage for this duration, or Nx times, as go test -benchtime")
+	fCount := fs.Int("count", 1, "run each benchmark this many times, for benchstat")
+	fProcs := fs.String("procs", "", "comma separated GOMAXPROCS values to run the benchmarks with, the current one if not set")
+	fWorkers := fs.String("workers", "", "comma separated numbers of concurrent proofs: also measure the proving throughput of BN254 (Throughput)")
+	fHashChain := fs.String("hash-chain", "", "comma separated iteration counts: measure how the hash-chain circuit and its proofs scale instead, and plot them")
+	assertNoError(fs.Parse(args))
+	procs := []int{runtime.GOMAXPROCS(0)}
+	if *fProcs != "" {
+		procs = parseInts(*fProcs, "GOMAXPROCS", runtime.NumCPU())
+	}
+	var workers []int
+	if *fWorkers != "" {
+		workers = parseInts(*fWorkers, "workers", 1024)
+	}
+
+	// testing.Benchmark reads its settings from the test flags
+	testing.Init()
+	assertNoError(flag.Set("test.benchtime", *fBenchtime))
+
+	var curves []ecc.ID
+	for _, name := range strings.Split(*fCurves, ",") {
+		curveID, ok := benchCurves[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			log.Fatalf("bench: unknown curve %q", name)
+		}
+		curves = append(curves, curveID)
+	}
+
+	fmt.Printf("goos: %s\ngoarch: %s\npkg: github.com/gbotrel/gnark-workshop\n", runtime.GOOS, runtime.GOARCH)
+	if *fHashChain != "" {
+		benchHashChain(*fHashChain, *fCount)
+		return
+	}
+	for i := 0; i < *fCount; i++ {
+		for _, p := range procs {
+			runtime.GOMAXPROCS(p)
+			for _, name := range strings.Split(*fCircuits, ",") {
+				name = strings.TrimSpace(name)
+				c, err := circuits.Get(name)
+				assertNoError(err)
+				// examples are built for bn254: they usually don't solve on the other curves
+				var witness frontend.Circuit
+				if w, err := circuits.Example(name); err == nil {
+					witness = w
+				}
+				for _, curveID := range curves {
+					err := bench.Prover(name, curveID, c, witness, func(r bench.Result) {
+						fmt.Println(r)
+					})
+					if err != nil {
+						log.Println(err)
+					}
+				}
+				if len(workers) != 0 && witness != nil {
+					err := bench.Throughput(name, c, witness, workers, func(r bench.Result) {
+						fmt.Println(r)
+					})
+					if err != nil {
+						log.Println(err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// parseInts parses a comma separated list of integers between 1 and limit, for the flag of what
+func parseInts(list, what string, limit int) []int {
+	var values []int
+	for _, s := range strings.Split(list, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || v < 1 || v > limit {
+			log.Fatalf("bench: invalid %s %q, expected 1 to %d", what, s, limit)
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// benchHashChain measures the hash-chain circuit with each of the comma separated iteration counts
+// (bench.Scaling), then plots its constraints and proving time against the iterations on stderr
+func benchHashChain(sizes string, count int) {
+	iterations := parseInts(sizes, "iterations", 1<<20)
+	var results []bench.Result
+	for i := 0; i < count; i++ {
+		err := bench.Scaling("hash-chain", iterations, func(n int) (frontend.Circuit, frontend.Circuit, error) {
+			witness, err := hashchain.NewWitness([]byte("secret"), n)
+			return hashchain.New(n), witness, err
+		}, func(r bench.Result) {
+			fmt.Println(r)
+			results = append(results, r)
+		})
+		assertNoError(err)
+	}
+
+	// the last run of each size
+	results = results[len(results)-len(iterations):]
+	var maxConstraints, maxNs float64
+	for _, r := range results {
+		maxConstraints = math.Max(maxConstraints, r.Extra["constraints"])
+		maxNs = math.Max(maxNs, float64(r.NsPerOp()))
+	}
+	const width = 40
+	bar := func(v, max float64) string {
+		return strings.Repeat("#", int(math.Round(v/max*width)))
+	}
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "iterations\tconstraints\t\tprove\t\t")
+	for i, r := range results {
+		prove := time.Duration(r.NsPerOp())
+		fmt.Fprintf(w, "%d\t%.0f\t%s\t%s\t%s\t\n", iterations[i], r.Extra["constraints"], bar(r.Extra["constraints"], maxConstraints),
+			prove.Round(time.Millisecond), bar(float64(prove), maxNs))
+	}
+	assertNoError(w.Flush())
+}
+
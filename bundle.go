@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// bundleVerifierBin is the verifier creation bytecode in a bundle directory, next to the store files
+const bundleVerifierBin = "verifier.bin"
+
+// runBundle copies the current setup of the circuits given as arguments (the selected circuit if
+// none is) into a bundle directory, as read by prover.FSLoader: the keys of the proverd image, and
+// the artifacts embedded in binaries built with -tags embedbundle
+func runBundle(args []string) {
+	fs := flag.NewFlagSet("bundle", flag.ExitOnError)
+	fOut := fs.String("out", filepath.Join("proverd", "bundle"), "bundle directory")
+	fAll := fs.Bool("all", false, "bundle every registered circuit")
+	assertNoError(fs.Parse(args))
+
+	names := fs.Args()
+	if *fAll {
+		names = circuits.Names()
+	} else if len(names) == 0 {
+		names = []string{*fCircuit}
+	}
+
+	for _, name := range names {
+		_, err := circuits.Get(name)
+		assertNoError(err)
+		cf := filesOf(name)
+		manifest, err := artifacts.ReadManifest(cf.manifest)
+		assertNoError(err)
+		assertNoError(checkSetup(name, manifest, cf))
+
+		dir := filepath.Join(*fOut, name)
+		assertNoError(os.MkdirAll(dir, 0755))
+		for src, dst := range map[string]string{
+			cf.r1cs:        artifacts.R1CSFile,
+			cf.pk:          artifacts.PKFile,
+			cf.vk:          artifacts.VKFile,
+			cf.manifest:    artifacts.ManifestFile,
+			cf.verifierBin: bundleVerifierBin,
+		} {
+			if src == cf.manifest && manifest.Circuit == "" {
+				continue // set up before manifests existed
+			}
+			if err := copyFile(src, filepath.Join(dir, dst)); err != nil {
+				log.Fatalf("%v (run -init -circuit %s)", err, name)
+			}
+		}
+		log.Printf("%s bundled in %s", name, dir)
+	}
+}
+
+// hasEmbedded returns true if the binary embeds the artifacts of the circuit registered under name
+func hasEmbedded(name string) bool {
+	if embedded == nil {
+		return false
+	}
+	_, err := fs.Stat(embedded, path.Join(name, artifacts.R1CSFile))
+	return err == nil
+}
+
+// verifierBytecode returns the creation bytecode of the selected circuit verifier, embedded or
+// written by -init
+func verifierBytecode() ([]byte, error) {
+	if hasEmbedded(*fCircuit) {
+		data, err := fs.ReadFile(embedded, path.Join(*fCircuit, bundleVerifierBin))
+		if err != nil {
+			return nil, err
+		}
+		return ethereum.DecodeBytecode(data)
+	}
+	return ethereum.ReadBytecode(files.verifierBin)
+}
+
+// copyFile copies src, or its chunks, as stored: encrypted keys stay encrypted, as the manifest hashes them
+func copyFile(src, dst string) error {
+	in, err := artifacts.OpenRaw(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
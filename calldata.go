@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runCalldata prints the hex encoded verifyProof calldata of a serialized proof
+// usable with `cast call <verifier address> <calldata>` or ethers
+func runCalldata(args []string) {
+	fs := flag.NewFlagSet("calldata", flag.ExitOnError)
+	fVK := fs.String("vk", files.vk, "verifying key file, giving the expected number of public inputs")
+	fProof := fs.String("proof", files.proof, "proof file")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	assertNoError(fs.Parse(args))
+
+	proof := groth16.NewProof(ecc.BN254)
+	deserialize(proof, *fProof)
+
+	publicWitness := readPublicWitness(*fPublic, *fVK)
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	assertNoError(err)
+	if !jsonOutput() {
+		assertNoError(solidityInputs.ExportCalldata(os.Stdout))
+		return
+	}
+
+	calldata, err := solidityInputs.Calldata()
+	assertNoError(err)
+	result := calldataResult{Calldata: hexutil.Encode(calldata), PublicInputsHex: ethereum.PublicWitnessHex(publicWitness)}
+	for _, input := range solidityInputs.Input {
+		result.PublicInputs = append(result.PublicInputs, input.String())
+	}
+	emit("calldata", result)
+}
+
+// calldataResult is the JSON output of calldata; public inputs are decimal strings, and
+// 32 bytes big endian hex words in publicInputsHex
+type calldataResult struct {
+	Calldata        string   `json:"calldata"`
+	PublicInputs    []string `json:"publicInputs"`
+	PublicInputsHex []string `json:"publicInputsHex"`
+}
+
+// readPublicWitness reads a binary public witness (as written by witness.WritePublicTo)
+// and checks it has as many public inputs as the verifying key in vkFile expects
+func readPublicWitness(fileName, vkFile string) []fr.Element {
+	data, err := ioutil.ReadFile(fileName)
+	assertNoError(err)
+	publicWitness, err := ethereum.DecodePublicWitness(data)
+	assertNoError(err)
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, vkFile)
+	assertNoError(ethereum.CheckPublicWitness(vk, publicWitness))
+
+	return publicWitness
+}
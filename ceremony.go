@@ -0,0 +1,198 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/ceremony"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/workshop"
+)
+
+// runCeremony runs a step of the phase-2 ceremony of the selected circuit, see package ceremony
+//
+//	ceremony init                 compile and setup, the coordinator's keys are contribution 0
+//	ceremony contribute -name     re-randomize δ on top of the last contribution
+//	ceremony contribute -beacon   re-randomize δ with a public random beacon, last
+//	ceremony verify               check the chain of contributions
+//	ceremony finalize             verify, then store the last keys as the circuit's current setup
+func runCeremony(args []string) {
+	fs := flag.NewFlagSet("ceremony", flag.ExitOnError)
+	fDir := fs.String("dir", ceremonyDir(*fCircuit), "directory of the contribution files")
+	fName := fs.String("name", "", "contributor name, recorded in the contribution (contribute)")
+	fBeaconValue := fs.String("beacon", "", "public random beacon (a block hash, the randomness of a drand round) to contribute with instead of a secret, picked before the last contribution (contribute)")
+	if len(args) == 0 {
+		log.Fatal("usage: ceremony init|contribute|verify|finalize [-dir dir] [-name name] [-beacon value]")
+	}
+	step := args[0]
+	assertNoError(fs.Parse(args[1:]))
+	dir := *fDir
+
+	switch step {
+	case "init":
+		ceremonyInit(dir)
+	case "contribute":
+		if *fBeaconValue != "" && *fName == "" {
+			*fName = "beacon"
+		}
+		if *fName == "" {
+			log.Fatal("ceremony contribute: -name is required")
+		}
+		ceremonyContribute(dir, *fName, *fBeaconValue)
+	case "verify":
+		contributions := ceremonyVerify(dir)
+		if jsonOutput() {
+			emit("ceremony-verify", contributions)
+			return
+		}
+		for _, c := range contributions {
+			fmt.Printf("%04d  %-20s vk %s\n", c.Index, c.Contributor, c.VK)
+		}
+		fmt.Printf("%d contribution(s) verified\n", len(contributions))
+	case "finalize":
+		if _, err := exec.LookPath(ethereum.Solc); err != nil {
+			log.Fatal("please install solc", err)
+		}
+		defer report.print()
+		contributions := ceremonyVerify(dir)
+		if len(contributions) == 0 {
+			log.Fatal("ceremony finalize: no contribution, the keys are the coordinator's setup")
+		}
+		circuit, err := circuits.Get(*fCircuit)
+		assertNoError(err)
+		r1cs := groth16.NewCS(ecc.BN254)
+		deserialize(r1cs, filepath.Join(dir, "circuit.r1cs"))
+		pk, vk := readCeremonyKeys(dir, len(contributions))
+		var beacon *artifacts.Beacon
+		if last := contributions[len(contributions)-1]; last.Beacon != "" {
+			beacon = beaconOf(&last)
+		} else {
+			log.Println("the last contribution is not a beacon's: the last contributor could bias the keys")
+		}
+		log.Printf("finalizing %s with %d contribution(s)", *fCircuit, len(contributions))
+		emit("ceremony-finalize", saveSetup(circuit, r1cs, pk, vk, beacon, false))
+	default:
+		log.Fatalf("unknown ceremony step %q (expected init, contribute, verify or finalize)", step)
+	}
+}
+
+// ceremonyInit compiles the circuit and runs groth16.Setup, whose keys are contribution 0
+func ceremonyInit(dir string) {
+	if _, err := os.Stat(ceremonyFile(dir, 0, "vk")); err == nil {
+		log.Fatalf("a ceremony already exists in %s", dir)
+	}
+	assertNoError(os.MkdirAll(dir, 0755))
+
+	circuit, err := circuits.Get(*fCircuit)
+	assertNoError(err)
+	log.Println("compiling circuit", *fCircuit)
+	var r1cs frontend.CompiledConstraintSystem
+	assertCompleted(workshop.Run(mainCtx, func() (err error) {
+		r1cs, err = frontend.Compile(ecc.BN254, backend.GROTH16, circuit)
+		return
+	}))
+	log.Println("running groth16.Setup")
+	var (
+		pk groth16.ProvingKey
+		vk groth16.VerifyingKey
+	)
+	assertCompleted(workshop.Run(mainCtx, func() (err error) {
+		pk, vk, err = groth16.Setup(r1cs)
+		return
+	}))
+
+	serialize(r1cs, filepath.Join(dir, "circuit.r1cs"))
+	serialize(pk, ceremonyFile(dir, 0, "pk"))
+	serialize(vk, ceremonyFile(dir, 0, "vk"))
+	log.Println("ceremony initialized in", dir)
+}
+
+// ceremonyContribute adds a contribution on top of the last one, derived from beacon if set
+// The previous contributions are not verified: contributors should run ceremony verify first.
+func ceremonyContribute(dir, name, beacon string) {
+	last := lastContribution(dir)
+	pk, vk := readCeremonyKeys(dir, last)
+
+	log.Println("contributing on top of contribution", last)
+	var (
+		c   *ceremony.Contribution
+		err error
+	)
+	if beacon != "" {
+		vk, c, err = ceremony.ContributeBeacon(pk, vk, beacon)
+	} else {
+		vk, c, err = ceremony.Contribute(pk, vk, rand.Reader)
+	}
+	assertNoError(err)
+	c.Index, c.Contributor = last+1, name
+
+	serialize(pk, ceremonyFile(dir, c.Index, "pk"))
+	serialize(vk, ceremonyFile(dir, c.Index, "vk"))
+	data, err := json.MarshalIndent(c, "", "\t")
+	assertNoError(err)
+	assertNoError(ioutil.WriteFile(ceremonyFile(dir, c.Index, "json"), append(data, '\n'), 0644))
+
+	log.Printf("contribution %d written to %s", c.Index, dir)
+	emit("ceremony-contribute", c)
+}
+
+// ceremonyVerify checks every contribution against the keys before and after it
+func ceremonyVerify(dir string) []ceremony.Contribution {
+	last := lastContribution(dir)
+	contributions := make([]ceremony.Contribution, 0, last)
+	prevPK, prevVK := readCeremonyKeys(dir, 0)
+	for i := 1; i <= last; i++ {
+		data, err := ioutil.ReadFile(ceremonyFile(dir, i, "json"))
+		assertNoError(err)
+		var c ceremony.Contribution
+		assertNoError(json.Unmarshal(data, &c))
+		if c.Index != i {
+			log.Fatalf("%s: contribution index %d, expected %d", ceremonyFile(dir, i, "json"), c.Index, i)
+		}
+		pk, vk := readCeremonyKeys(dir, i)
+		if err := ceremony.Verify(prevPK, prevVK, pk, vk, &c); err != nil {
+			log.Fatal(err)
+		}
+		contributions = append(contributions, c)
+		prevPK, prevVK = pk, vk
+	}
+	return contributions
+}
+
+// lastContribution returns the index of the last contribution in dir, 0 if there is none
+func lastContribution(dir string) int {
+	if _, err := os.Stat(ceremonyFile(dir, 0, "vk")); err != nil {
+		log.Fatalf("no ceremony in %s, run ceremony init first", dir)
+	}
+	last := 0
+	for {
+		if _, err := os.Stat(ceremonyFile(dir, last+1, "json")); err != nil {
+			return last
+		}
+		last++
+	}
+}
+
+func readCeremonyKeys(dir string, i int) (groth16.ProvingKey, groth16.VerifyingKey) {
+	pk, vk := groth16.NewProvingKey(ecc.BN254), groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(pk, ceremonyFile(dir, i, "pk"))
+	deserialize(vk, ceremonyFile(dir, i, "vk"))
+	return pk, vk
+}
+
+func ceremonyFile(dir string, i int, ext string) string {
+	return filepath.Join(dir, fmt.Sprintf("%04d.%s", i, ext))
+}
@@ -0,0 +1,59 @@
+package ceremony
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// beaconDomain separates the secrets derived from a beacon from other hashes of the same value
+const beaconDomain = "gnark-workshop ceremony beacon:"
+
+// ContributeBeacon is Contribute with x derived from a public random beacon, a block hash or a drand
+// round picked in advance: x is known to everyone, so it adds no secret, but nobody could choose the
+// previous keys knowing it. Made last, it keeps the final keys from being biased by the last
+// contributor. The beacon is recorded in the contribution, see CheckBeacon.
+func ContributeBeacon(pk groth16.ProvingKey, vk groth16.VerifyingKey, beacon string) (groth16.VerifyingKey, *Contribution, error) {
+	if beacon == "" {
+		return nil, nil, fmt.Errorf("ceremony: empty beacon")
+	}
+	vk, c, err := Contribute(pk, vk, beaconReader(beacon))
+	if err != nil {
+		return nil, nil, err
+	}
+	c.Beacon = beacon
+	return vk, c, nil
+}
+
+// CheckBeacon returns ErrInvalidContribution if x1, the hex [x]₁ of a contribution, is not derived
+// from beacon
+func CheckBeacon(beacon, x1 string) error {
+	var x fr.Element
+	if err := setRandom(&x, beaconReader(beacon)); err != nil {
+		return err
+	}
+	var bx big.Int
+	x.ToBigIntRegular(&bx)
+	_, _, g1, _ := bn254.Generators()
+	var expected bn254.G1Affine
+	expected.ScalarMultiplication(&g1, &bx)
+	b := expected.Bytes()
+	if x1 != hex.EncodeToString(b[:]) {
+		return fmt.Errorf("%w: [x]₁ is not derived from the beacon %q", ErrInvalidContribution, beacon)
+	}
+	return nil
+}
+
+// beaconReader returns the bytes x is read from: sha512(beaconDomain || beacon), more than the 48
+// bytes setRandom reduces
+func beaconReader(beacon string) io.Reader {
+	h := sha512.Sum512([]byte(beaconDomain + beacon))
+	return bytes.NewReader(h[:])
+}
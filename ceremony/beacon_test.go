@@ -0,0 +1,117 @@
+package ceremony
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// cubeCircuit proves the knowledge of the cube root of a public Y
+type cubeCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubeCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+func cubeWitness() *cubeCircuit {
+	var w cubeCircuit
+	w.X.Assign(3)
+	w.Y.Assign(27)
+	return &w
+}
+
+// setup returns the R1CS and two copies of the keys of cubeCircuit
+func setup(t *testing.T) (frontend.CompiledConstraintSystem, [2]groth16.ProvingKey, [2]groth16.VerifyingKey) {
+	t.Helper()
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &cubeCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pks := [2]groth16.ProvingKey{pk, groth16.NewProvingKey(ecc.BN254)}
+	vks := [2]groth16.VerifyingKey{vk, groth16.NewVerifyingKey(ecc.BN254)}
+	var buf bytes.Buffer
+	if _, err := pk.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pks[1].ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := vk.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vks[1].ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return r1cs, pks, vks
+}
+
+func TestContributeBeacon(t *testing.T) {
+	const beacon = "drand round 1"
+	r1cs, pks, vks := setup(t)
+
+	vk, c, err := ContributeBeacon(pks[1], vks[1], beacon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Beacon != beacon {
+		t.Fatalf("contribution records the beacon %q, expected %q", c.Beacon, beacon)
+	}
+	if err := Verify(pks[0], vks[0], pks[1], vk, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckBeacon(beacon, c.X1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the updated keys prove and verify
+	proof, err := groth16.Prove(r1cs, pks[1], cubeWitness())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, cubeWitness()); err != nil {
+		t.Fatal(err)
+	}
+
+	// anyone mixing the same beacon into the same keys gets the same contribution
+	_, again, err := ContributeBeacon(pks[0], vks[0], beacon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.X1 != c.X1 || again.VK != c.VK {
+		t.Fatal("the same beacon gave two different contributions")
+	}
+}
+
+func TestCheckBeacon(t *testing.T) {
+	_, pks, vks := setup(t)
+	vk, c, err := ContributeBeacon(pks[1], vks[1], "round 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckBeacon("round 2", c.X1); !errors.Is(err, ErrInvalidContribution) {
+		t.Fatalf("another beacon: got %v, expected ErrInvalidContribution", err)
+	}
+	// a contribution of a secret x claiming to be a beacon's
+	c.Beacon = "round 2"
+	if err := Verify(pks[0], vks[0], pks[1], vk, c); !errors.Is(err, ErrInvalidContribution) {
+		t.Fatalf("relabeled contribution: got %v, expected ErrInvalidContribution", err)
+	}
+	if _, _, err := ContributeBeacon(pks[1], vk, ""); err == nil {
+		t.Fatal("contributed an empty beacon")
+	}
+}
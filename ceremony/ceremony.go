@@ -0,0 +1,247 @@
+// Package ceremony implements a groth16 phase-2 contribution flow on BN254 keys: each
+// contributor multiplies δ by a secret x they discard, so that the keys are sound as long as
+// one contributor was honest about δ.
+//
+// This is a workshop simplification of the BGM17 phase 2: phase 1 (τ, α, β) is the coordinator's
+// groth16.Setup, whose randomness must be trusted; and contributions are bound to x by [x]₁ and
+// [x]₂ only, without a proof of knowledge hashed from the transcript.
+package ceremony
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// ErrInvalidContribution is returned when a contribution doesn't match the keys it produced
+var ErrInvalidContribution = errors.New("ceremony: invalid contribution")
+
+// Contribution is the public record of one contribution
+type Contribution struct {
+	Index       int    `json:"index"`
+	Contributor string `json:"contributor"`
+	// X1 = [x]₁ and X2 = [x]₂, compressed and hex encoded
+	X1 string `json:"x1"`
+	X2 string `json:"x2"`
+	// Delta1 is the new [δ]₁, compressed and hex encoded
+	Delta1 string `json:"delta1"`
+	// PrevVK and VK are the sha256 of the verifying keys before and after the contribution
+	PrevVK string `json:"prevVk"`
+	VK     string `json:"vk"`
+	// Beacon is the public beacon x was derived from, for a beacon contribution (see ContributeBeacon)
+	Beacon string `json:"beacon,omitempty"`
+}
+
+// Contribute updates pk and vk in place with a fresh secret read from rand, and returns the
+// contribution record and the updated verifying key (to be used instead of vk)
+// The secret never leaves this function.
+func Contribute(pk groth16.ProvingKey, vk groth16.VerifyingKey, rand io.Reader) (groth16.VerifyingKey, *Contribution, error) {
+	d, err := deltaOf(pk, vk)
+	if err != nil {
+		return nil, nil, err
+	}
+	prevVK, err := hashOf(vk)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var x fr.Element
+	if err := setRandom(&x, rand); err != nil {
+		return nil, nil, err
+	}
+	var xInv fr.Element
+	xInv.Inverse(&x)
+	var bx, bxInv big.Int
+	x.ToBigIntRegular(&bx)
+	xInv.ToBigIntRegular(&bxInv)
+
+	// δ' = δ·x, and the queries divided by δ are divided by x
+	d.pkDelta1.ScalarMultiplication(d.pkDelta1, &bx)
+	d.pkDelta2.ScalarMultiplication(d.pkDelta2, &bx)
+	d.vkDelta1.ScalarMultiplication(d.vkDelta1, &bx)
+	d.vkDelta2.ScalarMultiplication(d.vkDelta2, &bx)
+	for _, query := range []*[]bn254.G1Affine{d.z, d.k} {
+		for i := range *query {
+			(*query)[i].ScalarMultiplication(&(*query)[i], &bxInv)
+		}
+	}
+
+	_, _, g1, g2 := bn254.Generators()
+	var x1 bn254.G1Affine
+	var x2 bn254.G2Affine
+	x1.ScalarMultiplication(&g1, &bx)
+	x2.ScalarMultiplication(&g2, &bx)
+	x.SetZero()
+	bx.SetUint64(0)
+
+	vk, err = refresh(vk)
+	if err != nil {
+		return nil, nil, err
+	}
+	newVK, err := hashOf(vk)
+	if err != nil {
+		return nil, nil, err
+	}
+	x1Bytes, x2Bytes, delta1Bytes := x1.Bytes(), x2.Bytes(), d.vkDelta1.Bytes()
+	return vk, &Contribution{
+		X1:     hex.EncodeToString(x1Bytes[:]),
+		X2:     hex.EncodeToString(x2Bytes[:]),
+		Delta1: hex.EncodeToString(delta1Bytes[:]),
+		PrevVK: prevVK,
+		VK:     newVK,
+	}, nil
+}
+
+// Verify checks that (pk, vk) is (prevPK, prevVK) updated by the contribution c
+// With the BN254 pairing e:
+//
+//	e([x]₁, [1]₂) = e([1]₁, [x]₂)                      x1 and x2 share x
+//	e([δ']₁, [1]₂) = e([δ]₁, [x]₂)                      δ' = δ·x
+//	e([1]₁, [δ']₂) = e([δ']₁, [1]₂)                     in G1 and G2, in pk and vk
+//	e(Σρᵢ[qᵢ']₁, [δ']₂) = e(Σρᵢ[qᵢ]₁, [δ]₂)              the queries are divided by x
+//
+// the last check batches every element of the Z and K queries with random ρᵢ. Every element that
+// doesn't depend on δ (α, β, γ, the A and B queries, the K of the verifying key and the domain)
+// must be byte-equal to the previous one. For a beacon contribution, [x]₁ is also checked to be
+// derived from the beacon.
+func Verify(prevPK groth16.ProvingKey, prevVK groth16.VerifyingKey, pk groth16.ProvingKey, vk groth16.VerifyingKey, c *Contribution) error {
+	prev, err := deltaOf(prevPK, prevVK)
+	if err != nil {
+		return err
+	}
+	next, err := deltaOf(pk, vk)
+	if err != nil {
+		return err
+	}
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w %d (%s): %s", ErrInvalidContribution, c.Index, c.Contributor, reason)
+	}
+
+	prevFixed, err := fixedOf(prevPK, prevVK)
+	if err != nil {
+		return err
+	}
+	nextFixed, err := fixedOf(pk, vk)
+	if err != nil {
+		return err
+	}
+
+	if h, err := hashOf(prevVK); err != nil || h != c.PrevVK {
+		return invalid("previous verifying key mismatch")
+	}
+	if h, err := hashOf(vk); err != nil || h != c.VK {
+		return invalid("verifying key mismatch")
+	}
+	if !bytes.Equal(prevFixed, nextFixed) {
+		return invalid("an element that doesn't depend on δ changed")
+	}
+	if c.Beacon != "" {
+		if err := CheckBeacon(c.Beacon, c.X1); err != nil {
+			return invalid("x is not derived from the beacon")
+		}
+	}
+	var x1, delta1 bn254.G1Affine
+	var x2 bn254.G2Affine
+	if err := setHex(&x1, c.X1); err != nil {
+		return invalid("x1: " + err.Error())
+	}
+	if err := setHex(&x2, c.X2); err != nil {
+		return invalid("x2: " + err.Error())
+	}
+	if err := setHex(&delta1, c.Delta1); err != nil {
+		return invalid("delta1: " + err.Error())
+	}
+	if !delta1.Equal(next.vkDelta1) || !next.pkDelta1.Equal(next.vkDelta1) || !next.pkDelta2.Equal(next.vkDelta2) {
+		return invalid("δ differs between the keys and the record")
+	}
+	if x1.IsInfinity() {
+		return invalid("x is zero")
+	}
+	if len(*prev.z) != len(*next.z) || len(*prev.k) != len(*next.k) {
+		return invalid("query sizes changed")
+	}
+
+	_, _, g1, g2 := bn254.Generators()
+	if ok, err := pairingEqual(x1, g2, g1, x2); err != nil || !ok {
+		return invalid("[x]₁ and [x]₂ don't match")
+	}
+	if ok, err := pairingEqual(*next.vkDelta1, g2, *prev.vkDelta1, x2); err != nil || !ok {
+		return invalid("δ was not multiplied by x")
+	}
+	if ok, err := pairingEqual(g1, *next.vkDelta2, *next.vkDelta1, g2); err != nil || !ok {
+		return invalid("[δ]₁ and [δ]₂ don't match")
+	}
+
+	var prevSum, nextSum bn254.G1Jac
+	for _, q := range []struct{ prev, next []bn254.G1Affine }{{*prev.z, *next.z}, {*prev.k, *next.k}} {
+		for i := range q.prev {
+			var rho fr.Element
+			if _, err := rho.SetRandom(); err != nil {
+				return err
+			}
+			var bRho big.Int
+			rho.ToBigIntRegular(&bRho)
+			var p bn254.G1Affine
+			p.ScalarMultiplication(&q.prev[i], &bRho)
+			prevSum.AddMixed(&p)
+			p.ScalarMultiplication(&q.next[i], &bRho)
+			nextSum.AddMixed(&p)
+		}
+	}
+	var prevAff, nextAff bn254.G1Affine
+	prevAff.FromJacobian(&prevSum)
+	nextAff.FromJacobian(&nextSum)
+	if ok, err := pairingEqual(nextAff, *next.vkDelta2, prevAff, *prev.vkDelta2); err != nil || !ok {
+		return invalid("the Z and K queries were not divided by x")
+	}
+	return nil
+}
+
+// pairingEqual returns e(a, b) == e(c, d)
+func pairingEqual(a bn254.G1Affine, b bn254.G2Affine, c bn254.G1Affine, d bn254.G2Affine) (bool, error) {
+	var cNeg bn254.G1Affine
+	cNeg.Neg(&c)
+	return bn254.PairingCheck([]bn254.G1Affine{a, cNeg}, []bn254.G2Affine{b, d})
+}
+
+func hashOf(vk groth16.VerifyingKey) (string, error) {
+	h := sha256.New()
+	if _, err := vk.WriteTo(h); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type point interface {
+	SetBytes([]byte) (int, error)
+}
+
+func setHex(p point, s string) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	_, err = p.SetBytes(b)
+	return err
+}
+
+// setRandom sets x to a non zero element read from rand
+func setRandom(x *fr.Element, rand io.Reader) error {
+	var b [fr.Bytes + 16]byte
+	for x.IsZero() {
+		if _, err := io.ReadFull(rand, b[:]); err != nil {
+			return err
+		}
+		// reduce 48 random bytes, the bias is negligible
+		x.SetBigInt(new(big.Int).SetBytes(b[:]))
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package ceremony
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+func TestContribute(t *testing.T) {
+	r1cs, pks, vks := setup(t)
+	vk, c, err := Contribute(pks[1], vks[1], rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(pks[0], vks[0], pks[1], vk, c); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pks[1], cubeWitness())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, cubeWitness()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestVerifyTampered contributes, then changes one element of the keys the way a malicious
+// contributor would, recording the hash of the tampered verifying key: Verify must refuse it
+func TestVerifyTampered(t *testing.T) {
+	var two big.Int
+	two.SetUint64(2)
+	// double1 doubles the element at path, the first finite one of a query
+	double1 := func(key interface{}, path ...string) func(t *testing.T) {
+		return func(t *testing.T) {
+			v, err := field(key, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch p := v.Addr().Interface().(type) {
+			case *bn254.G1Affine:
+				p.ScalarMultiplication(p, &two)
+			case *[]bn254.G1Affine:
+				i := 0
+				for (*p)[i].IsInfinity() {
+					i++
+				}
+				(*p)[i].ScalarMultiplication(&(*p)[i], &two)
+			case *bn254.G2Affine:
+				p.ScalarMultiplication(p, &two)
+			case *[]bn254.G2Affine:
+				i := 0
+				for (*p)[i].IsInfinity() {
+					i++
+				}
+				(*p)[i].ScalarMultiplication(&(*p)[i], &two)
+			default:
+				t.Fatalf("%v is a %T", path, p)
+			}
+		}
+	}
+
+	for name, tamper := range map[string]func(pk groth16.ProvingKey, vk groth16.VerifyingKey) func(t *testing.T){
+		"γ": func(_ groth16.ProvingKey, vk groth16.VerifyingKey) func(t *testing.T) {
+			return double1(vk, "G2", "Gamma")
+		},
+		"vk K": func(_ groth16.ProvingKey, vk groth16.VerifyingKey) func(t *testing.T) {
+			return double1(vk, "G1", "K")
+		},
+		"α": func(pk groth16.ProvingKey, vk groth16.VerifyingKey) func(t *testing.T) {
+			return func(t *testing.T) {
+				double1(pk, "G1", "Alpha")(t)
+				double1(vk, "G1", "Alpha")(t)
+			}
+		},
+		"β": func(pk groth16.ProvingKey, vk groth16.VerifyingKey) func(t *testing.T) {
+			return func(t *testing.T) {
+				double1(pk, "G2", "Beta")(t)
+				double1(vk, "G2", "Beta")(t)
+			}
+		},
+		"A query": func(pk groth16.ProvingKey, _ groth16.VerifyingKey) func(t *testing.T) {
+			return double1(pk, "G1", "A")
+		},
+		"B query": func(pk groth16.ProvingKey, _ groth16.VerifyingKey) func(t *testing.T) {
+			return double1(pk, "G1", "B")
+		},
+		"G2 B query": func(pk groth16.ProvingKey, _ groth16.VerifyingKey) func(t *testing.T) {
+			return double1(pk, "G2", "B")
+		},
+		// δ dependent elements, which the pairing checks cover
+		"Z query": func(pk groth16.ProvingKey, _ groth16.VerifyingKey) func(t *testing.T) {
+			return double1(pk, "G1", "Z")
+		},
+		"pk K": func(pk groth16.ProvingKey, _ groth16.VerifyingKey) func(t *testing.T) {
+			return double1(pk, "G1", "K")
+		},
+		"δ": func(pk groth16.ProvingKey, vk groth16.VerifyingKey) func(t *testing.T) {
+			return func(t *testing.T) {
+				double1(pk, "G1", "Delta")(t)
+				double1(pk, "G2", "Delta")(t)
+				double1(vk, "G1", "Delta")(t)
+				double1(vk, "G2", "Delta")(t)
+			}
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, pks, vks := setup(t)
+			vk, c, err := Contribute(pks[1], vks[1], rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tamper(pks[1], vk)(t)
+			if vk, err = refresh(vk); err != nil {
+				t.Fatal(err)
+			}
+			if c.VK, err = hashOf(vk); err != nil {
+				t.Fatal(err)
+			}
+			d, err := deltaOf(pks[1], vk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			delta1 := d.vkDelta1.Bytes()
+			c.Delta1 = hex.EncodeToString(delta1[:])
+
+			if err := Verify(pks[0], vks[0], pks[1], vk, c); !errors.Is(err, ErrInvalidContribution) {
+				t.Fatalf("got %v, expected ErrInvalidContribution", err)
+			}
+		})
+	}
+}
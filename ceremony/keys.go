@@ -0,0 +1,133 @@
+package ceremony
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// delta are the δ dependent elements of a BN254 groth16 key pair, pointing into the keys
+// gnark's key types are internal: their (exported) fields are reached by reflection.
+type delta struct {
+	pkDelta1 *bn254.G1Affine
+	pkDelta2 *bn254.G2Affine
+	vkDelta1 *bn254.G1Affine
+	vkDelta2 *bn254.G2Affine
+	// [t(τ)τ^i/δ]₁ and [(βA_j(τ)+αB_j(τ)+C_j(τ))/δ]₁ of the private wires
+	z, k *[]bn254.G1Affine
+}
+
+func deltaOf(pk groth16.ProvingKey, vk groth16.VerifyingKey) (*delta, error) {
+	var d delta
+	for _, f := range []struct {
+		dst  interface{}
+		key  interface{}
+		path []string
+	}{
+		{&d.pkDelta1, pk, []string{"G1", "Delta"}},
+		{&d.pkDelta2, pk, []string{"G2", "Delta"}},
+		{&d.vkDelta1, vk, []string{"G1", "Delta"}},
+		{&d.vkDelta2, vk, []string{"G2", "Delta"}},
+		{&d.z, pk, []string{"G1", "Z"}},
+		{&d.k, pk, []string{"G1", "K"}},
+	} {
+		v, err := field(f.key, f.path)
+		if err != nil {
+			return nil, err
+		}
+		ptr := reflect.ValueOf(f.dst).Elem()
+		if v.Addr().Type() != ptr.Type() {
+			return nil, fmt.Errorf("ceremony: unsupported key layout (%s is a %s)", strings.Join(f.path, "."), v.Type())
+		}
+		ptr.Set(v.Addr())
+	}
+	return &d, nil
+}
+
+// fixedOf returns the encoding of the elements of a BN254 groth16 key pair that don't depend on
+// δ: a contribution must leave them unchanged
+func fixedOf(pk groth16.ProvingKey, vk groth16.VerifyingKey) ([]byte, error) {
+	var buf bytes.Buffer
+	domain, err := field(pk, []string{"Domain"})
+	if err != nil {
+		return nil, err
+	}
+	writer, ok := domain.Addr().Interface().(io.WriterTo)
+	if !ok {
+		return nil, errors.New("ceremony: unsupported key layout (Domain)")
+	}
+	if _, err := writer.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	enc := bn254.NewEncoder(&buf, bn254.RawEncoding())
+	for _, f := range []struct {
+		key  interface{}
+		path []string
+	}{
+		{pk, []string{"G1", "Alpha"}},
+		{pk, []string{"G1", "Beta"}},
+		{pk, []string{"G1", "A"}},
+		{pk, []string{"G1", "B"}},
+		{pk, []string{"G2", "Beta"}},
+		{pk, []string{"G2", "B"}},
+		{vk, []string{"G1", "Alpha"}},
+		{vk, []string{"G1", "Beta"}},
+		{vk, []string{"G1", "K"}},
+		{vk, []string{"G2", "Beta"}},
+		{vk, []string{"G2", "Gamma"}},
+	} {
+		v, err := field(f.key, f.path)
+		if err != nil {
+			return nil, err
+		}
+		if v.Kind() != reflect.Slice {
+			v = v.Addr()
+		}
+		if err := enc.Encode(v.Interface()); err != nil {
+			return nil, fmt.Errorf("ceremony: unsupported key layout (%s): %w", strings.Join(f.path, "."), err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// field returns the addressable field of key at path
+func field(key interface{}, path []string) (reflect.Value, error) {
+	v := reflect.ValueOf(key)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	for _, name := range path {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("ceremony: unsupported key layout (%s)", strings.Join(path, "."))
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return reflect.Value{}, fmt.Errorf("ceremony: unsupported key layout (no %s)", strings.Join(path, "."))
+		}
+	}
+	if !v.CanAddr() {
+		return reflect.Value{}, fmt.Errorf("ceremony: unsupported key layout (%s)", strings.Join(path, "."))
+	}
+	return v, nil
+}
+
+// refresh returns a copy of vk read back from its serialization, so that gnark recomputes the
+// values it derives from δ (they are unexported, and not reachable by reflection)
+func refresh(vk groth16.VerifyingKey) (groth16.VerifyingKey, error) {
+	var buf bytes.Buffer
+	if _, err := vk.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	fresh := groth16.NewVerifyingKey(ecc.BN254)
+	if _, err := fresh.ReadFrom(&buf); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
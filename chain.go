@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	gethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// dial connects to a JSON-RPC endpoint; if chainID is 0, it is queried from the node
+func dial(ctx context.Context, rpcURL string, chainID int64) (*ethclient.Client, *big.Int) {
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	assertNoError(err)
+
+	if chainID != 0 {
+		return client, big.NewInt(chainID)
+	}
+	id, err := client.ChainID(ctx)
+	assertNoError(err)
+	return client, id
+}
+
+// signerFlags select the account signing the transactions
+type signerFlags struct {
+	privateKey *string
+	keystore   *string
+	mnemonic   *string
+	hdPath     *string
+	ledger     *bool
+}
+
+// addSignerFlags registers the signer flags; account describes the account in the usage messages
+func addSignerFlags(fs *flag.FlagSet, account string) *signerFlags {
+	return &signerFlags{
+		privateKey: fs.String("private-key", "", "hex encoded private key of the "+account),
+		keystore:   fs.String("keystore", "", "go-ethereum keystore file of the "+account+" (the passphrase is prompted)"),
+		mnemonic:   fs.String("mnemonic", "", "BIP-39 mnemonic of the "+account+", prompted if set to -"),
+		hdPath:     fs.String("hd-path", "m/44'/60'/0'/0/0", "derivation path of the account (with -mnemonic or -ledger)"),
+		ledger:     fs.Bool("ledger", false, "sign with the Ethereum app of a Ledger, confirming each transaction on the device"),
+	}
+}
+
+// signer returns the selected signer, and a function releasing it
+func (f *signerFlags) signer() (ethereum.Signer, func()) {
+	path, err := accounts.ParseDerivationPath(*f.hdPath)
+	assertNoError(err)
+
+	switch {
+	case *f.privateKey != "":
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(*f.privateKey, "0x"))
+		assertNoError(err)
+		return ethereum.NewKeySigner(key), func() {}
+	case *f.keystore != "":
+		passphrase, err := prompt.Stdin.PromptPassword("Passphrase of " + *f.keystore + ": ")
+		assertNoError(err)
+		s, err := ethereum.KeystoreSigner(*f.keystore, passphrase)
+		assertNoError(err)
+		return s, func() {}
+	case *f.mnemonic != "":
+		mnemonic := *f.mnemonic
+		if mnemonic == "-" {
+			mnemonic, err = prompt.Stdin.PromptPassword("Mnemonic: ")
+			assertNoError(err)
+		}
+		passphrase, err := prompt.Stdin.PromptPassword("Mnemonic passphrase (empty if none): ")
+		assertNoError(err)
+		s, err := ethereum.MnemonicSigner(mnemonic, passphrase, path)
+		assertNoError(err)
+		return s, func() {}
+	case *f.ledger:
+		ledger, err := ethereum.OpenLedger(path)
+		assertNoError(err)
+		log.Printf("using Ledger account %s (%s)", ledger.Address().Hex(), path)
+		return ledger, func() { _ = ledger.Close() }
+	}
+	log.Fatal("please provide an account with -private-key, -keystore, -mnemonic or -ledger")
+	return nil, nil
+}
+
+// verifierAddress returns address if set, else the verifier of the selected circuit recorded in the
+// deployments file for chainID
+func verifierAddress(address, deploymentsFile string, chainID *big.Int) common.Address {
+	if address != "" {
+		if !common.IsHexAddress(address) {
+			log.Fatal("invalid verifier address ", address)
+		}
+		return common.HexToAddress(address)
+	}
+	deployments, err := ethereum.ReadDeployments(deploymentsFile)
+	assertNoError(err)
+	deployment, ok := deployments.Get(chainID, *fCircuit)
+	if !ok {
+		log.Fatalf("no %s verifier deployed on chain %s in %s, please provide -address", *fCircuit, chainID, deploymentsFile)
+	}
+	return deployment.Verifier
+}
+
+// nodeFlags select the node of the commands reading the chain
+type nodeFlags struct {
+	fs      *flag.FlagSet
+	network *string
+	rpcURL  *string
+	chainID *int64
+}
+
+func addNodeFlags(fs *flag.FlagSet) *nodeFlags {
+	return &nodeFlags{
+		fs:      fs,
+		network: fs.String("network", "", "known network (sepolia, holesky or local), setting -rpc-url and -chain-id"),
+		rpcURL:  fs.String("rpc-url", "http://localhost:8545", "JSON-RPC endpoint of the node"),
+		chainID: fs.Int64("chain-id", 0, "chain ID, queried from the node if not set"),
+	}
+}
+
+// dial connects to the selected node; an explicit -rpc-url overrides the public endpoint of -network
+func (f *nodeFlags) dial(ctx context.Context) *node {
+	rpcURL, chainID := *f.rpcURL, *f.chainID
+	if *f.network != "" {
+		network, err := ethereum.LookupNetwork(*f.network)
+		assertNoError(err)
+		rpcURLSet := false
+		f.fs.Visit(func(fl *flag.Flag) { rpcURLSet = rpcURLSet || fl.Name == "rpc-url" })
+		if !rpcURLSet {
+			rpcURL = network.RPCURL
+		}
+		if chainID != 0 && chainID != network.ChainID {
+			log.Fatalf("-chain-id %d is not the chain ID of %s (%d)", chainID, network.Name, network.ChainID)
+		}
+		chainID = network.ChainID
+	}
+	return dialNode(ctx, rpcURL, chainID)
+}
+
+// txFlags are the network and fee flags of the commands sending transactions
+type txFlags struct {
+	*nodeFlags
+	tipGwei *float64
+	timeout *time.Duration
+}
+
+func addTxFlags(fs *flag.FlagSet) *txFlags {
+	return &txFlags{
+		nodeFlags: addNodeFlags(fs),
+		tipGwei:   fs.Float64("tip-gwei", 0, "priority fee per gas in gwei, suggested by the node if not set"),
+		timeout:   fs.Duration("timeout", 5*time.Minute, "how long to wait for a transaction to be mined"),
+	}
+}
+
+// dial connects to the selected node, see nodeFlags.dial
+func (f *txFlags) dial(ctx context.Context) *node {
+	n := f.nodeFlags.dial(ctx)
+	if *f.tipGwei != 0 {
+		n.tip, _ = new(big.Float).Mul(big.NewFloat(*f.tipGwei), big.NewFloat(1e9)).Int(nil)
+	}
+	n.timeout = *f.timeout
+	return n
+}
+
+// node is a JSON-RPC endpoint sending transactions with EIP-1559 based fees and local nonces
+type node struct {
+	*ethclient.Client
+	rpc     *rpc.Client
+	chainID *big.Int
+	// network is the known network of chainID, if any
+	network ethereum.Network
+	nonces  *ethereum.Nonces
+	// tip overrides the priority fee suggested by the node
+	tip     *big.Int
+	timeout time.Duration
+}
+
+// dialNode connects to a JSON-RPC endpoint; if chainID is not 0, it must be the node's chain ID
+func dialNode(ctx context.Context, rpcURL string, chainID int64) *node {
+	c, err := rpc.DialContext(ctx, rpcURL)
+	assertNoError(err)
+	n := &node{Client: ethclient.NewClient(c), rpc: c, timeout: 5 * time.Minute}
+	n.nonces = ethereum.NewNonces(n.Client)
+
+	n.chainID, err = n.Client.ChainID(ctx)
+	assertNoError(err)
+	if chainID != 0 && n.chainID.Int64() != chainID {
+		log.Fatalf("%s is on chain %s, expected %d", rpcURL, n.chainID, chainID)
+	}
+	n.network, _ = ethereum.NetworkOf(n.chainID)
+	return n
+}
+
+// fees returns the fees of the next transaction
+func (n *node) fees(ctx context.Context) ethereum.Fees {
+	fees, err := ethereum.SuggestFees(ctx, n.rpc)
+	assertNoError(err)
+	if n.tip != nil {
+		fees.Tip = n.tip
+	}
+	log.Println("fees:", fees)
+	return fees
+}
+
+// transactor returns the transact options of the next transaction signed by signer
+func (n *node) transactor(ctx context.Context, signer ethereum.Signer) *bind.TransactOpts {
+	auth := ethereum.NewTransactor(signer, n.chainID)
+	nonce, err := n.nonces.Next(ctx, auth.From)
+	assertNoError(err)
+	auth.Context = ctx
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+	if fees := n.fees(ctx); fees.BaseFee != nil && ethereum.SignsTypedTx(signer) {
+		auth.GasTipCap, auth.GasFeeCap = fees.Tip, fees.MaxFeePerGas()
+	} else {
+		auth.GasPrice = fees.GasPrice()
+	}
+	return auth
+}
+
+// wait polls the receipt of tx until it is mined, or the timeout expires
+func (n *node) wait(ctx context.Context, tx *types.Transaction) *types.Receipt {
+	if url := n.network.TxURL(tx.Hash().Hex()); url != "" {
+		log.Println("waiting for transaction", url)
+	} else {
+		log.Println("waiting for transaction", tx.Hash().Hex())
+	}
+	receipt, err := ethereum.WaitReceipt(ctx, n, tx.Hash(), 2*time.Second, n.timeout)
+	assertNoError(err)
+	return receipt
+}
+
+// sendCall signs and sends a transaction calling to with calldata, and waits for it to be mined
+// if gas is 0, it is estimated.
+func (n *node) sendCall(ctx context.Context, signer ethereum.Signer, to common.Address, calldata []byte, gas uint64) *types.Receipt {
+	from := signer.Address()
+	var err error
+	if gas == 0 {
+		gas, err = n.EstimateGas(ctx, gethereum.CallMsg{From: from, To: &to, Data: calldata})
+		assertNoError(err)
+	}
+	nonce, err := n.nonces.Next(ctx, from)
+	assertNoError(err)
+
+	tx := types.NewTx(n.fees(ctx).TxData(nonce, to, gas, calldata, ethereum.SignsTypedTx(signer)))
+	tx, err = signer.SignTx(tx, n.chainID)
+	assertNoError(err)
+	if err := n.SendTransaction(ctx, tx); err != nil {
+		n.nonces.Reset(from)
+		log.Fatal(err)
+	}
+	return n.wait(ctx, tx)
+}
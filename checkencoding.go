@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runCheckSolidityEncoding checks the verifyProof arguments of a proof, as the Go slicing computes
+// them or as given calldata holds them, against the points of the proof (see
+// ethereum.CheckSolidityEncoding), to find negation and byte order mistakes before calling a verifier
+func runCheckSolidityEncoding(args []string) {
+	fs := flag.NewFlagSet("check-solidity-encoding", flag.ExitOnError)
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	fProof := fs.String("proof", files.proof, "proof file")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	fCalldata := fs.String("calldata", "", "if set, verifyProof calldata to check instead of the Go encoding: 0x prefixed hex, or @file holding it")
+	assertNoError(fs.Parse(args))
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+	proof := groth16.NewProof(ecc.BN254)
+	deserialize(proof, *fProof)
+	publicWitness := readPublicWitness(*fPublic, *fVK)
+
+	inputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	assertNoError(err)
+	if *fCalldata != "" {
+		hexCalldata := *fCalldata
+		if strings.HasPrefix(hexCalldata, "@") {
+			data, err := ioutil.ReadFile(hexCalldata[1:])
+			assertNoError(err)
+			hexCalldata = strings.TrimSpace(string(data))
+		}
+		calldata, err := hexutil.Decode(hexCalldata)
+		assertNoError(err)
+		inputs, err = ethereum.DecodeCalldata(calldata, len(publicWitness))
+		assertNoError(err)
+	}
+
+	checks, err := ethereum.CheckSolidityEncoding(inputs, proof, vk, publicWitness)
+	assertNoError(err)
+	failed := 0
+	for _, check := range checks {
+		if !check.OK {
+			failed++
+		}
+		if jsonOutput() {
+			continue
+		}
+		if check.OK {
+			fmt.Printf("ok    %s\n", check.Name)
+		} else {
+			fmt.Printf("FAIL  %s: %s\n", check.Name, check.Problem)
+		}
+	}
+	emit("check-solidity-encoding", checkEncodingResult{Circuit: *fCircuit, Checks: checks, OK: failed == 0})
+	if failed != 0 {
+		log.Printf("%d of %d checks failed: the verifier would return false", failed, len(checks))
+		os.Exit(1)
+	}
+	log.Println("the encoding matches what the verifier expects")
+}
+
+// checkEncodingResult is the JSON output of check-solidity-encoding
+type checkEncodingResult struct {
+	Circuit string                   `json:"circuit"`
+	Checks  []ethereum.EncodingCheck `json:"checks"`
+	OK      bool                     `json:"ok"`
+}
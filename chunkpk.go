@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/gbotrel/gnark-workshop/artifacts"
+)
+
+// runChunkPK splits the proving key of the selected circuit in chunks (see artifacts.Split), read
+// one at a time when the key is loaded, and removes the original file
+// This is transport chunking: the chunks bound the size of the files, not the memory of the prover,
+// the key is reassembled and decoded whole.
+func runChunkPK(args []string) {
+	fs := flag.NewFlagSet("chunk-pk", flag.ExitOnError)
+	fSize := fs.Int64("size", 64, "chunk size, in MiB")
+	fKeep := fs.Bool("keep", false, "keep the unsplit proving key")
+	assertNoError(fs.Parse(args))
+
+	index, err := artifacts.Split(files.pk, *fSize<<20)
+	assertNoError(err)
+	log.Printf("%s split in %d chunks of %d MiB, index in %s", files.pk, len(index.Chunks), *fSize, artifacts.IndexFile(files.pk))
+	if !*fKeep {
+		assertNoError(os.Remove(files.pk))
+	}
+	emit("chunk-pk", chunkPKResult{Circuit: *fCircuit, PK: files.pk, Index: artifacts.IndexFile(files.pk), Chunks: *index})
+}
+
+// chunkPKResult is the JSON output of chunk-pk
+type chunkPKResult struct {
+	Circuit string               `json:"circuit"`
+	PK      string               `json:"pk"`
+	Index   string               `json:"index"`
+	Chunks  artifacts.ChunkIndex `json:"chunks"`
+}
@@ -0,0 +1,39 @@
+package circuit
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// BatchSize is the number of pre-images a Batch proof covers
+const BatchSize = 4
+
+// Batch defines an aggregated pre-image knowledge proof
+// mimc(secrets[i]) = public hashes[i], for each i < BatchSize
+//
+// One Batch proof settles BatchSize Circuit statements at once: on-chain, the
+// pairing check is paid once and each extra statement only costs a scalar
+// multiplication for its public input.
+type Batch struct {
+	Secrets [BatchSize]frontend.Variable
+	Hashes  [BatchSize]frontend.Variable `gnark:",public"`
+}
+
+// Define declares the circuit's constraints
+// assert mimc(secrets[i]) == hashes[i]
+func (circuit *Batch) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	for i := 0; i < BatchSize; i++ {
+		// fresh hash function for each pre-image
+		mimc, err := mimc.NewMiMC(Seed, curveID, cs)
+		if err != nil {
+			return err
+		}
+
+		mimc.Write(circuit.Secrets[i])
+		hash := mimc.Sum()
+		cs.AssertIsEqual(hash, circuit.Hashes[i])
+	}
+
+	return nil
+}
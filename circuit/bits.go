@@ -0,0 +1,100 @@
+package circuit
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// bit-level helpers of the binary hash circuits (sha256, keccak)
+// A word is a slice of boolean variables, least significant bit first, as returned by cs.ToBinary.
+
+// constantWord returns the nbBits bits of v as constants
+func constantWord(cs *frontend.ConstraintSystem, v uint64, nbBits int) []frontend.Variable {
+	w := make([]frontend.Variable, nbBits)
+	for i := range w {
+		w[i] = cs.Constant(int((v >> i) & 1))
+	}
+	return w
+}
+
+// rotr rotates w right by n bits (free: it only renames variables)
+func rotr(w []frontend.Variable, n int) []frontend.Variable {
+	out := make([]frontend.Variable, len(w))
+	for i := range out {
+		out[i] = w[(i+n)%len(w)]
+	}
+	return out
+}
+
+// rotl rotates w left by n bits
+func rotl(w []frontend.Variable, n int) []frontend.Variable {
+	return rotr(w, len(w)-n%len(w))
+}
+
+// shr shifts w right by n bits
+func shr(cs *frontend.ConstraintSystem, w []frontend.Variable, n int) []frontend.Variable {
+	out := make([]frontend.Variable, len(w))
+	for i := range out {
+		if i+n < len(w) {
+			out[i] = w[i+n]
+		} else {
+			out[i] = cs.Constant(0)
+		}
+	}
+	return out
+}
+
+// xorBit returns a XOR b = a + b - 2ab for boolean a, b (one constraint)
+func xorBit(cs *frontend.ConstraintSystem, a, b frontend.Variable) frontend.Variable {
+	return cs.Sub(cs.Add(a, b), cs.Mul(cs.Mul(a, b), 2))
+}
+
+// xorWords returns the bitwise XOR of the words
+func xorWords(cs *frontend.ConstraintSystem, words ...[]frontend.Variable) []frontend.Variable {
+	out := make([]frontend.Variable, len(words[0]))
+	copy(out, words[0])
+	for _, w := range words[1:] {
+		for i := range out {
+			out[i] = xorBit(cs, out[i], w[i])
+		}
+	}
+	return out
+}
+
+// addWords returns the sum of the words modulo 2^len(word)
+// The sum is computed in the field, then decomposed on enough bits to hold the carries.
+func addWords(cs *frontend.ConstraintSystem, words ...[]frontend.Variable) []frontend.Variable {
+	nbBits := len(words[0])
+	sum := fromBits(cs, words[0])
+	for _, w := range words[1:] {
+		sum = cs.Add(sum, fromBits(cs, w))
+	}
+	carryBits := 0
+	for n := len(words) - 1; n > 0; n >>= 1 {
+		carryBits++
+	}
+	return cs.ToBinary(sum, nbBits+carryBits)[:nbBits]
+}
+
+// fromBits returns the value of w, a linear combination of its bits (no constraint)
+// Unlike cs.FromBinary, it doesn't assert that the bits are boolean: the bits of words are by
+// construction, and cs.FromBinary panics on constant bits (padding, round constants).
+func fromBits(cs *frontend.ConstraintSystem, w []frontend.Variable) frontend.Variable {
+	sum := cs.Constant(0)
+	c := big.NewInt(1)
+	for _, b := range w {
+		sum = cs.Add(sum, cs.Mul(c, b))
+		c = new(big.Int).Lsh(c, 1)
+	}
+	return sum
+}
+
+// bytesToBits decomposes byte variables in 8 bits each, asserting they are bytes
+func bytesToBits(cs *frontend.ConstraintSystem, bytes []frontend.Variable) [][]frontend.Variable {
+	bits := make([][]frontend.Variable, len(bytes))
+	for i, b := range bytes {
+		bits[i] = cs.ToBinary(b, 8)
+	}
+	return bits
+}
@@ -0,0 +1,73 @@
+package circuit
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+)
+
+// ChunkSize is the number of message bytes per chunk; one byte less than fr.Bytes so that
+// any chunk is a field element as is, without modular reduction
+const ChunkSize = fr.Bytes - 1
+
+// ErrMessageTooLongN is returned for messages that don't fit in NbChunks chunks
+var ErrMessageTooLongN = fmt.Errorf("message must be at most %d bytes long", NbChunks*ChunkSize)
+
+// Chunks splits msg in NbChunks big-endian chunks of ChunkSize bytes, the last non-empty
+// chunk possibly shorter, the remaining ones empty (0)
+// The circuit sees field elements: messages only differing by trailing zero chunks, or by
+// leading zero bytes of their last chunk, have the same chunks.
+func Chunks(msg []byte) ([][]byte, error) {
+	if len(msg) > NbChunks*ChunkSize {
+		return nil, ErrMessageTooLongN
+	}
+	chunks := make([][]byte, NbChunks)
+	for i := range chunks {
+		start := i * ChunkSize
+		if start >= len(msg) {
+			chunks[i] = []byte{}
+			continue
+		}
+		end := start + ChunkSize
+		if end > len(msg) {
+			end = len(msg)
+		}
+		chunks[i] = msg[start:end]
+	}
+	return chunks, nil
+}
+
+// HashN returns mimc(Chunks(msg)...), the public input of the CircuitN statement
+func HashN(msg []byte) ([]byte, error) {
+	chunks, err := Chunks(msg)
+	if err != nil {
+		return nil, err
+	}
+	hFunc := mimc.NewMiMC(Seed)
+	for _, chunk := range chunks {
+		// one mimc block per chunk: left pad to fr.Bytes, as the chunk value is in the circuit
+		var block [fr.Bytes]byte
+		copy(block[fr.Bytes-len(chunk):], chunk)
+		hFunc.Write(block[:])
+	}
+	return hFunc.Sum(make([]byte, 0, fr.Bytes)), nil
+}
+
+// NewWitnessN returns a full CircuitN assignment for msg
+func NewWitnessN(msg []byte) (*CircuitN, error) {
+	hash, err := HashN(msg)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := Chunks(msg)
+	if err != nil {
+		return nil, err
+	}
+	var witness CircuitN
+	for i, chunk := range chunks {
+		witness.Secrets[i].Assign(chunk)
+	}
+	witness.Hash.Assign(hash)
+	return &witness, nil
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/consensys/gnark/std/hash/mimc"
 )
 
+// Seed is the MiMC seed shared by the circuits and the host-side hashing
+const Seed = "seed"
+
 // Circuit defines a pre-image knowledge proof
 // mimc(secret preImage) = public hash
 type Circuit struct {
@@ -16,19 +19,16 @@ type Circuit struct {
 // Define declares the circuit's constraints
 // assert mimc(secret) == hash
 func (circuit *Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
-	const seed = "seed"
-
 	// hash function
-	mimc, err := mimc.NewMiMC(seed, curveID, cs)
+	mimc, err := mimc.NewMiMC(Seed, curveID, cs)
 	if err != nil {
 		return err
 	}
 
 	// assert mimc(secret) == hash
 	mimc.Write(circuit.Secret)
-	cs.AssertIsEqual(mimc.Sum(), circuit.Hash)
+	hash := mimc.Sum()
+	cs.AssertIsEqual(hash, circuit.Hash)
 
 	return nil
 }
-
-//go:generate go run main.go && abigen --sol solidity/mimc_verifier.sol --pkg solidity --out solidity/solidity.go
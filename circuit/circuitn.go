@@ -0,0 +1,34 @@
+package circuit
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// NbChunks is the number of message chunks a CircuitN hashes
+const NbChunks = 4
+
+// CircuitN defines a pre-image knowledge proof for messages longer than a field element
+// mimc(secret chunks[0], ..., chunks[NbChunks-1]) = public hash
+//
+// Use Chunks to split a message consistently with the in-circuit hashing.
+type CircuitN struct {
+	Secrets [NbChunks]frontend.Variable
+	Hash    frontend.Variable `gnark:",public"`
+}
+
+// Define declares the circuit's constraints
+// assert mimc(chunks...) == hash
+func (circuit *CircuitN) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	mimc, err := mimc.NewMiMC(Seed, curveID, cs)
+	if err != nil {
+		return err
+	}
+
+	mimc.Write(circuit.Secrets[:]...)
+	hash := mimc.Sum()
+	cs.AssertIsEqual(hash, circuit.Hash)
+
+	return nil
+}
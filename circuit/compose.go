@@ -0,0 +1,99 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrNotComposite is returned for composites that are not pointers to structs of circuits
+var ErrNotComposite = errors.New("composite must be a pointer to a struct of circuits")
+
+// A composite statement is a struct whose fields are the sub-circuits it asserts, its parts:
+//
+//	type PreImageInBatch struct {
+//		PreImage circuit.Circuit `gnark:",embed"`
+//		Batch    circuit.Batch   `gnark:",embed"`
+//	}
+//
+// The parts are struct values, not pointers or interfaces: gnark only allocates the variables it
+// reaches through struct fields, slices and arrays. They are tagged embed, or gnark would make all
+// their variables secret, public inputs included. Each part has its own variables even if parts
+// share a type. The Define of the composite calls DefineParts, then binds the parts together with
+// constraints of its own.
+
+// optEmbed is the gnark tag option of the parts
+const optEmbed = "embed"
+
+// DefineParts declares the constraints of every part of composite, in field order
+func DefineParts(curveID ecc.ID, cs *frontend.ConstraintSystem, composite interface{}) error {
+	parts, names, err := partsOf(composite)
+	if err != nil {
+		return err
+	}
+	for i, p := range parts {
+		if err := p.Define(curveID, cs); err != nil {
+			return fmt.Errorf("%s: %w", names[i], err)
+		}
+	}
+	return nil
+}
+
+// AssignParts fills the parts of the composite assignment with the assignments of the parts, keyed
+// by field name, as built by the witness functions of each circuit.
+// Each assignment must be a pointer to the type of the part it fills; missing and unknown parts are
+// errors.
+func AssignParts(composite interface{}, assignments map[string]frontend.Circuit) error {
+	parts, names, err := partsOf(composite)
+	if err != nil {
+		return err
+	}
+	for i, p := range parts {
+		assignment, ok := assignments[names[i]]
+		if !ok {
+			return fmt.Errorf("missing assignment for part %q", names[i])
+		}
+		if reflect.TypeOf(assignment) != reflect.TypeOf(p) {
+			return fmt.Errorf("part %q expects a %T assignment, got %T", names[i], p, assignment)
+		}
+		reflect.ValueOf(p).Elem().Set(reflect.ValueOf(assignment).Elem())
+	}
+	if len(assignments) != len(parts) {
+		return errors.New("assignments contain unknown parts")
+	}
+	return nil
+}
+
+// partsOf returns the fields of composite implementing frontend.Circuit, and their names
+func partsOf(composite interface{}) ([]frontend.Circuit, []string, error) {
+	v := reflect.ValueOf(composite)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("%w, got %T", ErrNotComposite, composite)
+	}
+	v = v.Elem()
+	var parts []frontend.Circuit
+	var names []string
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" || field.Tag.Get("gnark") == "-" || field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		p, ok := v.Field(i).Addr().Interface().(frontend.Circuit)
+		if !ok {
+			continue
+		}
+		if !strings.Contains(field.Tag.Get("gnark"), optEmbed) {
+			return nil, nil, fmt.Errorf("%w: part %s is not tagged `gnark:\",%s\"`", ErrNotComposite, field.Name, optEmbed)
+		}
+		parts = append(parts, p)
+		names = append(names, field.Name)
+	}
+	if len(parts) == 0 {
+		return nil, nil, fmt.Errorf("%w: %T has no part", ErrNotComposite, composite)
+	}
+	return parts, names, nil
+}
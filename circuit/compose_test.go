@@ -0,0 +1,95 @@
+package circuit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// preImageInBatch proves the knowledge of a pre-image whose hash is the first of a batch
+type preImageInBatch struct {
+	PreImage Circuit `gnark:",embed"`
+	Batch    Batch   `gnark:",embed"`
+}
+
+func (c *preImageInBatch) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	if err := DefineParts(curveID, cs, c); err != nil {
+		return err
+	}
+	cs.AssertIsEqual(c.PreImage.Hash, c.Batch.Hashes[0])
+	return nil
+}
+
+func TestComposeProveVerify(t *testing.T) {
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &preImageInBatch{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, public := r1cs.GetNbVariables(); public != 1+1+BatchSize {
+		t.Fatalf("got %d public variables, expected %d", public, 1+1+BatchSize)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := (&Batch{}).Example()
+	if err != nil {
+		t.Fatal(err)
+	}
+	preImage, err := NewWitness([]byte("secret-0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var witness preImageInBatch
+	if err := AssignParts(&witness, map[string]frontend.Circuit{"PreImage": preImage, "Batch": batch}); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, &witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, &witness); err != nil {
+		t.Fatal(err)
+	}
+
+	// the parts hold on their own, the glue doesn't
+	other, err := NewWitness([]byte("secret-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AssignParts(&witness, map[string]frontend.Circuit{"PreImage": other, "Batch": batch}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := groth16.Prove(r1cs, pk, &witness); err == nil {
+		t.Fatal("proved a pre-image that is not in the batch")
+	}
+}
+
+func TestAssignParts(t *testing.T) {
+	preImage, err := NewWitness([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var witness preImageInBatch
+	for name, assignments := range map[string]map[string]frontend.Circuit{
+		"missing": {"PreImage": preImage},
+		"unknown": {"PreImage": preImage, "Batch": &Batch{}, "Other": &Circuit{}},
+		"type":    {"PreImage": preImage, "Batch": &Circuit{}},
+	} {
+		if err := AssignParts(&witness, assignments); err == nil {
+			t.Errorf("%s: assigned, expected an error", name)
+		}
+	}
+	if err := AssignParts(witness, nil); !errors.Is(err, ErrNotComposite) {
+		t.Errorf("struct value: got %v, expected ErrNotComposite", err)
+	}
+	untagged := struct{ PreImage Circuit }{}
+	if err := AssignParts(&untagged, map[string]frontend.Circuit{"PreImage": preImage}); !errors.Is(err, ErrNotComposite) {
+		t.Errorf("untagged part: got %v, expected ErrNotComposite", err)
+	}
+}
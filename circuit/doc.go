@@ -0,0 +1,20 @@
+// Package circuit holds the workshop circuits and their host-side helpers.
+//
+// Threat model of the host helpers (Hash, NewWitness, IsPreImage, HashElements):
+//
+// the secret is a pre-image, and the helpers are NOT constant time. The mimc
+// digest reduces its input through fr.Element.SetBytes, that is big.Int
+// SetBytes, Cmp and Mod, whose running time depends on the value, and the
+// witness assignment stores the secret as a big.Int too. What the helpers do
+// guarantee: their own branches and allocations depend on the secret length
+// only (bounded by fr.Bytes, and not hidden by the circuit either), and
+// IsPreImage compares the digests with crypto/subtle.ConstantTimeCompare.
+// host_test.go asserts the length bound and allocations independent of the
+// content.
+//
+// Don't hash or prove secrets on a machine whose timing an attacker can
+// measure (a shared host, a remote prover answering per request). Also out of
+// scope: the gnark solver and prover (big.Int based as well), side channels of
+// the OS / Go runtime (GC copies, swap), and the secret once it is handed to
+// the caller.
+package circuit
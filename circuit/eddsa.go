@@ -0,0 +1,32 @@
+package circuit
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// EdDSA defines a signature knowledge proof
+// the secret signature is a valid EdDSA signature of the public message under the public key
+//
+// Keys live on the twisted Edwards curve whose base field is the scalar field of the proving
+// curve (BabyJubJub-like for BN254); the message is a single field element, hashed with mimc.
+type EdDSA struct {
+	PublicKey eddsa.PublicKey   `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+	Signature eddsa.Signature
+}
+
+// Define declares the circuit's constraints
+// assert eddsa.Verify(signature, message, publicKey)
+func (circuit *EdDSA) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	curve, err := twistededwards.NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+	circuit.PublicKey.Curve = curve
+
+	err = eddsa.Verify(cs, circuit.Signature, circuit.Message, circuit.PublicKey)
+	return err
+}
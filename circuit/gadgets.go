@@ -0,0 +1,94 @@
+package circuit
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/poseidon"
+)
+
+// the gadgets of the workshop circuits, measured on their own by circuits.GadgetProfiles: each
+// circuit makes one call and asserts nothing else
+func init() {
+	circuits.RegisterGadget("mimc", &mimcGadget{})
+	circuits.RegisterGadget("poseidon", &poseidonGadget{})
+	circuits.RegisterGadget("sha256", &sha256GadgetCircuit{})
+	circuits.RegisterGadget("keccak256", &keccak256GadgetCircuit{})
+	circuits.RegisterGadget("merkle-step", &merkleStepGadget{})
+	circuits.RegisterGadget("packing", &packingGadget{})
+}
+
+// mimcGadget hashes one field element with MiMC
+type mimcGadget struct {
+	In frontend.Variable
+}
+
+func (c *mimcGadget) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC(Seed, curveID, cs)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	h.Sum()
+	return nil
+}
+
+// poseidonGadget hashes one field element with Poseidon
+type poseidonGadget struct {
+	In frontend.Variable
+}
+
+func (c *poseidonGadget) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	poseidon.HashInCircuit(cs, c.In)
+	return nil
+}
+
+// sha256GadgetCircuit hashes a SHA256Circuit pre-image
+type sha256GadgetCircuit struct {
+	In [SHA256PreimageLen]frontend.Variable
+}
+
+func (c *sha256GadgetCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	sha256Gadget(cs, c.In[:])
+	return nil
+}
+
+// keccak256GadgetCircuit hashes a Keccak256Circuit pre-image
+type keccak256GadgetCircuit struct {
+	In [KeccakPreimageLen]frontend.Variable
+}
+
+func (c *keccak256GadgetCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	keccak256Gadget(cs, c.In[:])
+	return nil
+}
+
+// merkleStepGadget climbs one level of a Merkle path as MerkleRoot does: a path of depth d costs a
+// mimc call (the leaf) and d steps
+type merkleStepGadget struct {
+	Node, Sibling, Direction frontend.Variable
+}
+
+func (c *merkleStepGadget) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsBoolean(c.Direction)
+	left := cs.Select(c.Direction, c.Sibling, c.Node)
+	right := cs.Select(c.Direction, c.Node, c.Sibling)
+	h, err := mimc.NewMiMC(Seed, curveID, cs)
+	if err != nil {
+		return err
+	}
+	h.Write(left, right)
+	h.Sum()
+	return nil
+}
+
+// packingGadget packs the BatchSize public inputs of PackedBatch
+type packingGadget struct {
+	In [BatchSize]frontend.Variable
+}
+
+func (c *packingGadget) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	PackInputs(cs, c.In[:]...)
+	return nil
+}
@@ -0,0 +1,75 @@
+package circuit
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+)
+
+// ErrSecretTooLong is returned for secrets that don't fit in a single field element;
+// hashing them on the host would process several blocks while the circuit hashes one.
+var ErrSecretTooLong = errors.New("secret must be at most fr.Bytes long")
+
+// ErrElementTooLong is returned by HashElements for elements longer than fr.Bytes, which the
+// circuits couldn't assign to a single variable
+var ErrElementTooLong = errors.New("element must be at most fr.Bytes long")
+
+// ErrDigestSize is returned if the mimc digest is not fr.Bytes long, which would not be the public
+// input of the circuits
+var ErrDigestSize = errors.New("mimc digest is not fr.Bytes long")
+
+// Hash returns mimc(secret), the public input of the Circuit statement
+// Its own branches depend on len(secret) only; the mimc reduction isn't constant time (see the
+// package doc).
+func Hash(secret []byte) ([]byte, error) {
+	if len(secret) > fr.Bytes {
+		return nil, ErrSecretTooLong
+	}
+	hFunc := mimc.NewMiMC(Seed)
+	hFunc.Write(secret)
+
+	hash := hFunc.Sum(make([]byte, 0, fr.Bytes))
+	if len(hash) != fr.Bytes {
+		return nil, ErrDigestSize
+	}
+	return hash, nil
+}
+
+// NewWitness returns a full Circuit assignment for secret
+func NewWitness(secret []byte) (*Circuit, error) {
+	hash, err := Hash(secret)
+	if err != nil {
+		return nil, err
+	}
+	var witness Circuit
+	witness.Hash.Assign(hash)
+	witness.Secret.Assign(secret)
+	return &witness, nil
+}
+
+// IsPreImage returns true if mimc(secret) == hash, comparing in constant time
+func IsPreImage(secret, hash []byte) bool {
+	computed, err := Hash(secret)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(computed, hash) == 1
+}
+
+// HashElements returns the mimc hash of the big-endian values elements, of at most fr.Bytes bytes,
+// as the circuits hash them: one block per element
+func HashElements(elements ...[]byte) ([]byte, error) {
+	hFunc := mimc.NewMiMC(Seed)
+	for _, e := range elements {
+		if len(e) > fr.Bytes {
+			return nil, ErrElementTooLong
+		}
+		// left pad to fr.Bytes, as the value is in the circuit
+		var block [fr.Bytes]byte
+		copy(block[fr.Bytes-len(e):], e)
+		hFunc.Write(block[:])
+	}
+	return hFunc.Sum(make([]byte, 0, fr.Bytes)), nil
+}
@@ -0,0 +1,100 @@
+package circuit
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestHash(t *testing.T) {
+	for n := 0; n <= fr.Bytes; n++ {
+		hash, err := Hash(bytes.Repeat([]byte{0x01}, n))
+		if err != nil {
+			t.Fatalf("%d bytes secret: %v", n, err)
+		}
+		if len(hash) != fr.Bytes {
+			t.Fatalf("%d bytes secret: %d bytes digest", n, len(hash))
+		}
+	}
+	if _, err := Hash(make([]byte, fr.Bytes+1)); !errors.Is(err, ErrSecretTooLong) {
+		t.Fatalf("got %v, expected ErrSecretTooLong", err)
+	}
+}
+
+func TestIsPreImage(t *testing.T) {
+	secret := []byte("secret")
+	hash, err := Hash(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsPreImage(secret, hash) {
+		t.Fatal("secret is not the pre-image of its hash")
+	}
+
+	flipped := append([]byte(nil), hash...)
+	flipped[len(flipped)-1] ^= 1
+	for name, tc := range map[string]struct{ secret, hash []byte }{
+		"wrong secret":    {[]byte("secreT"), hash},
+		"wrong hash":      {secret, flipped},
+		"truncated hash":  {secret, hash[:fr.Bytes-1]},
+		"empty hash":      {secret, nil},
+		"secret too long": {make([]byte, fr.Bytes+1), hash},
+	} {
+		if IsPreImage(tc.secret, tc.hash) {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+// the helpers allocate as much for all secrets of a given length: allocations don't depend on the
+// secret content
+func TestHostAllocationsIndependentOfSecret(t *testing.T) {
+	hash, err := Hash([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := [][]byte{[]byte("secret"), []byte("\x00\x00\x00\x00\x00\x00"), []byte("\xff\xff\xff\xff\xff\xff")}
+	for name, f := range map[string]func(secret []byte){
+		"Hash":       func(secret []byte) { _, _ = Hash(secret) },
+		"NewWitness": func(secret []byte) { _, _ = NewWitness(secret) },
+		"IsPreImage": func(secret []byte) { IsPreImage(secret, hash) },
+	} {
+		var allocs []float64
+		for _, secret := range secrets {
+			allocs = append(allocs, testing.AllocsPerRun(100, func() { f(secret) }))
+		}
+		for i := range allocs {
+			if allocs[i] != allocs[0] {
+				t.Errorf("%s: %v allocations for secrets of the same length", name, allocs)
+				break
+			}
+		}
+	}
+}
+
+func TestHashElements(t *testing.T) {
+	// elements are left padded: 0x01 and 0x0001 are the same value
+	short, err := HashElements([]byte{0x01}, []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	padded, err := HashElements([]byte{0x00, 0x01}, []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(short, padded) {
+		t.Fatal("left padding changes the hash")
+	}
+	if len(short) != fr.Bytes {
+		t.Fatalf("%d bytes digest", len(short))
+	}
+
+	if _, err := HashElements(make([]byte, fr.Bytes)); err != nil {
+		t.Fatalf("%d bytes element: %v", fr.Bytes, err)
+	}
+	if _, err := HashElements([]byte("salt"), make([]byte, fr.Bytes+1)); !errors.Is(err, ErrElementTooLong) {
+		t.Fatalf("%d bytes element: got %v, expected ErrElementTooLong", fr.Bytes+1, err)
+	}
+}
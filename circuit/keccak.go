@@ -0,0 +1,225 @@
+package circuit
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeccakPreimageLen is the length in bytes of the pre-image of the Keccak256Circuit
+// 64 bytes is the pre-image of a Solidity mapping storage slot, keccak256(key . slot).
+const KeccakPreimageLen = 64
+
+// Keccak256Circuit defines a pre-image knowledge proof with Ethereum's keccak256
+// keccak256(secret bytes) = public digest
+//
+// The digest is public as two 128-bit halves, see Keccak256PublicInputs.
+type Keccak256Circuit struct {
+	Secret   [KeccakPreimageLen]frontend.Variable
+	DigestHi frontend.Variable `gnark:",public"`
+	DigestLo frontend.Variable `gnark:",public"`
+}
+
+// Define declares the circuit's constraints
+// assert keccak256(secret) == digestHi || digestLo
+func (circuit *Keccak256Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	assertKeccakDigest(cs, keccak256Gadget(cs, circuit.Secret[:]), circuit.DigestHi, circuit.DigestLo)
+	return nil
+}
+
+// assertKeccakDigest asserts that digest is hi || lo, its big-endian 128-bit halves
+func assertKeccakDigest(cs *frontend.ConstraintSystem, digest [32][]frontend.Variable, hi, lo frontend.Variable) {
+	// digest bytes are big-endian in the halves, bits little-endian in the bytes
+	var hiBits, loBits []frontend.Variable
+	for i := 15; i >= 0; i-- {
+		hiBits = append(hiBits, digest[i]...)
+		loBits = append(loBits, digest[i+16]...)
+	}
+	cs.AssertIsEqual(fromBits(cs, hiBits), hi)
+	cs.AssertIsEqual(fromBits(cs, loBits), lo)
+}
+
+// Keccak256PublicInputs splits digest in its big-endian 128-bit halves (hi, lo), the public
+// inputs of the Keccak256Circuit statement
+func Keccak256PublicInputs(digest common.Hash) (hi, lo []byte) {
+	return digest[:16], digest[16:]
+}
+
+// NewKeccak256Witness returns a full Keccak256Circuit assignment for secret, hashed with
+// go-ethereum's crypto.Keccak256
+func NewKeccak256Witness(secret []byte) (*Keccak256Circuit, error) {
+	if len(secret) != KeccakPreimageLen {
+		return nil, fmt.Errorf("keccak256 pre-image must be %d bytes long", KeccakPreimageLen)
+	}
+	var witness Keccak256Circuit
+	for i, b := range secret {
+		witness.Secret[i].Assign(int(b))
+	}
+	hi, lo := Keccak256PublicInputs(crypto.Keccak256Hash(secret))
+	witness.DigestHi.Assign(hi)
+	witness.DigestLo.Assign(lo)
+	return &witness, nil
+}
+
+// MappingSlotPreimage returns key . slot, whose keccak256 is the storage slot of
+// mapping[key] for a Solidity mapping declared at slot
+func MappingSlotPreimage(key common.Hash, slot uint64) []byte {
+	preimage := make([]byte, KeccakPreimageLen)
+	copy(preimage, key[:])
+	binary.BigEndian.PutUint64(preimage[KeccakPreimageLen-8:], slot)
+	return preimage
+}
+
+const keccakRate = 136 // bytes absorbed per permutation, for a 256-bit output
+
+var keccakRC = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations[x][y] is the rho rotation of lane (x, y)
+var keccakRotations = [5][5]int{
+	{0, 36, 3, 41, 18},
+	{1, 44, 10, 45, 2},
+	{62, 6, 43, 15, 61},
+	{28, 55, 25, 21, 56},
+	{27, 20, 39, 8, 14},
+}
+
+// keccak256Gadget returns the 32 bytes (as 8 bits each, least significant first) of
+// keccak256(msg), msg being byte variables of fixed length
+// This is the original Keccak padding (0x01), as Ethereum uses, not SHA3's (0x06).
+func keccak256Gadget(cs *frontend.ConstraintSystem, msg []frontend.Variable) [32][]frontend.Variable {
+	return keccak256Bits(cs, bytesToBits(cs, msg))
+}
+
+// keccak256Bits is keccak256Gadget for a message given as the bits of its bytes, least significant first
+func keccak256Bits(cs *frontend.ConstraintSystem, bytes [][]frontend.Variable) [32][]frontend.Variable {
+	msgLen := len(bytes)
+
+	// padding: 0x01, zeros, 0x80 (0x81 if there is a single padding byte)
+	nbBlocks := msgLen/keccakRate + 1
+	padding := make([]byte, nbBlocks*keccakRate-msgLen)
+	padding[0] |= 0x01
+	padding[len(padding)-1] |= 0x80
+	for _, p := range padding {
+		bytes = append(bytes, constantWord(cs, uint64(p), 8))
+	}
+
+	// state lanes, lane (x, y) at index x+5y; the state starts at zero, the first block is
+	// absorbed without constraint
+	var state [25][]frontend.Variable
+	for block := 0; block < nbBlocks; block++ {
+		for i := 0; i < keccakRate/8; i++ {
+			// little-endian lane from 8 bytes
+			var lane []frontend.Variable
+			for _, b := range bytes[block*keccakRate+8*i : block*keccakRate+8*i+8] {
+				lane = append(lane, b...)
+			}
+			if block == 0 {
+				state[i] = lane
+				continue
+			}
+			for j := range lane {
+				state[i][j] = keccakXor(cs, state[i][j], lane[j])
+			}
+		}
+		if block == 0 {
+			for i := keccakRate / 8; i < 25; i++ {
+				state[i] = constantWord(cs, 0, 64)
+			}
+		}
+		keccakF(cs, &state)
+	}
+
+	var digest [32][]frontend.Variable
+	for i := range digest {
+		digest[i] = state[i/8][(i%8)*8 : (i%8)*8+8]
+	}
+	return digest
+}
+
+// keccakPi[i] is the lane moved to lane i by pi, rotated by keccakPiRotation[i] (rho)
+var keccakPi, keccakPiRotation [25]int
+
+func init() {
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			i := y + 5*((2*x+3*y)%5)
+			keccakPi[i], keccakPiRotation[i] = x+5*y, keccakRotations[x][y]
+		}
+	}
+}
+
+// keccakXor returns a XOR b for boolean a, b as a new variable: (a-b)^2 = a + b - 2ab, one
+// constraint. Returning a + b - 2ab instead would grow the linear expressions of the lanes round
+// after round, and the permutation would never compile.
+func keccakXor(cs *frontend.ConstraintSystem, a, b frontend.Variable) frontend.Variable {
+	d := cs.Sub(a, b)
+	return cs.Mul(d, d)
+}
+
+// keccakF is the Keccak-f[1600] permutation
+// A round costs 2 constraints per state bit in theta and 2 in chi; rho, pi and iota are free.
+func keccakF(cs *frontend.ConstraintSystem, a *[25][]frontend.Variable) {
+	for round := 0; round < 24; round++ {
+		// theta
+		var c [5][]frontend.Variable
+		for x := 0; x < 5; x++ {
+			c[x] = make([]frontend.Variable, 64)
+			for i := range c[x] {
+				c[x][i] = a[x][i]
+				for y := 1; y < 5; y++ {
+					c[x][i] = keccakXor(cs, c[x][i], a[x+5*y][i])
+				}
+			}
+		}
+		for x := 0; x < 5; x++ {
+			for i := 0; i < 64; i++ {
+				// d[x] = c[x-1] ^ rotl(c[x+1], 1)
+				d := keccakXor(cs, c[(x+4)%5][i], c[(x+1)%5][(i+63)%64])
+				for y := 0; y < 5; y++ {
+					a[x+5*y][i] = keccakXor(cs, a[x+5*y][i], d)
+				}
+			}
+		}
+
+		// rho and pi are read in place by chi: bit i of lane j after rho and pi is bit
+		// i - keccakPiRotation[j] of lane keccakPi[j]
+		bit := func(j, i int) frontend.Variable {
+			return a[keccakPi[j]][(i-keccakPiRotation[j]+64)%64]
+		}
+
+		// chi: a = b ^ (^b1 & b2)
+		var next [25][]frontend.Variable
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				j0, j1, j2 := x+5*y, (x+1)%5+5*y, (x+2)%5+5*y
+				lane := make([]frontend.Variable, 64)
+				for i := range lane {
+					// ^b1 & b2 = b2 - b1 b2
+					b2 := bit(j2, i)
+					t := cs.Sub(b2, cs.Mul(bit(j1, i), b2))
+					lane[i] = keccakXor(cs, bit(j0, i), t)
+				}
+				next[j0] = lane
+			}
+		}
+		*a = next
+
+		// iota: xor with a constant only flips bits, no constraint
+		for i := 0; i < 64; i++ {
+			if (keccakRC[round]>>i)&1 == 1 {
+				a[0][i] = cs.Sub(1, a[0][i])
+			}
+		}
+	}
+}
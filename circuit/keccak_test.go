@@ -0,0 +1,82 @@
+package circuit
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keccakLenCircuit is the Keccak256Circuit statement for a message of len(Msg) bytes
+type keccakLenCircuit struct {
+	Msg      []frontend.Variable
+	DigestHi frontend.Variable `gnark:",public"`
+	DigestLo frontend.Variable `gnark:",public"`
+}
+
+func (circuit *keccakLenCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	assertKeccakDigest(cs, keccak256Gadget(cs, circuit.Msg), circuit.DigestHi, circuit.DigestLo)
+	return nil
+}
+
+func newKeccakLenWitness(msg, digestOf []byte) *keccakLenCircuit {
+	witness := keccakLenCircuit{Msg: make([]frontend.Variable, len(msg))}
+	for i, b := range msg {
+		witness.Msg[i].Assign(int(b))
+	}
+	hi, lo := Keccak256PublicInputs(crypto.Keccak256Hash(digestOf))
+	witness.DigestHi.Assign(hi)
+	witness.DigestLo.Assign(lo)
+	return &witness
+}
+
+// TestKeccak256Gadget checks the gadget against go-ethereum's Keccak256, on a message without
+// data block, one shorter than a block, and one filling a block (the padding takes a second one)
+func TestKeccak256Gadget(t *testing.T) {
+	for _, msgLen := range []int{0, 1, keccakRate} {
+		msgLen := msgLen
+		t.Run(fmt.Sprintf("%d bytes", msgLen), func(t *testing.T) {
+			assert := groth16.NewAssert(t)
+			r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &keccakLenCircuit{Msg: make([]frontend.Variable, msgLen)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg := bytes.Repeat([]byte{0xa5}, msgLen)
+			assert.SolvingSucceeded(r1cs, newKeccakLenWitness(msg, msg))
+			assert.SolvingFailed(r1cs, newKeccakLenWitness(msg, append(msg, 0)))
+		})
+	}
+}
+
+func TestKeccak256Circuit(t *testing.T) {
+	assert := groth16.NewAssert(t)
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &Keccak256Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := bytes.Repeat([]byte{0xa5}, KeccakPreimageLen)
+	witness, err := NewKeccak256Witness(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.SolvingSucceeded(r1cs, witness)
+
+	// the digest of another pre-image
+	other, err := NewKeccak256Witness(bytes.Repeat([]byte{0x5a}, KeccakPreimageLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wrong Keccak256Circuit
+	for i, b := range secret {
+		wrong.Secret[i].Assign(int(b))
+	}
+	wrong.DigestHi, wrong.DigestLo = other.DigestHi, other.DigestLo
+	assert.SolvingFailed(r1cs, &wrong)
+}
@@ -0,0 +1,69 @@
+package circuit
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// MerkleDepth is the depth of the registered "merkle" circuit (up to 2^MerkleDepth leaves)
+const MerkleDepth = 4
+
+// Merkle defines a Merkle-tree membership proof
+// the private leaf is in the tree of public root
+//
+// Leaves are hashed as mimc(leaf), inner nodes as mimc(left, right). Path[i] is the sibling of
+// the current node at height i, and Directions[i] is 1 if the current node is a right child.
+// The depth of the tree is the length of Path; use NewMerkle to allocate a circuit definition.
+type Merkle struct {
+	Leaf       frontend.Variable
+	Path       []frontend.Variable
+	Directions []frontend.Variable
+	Root       frontend.Variable `gnark:",public"`
+}
+
+// NewMerkle returns a Merkle circuit definition for trees of the given depth
+func NewMerkle(depth int) *Merkle {
+	return &Merkle{
+		Path:       make([]frontend.Variable, depth),
+		Directions: make([]frontend.Variable, depth),
+	}
+}
+
+// Define declares the circuit's constraints
+// assert root == fold(mimc(leaf), path, directions)
+func (circuit *Merkle) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	root, err := MerkleRoot(curveID, cs, circuit.Leaf, circuit.Path, circuit.Directions)
+	if err != nil {
+		return err
+	}
+	cs.AssertIsEqual(root, circuit.Root)
+
+	return nil
+}
+
+// MerkleRoot returns the root of the tree in which leaf sits at path, as MerkleTree computes it
+// directions[i] is 1 if the current node is a right child at height i; they are asserted boolean.
+func MerkleRoot(curveID ecc.ID, cs *frontend.ConstraintSystem, leaf frontend.Variable, path, directions []frontend.Variable) (frontend.Variable, error) {
+	// fresh hash function for each node
+	hFunc, err := mimc.NewMiMC(Seed, curveID, cs)
+	if err != nil {
+		return frontend.Variable{}, err
+	}
+	hFunc.Write(leaf)
+	node := hFunc.Sum()
+
+	for i := range path {
+		cs.AssertIsBoolean(directions[i])
+		left := cs.Select(directions[i], path[i], node)
+		right := cs.Select(directions[i], node, path[i])
+
+		hFunc, err := mimc.NewMiMC(Seed, curveID, cs)
+		if err != nil {
+			return frontend.Variable{}, err
+		}
+		hFunc.Write(left, right)
+		node = hFunc.Sum()
+	}
+	return node, nil
+}
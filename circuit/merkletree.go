@@ -0,0 +1,116 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+)
+
+// ErrTooManyLeaves is returned when the leaves don't fit in a tree of the requested depth
+var ErrTooManyLeaves = errors.New("too many leaves for the tree depth")
+
+// MerkleTree is the host-side counterpart of the Merkle circuit
+// Missing leaves (up to 2^depth) are empty, i.e. hashed as mimc(0).
+// Leaves are read as big-endian field elements, as the circuit does: leaves only differing
+// by leading zero bytes are equal.
+type MerkleTree struct {
+	leaves [][]byte
+	// levels[0] holds the leaf hashes, levels[depth] the root
+	levels [][][]byte
+}
+
+// NewMerkleTree builds the tree of the given depth over leaves; each leaf is at most fr.Bytes long
+func NewMerkleTree(depth int, leaves [][]byte) (*MerkleTree, error) {
+	if depth < 0 || depth > 24 {
+		return nil, fmt.Errorf("invalid tree depth %d", depth)
+	}
+	if len(leaves) > 1<<depth {
+		return nil, ErrTooManyLeaves
+	}
+
+	t := &MerkleTree{leaves: leaves, levels: make([][][]byte, depth+1)}
+	level := make([][]byte, 1<<depth)
+	for i := range level {
+		// the circuit hashes a leaf as one field element: an empty leaf is 0
+		leaf := []byte{0}
+		if i < len(leaves) && len(leaves[i]) != 0 {
+			leaf = leaves[i]
+		}
+		h, err := Hash(leaf)
+		if err != nil {
+			return nil, fmt.Errorf("leaf %d: %w", i, err)
+		}
+		level[i] = h
+	}
+	t.levels[0] = level
+
+	for d := 1; d <= depth; d++ {
+		prev := t.levels[d-1]
+		level := make([][]byte, len(prev)/2)
+		for i := range level {
+			level[i] = hashNodes(prev[2*i], prev[2*i+1])
+		}
+		t.levels[d] = level
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree
+func (t *MerkleTree) Depth() int {
+	return len(t.levels) - 1
+}
+
+// Root returns the root of the tree, the public input of the Merkle statement
+func (t *MerkleTree) Root() []byte {
+	return t.levels[t.Depth()][0]
+}
+
+// Path returns the siblings of leaf index from the bottom up, and for each of them,
+// true if the current node is a right child
+func (t *MerkleTree) Path(index int) ([][]byte, []bool, error) {
+	if index < 0 || index >= len(t.levels[0]) {
+		return nil, nil, fmt.Errorf("leaf index %d out of range", index)
+	}
+	path := make([][]byte, t.Depth())
+	directions := make([]bool, t.Depth())
+	for d := 0; d < t.Depth(); d++ {
+		path[d] = t.levels[d][index^1]
+		directions[d] = index&1 == 1
+		index >>= 1
+	}
+	return path, directions, nil
+}
+
+// Witness returns a full Merkle assignment proving membership of leaf index
+func (t *MerkleTree) Witness(index int) (*Merkle, error) {
+	path, directions, err := t.Path(index)
+	if err != nil {
+		return nil, err
+	}
+	witness := NewMerkle(t.Depth())
+	if index < len(t.leaves) {
+		witness.Leaf.Assign(t.leaves[index])
+	} else {
+		witness.Leaf.Assign(0)
+	}
+	for i := range path {
+		witness.Path[i].Assign(path[i])
+		if directions[i] {
+			witness.Directions[i].Assign(1)
+		} else {
+			witness.Directions[i].Assign(0)
+		}
+	}
+	witness.Root.Assign(t.Root())
+	return witness, nil
+}
+
+// hashNodes returns mimc(left, right), as the Merkle circuit hashes inner nodes
+func hashNodes(left, right []byte) []byte {
+	hFunc := mimc.NewMiMC(Seed)
+	hFunc.Write(left)
+	hFunc.Write(right)
+	return hFunc.Sum(make([]byte, 0, fr.Bytes))
+}
@@ -0,0 +1,186 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// NonMembershipDepth is the depth of the registered "non-membership" circuit (up to 2^NonMembershipDepth - 2 elements)
+const NonMembershipDepth = 4
+
+// ElementBits is the size of the elements of a SortedSet, ChunkSize bytes
+const ElementBits = ChunkSize * 8
+
+var (
+	// ErrMember is returned when building a non-membership witness for an element of the set
+	ErrMember = errors.New("element is in the set")
+	// ErrInvalidElement is returned for set elements, or non-membership candidates, that are
+	// empty (0), longer than ChunkSize bytes, or the high sentinel
+	ErrInvalidElement = errors.New("elements must be 1 to 31 bytes long, not 0 nor the high sentinel")
+)
+
+// NonMembership defines a non-membership proof: the private element is not in the set of public
+// root, a SortedSet
+// The set is a Merkle tree of its sorted elements, between the sentinels 0 and 2^ElementBits - 1;
+// the proof opens two adjacent leaves low and high such that low < element < high: as the leaves
+// are sorted, no leaf equals element.
+type NonMembership struct {
+	Element        frontend.Variable
+	Low, High      frontend.Variable
+	LowPath        []frontend.Variable
+	LowDirections  []frontend.Variable
+	HighPath       []frontend.Variable
+	HighDirections []frontend.Variable
+	Root           frontend.Variable `gnark:",public"`
+}
+
+// NewNonMembership returns a NonMembership circuit definition for sets of the given depth
+func NewNonMembership(depth int) *NonMembership {
+	return &NonMembership{
+		LowPath:        make([]frontend.Variable, depth),
+		LowDirections:  make([]frontend.Variable, depth),
+		HighPath:       make([]frontend.Variable, depth),
+		HighDirections: make([]frontend.Variable, depth),
+	}
+}
+
+// Define declares the circuit's constraints
+// assert low and high are adjacent leaves of root && low < element < high
+func (circuit *NonMembership) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	for _, leaf := range []struct {
+		value            frontend.Variable
+		path, directions []frontend.Variable
+	}{{circuit.Low, circuit.LowPath, circuit.LowDirections}, {circuit.High, circuit.HighPath, circuit.HighDirections}} {
+		root, err := MerkleRoot(curveID, cs, leaf.value, leaf.path, leaf.directions)
+		if err != nil {
+			return err
+		}
+		cs.AssertIsEqual(root, circuit.Root)
+	}
+
+	// the directions are the bits of the leaf index, least significant first
+	cs.AssertIsEqual(cs.Add(cs.FromBinary(circuit.LowDirections...), 1), cs.FromBinary(circuit.HighDirections...))
+
+	// the values are ElementBits numbers, so that the comparisons are the ones of integers
+	for _, v := range []frontend.Variable{circuit.Element, circuit.Low, circuit.High} {
+		cs.ToBinary(v, ElementBits)
+	}
+	cs.AssertIsLessOrEqual(cs.Add(circuit.Low, 1), circuit.Element)
+	cs.AssertIsLessOrEqual(cs.Add(circuit.Element, 1), circuit.High)
+	return nil
+}
+
+// SortedSet is the host-side counterpart of the NonMembership circuit: a Merkle tree (MerkleTree)
+// of the sorted elements, between the sentinels
+type SortedSet struct {
+	tree *MerkleTree
+	// leaves are the sorted elements, with the sentinels, as numbers
+	leaves []*big.Int
+}
+
+// highSentinel is the last leaf of a SortedSet, 2^ElementBits - 1
+var highSentinel = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), ElementBits), big.NewInt(1))
+
+// NewSortedSet builds the set of depth over elements, read as big-endian numbers; duplicates are
+// ignored, and a set of depth d holds up to 2^d - 2 elements
+func NewSortedSet(depth int, elements [][]byte) (*SortedSet, error) {
+	leaves := []*big.Int{new(big.Int)}
+	for _, e := range elements {
+		v, err := elementOf(e)
+		if err != nil {
+			return nil, fmt.Errorf("%x: %w", e, err)
+		}
+		leaves = append(leaves, v)
+	}
+	sort.Slice(leaves, func(i, j int) bool { return leaves[i].Cmp(leaves[j]) < 0 })
+	unique := leaves[:1]
+	for _, v := range leaves[1:] {
+		if v.Cmp(unique[len(unique)-1]) != 0 {
+			unique = append(unique, v)
+		}
+	}
+	leaves = append(unique, highSentinel)
+
+	data := make([][]byte, len(leaves))
+	for i, v := range leaves {
+		data[i] = v.Bytes()
+	}
+	tree, err := NewMerkleTree(depth, data)
+	if err != nil {
+		return nil, err
+	}
+	return &SortedSet{tree: tree, leaves: leaves}, nil
+}
+
+// Root returns the root of the set, the public input of the NonMembership statement
+func (s *SortedSet) Root() []byte {
+	return s.tree.Root()
+}
+
+// Contains returns true if element is in the set
+func (s *SortedSet) Contains(element []byte) bool {
+	v, err := elementOf(element)
+	if err != nil {
+		return false
+	}
+	i := s.search(v)
+	return i < len(s.leaves) && s.leaves[i].Cmp(v) == 0
+}
+
+// NonMembershipWitness returns a full NonMembership assignment proving that element is not in the set
+func (s *SortedSet) NonMembershipWitness(element []byte) (*NonMembership, error) {
+	v, err := elementOf(element)
+	if err != nil {
+		return nil, err
+	}
+	high := s.search(v)
+	if s.leaves[high].Cmp(v) == 0 {
+		return nil, ErrMember
+	}
+
+	witness := NewNonMembership(s.tree.Depth())
+	witness.Element.Assign(v)
+	witness.Root.Assign(s.Root())
+	for _, leaf := range []struct {
+		index            int
+		value            *frontend.Variable
+		path, directions []frontend.Variable
+	}{{high - 1, &witness.Low, witness.LowPath, witness.LowDirections}, {high, &witness.High, witness.HighPath, witness.HighDirections}} {
+		path, directions, err := s.tree.Path(leaf.index)
+		if err != nil {
+			return nil, err
+		}
+		leaf.value.Assign(s.leaves[leaf.index])
+		for i := range path {
+			leaf.path[i].Assign(path[i])
+			if directions[i] {
+				leaf.directions[i].Assign(1)
+			} else {
+				leaf.directions[i].Assign(0)
+			}
+		}
+	}
+	return witness, nil
+}
+
+// search returns the index of the first leaf >= v; v is between the sentinels
+func (s *SortedSet) search(v *big.Int) int {
+	return sort.Search(len(s.leaves), func(i int) bool { return s.leaves[i].Cmp(v) >= 0 })
+}
+
+// elementOf returns e as a number, if it can be a set element
+func elementOf(e []byte) (*big.Int, error) {
+	if len(e) > ChunkSize {
+		return nil, ErrInvalidElement
+	}
+	v := new(big.Int).SetBytes(e)
+	if v.Sign() == 0 || v.Cmp(highSentinel) == 0 {
+		return nil, ErrInvalidElement
+	}
+	return v, nil
+}
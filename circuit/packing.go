@@ -0,0 +1,108 @@
+package circuit
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// PackingBits is the size of the commitments of PackPublicInputs: the keccak256 digest is truncated
+// to its PackingBits low bits, so that it is a field element
+const PackingBits = 253
+
+// ErrInputNotInField is returned when packing values that are not field elements
+var ErrInputNotInField = errors.New("public input is not a field element")
+
+// PackPublicInputs returns the commitment to inputs the packed circuits expose as their only
+// public input, as the Solidity PublicInputs.pack computes it (see ethereum.ExportPacking):
+// keccak256(abi.encodePacked(inputs)), inputs being uint256 words, truncated to PackingBits bits
+func PackPublicInputs(inputs []*big.Int) (*big.Int, error) {
+	data := make([]byte, 0, 32*len(inputs))
+	for _, input := range inputs {
+		if input.Sign() < 0 || input.Cmp(fr.Modulus()) >= 0 {
+			return nil, ErrInputNotInField
+		}
+		var word [32]byte
+		input.FillBytes(word[:])
+		data = append(data, word[:]...)
+	}
+	commitment := new(big.Int).SetBytes(crypto.Keccak256(data))
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), PackingBits), big.NewInt(1))
+	return commitment.And(commitment, mask), nil
+}
+
+// PackInputs returns PackPublicInputs(inputs) in a circuit
+// Each input costs a 254 bits decomposition, and each 136 bytes (4 inputs) a keccak256 permutation,
+// ~150k constraints: packing trades proving time for verification gas. The decomposition of an
+// input is not unique below 2^254, the contract must refuse inputs that are not field elements
+// (as PublicInputs.pack does).
+func PackInputs(cs *frontend.ConstraintSystem, inputs ...frontend.Variable) frontend.Variable {
+	// big-endian 32 bytes words, bits least significant first in each byte
+	var bytes [][]frontend.Variable
+	for _, input := range inputs {
+		bits := append(cs.ToBinary(input, 254), constantWord(cs, 0, 2)...)
+		for i := 31; i >= 0; i-- {
+			bytes = append(bytes, bits[8*i:8*i+8])
+		}
+	}
+	digest := keccak256Bits(cs, bytes)
+
+	// the digest is big-endian: its least significant byte is the last one
+	var bits []frontend.Variable
+	for i := 31; i >= 0; i-- {
+		bits = append(bits, digest[i]...)
+	}
+	return fromBits(cs, bits[:PackingBits])
+}
+
+// PackedBatch is Batch with packed public inputs: the hashes are private, and the statement is
+// bound to them by the public commitment PackPublicInputs(hashes)
+// mimc(secrets[i]) = hashes[i], for each i < BatchSize, and PackInputs(hashes) = commitment
+type PackedBatch struct {
+	Secrets    [BatchSize]frontend.Variable
+	Hashes     [BatchSize]frontend.Variable
+	Commitment frontend.Variable `gnark:",public"`
+}
+
+// Define declares the circuit's constraints
+// assert mimc(secrets[i]) == hashes[i] && pack(hashes) == commitment
+func (circuit *PackedBatch) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	for i := 0; i < BatchSize; i++ {
+		mimc, err := mimc.NewMiMC(Seed, curveID, cs)
+		if err != nil {
+			return err
+		}
+		mimc.Write(circuit.Secrets[i])
+		hash := mimc.Sum()
+		cs.AssertIsEqual(hash, circuit.Hashes[i])
+	}
+	cs.AssertIsEqual(PackInputs(cs, circuit.Hashes[:]...), circuit.Commitment)
+
+	return nil
+}
+
+// NewPackedBatchWitness returns the PackedBatch assignment of BatchSize secrets
+func NewPackedBatchWitness(secrets [BatchSize][]byte) (*PackedBatch, error) {
+	var witness PackedBatch
+	hashes := make([]*big.Int, BatchSize)
+	for i, secret := range secrets {
+		hash, err := Hash(secret)
+		if err != nil {
+			return nil, err
+		}
+		witness.Secrets[i].Assign(secret)
+		witness.Hashes[i].Assign(hash)
+		hashes[i] = new(big.Int).SetBytes(hash)
+	}
+	commitment, err := PackPublicInputs(hashes)
+	if err != nil {
+		return nil, err
+	}
+	witness.Commitment.Assign(commitment)
+	return &witness, nil
+}
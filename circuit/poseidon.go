@@ -0,0 +1,53 @@
+package circuit
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/poseidon"
+)
+
+// PoseidonCircuit defines a pre-image knowledge proof with Poseidon instead of MiMC
+// poseidon(secret preImage) = public hash
+type PoseidonCircuit struct {
+	Secret frontend.Variable
+	Hash   frontend.Variable `gnark:",public"`
+}
+
+// Define declares the circuit's constraints
+// assert poseidon(secret) == hash
+func (circuit *PoseidonCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	// the poseidon parameters are defined over the BN254 scalar field
+	if curveID != ecc.BN254 {
+		return fmt.Errorf("poseidon circuit: unsupported curve %s", curveID)
+	}
+	hash := poseidon.HashInCircuit(cs, circuit.Secret)
+	cs.AssertIsEqual(hash, circuit.Hash)
+	return nil
+}
+
+// PoseidonHash returns poseidon(secret), the public input of the PoseidonCircuit statement
+func PoseidonHash(secret []byte) ([]byte, error) {
+	if len(secret) > fr.Bytes {
+		return nil, ErrSecretTooLong
+	}
+	var e fr.Element
+	e.SetBytes(secret)
+	hash := poseidon.Hash(e)
+	b := hash.Bytes()
+	return b[:], nil
+}
+
+// NewPoseidonWitness returns a full PoseidonCircuit assignment for secret
+func NewPoseidonWitness(secret []byte) (*PoseidonCircuit, error) {
+	hash, err := PoseidonHash(secret)
+	if err != nil {
+		return nil, err
+	}
+	var witness PoseidonCircuit
+	witness.Hash.Assign(hash)
+	witness.Secret.Assign(secret)
+	return &witness, nil
+}
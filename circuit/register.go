@@ -0,0 +1,131 @@
+package circuit
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gbotrel/gnark-workshop/circuits"
+)
+
+func init() {
+	circuits.Register("mimc", &Circuit{})
+	circuits.Register("batch", &Batch{})
+	circuits.Register("batch-packed", &PackedBatch{})
+	circuits.RegisterFactory("merkle", circuits.Sized("depth", MerkleDepth, 1, maxDepth, func(depth int) frontend.Circuit {
+		return NewMerkle(depth)
+	}))
+	circuits.Register("eddsa", &EdDSA{})
+	circuits.Register("mimc-n", &CircuitN{})
+	circuits.Register("poseidon", &PoseidonCircuit{})
+	circuits.Register("sha256", &SHA256Circuit{})
+	circuits.Register("keccak256", &Keccak256Circuit{})
+	circuits.RegisterFactory("non-membership", circuits.Sized("depth", NonMembershipDepth, 1, maxDepth, func(depth int) frontend.Circuit {
+		return NewNonMembership(depth)
+	}))
+}
+
+// maxDepth bounds the depth of the tree circuits, as NewMerkleTree: their examples build the whole tree
+const maxDepth = 24
+
+// Example returns the assignment of the workshop demo: mimc("secret") = hash
+func (circuit *Circuit) Example() (frontend.Circuit, error) {
+	return NewWitness([]byte("secret"))
+}
+
+// WithSecret returns the assignment proving the knowledge of the pre-image secret of its hash
+func (circuit *Circuit) WithSecret(secret []byte) (frontend.Circuit, error) {
+	return NewWitness(secret)
+}
+
+// Example returns an assignment for BatchSize secrets
+func (circuit *Batch) Example() (frontend.Circuit, error) {
+	var witness Batch
+	for i := 0; i < BatchSize; i++ {
+		secret := []byte(fmt.Sprintf("secret-%d", i))
+		hash, err := Hash(secret)
+		if err != nil {
+			return nil, err
+		}
+		witness.Secrets[i].Assign(secret)
+		witness.Hashes[i].Assign(hash)
+	}
+	return &witness, nil
+}
+
+// Example returns an assignment for BatchSize secrets, as Batch
+func (circuit *PackedBatch) Example() (frontend.Circuit, error) {
+	var secrets [BatchSize][]byte
+	for i := range secrets {
+		secrets[i] = []byte(fmt.Sprintf("secret-%d", i))
+	}
+	return NewPackedBatchWitness(secrets)
+}
+
+// Example returns an assignment proving that "bob" is in a tree of workshop attendees
+func (circuit *Merkle) Example() (frontend.Circuit, error) {
+	leaves := [][]byte{[]byte("alice"), []byte("bob"), []byte("carol"), []byte("dave")}
+	tree, err := NewMerkleTree(len(circuit.Path), leaves)
+	if err != nil {
+		return nil, err
+	}
+	return tree.Witness(1)
+}
+
+// Example returns an assignment for a signature of "hello gnark" under a fresh key
+func (circuit *EdDSA) Example() (frontend.Circuit, error) {
+	key, err := GenerateSigningKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	msg := []byte("hello gnark")
+	sig, err := Sign(key, msg)
+	if err != nil {
+		return nil, err
+	}
+	return NewEdDSAWitness(key.PublicKey, msg, sig)
+}
+
+// Example returns the assignment for a message spanning several chunks
+func (circuit *CircuitN) Example() (frontend.Circuit, error) {
+	return NewWitnessN([]byte("a secret too long to fit in a single field element"))
+}
+
+// WithSecret returns the assignment for secret, split in chunks
+func (circuit *CircuitN) WithSecret(secret []byte) (frontend.Circuit, error) {
+	return NewWitnessN(secret)
+}
+
+// Example returns the assignment of the workshop demo, with poseidon: poseidon("secret") = hash
+func (circuit *PoseidonCircuit) Example() (frontend.Circuit, error) {
+	return NewPoseidonWitness([]byte("secret"))
+}
+
+// WithSecret returns the assignment proving the knowledge of the pre-image secret of its poseidon hash
+func (circuit *PoseidonCircuit) WithSecret(secret []byte) (frontend.Circuit, error) {
+	return NewPoseidonWitness(secret)
+}
+
+// Example returns an assignment for a 32 bytes secret
+func (circuit *SHA256Circuit) Example() (frontend.Circuit, error) {
+	secret := make([]byte, SHA256PreimageLen)
+	copy(secret, "secret")
+	return NewSHA256Witness(secret)
+}
+
+// Example returns an assignment for the storage slot of balances[0x...01] (balances declared at slot 0)
+func (circuit *Keccak256Circuit) Example() (frontend.Circuit, error) {
+	return NewKeccak256Witness(MappingSlotPreimage(common.BigToHash(big.NewInt(1)), 0))
+}
+
+// Example returns an assignment proving that "alice" is not on a sanctions list
+func (circuit *NonMembership) Example() (frontend.Circuit, error) {
+	list := [][]byte{[]byte("mallory"), []byte("trudy"), []byte("eve"), []byte("oscar")}
+	set, err := NewSortedSet(len(circuit.LowPath), list)
+	if err != nil {
+		return nil, err
+	}
+	return set.NonMembershipWitness([]byte("alice"))
+}
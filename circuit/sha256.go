@@ -0,0 +1,142 @@
+package circuit
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// SHA256PreimageLen is the length in bytes of the pre-image of the SHA256Circuit
+const SHA256PreimageLen = 32
+
+// SHA256Circuit defines a pre-image knowledge proof with SHA256
+// sha256(secret bytes) = public digest
+//
+// SHA256 works on bits: the pre-image bytes are decomposed, and every XOR, AND and addition
+// costs constraints, unlike MiMC which is native to the field; compare the constraint counts.
+// The 256-bit digest doesn't fit in a field element: it is public as two 128-bit halves
+// (see SHA256PublicInputs).
+type SHA256Circuit struct {
+	Secret   [SHA256PreimageLen]frontend.Variable
+	DigestHi frontend.Variable `gnark:",public"`
+	DigestLo frontend.Variable `gnark:",public"`
+}
+
+// Define declares the circuit's constraints
+// assert sha256(secret) == digestHi || digestLo
+func (circuit *SHA256Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	digest := sha256Gadget(cs, circuit.Secret[:])
+
+	// words are big-endian in the digest, bits little-endian in the words
+	var hi, lo []frontend.Variable
+	for i := 3; i >= 0; i-- {
+		hi = append(hi, digest[i]...)
+		lo = append(lo, digest[i+4]...)
+	}
+	cs.AssertIsEqual(cs.FromBinary(hi...), circuit.DigestHi)
+	cs.AssertIsEqual(cs.FromBinary(lo...), circuit.DigestLo)
+
+	return nil
+}
+
+// SHA256PublicInputs splits digest in its big-endian 128-bit halves (hi, lo), the public inputs
+// of the SHA256Circuit statement
+func SHA256PublicInputs(digest [sha256.Size]byte) (hi, lo []byte) {
+	return digest[:16], digest[16:]
+}
+
+// NewSHA256Witness returns a full SHA256Circuit assignment for secret
+func NewSHA256Witness(secret []byte) (*SHA256Circuit, error) {
+	if len(secret) != SHA256PreimageLen {
+		return nil, fmt.Errorf("sha256 pre-image must be %d bytes long", SHA256PreimageLen)
+	}
+	var witness SHA256Circuit
+	for i, b := range secret {
+		witness.Secret[i].Assign(int(b))
+	}
+	hi, lo := SHA256PublicInputs(sha256.Sum256(secret))
+	witness.DigestHi.Assign(hi)
+	witness.DigestLo.Assign(lo)
+	return &witness, nil
+}
+
+var sha256K = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+var sha256IV = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+// sha256Gadget returns the 8 words of sha256(msg), msg being byte variables of fixed length
+// The padding depends on the length only: it is made of constants.
+func sha256Gadget(cs *frontend.ConstraintSystem, msg []frontend.Variable) [8][]frontend.Variable {
+	bytes := bytesToBits(cs, msg)
+
+	// padding: 0x80, zeros, then the bit length on 8 bytes
+	nbBlocks := (len(msg) + 9 + 63) / 64
+	bitLen := uint64(len(msg)) * 8
+	bytes = append(bytes, constantWord(cs, 0x80, 8))
+	for len(bytes) < nbBlocks*64-8 {
+		bytes = append(bytes, constantWord(cs, 0, 8))
+	}
+	for i := 7; i >= 0; i-- {
+		bytes = append(bytes, constantWord(cs, (bitLen>>(8*i))&0xff, 8))
+	}
+
+	var h [8][]frontend.Variable
+	for i := range h {
+		h[i] = constantWord(cs, uint64(sha256IV[i]), 32)
+	}
+
+	for block := 0; block < nbBlocks; block++ {
+		var w [64][]frontend.Variable
+		for t := 0; t < 16; t++ {
+			// big-endian word from 4 bytes, least significant bit first
+			b := bytes[block*64+4*t : block*64+4*t+4]
+			w[t] = append(append(append(append([]frontend.Variable{}, b[3]...), b[2]...), b[1]...), b[0]...)
+		}
+		for t := 16; t < 64; t++ {
+			s0 := xorWords(cs, rotr(w[t-15], 7), rotr(w[t-15], 18), shr(cs, w[t-15], 3))
+			s1 := xorWords(cs, rotr(w[t-2], 17), rotr(w[t-2], 19), shr(cs, w[t-2], 10))
+			w[t] = addWords(cs, w[t-16], s0, w[t-7], s1)
+		}
+
+		a, b, c, d, e, f, g, hh := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+		for t := 0; t < 64; t++ {
+			s1 := xorWords(cs, rotr(e, 6), rotr(e, 11), rotr(e, 25))
+			ch := make([]frontend.Variable, 32)
+			for i := range ch {
+				// ch = e ? f : g
+				ch[i] = cs.Add(g[i], cs.Mul(e[i], cs.Sub(f[i], g[i])))
+			}
+			s0 := xorWords(cs, rotr(a, 2), rotr(a, 13), rotr(a, 22))
+			maj := make([]frontend.Variable, 32)
+			for i := range maj {
+				// maj = ab + c(a xor b)
+				ab := cs.Mul(a[i], b[i])
+				maj[i] = cs.Add(ab, cs.Mul(c[i], cs.Sub(cs.Add(a[i], b[i]), cs.Mul(ab, 2))))
+			}
+			k := constantWord(cs, uint64(sha256K[t]), 32)
+
+			// e' = d + temp1, a' = temp1 + temp2, summed at once
+			newE := addWords(cs, d, hh, s1, ch, k, w[t])
+			newA := addWords(cs, hh, s1, ch, k, w[t], s0, maj)
+			hh, g, f, e = g, f, e, newE
+			d, c, b, a = c, b, a, newA
+		}
+		for i, v := range [8][]frontend.Variable{a, b, c, d, e, f, g, hh} {
+			h[i] = addWords(cs, h[i], v)
+		}
+	}
+	return h
+}
@@ -0,0 +1,38 @@
+package circuit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+func TestSHA256Circuit(t *testing.T) {
+	assert := groth16.NewAssert(t)
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &SHA256Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := bytes.Repeat([]byte{0xa5}, SHA256PreimageLen)
+	witness, err := NewSHA256Witness(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.SolvingSucceeded(r1cs, witness)
+
+	// the digest of another pre-image
+	other, err := NewSHA256Witness(bytes.Repeat([]byte{0x5a}, SHA256PreimageLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wrong SHA256Circuit
+	for i, b := range secret {
+		wrong.Secret[i].Assign(int(b))
+	}
+	wrong.DigestHi, wrong.DigestLo = other.DigestHi, other.DigestLo
+	assert.SolvingFailed(r1cs, &wrong)
+}
@@ -0,0 +1,64 @@
+package circuit
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/hash"
+	stdeddsa "github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// ErrMessageTooLong is returned for messages that don't fit in a single field element
+var ErrMessageTooLong = errors.New("message must be at most fr.Bytes long")
+
+// GenerateSigningKey returns a new EdDSA key for the EdDSA circuit, reading randomness from r
+func GenerateSigningKey(r io.Reader) (eddsa.PrivateKey, error) {
+	return eddsa.GenerateKey(r)
+}
+
+// Sign returns the EdDSA signature of msg the EdDSA circuit verifies
+// msg is a single field element, at most fr.Bytes long.
+// gnark's in-circuit verifier hashes with mimc seeded with "seed", which is Seed.
+func Sign(key eddsa.PrivateKey, msg []byte) ([]byte, error) {
+	if len(msg) > fr.Bytes {
+		return nil, ErrMessageTooLong
+	}
+	return key.Sign(msg, hash.MIMC_BN254.New(Seed))
+}
+
+// NewEdDSAWitness returns a full EdDSA assignment for the signature sig of msg under publicKey
+func NewEdDSAWitness(publicKey eddsa.PublicKey, msg, sig []byte) (*EdDSA, error) {
+	if len(msg) > fr.Bytes {
+		return nil, ErrMessageTooLong
+	}
+	var witness EdDSA
+	AssignPublicKey(&witness.PublicKey, publicKey)
+	if err := AssignSignature(&witness.Signature, sig); err != nil {
+		return nil, err
+	}
+	witness.Message.Assign(msg)
+	return &witness, nil
+}
+
+// AssignPublicKey assigns the point of publicKey to the in-circuit key p
+func AssignPublicKey(p *stdeddsa.PublicKey, publicKey eddsa.PublicKey) {
+	p.A.X.Assign(publicKey.A.X.ToBigIntRegular(new(big.Int)))
+	p.A.Y.Assign(publicKey.A.Y.ToBigIntRegular(new(big.Int)))
+}
+
+// AssignSignature assigns the serialized signature sig to the in-circuit signature s: R, and S
+// split in its big-endian 128-bit halves S1 and S2, as gnark's verifier recombines it
+func AssignSignature(s *stdeddsa.Signature, sig []byte) error {
+	var signature eddsa.Signature
+	if _, err := signature.SetBytes(sig); err != nil {
+		return err
+	}
+	s.R.X.Assign(signature.R.X.ToBigIntRegular(new(big.Int)))
+	s.R.Y.Assign(signature.R.Y.ToBigIntRegular(new(big.Int)))
+	s.S1.Assign(new(big.Int).SetBytes(signature.S[:16]))
+	s.S2.Assign(new(big.Int).SetBytes(signature.S[16:]))
+	return nil
+}
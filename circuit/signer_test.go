@@ -0,0 +1,59 @@
+package circuit
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+func TestEdDSASignProveVerify(t *testing.T) {
+	key, err := GenerateSigningKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("transfer 42")
+	sig, err := Sign(key, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness, err := NewEdDSAWitness(key.PublicKey, msg, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &EdDSA{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, witness); err != nil {
+		t.Fatal(err)
+	}
+
+	// the signature of another message doesn't prove this one
+	tampered, err := NewEdDSAWitness(key.PublicKey, []byte("transfer 43"), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := groth16.Prove(r1cs, pk, tampered); err == nil {
+		t.Fatal("proved a signature of another message")
+	}
+	if err := groth16.Verify(proof, vk, tampered); err == nil {
+		t.Fatal("the proof verifies for another message")
+	}
+
+	if _, err := Sign(key, make([]byte, 33)); err != ErrMessageTooLong {
+		t.Fatalf("expected ErrMessageTooLong, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package age is the age circuit, a selective disclosure of a birth year, and its host helpers.
+package age
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/gbotrel/gnark-workshop/circuit"
+)
+
+// AttributeBits is the size of the attributes of Circuit: years, ages and scores fit in 16 bits
+const AttributeBits = 16
+
+// ErrThresholdNotMet is returned when building a witness for an attribute that doesn't satisfy
+// the disclosed comparison: no proof exists for it
+var ErrThresholdNotMet = errors.New("attribute doesn't satisfy the threshold")
+
+// Circuit defines a selective disclosure of a birth year: the holder of a credential committing
+// to their birth year proves being born in or before a public year (e.g. this year - 18), without
+// disclosing the year itself
+// mimc(secret birthYear, secret blinding) = public commitment, birthYear <= public maxBirthYear
+//
+// The commitment is the one the credential issuer signed (see circuit.EdDSA): it binds the holder to their
+// birth year, the blinding keeps it from being brute forced over the few possible years.
+type Circuit struct {
+	BirthYear    frontend.Variable
+	Blinding     frontend.Variable
+	Commitment   frontend.Variable `gnark:",public"`
+	MaxBirthYear frontend.Variable `gnark:",public"`
+}
+
+// Define declares the circuit's constraints
+// assert mimc(birthYear, blinding) == commitment && birthYear <= maxBirthYear
+func (c *Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	mimc, err := mimc.NewMiMC(circuit.Seed, curveID, cs)
+	if err != nil {
+		return err
+	}
+	mimc.Write(c.BirthYear, c.Blinding)
+	commitment := mimc.Sum()
+	cs.AssertIsEqual(commitment, c.Commitment)
+
+	// both sides are AttributeBits numbers, so that the comparison is the one of integers, not
+	// of field elements
+	cs.ToBinary(c.BirthYear, AttributeBits)
+	cs.ToBinary(c.MaxBirthYear, AttributeBits)
+	cs.AssertIsLessOrEqual(c.BirthYear, c.MaxBirthYear)
+	return nil
+}
+
+// NewBlinding returns a random blinding of circuit.ChunkSize bytes, drawn by the issuer for each credential
+// (e.g. from crypto/rand.Reader)
+func NewBlinding(rand io.Reader) ([]byte, error) {
+	blinding := make([]byte, circuit.ChunkSize)
+	if _, err := io.ReadFull(rand, blinding); err != nil {
+		return nil, err
+	}
+	return blinding, nil
+}
+
+// Commitment returns mimc(value, blinding), the commitment to an attribute a credential holds
+func Commitment(value uint16, blinding []byte) ([]byte, error) {
+	if len(blinding) > circuit.ChunkSize {
+		return nil, errors.New("blinding must be at most 31 bytes long")
+	}
+	return circuit.HashElements(big.NewInt(int64(value)).Bytes(), blinding)
+}
+
+// NewWitness returns a full Circuit assignment disclosing that birthYear <= maxBirthYear,
+// for the credential committing to birthYear with blinding
+func NewWitness(birthYear uint16, blinding []byte, maxBirthYear uint16) (*Circuit, error) {
+	if birthYear > maxBirthYear {
+		return nil, ErrThresholdNotMet
+	}
+	commitment, err := Commitment(birthYear, blinding)
+	if err != nil {
+		return nil, err
+	}
+	var witness Circuit
+	witness.BirthYear.Assign(int(birthYear))
+	witness.Blinding.Assign(blinding)
+	witness.Commitment.Assign(commitment)
+	witness.MaxBirthYear.Assign(int(maxBirthYear))
+	return &witness, nil
+}
@@ -0,0 +1,21 @@
+package age
+
+import (
+	"crypto/rand"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuits"
+)
+
+func init() {
+	circuits.Register("age", &Circuit{})
+}
+
+// Example returns an assignment disclosing that a holder born in 1990 is born in or before 2005
+func (c *Circuit) Example() (frontend.Circuit, error) {
+	blinding, err := NewBlinding(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewWitness(1990, blinding, 2005)
+}
@@ -0,0 +1,10 @@
+// Package all registers every workshop circuit: the circuits of package circuit and the ones with
+// their own package in circuits/<name>. Binaries import it for its side effects.
+package all
+
+import (
+	_ "github.com/gbotrel/gnark-workshop/circuit"             // mimc, merkle, eddsa, keccak256...
+	_ "github.com/gbotrel/gnark-workshop/circuits/age"        // age
+	_ "github.com/gbotrel/gnark-workshop/circuits/hash-chain" // hash-chain
+	_ "github.com/gbotrel/gnark-workshop/circuits/password"   // password
+)
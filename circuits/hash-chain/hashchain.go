@@ -0,0 +1,82 @@
+// Package hashchain is the hash-chain circuit, a sequential work proof, and its host helpers.
+package hashchain
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/gbotrel/gnark-workshop/circuit"
+)
+
+// Iterations is the number of iterations of the registered "hash-chain" circuit
+const Iterations = 64
+
+// ErrInvalidIterations is returned for hash chains of less than one iteration
+var ErrInvalidIterations = errors.New("a hash chain has at least one iteration")
+
+// Circuit defines a sequential work proof: n MiMC iterations of a secret
+// mimc(mimc(...mimc(secret))) = public head
+//
+// The iterations can't be computed in parallel, and each adds the constraints of one MiMC: the
+// circuit grows linearly with n, the number of iterations, set by New at compile time.
+type Circuit struct {
+	Secret frontend.Variable
+	Head   frontend.Variable `gnark:",public"`
+
+	iterations int
+}
+
+// New returns a Circuit definition of iterations iterations
+func New(iterations int) *Circuit {
+	return &Circuit{iterations: iterations}
+}
+
+// Define declares the circuit's constraints
+// assert mimc^n(secret) == head
+func (c *Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	if c.iterations < 1 {
+		return ErrInvalidIterations
+	}
+	node := c.Secret
+	for i := 0; i < c.iterations; i++ {
+		// fresh hash function for each iteration
+		hFunc, err := mimc.NewMiMC(circuit.Seed, curveID, cs)
+		if err != nil {
+			return err
+		}
+		hFunc.Write(node)
+		node = hFunc.Sum()
+	}
+	cs.AssertIsEqual(node, c.Head)
+
+	return nil
+}
+
+// Head returns mimc^iterations(secret), as Circuit computes it
+func Head(secret []byte, iterations int) ([]byte, error) {
+	if iterations < 1 {
+		return nil, ErrInvalidIterations
+	}
+	head := secret
+	for i := 0; i < iterations; i++ {
+		var err error
+		if head, err = circuit.Hash(head); err != nil {
+			return nil, err
+		}
+	}
+	return head, nil
+}
+
+// NewWitness returns the assignment of a Circuit of iterations iterations for secret
+func NewWitness(secret []byte, iterations int) (*Circuit, error) {
+	head, err := Head(secret, iterations)
+	if err != nil {
+		return nil, err
+	}
+	witness := New(iterations)
+	witness.Secret.Assign(secret)
+	witness.Head.Assign(head)
+	return witness, nil
+}
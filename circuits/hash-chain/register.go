@@ -0,0 +1,17 @@
+package hashchain
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuits"
+)
+
+func init() {
+	circuits.RegisterFactory("hash-chain", circuits.Sized("n", Iterations, 1, 1<<20, func(n int) frontend.Circuit {
+		return New(n)
+	}))
+}
+
+// Example returns the assignment of a chain of "secret"
+func (c *Circuit) Example() (frontend.Circuit, error) {
+	return NewWitness([]byte("secret"), c.iterations)
+}
@@ -0,0 +1,228 @@
+package circuits
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+var tVariable = reflect.TypeOf(frontend.Variable{})
+
+// FromJSON returns an assignment of the circuit registered under name, read from a JSON document
+// The document mirrors the circuit struct: an object per struct, keyed by the gnark tag name
+// (the field name if the tag has none), an array per slice or array, and for each
+// frontend.Variable a number or a string (decimal, or hexadecimal with a 0x prefix).
+// Missing and unknown fields are errors, so that a typo doesn't silently prove a zero.
+func FromJSON(name string, data []byte) (frontend.Circuit, error) {
+	return fromJSON(name, data, false)
+}
+
+// PublicFromJSON is FromJSON for a document holding the public variables only (to verify a proof);
+// the secret variables of the returned assignment are not set.
+func PublicFromJSON(name string, data []byte) (frontend.Circuit, error) {
+	return fromJSON(name, data, true)
+}
+
+func fromJSON(name string, data []byte, publicOnly bool) (frontend.Circuit, error) {
+	c, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("witness: %w", err)
+	}
+	r := jsonReader{publicOnly: publicOnly}
+	if err := r.read(reflect.ValueOf(c).Elem(), doc, "", false); err != nil {
+		return nil, fmt.Errorf("witness of circuit %q: %w", name, err)
+	}
+	return c, nil
+}
+
+type jsonReader struct {
+	publicOnly bool
+}
+
+// read assigns v from doc; path locates v in error messages
+func (r jsonReader) read(v reflect.Value, doc interface{}, path string, public bool) error {
+	if v.Type() == tVariable {
+		if r.publicOnly && !public {
+			return nil
+		}
+		value, err := parseValue(doc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", displayPath(path), err)
+		}
+		v.Addr().Interface().(*frontend.Variable).Assign(value)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object", displayPath(path))
+		}
+		seen := make(map[string]bool)
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			name, fieldPublic, skip := parseTag(f, public)
+			if skip || !v.Field(i).CanSet() || !hasVariable(f.Type) {
+				continue
+			}
+			if r.publicOnly && !fieldPublic && !hasPublic(f.Type, v.Field(i)) {
+				continue
+			}
+			child, ok := obj[name]
+			if !ok {
+				return fmt.Errorf("%s: missing", displayPath(join(path, name)))
+			}
+			seen[name] = true
+			if err := r.read(v.Field(i), child, join(path, name), fieldPublic); err != nil {
+				return err
+			}
+		}
+		var extra []string
+		for name := range obj {
+			if !seen[name] {
+				extra = append(extra, join(path, name))
+			}
+		}
+		if len(extra) != 0 {
+			sort.Strings(extra)
+			return fmt.Errorf("unknown fields %s", strings.Join(extra, ", "))
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		arr, ok := doc.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an array", displayPath(path))
+		}
+		if len(arr) != v.Len() {
+			return fmt.Errorf("%s: expected %d elements, got %d", displayPath(path), v.Len(), len(arr))
+		}
+		for i := range arr {
+			if err := r.read(v.Index(i), arr[i], fmt.Sprintf("%s[%d]", path, i), public); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// not a variable container (e.g. a constant parameter of the circuit)
+	return nil
+}
+
+// parseTag returns the JSON name of f, its visibility (inherited from its parent if the tag
+// doesn't set one), and whether gnark ignores it
+func parseTag(f reflect.StructField, parentPublic bool) (name string, public, skip bool) {
+	name, public = f.Name, parentPublic
+	tag, ok := f.Tag.Lookup("gnark")
+	if !ok {
+		return name, public, false
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	if parts[0] != "" {
+		name = parts[0]
+	}
+	for _, opt := range parts[1:] {
+		switch strings.TrimSpace(opt) {
+		case "public":
+			public = true
+		case "secret":
+			public = false
+		}
+	}
+	return name, public, false
+}
+
+// hasPublic returns true if a variable below v (of type t) is tagged public
+func hasPublic(t reflect.Type, v reflect.Value) bool {
+	if t == tVariable {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			_, public, skip := parseTag(t.Field(i), false)
+			if skip {
+				continue
+			}
+			if public || hasPublic(t.Field(i).Type, v.Field(i)) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if hasPublic(t.Elem(), v.Index(i)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasVariable returns true if t holds Variables: constant parameters of the circuit (e.g. a depth)
+// are not in the document
+func hasVariable(t reflect.Type) bool {
+	if t == tVariable {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if _, _, skip := parseTag(t.Field(i), false); !skip && hasVariable(t.Field(i).Type) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		return hasVariable(t.Elem())
+	}
+	return false
+}
+
+// parseValue reads a JSON number, or a decimal / 0x-prefixed hexadecimal string
+func parseValue(doc interface{}) (*big.Int, error) {
+	var s string
+	switch d := doc.(type) {
+	case json.Number:
+		s = d.String()
+	case string:
+		s = d
+	default:
+		return nil, fmt.Errorf("expected a number or a string, got %T", doc)
+	}
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s, base = s[2:], 16
+	}
+	value, ok := new(big.Int).SetString(s, base)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid value %q", s)
+	}
+	return value, nil
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "witness"
+	}
+	return path
+}
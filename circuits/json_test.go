@@ -0,0 +1,67 @@
+package circuits
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// fuzzCircuit has the shapes of the workshop circuits: public and secret variables, arrays,
+// slices, nested structs, renamed and ignored fields
+type fuzzCircuit struct {
+	Hash    frontend.Variable `gnark:"hash,public"`
+	Secrets [2]frontend.Variable
+	Path    []frontend.Variable
+	Key     struct {
+		X, Y frontend.Variable `gnark:",public"`
+	}
+	Ignored frontend.Variable `gnark:"-"`
+	Depth   int
+}
+
+func (c *fuzzCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	return nil
+}
+
+func init() {
+	Register("fuzz", &fuzzCircuit{Path: make([]frontend.Variable, 3)})
+}
+
+func TestFromJSON(t *testing.T) {
+	full := `{"hash": "0x2a", "Secrets": [1, "2"], "Path": [3, 4, 5], "Key": {"X": 6, "Y": 7}}`
+	if _, err := FromJSON("fuzz", []byte(full)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PublicFromJSON("fuzz", []byte(`{"hash": 42, "Key": {"X": 6, "Y": 7}}`)); err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []string{
+		`{"hash": 42}`,
+		`{"hash": "forty-two", "Secrets": [1, 2], "Path": [3, 4, 5], "Key": {"X": 6, "Y": 7}}`,
+		`{"hash": 42, "Secrets": [1, 2], "Path": [3, 4], "Key": {"X": 6, "Y": 7}}`,
+		`{"hash": -1, "Secrets": [1, 2], "Path": [3, 4, 5], "Key": {"X": 6, "Y": 7}}`,
+	} {
+		if _, err := FromJSON("fuzz", []byte(doc)); err == nil {
+			t.Errorf("%s: loaded, expected an error", doc)
+		}
+	}
+}
+
+// FuzzFromJSON checks that the JSON witness loader gives an assignment or an error for any input,
+// without panicking
+func FuzzFromJSON(f *testing.F) {
+	for _, seed := range []string{
+		`{"hash": "0x2a", "Secrets": [1, "2"], "Path": [3, 4, 5], "Key": {"X": 6, "Y": 7}}`,
+		`{"hash": 42, "Key": {"X": 6, "Y": 7}}`,
+		`{"hash": 1e3, "Secrets": [null, {}], "Path": [], "Key": []}`,
+		`[]`,
+		``,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = FromJSON("fuzz", data)
+		_, _ = PublicFromJSON("fuzz", data)
+	})
+}
@@ -0,0 +1,172 @@
+package circuits
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// ParamsSeparator separates the name of a parameterized circuit from its parameters in the name of
+// an instance, e.g. merkle@depth=8: instance names are accepted wherever circuit names are, and
+// name the artifacts of the instance
+const ParamsSeparator = "@"
+
+// ErrInvalidParams is returned for parameters a circuit doesn't take, or values it can't be built with
+var ErrInvalidParams = errors.New("invalid circuit parameters")
+
+// Params are the compile time parameters of a circuit (a tree depth, a message length...), by name
+// They size the circuit definition: circuits compiled with different parameters are different
+// circuits, with their own keys and verifier.
+type Params map[string]int
+
+// Factory builds the definitions of a parameterized circuit
+type Factory interface {
+	// Params returns the parameters of the circuit, with their default values
+	Params() Params
+	// New returns the circuit definition for params, every parameter being set
+	New(params Params) (frontend.Circuit, error)
+}
+
+// NewFactory returns a Factory of defaults, building the circuits with build
+func NewFactory(defaults Params, build func(Params) (frontend.Circuit, error)) Factory {
+	return factory{defaults: defaults, build: build}
+}
+
+type factory struct {
+	defaults Params
+	build    func(Params) (frontend.Circuit, error)
+}
+
+func (f factory) Params() Params {
+	params := make(Params, len(f.defaults))
+	for k, v := range f.defaults {
+		params[k] = v
+	}
+	return params
+}
+
+func (f factory) New(params Params) (frontend.Circuit, error) {
+	return f.build(params)
+}
+
+// Sized returns the Factory of a circuit with a single parameter, key, between min and max
+func Sized(key string, defaultValue, min, max int, build func(int) frontend.Circuit) Factory {
+	return NewFactory(Params{key: defaultValue}, func(params Params) (frontend.Circuit, error) {
+		v := params[key]
+		if v < min || v > max {
+			return nil, fmt.Errorf("%w: %s=%d, expected %d to %d", ErrInvalidParams, key, v, min, max)
+		}
+		return build(v), nil
+	})
+}
+
+// ParseParams parses comma separated key=value parameters, e.g. depth=8,n=16
+func ParseParams(s string) (Params, error) {
+	params := make(Params)
+	if s == "" {
+		return params, nil
+	}
+	for _, kv := range strings.Split(s, ",") {
+		i := strings.IndexByte(kv, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("%w: %q is not key=value", ErrInvalidParams, kv)
+		}
+		v, err := strconv.Atoi(kv[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidParams, kv[:i], err)
+		}
+		params[strings.TrimSpace(kv[:i])] = v
+	}
+	return params, nil
+}
+
+// String returns the parameters as ParseParams reads them, sorted by name
+func (p Params) String() string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]string, len(keys))
+	for i, k := range keys {
+		kvs[i] = k + "=" + strconv.Itoa(p[k])
+	}
+	return strings.Join(kvs, ",")
+}
+
+// Instance returns the canonical name of the instance of the circuit registered under name (itself
+// possibly an instance name) with params on top of its own: parameters equal to their default are
+// left out, so that the instance of the defaults is named as the circuit
+func Instance(name string, params Params) (string, error) {
+	base, own, err := splitInstance(name)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range params {
+		own[k] = v
+	}
+	lock.RLock()
+	f, ok := factories[base]
+	lock.RUnlock()
+	if !ok {
+		if len(own) != 0 {
+			return "", fmt.Errorf("%w: circuit %q takes no parameters", ErrInvalidParams, base)
+		}
+		return base, nil
+	}
+	resolved, err := resolve(base, f, own)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range f.Params() {
+		if resolved[k] == v {
+			delete(resolved, k)
+		}
+	}
+	if len(resolved) == 0 {
+		return base, nil
+	}
+	return base + ParamsSeparator + resolved.String(), nil
+}
+
+// ParamsOf returns the parameters of the instance name, defaults included; nil for circuits that
+// take none
+func ParamsOf(name string) (Params, error) {
+	base, params, err := splitInstance(name)
+	if err != nil {
+		return nil, err
+	}
+	lock.RLock()
+	f, ok := factories[base]
+	lock.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+	return resolve(base, f, params)
+}
+
+// splitInstance splits an instance name in the name of its circuit and its parameters
+func splitInstance(name string) (string, Params, error) {
+	i := strings.Index(name, ParamsSeparator)
+	if i == -1 {
+		return name, make(Params), nil
+	}
+	params, err := ParseParams(name[i+len(ParamsSeparator):])
+	return name[:i], params, err
+}
+
+// resolve completes params with the defaults of f, refusing unknown parameters
+func resolve(name string, f Factory, params Params) (Params, error) {
+	resolved := f.Params()
+	for k, v := range params {
+		if _, ok := resolved[k]; !ok {
+			return nil, fmt.Errorf("%w: circuit %q has no parameter %q (parameters: %s)", ErrInvalidParams, name, k, resolved)
+		}
+		resolved[k] = v
+	}
+	return resolved, nil
+}
@@ -0,0 +1,92 @@
+// Package password is the password circuit, a password knowledge proof, and its host helpers.
+package password
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/gbotrel/gnark-workshop/circuit"
+)
+
+// Domain is hashed before the salt and the password: password hashes are never the hash of a
+// circuit.Circuit pre-image, or of another application hashing salt || secret with mimc
+const Domain = "gnark-workshop/password/v1"
+
+// SaltLen is the length of the salts of NewSalt; as passwords, salts are at most circuit.ChunkSize
+// bytes so that they are field elements as is
+const SaltLen = circuit.ChunkSize
+
+// ErrTooLong is returned for passwords or salts longer than circuit.ChunkSize bytes
+var ErrTooLong = errors.New("password and salt must be at most 31 bytes long")
+
+// Circuit defines a password knowledge proof: the server stores the public salt and hash of each
+// user, the user proves knowing the password without sending it
+// mimc(domain, salt, secret password) = public hash
+//
+// Challenge is a public value chosen by the verifier for each login (e.g. a nonce): the proof is
+// bound to it, so that a proof can't be replayed for another login.
+type Circuit struct {
+	Salt      frontend.Variable `gnark:",public"`
+	Hash      frontend.Variable `gnark:",public"`
+	Challenge frontend.Variable `gnark:",public"`
+	Password  frontend.Variable
+}
+
+// Define declares the circuit's constraints
+// assert mimc(domain, salt, password) == hash
+func (c *Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	mimc, err := mimc.NewMiMC(circuit.Seed, curveID, cs)
+	if err != nil {
+		return err
+	}
+
+	mimc.Write(cs.Constant(domain()), c.Salt, c.Password)
+	hash := mimc.Sum()
+	cs.AssertIsEqual(hash, c.Hash)
+
+	// a public input that no constraint uses doesn't bind the proof
+	cs.Mul(c.Challenge, c.Challenge)
+	return nil
+}
+
+func domain() *big.Int {
+	return new(big.Int).SetBytes([]byte(Domain))
+}
+
+// NewSalt returns a random salt of SaltLen bytes, to draw once per user (e.g. from crypto/rand.Reader)
+func NewSalt(rand io.Reader) ([]byte, error) {
+	salt := make([]byte, SaltLen)
+	if _, err := io.ReadFull(rand, salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// Hash returns mimc(domain, salt, password), the hash the server stores with salt
+// Its own branches depend on len(password) only; the mimc reduction isn't constant time (see the
+// circuit package doc).
+func Hash(salt, password []byte) ([]byte, error) {
+	if len(salt) > circuit.ChunkSize || len(password) > circuit.ChunkSize {
+		return nil, ErrTooLong
+	}
+	return circuit.HashElements([]byte(Domain), salt, password)
+}
+
+// NewWitness returns a full Circuit assignment proving the knowledge of password for the
+// user with salt, answering challenge
+func NewWitness(salt, password, challenge []byte) (*Circuit, error) {
+	hash, err := Hash(salt, password)
+	if err != nil {
+		return nil, err
+	}
+	var witness Circuit
+	witness.Salt.Assign(salt)
+	witness.Hash.Assign(hash)
+	witness.Challenge.Assign(challenge)
+	witness.Password.Assign(password)
+	return &witness, nil
+}
@@ -0,0 +1,21 @@
+package password
+
+import (
+	"crypto/rand"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuits"
+)
+
+func init() {
+	circuits.Register("password", &Circuit{})
+}
+
+// Example returns an assignment proving the knowledge of "correct horse" for a fresh salt
+func (c *Circuit) Example() (frontend.Circuit, error) {
+	salt, err := NewSalt(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewWitness(salt, []byte("correct horse"), []byte("login-1"))
+}
@@ -0,0 +1,85 @@
+package circuits
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Profile is the size of a compiled circuit
+type Profile struct {
+	Circuit     string `json:"circuit"`
+	Constraints int    `json:"constraints"`
+	Wires       int    `json:"wires"`
+	// Public doesn't count the constant wire
+	Public   int `json:"public"`
+	Secret   int `json:"secret"`
+	Internal int `json:"internal"`
+}
+
+// NewProfile compiles the circuit registered under name for BN254 and groth16, and profiles it
+func NewProfile(name string) (*Profile, error) {
+	c, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	ccs, err := frontend.Compile(ecc.BN254, backend.GROTH16, c)
+	if err != nil {
+		return nil, err
+	}
+
+	internal, secret, public := ccs.GetNbVariables()
+	return &Profile{
+		Circuit:     name,
+		Constraints: ccs.GetNbConstraints(),
+		Wires:       internal + secret + public,
+		Public:      public - 1,
+		Secret:      secret,
+		Internal:    internal,
+	}, nil
+}
+
+// gadgets are the circuits measuring a gadget, by name
+var gadgets = make(map[string]frontend.Circuit)
+
+// RegisterGadget registers c, a circuit making one call of the gadget name and nothing else
+// gnark v0.5 doesn't count constraints per gadget within a circuit: gadgets are compiled on their
+// own instead, see GadgetProfiles.
+func RegisterGadget(name string, c frontend.Circuit) {
+	if _, ok := gadgets[name]; ok {
+		panic("circuits: gadget " + name + " registered twice")
+	}
+	gadgets[name] = c
+}
+
+// GadgetProfile is the cost of one call of a gadget
+type GadgetProfile struct {
+	Name        string `json:"name"`
+	Constraints int    `json:"constraints"`
+	// Wires are the internal wires the call adds
+	Wires int `json:"wires"`
+}
+
+// GadgetProfiles compiles the registered gadgets for BN254 and groth16, and returns their costs
+// sorted by decreasing number of constraints
+func GadgetProfiles() ([]GadgetProfile, error) {
+	profiles := make([]GadgetProfile, 0, len(gadgets))
+	for name, c := range gadgets {
+		ccs, err := frontend.Compile(ecc.BN254, backend.GROTH16, c)
+		if err != nil {
+			return nil, fmt.Errorf("gadget %s: %w", name, err)
+		}
+		internal, _, _ := ccs.GetNbVariables()
+		profiles = append(profiles, GadgetProfile{Name: name, Constraints: ccs.GetNbConstraints(), Wires: internal})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Constraints != profiles[j].Constraints {
+			return profiles[i].Constraints > profiles[j].Constraints
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles, nil
+}
@@ -0,0 +1,187 @@
+// Package circuits is the registry of the circuits the workshop binary can compile, setup,
+// prove and export.
+//
+// A circuit registers itself from an init function, so adding a file is enough to make it
+// available through the -circuit flag:
+//
+//	func init() {
+//		circuits.Register("mycircuit", &MyCircuit{})
+//	}
+//
+// Circuits sized at compile time register a Factory instead, and are selected with their
+// parameters: merkle@depth=8 (see Instance).
+package circuits
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Exampler is implemented by circuits able to build a valid assignment of themselves,
+// used by the end-to-end demo to create a proof
+type Exampler interface {
+	Example() (frontend.Circuit, error)
+}
+
+// SecretProver is implemented by circuits proving the knowledge of a secret (e.g. a pre-image),
+// so that the demo can prove with a secret of the user instead of the example's
+type SecretProver interface {
+	WithSecret(secret []byte) (frontend.Circuit, error)
+}
+
+var (
+	lock      sync.RWMutex
+	registry  = make(map[string]frontend.Circuit)
+	factories = make(map[string]Factory)
+)
+
+// Register adds c under name; it panics if name is already taken
+// c is the circuit definition (its values are ignored), typically a pointer to a zero value struct.
+func Register(name string, c frontend.Circuit) {
+	lock.Lock()
+	defer lock.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("circuit %q registered twice", name))
+	}
+	if _, ok := factories[name]; ok {
+		panic(fmt.Sprintf("circuit %q registered twice", name))
+	}
+	registry[name] = c
+}
+
+// RegisterFactory adds the parameterized circuit built by f under name; it panics if name is
+// already taken, or contains ParamsSeparator
+func RegisterFactory(name string, f Factory) {
+	if strings.Contains(name, ParamsSeparator) {
+		panic(fmt.Sprintf("circuit name %q contains %q", name, ParamsSeparator))
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if _, ok := factories[name]; ok {
+		panic(fmt.Sprintf("circuit %q registered twice", name))
+	}
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("circuit %q registered twice", name))
+	}
+	factories[name] = f
+}
+
+// Get returns a new zero value of the circuit registered under name
+// For a parameterized circuit, name is an instance name (see Instance), or the name of the circuit
+// for its default parameters.
+func Get(name string) (frontend.Circuit, error) {
+	base, params, err := splitInstance(name)
+	if err != nil {
+		return nil, err
+	}
+	lock.RLock()
+	c, ok := registry[base]
+	f, isFactory := factories[base]
+	lock.RUnlock()
+	switch {
+	case isFactory:
+		resolved, err := resolve(base, f, params)
+		if err != nil {
+			return nil, err
+		}
+		return f.New(resolved)
+	case !ok:
+		return nil, fmt.Errorf("unknown circuit %q (registered: %v)", base, Names())
+	case len(params) != 0:
+		return nil, fmt.Errorf("%w: circuit %q takes no parameters", ErrInvalidParams, base)
+	}
+	return newZero(c), nil
+}
+
+// Example returns a valid assignment of the circuit registered under name
+func Example(name string) (frontend.Circuit, error) {
+	c, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := c.(Exampler)
+	if !ok {
+		return nil, fmt.Errorf("circuit %q doesn't provide an example assignment", name)
+	}
+	return e.Example()
+}
+
+// WithSecret returns the assignment of the circuit registered under name proving the knowledge of
+// secret; the assignment references secret, which the caller wipes once the proof is done
+func WithSecret(name string, secret []byte) (frontend.Circuit, error) {
+	c, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := c.(SecretProver)
+	if !ok {
+		return nil, fmt.Errorf("circuit %q doesn't prove the knowledge of a secret", name)
+	}
+	return p.WithSecret(secret)
+}
+
+// Names returns the sorted names of the registered circuits
+func Names() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+	names := make([]string, 0, len(registry)+len(factories))
+	for name := range registry {
+		names = append(names, name)
+	}
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newZero returns a pointer to a copy of c's value with its Variables reset, so that callers never
+// share (and mutate) the registered value
+// The other fields (e.g. the depth of a tree circuit) and the length of the slices are kept, so that
+// circuits sized at registration compile to the same constraint system.
+func newZero(c frontend.Circuit) frontend.Circuit {
+	t := reflect.TypeOf(c)
+	if t.Kind() != reflect.Ptr {
+		return c
+	}
+	zero := reflect.New(t.Elem())
+	zero.Elem().Set(reflect.ValueOf(c).Elem())
+	resetVariables(zero.Elem())
+	return zero.Interface().(frontend.Circuit)
+}
+
+// resetVariables zeroes the Variables of v, recursively, after copying the slices it goes through
+// so that they don't share the registered value's
+func resetVariables(v reflect.Value) {
+	if v.Type() == tVariable {
+		v.Set(reflect.Zero(tVariable))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				resetVariables(v.Field(i))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			resetVariables(v.Index(i))
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(s, v)
+		v.Set(s)
+		for i := 0; i < v.Len(); i++ {
+			resetVariables(v.Index(i))
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package circuits
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+// sizedCircuit is sized by a field that is not a Variable, as the tree circuits
+type sizedCircuit struct {
+	Depth int `gnark:"-"`
+	Path  []frontend.Variable
+	Root  frontend.Variable `gnark:",public"`
+}
+
+func (c *sizedCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	sum := cs.Constant(0)
+	for i := 0; i < c.Depth; i++ {
+		sum = cs.Add(sum, c.Path[i])
+	}
+	cs.AssertIsEqual(sum, c.Root)
+	return nil
+}
+
+func TestGetKeepsNonVariableFields(t *testing.T) {
+	Register("test-sized", &sizedCircuit{Depth: 3, Path: make([]frontend.Variable, 3)})
+
+	c, err := Get("test-sized")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sized := c.(*sizedCircuit)
+	if sized.Depth != 3 || len(sized.Path) != 3 {
+		t.Fatalf("got depth %d and a path of %d, expected 3 and 3", sized.Depth, len(sized.Path))
+	}
+
+	// the copy doesn't share the registered slices: assigning it leaves the next Get unassigned
+	for i := range sized.Path {
+		sized.Path[i].Assign(1)
+	}
+	sized.Root.Assign(3)
+	other, err := Get("test-sized")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := frontend.Compile(ecc.BN254, backend.GROTH16, other); err != nil {
+		t.Fatalf("compiling a fresh value: %v", err)
+	}
+}
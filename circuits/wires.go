@@ -0,0 +1,45 @@
+package circuits
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Wires returns the names of the public and secret variables of c, in the order gnark
+// allocates their wires (and writes them in witnesses): struct fields in declaration order,
+// slices and arrays element by element. Names are paths as in FromJSON error messages, e.g. Path[2].
+func Wires(c frontend.Circuit) (public, secret []string) {
+	var walk func(v reflect.Value, path string, isPublic bool)
+	walk = func(v reflect.Value, path string, isPublic bool) {
+		if v.Type() == tVariable {
+			if isPublic {
+				public = append(public, path)
+			} else {
+				secret = append(secret, path)
+			}
+			return
+		}
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if !v.IsNil() {
+				walk(v.Elem(), path, isPublic)
+			}
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				name, fieldPublic, skip := parseTag(v.Type().Field(i), isPublic)
+				if skip || !v.Field(i).CanSet() {
+					continue
+				}
+				walk(v.Field(i), join(path, name), fieldPublic)
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), isPublic)
+			}
+		}
+	}
+	walk(reflect.ValueOf(c), "", false)
+	return public, secret
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os/exec"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/commitreveal"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/testchain"
+)
+
+// runCommitReveal runs the commit / reveal workflow on the simulated backend, checking each step:
+// alice commits the example hash, mallory's commitment of the same hash is refused, alice's reveal
+// is refused before the reveal delay and accepted after it, mallory's replay of alice's proof and a
+// second reveal are refused
+func runCommitReveal(args []string) {
+	fs := flag.NewFlagSet("commit-reveal", flag.ExitOnError)
+	fDelay := fs.Uint64("delay", 2, "blocks between a commitment and its reveal")
+	assertNoError(fs.Parse(args))
+	if _, err := exec.LookPath(ethereum.Solc); err != nil {
+		log.Fatal("please install solc", err)
+	}
+	vk, proof, hash := proveExample()
+
+	chain, err := testchain.New(testchain.Genesis{Accounts: []string{"deployer", "alice", "mallory"}})
+	assertNoError(err)
+	log.Println("deploying verifier and CommitReveal")
+	auth := chain.Transactor("deployer")
+	var solidity bytes.Buffer
+	assertNoError(vk.ExportSolidity(&solidity))
+	verifier, _, err := ethereum.DeployContract(auth, chain, solidity.String(), "Verifier")
+	assertNoError(err)
+	cr, err := commitreveal.Deploy(auth, chain, verifier, *fDelay)
+	assertNoError(err)
+	chain.Commit()
+
+	result := commitRevealResult{Contract: cr.Address.Hex(), Hash: hash.String(), Delay: *fDelay}
+	// step sends a transaction as account and checks its status; the gas limit is set as
+	// estimating a reverting call fails
+	step := func(name, account string, expected uint64, send func(*bind.TransactOpts) (*types.Transaction, error)) {
+		opts := chain.Transactor(account)
+		opts.GasLimit = 1000000
+		tx, err := send(opts)
+		assertNoError(err)
+		receipt, err := chain.Mine(tx)
+		assertNoError(err)
+		if receipt.Status != expected {
+			log.Fatalf("%s: status %d, expected %d", name, receipt.Status, expected)
+		}
+		outcome := "accepted"
+		if expected == types.ReceiptStatusFailed {
+			outcome = "refused"
+		}
+		log.Printf("%s: %s (block %d)", name, outcome, receipt.BlockNumber)
+		result.Steps = append(result.Steps, commitRevealStep{Name: name, Account: account, Transaction: newTxResult(receipt)})
+	}
+	commit := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return cr.Commit(opts, hash)
+	}
+	reveal := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return cr.Reveal(opts, hash, proof)
+	}
+
+	// each step is mined in its own block
+	step("alice commits", "alice", types.ReceiptStatusSuccessful, commit)
+	if *fDelay > 1 {
+		step("alice reveals too early", "alice", types.ReceiptStatusFailed, reveal)
+	}
+	step("mallory commits the same hash", "mallory", types.ReceiptStatusFailed, commit)
+	commitment, err := cr.Commitment(&bind.CallOpts{Context: mainCtx}, hash)
+	assertNoError(err)
+	for {
+		head, err := chain.HeaderByNumber(mainCtx, nil)
+		assertNoError(err)
+		if head.Number.Uint64()+1 >= commitment.Block+*fDelay {
+			break
+		}
+		chain.Commit()
+	}
+	step("mallory replays alice's proof", "mallory", types.ReceiptStatusFailed, reveal)
+	step("alice reveals", "alice", types.ReceiptStatusSuccessful, reveal)
+	step("alice reveals again", "alice", types.ReceiptStatusFailed, reveal)
+
+	commitment, err = cr.Commitment(&bind.CallOpts{Context: mainCtx}, hash)
+	assertNoError(err)
+	alice, err := chain.Account("alice")
+	assertNoError(err)
+	if commitment.Committer != alice.Address || !commitment.Revealed {
+		log.Fatalf("commitment of %s: %+v, expected revealed by alice", hash, commitment)
+	}
+	log.Printf("hash %s committed at block %d and revealed by %s, without its pre-image", hash, commitment.Block, alice.Address.Hex())
+	emit("commit-reveal", result)
+}
+
+// commitRevealResult is the JSON output of commit-reveal
+type commitRevealResult struct {
+	Contract string             `json:"contract"`
+	Hash     string             `json:"hash"`
+	Delay    uint64             `json:"delay"`
+	Steps    []commitRevealStep `json:"steps"`
+}
+
+// commitRevealStep is a transaction of commit-reveal; its status says if it was refused
+type commitRevealStep struct {
+	Name        string    `json:"name"`
+	Account     string    `json:"account"`
+	Transaction *txResult `json:"transaction"`
+}
@@ -0,0 +1,54 @@
+// Compiled after IVerifier.sol, with its SPDX identifier and pragma: see commitreveal.Source.
+//
+// CommitReveal is a two phase claim of mimc hashes: a hash is first committed, publicly, then, at
+// least revealDelay blocks later, its committer reveals the knowledge of its pre-image with a proof,
+// checked by the IVerifier of the circuit.Circuit (mimc) circuit. The pre-image itself is never
+// revealed.
+//
+// Unlike SecretRegistry and Race, a proof seen in the mempool is useless to anyone else: only the
+// committer of a hash can reveal it. Someone can still commit a hash first, without knowing its
+// pre-image, to keep its owner from claiming it: applications would bond commitments.
+contract CommitReveal {
+    IVerifier public immutable verifier;
+    uint256 public immutable revealDelay;
+
+    struct Commitment {
+        address committer;
+        uint64 block;
+        bool revealed;
+    }
+
+    mapping(uint256 => Commitment) public commitments;
+
+    event Committed(uint256 indexed hash, address indexed committer);
+    event Revealed(uint256 indexed hash, address indexed committer);
+
+    constructor(IVerifier _verifier, uint256 _revealDelay) {
+        verifier = _verifier;
+        revealDelay = _revealDelay;
+    }
+
+    // commit records msg.sender as the only account allowed to reveal hash
+    function commit(uint256 hash) external {
+        require(commitments[hash].committer == address(0), "commit-already-committed");
+        commitments[hash] = Commitment(msg.sender, uint64(block.number), false);
+        emit Committed(hash, msg.sender);
+    }
+
+    // reveal proves that the committer of hash knows its pre-image
+    function reveal(
+        uint256 hash,
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c
+    ) external {
+        Commitment storage commitment = commitments[hash];
+        require(commitment.committer == msg.sender, "reveal-not-committer");
+        require(!commitment.revealed, "reveal-already-revealed");
+        require(block.number >= commitment.block + revealDelay, "reveal-too-early");
+        require(verifier.verifyProof(a, b, c, [hash]), "reveal-invalid-proof");
+
+        commitment.revealed = true;
+        emit Revealed(hash, msg.sender);
+    }
+}
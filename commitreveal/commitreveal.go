@@ -0,0 +1,105 @@
+// Package commitreveal drives CommitReveal, an example application contract where mimc hashes are
+// committed first, then revealed by their committer with a proof of knowledge of their pre-image,
+// so that a proof seen in the mempool can't be replayed by someone else.
+package commitreveal
+
+import (
+	_ "embed"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+//go:embed CommitReveal.sol
+var commitRevealSol string
+
+// commitRevealABI is the ABI of CommitReveal.sol, so that it can be bound without solc
+const commitRevealABI = `[
+	{"type":"function","name":"verifier","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"revealDelay","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"commitments","stateMutability":"view","inputs":[{"name":"","type":"uint256"}],"outputs":[{"name":"committer","type":"address"},{"name":"block","type":"uint64"},{"name":"revealed","type":"bool"}]},
+	{"type":"function","name":"commit","stateMutability":"nonpayable","inputs":[{"name":"hash","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"reveal","stateMutability":"nonpayable","inputs":[{"name":"hash","type":"uint256"},{"name":"a","type":"uint256[2]"},{"name":"b","type":"uint256[2][2]"},{"name":"c","type":"uint256[2]"}],"outputs":[]},
+	{"type":"event","name":"Committed","anonymous":false,"inputs":[{"name":"hash","type":"uint256","indexed":true},{"name":"committer","type":"address","indexed":true}]},
+	{"type":"event","name":"Revealed","anonymous":false,"inputs":[{"name":"hash","type":"uint256","indexed":true},{"name":"committer","type":"address","indexed":true}]}
+]`
+
+// Source returns the Solidity source of CommitReveal, with the IVerifier interface it needs
+func Source() (string, error) {
+	iVerifier, err := ethereum.VerifierInterface(1)
+	if err != nil {
+		return "", err
+	}
+	// a single SPDX identifier and pragma per source
+	return iVerifier + "\n" + commitRevealSol, nil
+}
+
+// CommitReveal is a handle on a deployed CommitReveal contract
+type CommitReveal struct {
+	Address  common.Address
+	contract *bind.BoundContract
+}
+
+// Commitment is the state of a committed hash
+type Commitment struct {
+	Committer common.Address
+	Block     uint64
+	Revealed  bool
+}
+
+// Deploy deploys a CommitReveal checking proofs with the mimc verifier deployed at verifier; hashes
+// can be revealed revealDelay blocks after their commitment
+// Requires solc in PATH; caller is responsible for committing / mining the transaction.
+func Deploy(auth *bind.TransactOpts, backend bind.ContractBackend, verifier common.Address, revealDelay uint64) (*CommitReveal, error) {
+	source, err := Source()
+	if err != nil {
+		return nil, err
+	}
+	address, contract, err := ethereum.DeployContract(auth, backend, source, "CommitReveal", verifier, new(big.Int).SetUint64(revealDelay))
+	if err != nil {
+		return nil, err
+	}
+	return &CommitReveal{Address: address, contract: contract}, nil
+}
+
+// Bind returns a handle on the CommitReveal deployed at address
+func Bind(address common.Address, backend bind.ContractBackend) (*CommitReveal, error) {
+	parsed, err := abi.JSON(strings.NewReader(commitRevealABI))
+	if err != nil {
+		return nil, err
+	}
+	return &CommitReveal{Address: address, contract: bind.NewBoundContract(address, parsed, backend, backend, backend)}, nil
+}
+
+// Commit commits hash, for the sender of auth
+func (c *CommitReveal) Commit(auth *bind.TransactOpts, hash *big.Int) (*types.Transaction, error) {
+	return c.contract.Transact(auth, "commit", hash)
+}
+
+// Reveal proves that the sender of auth, the committer of hash, knows its pre-image
+func (c *CommitReveal) Reveal(auth *bind.TransactOpts, hash *big.Int, proof groth16.Proof) (*types.Transaction, error) {
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.contract.Transact(auth, "reveal", hash, solidityInputs.A, solidityInputs.B, solidityInputs.C)
+}
+
+// Commitment returns the commitment of hash; its Committer is the zero address if hash wasn't committed
+func (c *CommitReveal) Commitment(opts *bind.CallOpts, hash *big.Int) (Commitment, error) {
+	var out []interface{}
+	if err := c.contract.Call(opts, &out, "commitments", hash); err != nil {
+		return Commitment{}, err
+	}
+	return Commitment{
+		Committer: *abi.ConvertType(out[0], new(common.Address)).(*common.Address),
+		Block:     *abi.ConvertType(out[1], new(uint64)).(*uint64),
+		Revealed:  *abi.ConvertType(out[2], new(bool)).(*bool),
+	}, nil
+}
@@ -0,0 +1,222 @@
+package commitreveal
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/testchain"
+)
+
+const revealDelay = 2
+
+// requireSolc skips the tests deploying or compiling CommitReveal if solc isn't in PATH
+func requireSolc(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath(ethereum.Solc); err != nil {
+		t.Skip("requires solc:", err)
+	}
+}
+
+// deploy deploys the mimc verifier of vk and a CommitReveal using it on a new chain
+func deploy(t *testing.T, vk groth16.VerifyingKey) (*testchain.Chain, *CommitReveal) {
+	t.Helper()
+	chain, err := testchain.New(testchain.Genesis{Accounts: []string{"deployer", "alice", "mallory"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { chain.Close() })
+	auth := chain.Transactor("deployer")
+	var solidity bytes.Buffer
+	if err := vk.ExportSolidity(&solidity); err != nil {
+		t.Fatal(err)
+	}
+	verifier, _, err := ethereum.DeployContract(auth, chain, solidity.String(), "Verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cr, err := Deploy(auth, chain, verifier, revealDelay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Commit()
+	return chain, cr
+}
+
+// prove returns the verifying key of a setup of the mimc circuit, a proof of the pre-image of a
+// hash, and the hash
+func prove(t *testing.T) (groth16.VerifyingKey, groth16.Proof, *big.Int) {
+	t.Helper()
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit.Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness, err := circuit.NewWitness([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicWitness, err := ethereum.PublicWitness(witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vk, proof, publicWitness[0].ToBigIntRegular(new(big.Int))
+}
+
+func TestCommitReveal(t *testing.T) {
+	requireSolc(t)
+	vk, proof, hash := prove(t)
+	chain, cr := deploy(t, vk)
+
+	// each step is mined in its own block; the gas limit is set as estimating a reverting call fails
+	step := func(name, account string, expected uint64, send func(*bind.TransactOpts) (*types.Transaction, error)) {
+		t.Helper()
+		opts := chain.Transactor(account)
+		opts.GasLimit = 1000000
+		tx, err := send(opts)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		receipt, err := chain.Mine(tx)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if receipt.Status != expected {
+			t.Fatalf("%s: status %d, expected %d", name, receipt.Status, expected)
+		}
+	}
+	commit := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return cr.Commit(opts, hash)
+	}
+	reveal := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return cr.Reveal(opts, hash, proof)
+	}
+
+	step("alice commits", "alice", types.ReceiptStatusSuccessful, commit)
+	step("alice reveals too early", "alice", types.ReceiptStatusFailed, reveal)
+	step("mallory commits the same hash", "mallory", types.ReceiptStatusFailed, commit)
+	step("mallory replays alice's proof", "mallory", types.ReceiptStatusFailed, reveal)
+	step("alice reveals", "alice", types.ReceiptStatusSuccessful, reveal)
+	step("alice reveals again", "alice", types.ReceiptStatusFailed, reveal)
+
+	// the commitment is read through a binding from the ABI, without solc
+	bound, err := Bind(cr.Address, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment, err := bound.Commitment(&bind.CallOpts{Context: context.Background()}, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, err := chain.Account("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commitment.Committer != alice.Address || !commitment.Revealed {
+		t.Fatalf("commitment %+v, expected revealed by alice", commitment)
+	}
+}
+
+func TestRevealInvalidProof(t *testing.T) {
+	requireSolc(t)
+	vk, _, hash := prove(t)
+	_, other, _ := prove(t)
+	chain, cr := deploy(t, vk)
+
+	tx, err := cr.Commit(chain.Transactor("alice"), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < revealDelay; i++ {
+		chain.Commit()
+	}
+
+	// a proof of the same pre-image, from another setup
+	opts := chain.Transactor("alice")
+	opts.GasLimit = 1000000
+	if tx, err = cr.Reveal(opts, hash, other); err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := chain.Mine(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("revealed with a proof of another setup")
+	}
+}
+
+func TestSource(t *testing.T) {
+	source, err := Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"SPDX-License-Identifier", "pragma solidity"} {
+		if n := strings.Count(source, s); n != 1 {
+			t.Errorf("%d %q in the source, expected 1", n, s)
+		}
+	}
+	if !strings.Contains(source, "uint256[1] memory input") {
+		t.Error("the source doesn't declare the IVerifier of the mimc circuit, with a single public input")
+	}
+	if _, err := Bind(common.Address{}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// the ABI CommitReveal is bound with matches the compiled contract
+func TestCommitRevealABI(t *testing.T) {
+	requireSolc(t)
+	source, err := Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contracts, err := ethereum.CompileSolidity(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled, _, err := ethereum.Artifact(contracts, "CommitReveal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bound, err := abi.JSON(strings.NewReader(commitRevealABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, method := range compiled.Methods {
+		if other, ok := bound.Methods[name]; !ok || other.Sig != method.Sig {
+			t.Errorf("method %s: compiled %s, bound %s", name, method.Sig, other.Sig)
+		}
+	}
+	for name, event := range compiled.Events {
+		if other, ok := bound.Events[name]; !ok || other.Sig != event.Sig {
+			t.Errorf("event %s: compiled %s, bound %s", name, event.Sig, other.Sig)
+		}
+	}
+	if len(compiled.Methods) != len(bound.Methods) || len(compiled.Events) != len(bound.Events) {
+		t.Errorf("compiled %d methods and %d events, bound %d and %d", len(compiled.Methods), len(compiled.Events), len(bound.Methods), len(bound.Events))
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	gnarkwitness "github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/r1cs"
+)
+
+// reConstraint matches the index of the unsatisfied constraint in gnark's solver errors
+var reConstraint = regexp.MustCompile(`constraint #(\d+)`)
+
+// runDebugWitness runs the witness solver of the selected circuit, without proving, and explains
+// which constraint an assignment doesn't satisfy
+func runDebugWitness(args []string) {
+	fs := flag.NewFlagSet("debug-witness", flag.ExitOnError)
+	fWitness := fs.String("witness", "", "JSON witness file (see circuits.FromJSON), the circuit example if not set")
+	assertNoError(fs.Parse(args))
+
+	circuit, err := circuits.Get(*fCircuit)
+	assertNoError(err)
+	var assignment frontend.Circuit
+	if *fWitness != "" {
+		data, err := ioutil.ReadFile(*fWitness)
+		assertNoError(err)
+		assignment, err = circuits.FromJSON(*fCircuit, data)
+		assertNoError(err)
+	} else {
+		assignment, err = circuits.Example(*fCircuit)
+		assertNoError(err)
+	}
+
+	ccs, err := frontend.Compile(ecc.BN254, backend.GROTH16, circuit)
+	assertNoError(err)
+	public, secret := circuits.Wires(circuit)
+	exported, err := r1cs.FromCompiled(ccs, public, secret)
+	assertNoError(err)
+
+	// the full witness holds the public inputs, then the secret ones: wires 1 to n
+	var buf bytes.Buffer
+	_, err = gnarkwitness.WriteFullTo(&buf, ecc.BN254, assignment)
+	assertNoError(err)
+	values, err := ethereum.DecodePublicWitness(buf.Bytes())
+	assertNoError(err)
+	inputs := make([]wireValue, len(values))
+	for i := range values {
+		wire := i + 1
+		inputs[i] = wireValue{Wire: wire, Name: exported.WireName(wire), Public: i < len(public), Value: values[i].String()}
+	}
+
+	result := debugWitnessResult{Circuit: *fCircuit, Constraints: exported.NConstraint, Inputs: inputs}
+	solveErr := groth16.IsSolved(ccs, assignment)
+	if solveErr == nil {
+		result.Solved = true
+		if !jsonOutput() {
+			fmt.Printf("the witness satisfies the %d constraints of %s\n", exported.NConstraint, *fCircuit)
+		}
+		emit("debug-witness", result)
+		return
+	}
+
+	result.Error = solveErr.Error()
+	if m := reConstraint.FindStringSubmatch(solveErr.Error()); m != nil {
+		if i, err := strconv.Atoi(m[1]); err == nil && i < exported.NConstraint {
+			u := &unsatisfiedConstraint{Index: i, Formula: exported.Format(i)}
+			for _, wire := range exported.Wires(i) {
+				if wire != 0 && exported.IsInput(wire) {
+					u.Inputs = append(u.Inputs, inputs[wire-1])
+				}
+			}
+			result.Unsatisfied = u
+		}
+	}
+
+	if !jsonOutput() {
+		fmt.Printf("the witness doesn't satisfy %s: %v\n", *fCircuit, solveErr)
+		if u := result.Unsatisfied; u != nil {
+			fmt.Printf("\nconstraint #%d of %d:\n\t%s\n", u.Index, exported.NConstraint, u.Formula)
+			if len(u.Inputs) != 0 {
+				fmt.Println("\nwith the inputs:")
+				for _, in := range u.Inputs {
+					fmt.Printf("\t%s\n", in)
+				}
+			}
+			fmt.Println("\nw<i> are internal wires, computed by the solver from the previous constraints")
+		}
+		fmt.Println("\nall the inputs:")
+		for _, in := range inputs {
+			fmt.Printf("\t%s\n", in)
+		}
+	}
+	emit("debug-witness", result)
+	os.Exit(1)
+}
+
+// debugWitnessResult is the JSON output of debug-witness
+type debugWitnessResult struct {
+	Circuit     string                 `json:"circuit"`
+	Constraints int                    `json:"constraints"`
+	Solved      bool                   `json:"solved"`
+	Error       string                 `json:"error,omitempty"`
+	Unsatisfied *unsatisfiedConstraint `json:"unsatisfied,omitempty"`
+	Inputs      []wireValue            `json:"inputs"`
+}
+
+// unsatisfiedConstraint is the first constraint the solver found unsatisfied
+type unsatisfiedConstraint struct {
+	Index   int    `json:"index"`
+	Formula string `json:"formula"`
+	// Inputs are the public and secret inputs appearing in the constraint
+	Inputs []wireValue `json:"inputs,omitempty"`
+}
+
+// wireValue is the assigned value of an input wire
+type wireValue struct {
+	Wire   int    `json:"wire"`
+	Name   string `json:"name"`
+	Public bool   `json:"public"`
+	Value  string `json:"value"`
+}
+
+func (w wireValue) String() string {
+	visibility := "secret"
+	if w.Public {
+		visibility = "public"
+	}
+	return fmt.Sprintf("%s (%s) = %s", w.Name, visibility, w.Value)
+}
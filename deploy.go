@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits/mimc"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runDeploy deploys the verifier contract on a real network through a JSON-RPC endpoint
+// and records its address in the deployments file, keyed by chain ID
+func runDeploy(args []string) {
+	fs := flag.NewFlagSet("deploy", flag.ExitOnError)
+	fTxFlags := addTxFlags(fs)
+	fSigner := addSignerFlags(fs, "(funded) deployer account")
+	fDeployments := fs.String("deployments", deploymentsPath, "deployments file to update")
+	fRaw := fs.Bool("raw", false, "deploy the stored creation bytecode directly, without the generated Go binding (always the case for non default circuits)")
+	fBin := fs.String("bin", files.verifierBin, "creation bytecode file (with -raw)")
+	fUpgradeable := fs.Bool("upgradeable", false, "deploy a VerifierProxy in front of the verifier, administered by the deployer, and record the proxy (see rotate-vk; requires solc)")
+	assertNoError(fs.Parse(args))
+
+	signer, closeSigner := fSigner.signer()
+	defer closeSigner()
+
+	ctx := mainCtx
+	client := fTxFlags.dial(ctx)
+	defer client.Close()
+	chainID := client.chainID
+	auth := client.transactor(ctx, signer)
+
+	log.Printf("deploying verifier contract on chain %s from %s", chainID, auth.From.Hex())
+	var (
+		tx  *types.Transaction
+		err error
+	)
+	if *fRaw || *fCircuit != defaultCircuit {
+		if *fBin == files.verifierBin {
+			// the bytecode of the current setup, as its manifest records it
+			manifest, err := artifacts.ReadManifest(files.manifest)
+			assertNoError(err)
+			assertNoError(checkManifest(manifest, files, filepath.Base(files.verifierBin)))
+		}
+		// the verifier has no constructor arguments: initcode is the creation bytecode
+		bytecode, err := ethereum.ReadBytecode(*fBin)
+		assertNoError(err)
+		initcode, err := ethereum.InitCode(bytecode, nil)
+		assertNoError(err)
+		_, tx, err = ethereum.DeployRaw(ctx, auth, client, initcode)
+		assertNoError(err)
+	} else {
+		_, tx, _, err = mimc.DeployVerifier(auth, client)
+		assertNoError(err)
+	}
+
+	receipt := client.wait(ctx, tx)
+	address := receipt.ContractAddress
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		log.Fatalf("deployment transaction %s reverted", tx.Hash().Hex())
+	}
+	code, err := client.CodeAt(ctx, address, nil)
+	assertNoError(err)
+	if len(code) == 0 {
+		assertNoError(bind.ErrNoCodeAfterDeploy)
+	}
+	log.Printf("verifier deployed at %s (block %d, gas used: %d)", address.Hex(), receipt.BlockNumber, receipt.GasUsed)
+
+	deployment := ethereum.Deployment{
+		Verifier:    address,
+		TxHash:      tx.Hash(),
+		BlockNumber: receipt.BlockNumber.Uint64(),
+	}
+	if *fUpgradeable {
+		auth = client.transactor(ctx, signer)
+		_, tx, err = ethereum.DeployProxy(auth, client, address, auth.From)
+		assertNoError(err)
+		receipt = client.wait(ctx, tx)
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			log.Fatalf("proxy deployment transaction %s reverted", tx.Hash().Hex())
+		}
+		log.Printf("proxy deployed at %s, administered by %s (gas used: %d)", receipt.ContractAddress.Hex(), auth.From.Hex(), receipt.GasUsed)
+		deployment = ethereum.Deployment{
+			Verifier:       receipt.ContractAddress,
+			Implementation: address,
+			TxHash:         tx.Hash(),
+			BlockNumber:    receipt.BlockNumber.Uint64(),
+		}
+	}
+
+	deployments, err := ethereum.ReadDeployments(*fDeployments)
+	assertNoError(err)
+	deployments.Set(chainID, *fCircuit, deployment)
+	assertNoError(deployments.Save(*fDeployments))
+	log.Println("recorded deployment in", *fDeployments)
+
+	emit("deploy", deployResult{
+		ChainID:     chainID.Int64(),
+		Circuit:     *fCircuit,
+		Verifier:    deployment.Verifier,
+		Transaction: newTxResult(receipt),
+	})
+}
+
+// deployResult is the JSON output of deploy
+type deployResult struct {
+	ChainID     int64          `json:"chainId"`
+	Circuit     string         `json:"circuit"`
+	Verifier    common.Address `json:"verifier"`
+	Transaction *txResult      `json:"transaction"`
+}
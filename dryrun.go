@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/bits"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/bench"
+	"github.com/gbotrel/gnark-workshop/circuits"
+)
+
+// calibrationLogSize is the log2 size of the MSM and FFT -dry-run measures
+const calibrationLogSize = 16
+
+// runDryRun estimates the time and memory of a proof of the selected circuit on this machine
+// (-dry-run), from its constraint and wire counts and a short calibration of the MSM and FFT, without
+// setup nor proof
+func runDryRun() {
+	p, err := circuits.NewProfile(*fCircuit)
+	assertNoError(err)
+	log.Printf("calibrating the MSM and FFT of 2^%d elements", calibrationLogSize)
+	cal, err := bench.Calibrate(calibrationLogSize)
+	assertNoError(err)
+	estimate := bench.EstimateProof(cal, p.Constraints, p.Wires, p.Public)
+
+	// the files of the current setup, if any: the proving key is larger in memory, uncompressed
+	result := dryRunResult{Circuit: *fCircuit, Profile: p, Calibration: cal, Estimate: estimate}
+	for _, fileName := range []string{files.r1cs, files.pk} {
+		size, err := artifacts.FileSize(fileName)
+		if err != nil {
+			result.KeysOnDisk = 0
+			break
+		}
+		result.KeysOnDisk += size
+	}
+
+	if !jsonOutput() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "circuit\t%s\t\n", *fCircuit)
+		fmt.Fprintf(w, "constraints\t%d\t\n", p.Constraints)
+		fmt.Fprintf(w, "wires\t%d (%d public)\t\n", p.Wires, p.Public)
+		fmt.Fprintf(w, "FFT domain\t2^%d\t\n", bits.TrailingZeros(uint(estimate.Domain)))
+		fmt.Fprintf(w, "calibration\tMSM 2^%d %s, FFT 2^%d %s, %d cores\t\n", cal.LogSize, cal.MSM.Round(time.Microsecond),
+			cal.LogSize, cal.FFT.Round(time.Microsecond), cal.Procs)
+		if result.KeysOnDisk != 0 {
+			fmt.Fprintf(w, "R1CS and proving key\t%d MiB on disk\t\n", result.KeysOnDisk>>20)
+		}
+		fmt.Fprintf(w, "estimated proving time\t%s\t\n", estimate.Time.Round(time.Millisecond))
+		fmt.Fprintf(w, "estimated peak memory\t%d MiB\t\n", estimate.Memory>>20)
+		assertNoError(w.Flush())
+	}
+	emit("dry-run", result)
+}
+
+// dryRunResult is the JSON output of -dry-run
+type dryRunResult struct {
+	Circuit     string              `json:"circuit"`
+	Profile     *circuits.Profile   `json:"profile"`
+	Calibration bench.Calibration   `json:"calibration"`
+	Estimate    bench.ProofEstimate `json:"estimate"`
+	// KeysOnDisk is the size of the R1CS and proving key files, 0 if not set up
+	KeysOnDisk int64 `json:"keysOnDisk,omitempty"`
+}
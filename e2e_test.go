@@ -0,0 +1,152 @@
+//go:build e2e
+// +build e2e
+
+package main
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/testchain"
+	"github.com/gbotrel/gnark-workshop/workshop"
+)
+
+// TestEndToEnd runs the flow of the demo in-process for every registered circuit (go test -run
+// TestEndToEnd/mimc for one): compile, setup, prove, deploy and verify on a testchain, with keys
+// and proofs round-tripped through a temporary directory. Built with -tags e2e only; requires solc.
+func TestEndToEnd(t *testing.T) {
+	if _, err := exec.LookPath(ethereum.Solc); err != nil {
+		t.Fatal("please install solc:", err)
+	}
+	for _, name := range circuits.Names() {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			endToEnd(t, context.Background(), name)
+		})
+	}
+}
+
+// endToEnd runs the flow of the demo for the circuit registered under name
+func endToEnd(t *testing.T, ctx context.Context, name string) {
+	dir := t.TempDir()
+
+	c, err := circuits.Get(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := circuits.Example(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := workshop.New(c)
+	if err := p.Compile(ctx); err != nil {
+		t.Fatal("compile:", err)
+	}
+	if err := p.Setup(ctx); err != nil {
+		t.Fatal("setup:", err)
+	}
+
+	// the keys and the proof the chain sees are read back from files
+	pk, vk := groth16.NewProvingKey(ecc.BN254), groth16.NewVerifyingKey(ecc.BN254)
+	roundTrip(t, dir, "pk", p.PK, pk)
+	roundTrip(t, dir, "vk", p.VK, vk)
+	p.PK, p.VK = pk, vk
+	proof, err := p.Prove(ctx, assignment)
+	if err != nil {
+		t.Fatal("prove:", err)
+	}
+	read := groth16.NewProof(ecc.BN254)
+	roundTrip(t, dir, "proof", proof, read)
+	proof = read
+	if err := p.VerifyLocal(ctx, proof, assignment); err != nil {
+		t.Fatal("verify:", err)
+	}
+
+	// deploy from one account, verify as another
+	chain, err := testchain.New(testchain.Genesis{Accounts: []string{"deployer", "prover"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+	_, tx, err := p.DeployVerifier(chain.Transactor("deployer"), chain)
+	if err != nil {
+		t.Fatal("deploy:", err)
+	}
+	if receipt, err := chain.Mine(tx); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("deploy: transaction failed (%v)", err)
+	}
+	valid, err := p.VerifyOnChain(chain.CallOpts("prover"), proof, assignment)
+	if err != nil {
+		t.Fatal("verifyProof:", err)
+	}
+	if !valid {
+		t.Fatal("verifyProof rejected a valid proof")
+	}
+
+	// verifyAndRecord accepts the proof, verifyProof refuses it with a wrong public input
+	publicWitness, err := ethereum.PublicWitness(assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := ethereum.NewVerifier(p.Verifier, chain, len(publicWitness))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err = verifier.VerifyAndRecord(chain.Transactor("prover"), inputs)
+	if err != nil {
+		t.Fatal("verifyAndRecord:", err)
+	}
+	if receipt, err := chain.Mine(tx); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("verifyAndRecord: transaction failed (%v)", err)
+	}
+	if len(inputs.Input) == 0 {
+		return
+	}
+	inputs.Input[0] = new(big.Int).Add(inputs.Input[0], big.NewInt(1))
+	valid, err = verifier.VerifyProof(&bind.CallOpts{Context: ctx}, inputs)
+	if err != nil {
+		t.Fatal("verifyProof:", err)
+	}
+	if valid {
+		t.Fatal("verifyProof accepted a wrong public input")
+	}
+}
+
+// roundTrip writes o to dir/name and reads it back into r
+func roundTrip(t *testing.T, dir, name string, o io.WriterTo, r io.ReaderFrom) {
+	t.Helper()
+	fileName := filepath.Join(dir, name)
+	f, err := os.Create(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.WriteTo(f); err != nil {
+		f.Close()
+		t.Fatalf("write %s: %v", name, err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = os.Open(fileName); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := r.ReadFrom(f); err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build embedbundle
+// +build embedbundle
+
+package main
+
+import (
+	"embed"
+
+	"github.com/gbotrel/gnark-workshop/prover"
+)
+
+//go:embed proverd/bundle
+var bundleFS embed.FS
+
+// embedded is the bundle written by the bundle command, baked into the binary: the demo runs
+// from it without -init nor reading artifact files. It is nil if nothing was bundled.
+var embedded = prover.EmbeddedBundle(bundleFS, "proverd/bundle")
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/gbotrel/gnark-workshop/artifacts"
+)
+
+// runEncryptKeys encrypts the proving and verifying keys of the selected circuit in place, with
+// AES-GCM and the passphrase of artifacts.PassphraseEnv (or the key source set in an init function,
+// e.g. a KMS), so that they can be stored and distributed over untrusted storage; every command
+// decrypts them transparently with the same key source. The manifest records the hashes of the
+// encrypted files.
+func runEncryptKeys(args []string) {
+	fs := flag.NewFlagSet("encrypt-keys", flag.ExitOnError)
+	fDecrypt := fs.Bool("decrypt", false, "decrypt the keys in place instead")
+	fVK := fs.Bool("vk", true, "also encrypt the verifying key, public but bound to the proving key")
+	assertNoError(fs.Parse(args))
+
+	ks := artifacts.CurrentKeySource()
+	if ks == nil {
+		log.Fatalf("encrypt-keys: set %s", artifacts.PassphraseEnv)
+	}
+	manifest, err := artifacts.ReadManifest(files.manifest)
+	assertNoError(err)
+	if manifest.Signature != nil && *fSigningKey == "" {
+		log.Fatal("encrypt-keys: the manifest is signed, set -signing-key to sign the new key hashes")
+	}
+	keys := []string{files.pk}
+	if *fVK || *fDecrypt {
+		keys = append(keys, files.vk)
+	}
+	var changed []string
+	for _, fileName := range keys {
+		encrypted, err := artifacts.IsEncrypted(fileName)
+		assertNoError(err)
+		switch {
+		case *fDecrypt && encrypted:
+			log.Println("decrypting", fileName)
+			assertNoError(artifacts.DecryptFile(fileName))
+		case !*fDecrypt && !encrypted:
+			log.Println("encrypting", fileName)
+			assertNoError(artifacts.EncryptFile(fileName, ks))
+		default:
+			continue
+		}
+		changed = append(changed, fileName)
+	}
+
+	// the manifest hashes the files as stored
+	if manifest.PKHash != "" {
+		manifest.PKHash, err = artifacts.HashFile(files.pk)
+		assertNoError(err)
+		manifest.VKHash, err = artifacts.HashFile(files.vk)
+		assertNoError(err)
+		if manifest.Signature != nil {
+			assertNoError(signManifest(manifest))
+		}
+		assertNoError(manifest.Save(files.manifest))
+	}
+	emit("encrypt-keys", encryptKeysResult{Circuit: *fCircuit, Decrypted: *fDecrypt, Files: changed})
+}
+
+// encryptKeysResult is the JSON output of encrypt-keys
+type encryptKeysResult struct {
+	Circuit   string   `json:"circuit"`
+	Decrypted bool     `json:"decrypted,omitempty"`
+	Files     []string `json:"files"`
+}
@@ -0,0 +1,86 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Backend is the chain the demos run on: the geth simulated backend, or a local dev node
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	// Commit mines the pending transactions
+	Commit()
+}
+
+// DevKey is the private key of the first prefunded account of anvil and hardhat nodes, derived
+// from their default mnemonic "test test test test test test test test test test test junk"
+const DevKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+// DevNode is a locally running anvil or hardhat node
+type DevNode struct {
+	*ethclient.Client
+	rpc *rpc.Client
+
+	// ChainID and ClientVersion are queried from the node
+	ChainID       *big.Int
+	ClientVersion string
+}
+
+// DialDevNode connects to the dev node at rpcURL and returns a transactor of its first prefunded
+// account; the node must use the default mnemonic
+func DialDevNode(ctx context.Context, rpcURL string) (node *DevNode, auth *bind.TransactOpts, err error) {
+	client, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			client.Close()
+		}
+	}()
+
+	node = &DevNode{Client: ethclient.NewClient(client), rpc: client}
+	if node.ChainID, err = node.Client.ChainID(ctx); err != nil {
+		return nil, nil, err
+	}
+	if err = client.CallContext(ctx, &node.ClientVersion, "web3_clientVersion"); err != nil {
+		return nil, nil, err
+	}
+	if !strings.HasPrefix(strings.ToLower(node.ClientVersion), "anvil") && !strings.HasPrefix(node.ClientVersion, "HardhatNetwork") {
+		return nil, nil, fmt.Errorf("%s is not an anvil or hardhat node (%s)", rpcURL, node.ClientVersion)
+	}
+
+	key, err := crypto.HexToECDSA(DevKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	var accounts []common.Address
+	if err = client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		return nil, nil, err
+	}
+	if len(accounts) == 0 || accounts[0] != crypto.PubkeyToAddress(key.PublicKey) {
+		return nil, nil, errors.New("the dev node doesn't use the default mnemonic, its first account is not prefunded")
+	}
+	auth, err = bind.NewKeyedTransactorWithChainID(key, node.ChainID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return node, auth, nil
+}
+
+// Commit mines a block with evm_mine
+// Dev nodes mine every transaction as it is sent: Commit only matters with interval mining.
+func (n *DevNode) Commit() {
+	_ = n.rpc.CallContext(context.Background(), nil, "evm_mine")
+}
@@ -0,0 +1,241 @@
+// Package ethereum is the glue between gnark objects and Ethereum: inputs of the exported
+// Solidity verifier and records of its deployments.
+package ethereum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrInvalidProof is returned when the raw proof bytes don't hold the 3 expected points
+var ErrInvalidProof = errors.New("invalid raw proof size")
+
+// ProofForSolidity is a proof typed as the arguments of the verifyProof function of the exported
+// Solidity verifier, with ABI (Pack, Calldata), text and JSON encodings
+// a, b and c are the 3 ecc points in the proof we feed to the pairing
+type ProofForSolidity struct {
+	A     [2]*big.Int
+	B     [2][2]*big.Int
+	C     [2]*big.Int
+	Input []*big.Int
+}
+
+// ProofToSolidityInputs slices a (BN254) groth16 proof and its public witness into
+// the verifyProof arguments
+func ProofToSolidityInputs(proof groth16.Proof, publicWitness []fr.Element) (*ProofForSolidity, error) {
+	// get proof bytes
+	var buf bytes.Buffer
+	if _, err := proof.WriteRawTo(&buf); err != nil {
+		return nil, err
+	}
+	proofBytes := buf.Bytes()
+
+	// proof.Ar, proof.Bs, proof.Krs are stored in the same order in the raw encoding
+	// each coordinate is a field element, of size fp.Bytes bytes
+	const fpSize = fp.Bytes
+	if len(proofBytes) < fpSize*8 {
+		return nil, ErrInvalidProof
+	}
+
+	var s ProofForSolidity
+	s.A[0] = new(big.Int).SetBytes(proofBytes[fpSize*0 : fpSize*1])
+	s.A[1] = new(big.Int).SetBytes(proofBytes[fpSize*1 : fpSize*2])
+	s.B[0][0] = new(big.Int).SetBytes(proofBytes[fpSize*2 : fpSize*3])
+	s.B[0][1] = new(big.Int).SetBytes(proofBytes[fpSize*3 : fpSize*4])
+	s.B[1][0] = new(big.Int).SetBytes(proofBytes[fpSize*4 : fpSize*5])
+	s.B[1][1] = new(big.Int).SetBytes(proofBytes[fpSize*5 : fpSize*6])
+	s.C[0] = new(big.Int).SetBytes(proofBytes[fpSize*6 : fpSize*7])
+	s.C[1] = new(big.Int).SetBytes(proofBytes[fpSize*7 : fpSize*8])
+
+	s.Input = make([]*big.Int, len(publicWitness))
+	for i := 0; i < len(publicWitness); i++ {
+		s.Input[i] = publicWitness[i].ToBigIntRegular(new(big.Int))
+	}
+
+	return &s, nil
+}
+
+// Calldata returns the ABI encoded verifyProof call (selector included)
+func (s *ProofForSolidity) Calldata() ([]byte, error) {
+	verifierABI, err := VerifierABI(len(s.Input))
+	if err != nil {
+		return nil, err
+	}
+	return verifierABI.Pack("verifyProof", s.A, s.B, s.C, s.Input)
+}
+
+// Pack returns the ABI encoding of the verifyProof arguments, without selector: abi.encode(a, b, c,
+// input) in Solidity, what abi.decode reads back
+func (s *ProofForSolidity) Pack() ([]byte, error) {
+	arguments, err := verifyProofArguments(len(s.Input))
+	if err != nil {
+		return nil, err
+	}
+	return arguments.Pack(s.A, s.B, s.C, s.Input)
+}
+
+// MarshalText encodes s as the 0x prefixed hex of Pack
+func (s *ProofForSolidity) MarshalText() ([]byte, error) {
+	packed, err := s.Pack()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(hexutil.Encode(packed)), nil
+}
+
+// UnmarshalText decodes the encoding of MarshalText; the arguments are static, their size gives the
+// number of public inputs
+func (s *ProofForSolidity) UnmarshalText(text []byte) error {
+	packed, err := hexutil.Decode(string(text))
+	if err != nil {
+		return err
+	}
+	if len(packed)%32 != 0 || len(packed) < 32*8 {
+		return fmt.Errorf("%w: %d bytes of verifyProof arguments", ErrInvalidProof, len(packed))
+	}
+	arguments, err := verifyProofArguments(len(packed)/32 - 8)
+	if err != nil {
+		return err
+	}
+	decoded, err := unpackSolidityInputs(arguments, packed)
+	if err != nil {
+		return err
+	}
+	*s = *decoded
+	return nil
+}
+
+// MarshalJSON encodes s as a ProofFixture without calldata: {"a", "b", "c", "input"}, in decimal
+func (s *ProofForSolidity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.decimal())
+}
+
+// UnmarshalJSON decodes the encoding of MarshalJSON, fixtures (ProofFixture) included; values may
+// also be 0x prefixed hex
+func (s *ProofForSolidity) UnmarshalJSON(data []byte) error {
+	var f ProofFixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	var decoded ProofForSolidity
+	words := []string{f.A[0], f.A[1], f.B[0][0], f.B[0][1], f.B[1][0], f.B[1][1], f.C[0], f.C[1]}
+	targets := []**big.Int{&decoded.A[0], &decoded.A[1], &decoded.B[0][0], &decoded.B[0][1], &decoded.B[1][0], &decoded.B[1][1], &decoded.C[0], &decoded.C[1]}
+	decoded.Input = make([]*big.Int, len(f.Input))
+	for i := range f.Input {
+		words = append(words, f.Input[i])
+		targets = append(targets, &decoded.Input[i])
+	}
+	for i, word := range words {
+		x, ok := new(big.Int).SetString(word, 0)
+		if !ok || x.Sign() < 0 || x.BitLen() > 256 {
+			return fmt.Errorf("invalid uint256 %q in verifyProof arguments", word)
+		}
+		*targets[i] = x
+	}
+	*s = decoded
+	return nil
+}
+
+// ProofFixture is a proof as the verifyProof arguments, in decimal, with its calldata
+// export-foundry and export-hardhat write it to proof.json, for scripts and other tools.
+type ProofFixture struct {
+	A        [2]string    `json:"a"`
+	B        [2][2]string `json:"b"`
+	C        [2]string    `json:"c"`
+	Input    []string     `json:"input"`
+	Calldata string       `json:"calldata,omitempty"`
+}
+
+// Fixture returns the ProofFixture of s
+func (s *ProofForSolidity) Fixture() (*ProofFixture, error) {
+	calldata, err := s.Calldata()
+	if err != nil {
+		return nil, err
+	}
+	f := s.decimal()
+	f.Calldata = hexutil.Encode(calldata)
+	return f, nil
+}
+
+func (s *ProofForSolidity) decimal() *ProofFixture {
+	f := &ProofFixture{Input: make([]string, len(s.Input))}
+	for i := 0; i < 2; i++ {
+		f.A[i] = s.A[i].String()
+		f.C[i] = s.C[i].String()
+		for j := 0; j < 2; j++ {
+			f.B[i][j] = s.B[i][j].String()
+		}
+	}
+	for i, x := range s.Input {
+		f.Input[i] = x.String()
+	}
+	return f
+}
+
+// verifyProofArguments returns the arguments of verifyProof with nbPublicInputs public inputs
+func verifyProofArguments(nbPublicInputs int) (abi.Arguments, error) {
+	verifierABI, err := VerifierABI(nbPublicInputs)
+	if err != nil {
+		return nil, err
+	}
+	return verifierABI.Methods["verifyProof"].Inputs, nil
+}
+
+// ExportCalldata writes the 0x prefixed hex encoded verifyProof calldata to w
+// output can be fed to `cast call <verifier> <calldata>` or ethers' provider.call({to, data})
+func (s *ProofForSolidity) ExportCalldata(w io.Writer) error {
+	calldata, err := s.Calldata()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "0x"+hex.EncodeToString(calldata)+"\n")
+	return err
+}
+
+// DecodeCalldata decodes verifyProof (or verifyAndRecord) calldata, selector included, of a
+// verifier with nbPublicInputs public inputs
+func DecodeCalldata(calldata []byte, nbPublicInputs int) (*ProofForSolidity, error) {
+	verifierABI, err := VerifierABI(nbPublicInputs)
+	if err != nil {
+		return nil, err
+	}
+	if len(calldata) < 4 {
+		return nil, errors.New("calldata shorter than a selector")
+	}
+	method, err := verifierABI.MethodById(calldata[:4])
+	if err != nil {
+		return nil, err
+	}
+	return unpackSolidityInputs(method.Inputs, calldata[4:])
+}
+
+// unpackSolidityInputs decodes the verifyProof arguments packed in data
+func unpackSolidityInputs(arguments abi.Arguments, data []byte) (*ProofForSolidity, error) {
+	args, err := arguments.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	var s ProofForSolidity
+	s.A = *abi.ConvertType(args[0], new([2]*big.Int)).(*[2]*big.Int)
+	s.B = *abi.ConvertType(args[1], new([2][2]*big.Int)).(*[2][2]*big.Int)
+	s.C = *abi.ConvertType(args[2], new([2]*big.Int)).(*[2]*big.Int)
+	// input is a [nbPublicInputs]*big.Int array, its type built at runtime
+	input := reflect.ValueOf(args[3])
+	s.Input = make([]*big.Int, input.Len())
+	for i := range s.Input {
+		s.Input[i] = input.Index(i).Interface().(*big.Int)
+	}
+	return &s, nil
+}
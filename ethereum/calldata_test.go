@@ -0,0 +1,191 @@
+package ethereum
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// testProof returns a proof of publicCircuit, its verifying key and public witness
+func testProof(t testing.TB) (groth16.Proof, groth16.VerifyingKey, []fr.Element) {
+	t.Helper()
+	var assignment publicCircuit
+	assignment.X.Assign(3)
+	assignment.Y.Assign(4)
+	assignment.Z.Assign(7)
+
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &publicCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, &assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicWitness, err := PublicWitness(&assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof, vk, publicWitness
+}
+
+// FuzzProofToSolidityInputs checks that a proof and a public witness read from external input, as
+// calldata and submit do, give calldata or an error, without panicking
+func FuzzProofToSolidityInputs(f *testing.F) {
+	proof, _, publicWitness := testProof(f)
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(EncodePublicWitness(publicWitness), buf.Bytes())
+	buf.Reset()
+	if _, err := proof.WriteRawTo(&buf); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(EncodePublicWitness(nil), buf.Bytes())
+	f.Add([]byte{0, 0, 0, 1}, []byte{})
+
+	f.Fuzz(func(t *testing.T, publicWitnessData, proofData []byte) {
+		publicWitness, err := DecodePublicWitness(publicWitnessData)
+		if err != nil {
+			return
+		}
+		proof := groth16.NewProof(ecc.BN254)
+		if _, err := proof.ReadFrom(bytes.NewReader(proofData)); err != nil {
+			return
+		}
+		inputs, err := ProofToSolidityInputs(proof, publicWitness)
+		if err != nil {
+			return
+		}
+		if _, err := inputs.Calldata(); err != nil {
+			t.Fatalf("decoded proof and public witness, but no calldata: %v", err)
+		}
+	})
+}
+
+func TestProofForSolidityABI(t *testing.T) {
+	proof, _, publicWitness := testProof(t)
+	s, err := ProofToSolidityInputs(proof, publicWitness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Input) != len(publicWitness) {
+		t.Fatalf("got %d inputs, expected %d", len(s.Input), len(publicWitness))
+	}
+
+	verifierABI, err := VerifierABI(len(s.Input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := verifierABI.Pack("verifyProof", s.A, s.B, s.C, s.Input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calldata, err := s.Calldata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(calldata, expected) {
+		t.Fatal("Calldata doesn't match the verifyProof encoding of VerifierABI")
+	}
+	packed, err := s.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(packed, expected[4:]) {
+		t.Fatal("Pack doesn't match the verifyProof arguments of VerifierABI")
+	}
+
+	decoded, err := DecodeCalldata(calldata, len(s.Input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalInputs(decoded, s) {
+		t.Fatalf("DecodeCalldata: got %+v, expected %+v", decoded, s)
+	}
+	if _, err := DecodeCalldata(calldata, len(s.Input)+1); err == nil {
+		t.Fatal("decoded calldata for another number of public inputs")
+	}
+}
+
+func TestProofForSolidityEncodings(t *testing.T) {
+	proof, _, publicWitness := testProof(t)
+	s, err := ProofToSolidityInputs(proof, publicWitness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := s.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromText ProofForSolidity
+	if err := fromText.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if !equalInputs(&fromText, s) {
+		t.Fatalf("UnmarshalText: got %+v, expected %+v", fromText, *s)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON ProofForSolidity
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !equalInputs(&fromJSON, s) {
+		t.Fatalf("UnmarshalJSON: got %+v, expected %+v", fromJSON, *s)
+	}
+
+	// fixtures decode too, their calldata ignored
+	fixture, err := s.Fixture()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = json.Marshal(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromFixture ProofForSolidity
+	if err := json.Unmarshal(data, &fromFixture); err != nil {
+		t.Fatal(err)
+	}
+	if !equalInputs(&fromFixture, s) {
+		t.Fatalf("UnmarshalJSON of a fixture: got %+v, expected %+v", fromFixture, *s)
+	}
+
+	for _, bad := range []string{`"0x1234"`, `"0xzz"`} {
+		var p ProofForSolidity
+		if err := json.Unmarshal([]byte(bad), &p); err == nil {
+			t.Errorf("UnmarshalJSON(%s): decoded, expected an error", bad)
+		}
+	}
+	if err := new(ProofForSolidity).UnmarshalText([]byte("0x1234")); err == nil {
+		t.Error("UnmarshalText of 2 bytes: decoded, expected an error")
+	}
+	overflow := `{"a": ["1", "2"], "b": [["1", "2"], ["3", "4"]], "c": ["5", "6"], "input": ["0x1` + strings.Repeat("0", 64) + `"]}`
+	if err := new(ProofForSolidity).UnmarshalJSON([]byte(overflow)); err == nil {
+		t.Error("UnmarshalJSON of a 257 bits input: decoded, expected an error")
+	}
+}
+
+// equalInputs returns true if a and b hold the same values
+func equalInputs(a, b *ProofForSolidity) bool {
+	x, y := a.decimal(), b.decimal()
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,61 @@
+package ethereum
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Deployment records a verifier contract deployed on a chain
+// Upgradeable verifiers are a VerifierProxy (see ExportProxy) at Verifier, forwarding to Implementation.
+type Deployment struct {
+	Verifier       common.Address `json:"verifier"`
+	Implementation common.Address `json:"implementation,omitempty"`
+	TxHash         common.Hash    `json:"txHash"`
+	BlockNumber    uint64         `json:"blockNumber"`
+}
+
+// Deployments maps a (decimal) chain ID to the deployments on that chain, keyed by circuit name
+type Deployments map[string]map[string]Deployment
+
+// ReadDeployments reads a deployments file; a missing file is an empty set of deployments
+func ReadDeployments(fileName string) (Deployments, error) {
+	deployments := make(Deployments)
+	data, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return deployments, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &deployments); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// Get returns the deployment of circuit's verifier on chainID, if any
+func (d Deployments) Get(chainID *big.Int, circuit string) (Deployment, bool) {
+	deployment, ok := d[chainID.String()][circuit]
+	return deployment, ok
+}
+
+// Set records (or replaces) the deployment of circuit's verifier on chainID
+func (d Deployments) Set(chainID *big.Int, circuit string, deployment Deployment) {
+	if d[chainID.String()] == nil {
+		d[chainID.String()] = make(map[string]Deployment)
+	}
+	d[chainID.String()][circuit] = deployment
+}
+
+// Save writes the deployments as indented JSON
+func (d Deployments) Save(fileName string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, append(data, '\n'), 0644)
+}
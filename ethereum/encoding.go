@@ -0,0 +1,176 @@
+package ethereum
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// EncodingCheck is a check of CheckSolidityEncoding
+type EncodingCheck struct {
+	Name string `json:"name"`
+	OK   bool   `json:"ok"`
+	// Problem is the likely mistake when the check fails, e.g. a negated point
+	Problem string `json:"problem,omitempty"`
+}
+
+// CheckSolidityEncoding checks inputs, the verifyProof arguments of proof and publicWitness as
+// ProofToSolidityInputs or any other encoder (e.g. an application's calldata) computed them, against
+// an independent reconstruction: the points of proof decoded from its compressed serialization, in
+// the EVM order, and the public inputs in regular form. A failed check names the encoding mistake
+// it matches (negated point, swapped coordinates or Fp2 coefficients, little endian words, Montgomery
+// form) if any. The last checks are that inputs survive the calldata round trip, and that the proof
+// is valid at all, natively, with the pairing precompile input (see PairingInput).
+func CheckSolidityEncoding(inputs *ProofForSolidity, proof groth16.Proof, vk groth16.VerifyingKey, publicWitness []fr.Element) ([]EncodingCheck, error) {
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	var (
+		a, c bn254.G1Affine
+		b    bn254.G2Affine
+	)
+	dec := bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&a, &b, &c} {
+		if err := dec.Decode(v); err != nil {
+			return nil, fmt.Errorf("decoding the proof: %w", err)
+		}
+	}
+
+	checks := []EncodingCheck{
+		checkWords("a", inputs.A[:], g1Variants(&a)),
+		checkWords("b", append(inputs.B[0][:], inputs.B[1][:]...), g2Variants(&b)),
+		checkWords("c", inputs.C[:], g1Variants(&c)),
+	}
+	if len(inputs.Input) != len(publicWitness) {
+		checks = append(checks, EncodingCheck{Name: "input", Problem: fmt.Sprintf("%d public inputs, the proof has %d", len(inputs.Input), len(publicWitness))})
+	} else {
+		checks = append(checks, checkWords("input", inputs.Input, frVariants(publicWitness)))
+	}
+
+	roundTrip := EncodingCheck{Name: "calldata", OK: true}
+	calldata, err := inputs.Calldata()
+	if err == nil {
+		var decoded *ProofForSolidity
+		if decoded, err = DecodeCalldata(calldata, len(inputs.Input)); err == nil && !sameInputs(decoded, inputs) {
+			roundTrip.OK, roundTrip.Problem = false, "decoded calldata differs from the arguments"
+		}
+	}
+	if err != nil {
+		roundTrip.OK, roundTrip.Problem = false, err.Error()
+	}
+	checks = append(checks, roundTrip)
+
+	valid := EncodingCheck{Name: "proof", Problem: "the proof doesn't verify with this verifying key and public witness: no encoding makes it pass"}
+	input, err := PairingInput(proof, vk, publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	if valid.OK, err = PairingCheck(input); err != nil {
+		return nil, err
+	}
+	if valid.OK {
+		valid.Problem = ""
+	}
+	return append(checks, valid), nil
+}
+
+// encodingVariant is an encoding of the expected words, named after the mistake producing it
+type encodingVariant struct {
+	problem string
+	words   []*big.Int
+}
+
+// checkWords compares got with variants, the first one being the correct encoding
+func checkWords(name string, got []*big.Int, variants []encodingVariant) EncodingCheck {
+	for i, v := range variants {
+		if equalWords(got, v.words) {
+			return EncodingCheck{Name: name, OK: i == 0, Problem: v.problem}
+		}
+	}
+	return EncodingCheck{Name: name, Problem: "matches no known encoding of the proof"}
+}
+
+func g1Variants(p *bn254.G1Affine) []encodingVariant {
+	var neg bn254.G1Affine
+	neg.Neg(p)
+	words := func(p *bn254.G1Affine, word func(*fp.Element) *big.Int) []*big.Int {
+		return []*big.Int{word(&p.X), word(&p.Y)}
+	}
+	return []encodingVariant{
+		{"", words(p, regular)},
+		{"negated point (y is -y)", words(&neg, regular)},
+		{"x and y swapped", []*big.Int{regular(&p.Y), regular(&p.X)}},
+		{"little endian words, the EVM reads big endian", words(p, littleEndian)},
+		{"coordinates in Montgomery form, use ToBigIntRegular", words(p, montgomery)},
+	}
+}
+
+func g2Variants(q *bn254.G2Affine) []encodingVariant {
+	var neg bn254.G2Affine
+	neg.Neg(q)
+	words := func(q *bn254.G2Affine, word func(*fp.Element) *big.Int) []*big.Int {
+		return []*big.Int{word(&q.X.A1), word(&q.X.A0), word(&q.Y.A1), word(&q.Y.A0)}
+	}
+	return []encodingVariant{
+		{"", words(q, regular)},
+		{"negated point (y is -y)", words(&neg, regular)},
+		{"Fp2 coefficients in A0, A1 order, EIP-197 expects A1 (imaginary) first", []*big.Int{regular(&q.X.A0), regular(&q.X.A1), regular(&q.Y.A0), regular(&q.Y.A1)}},
+		{"x and y swapped", []*big.Int{regular(&q.Y.A1), regular(&q.Y.A0), regular(&q.X.A1), regular(&q.X.A0)}},
+		{"little endian words, the EVM reads big endian", words(q, littleEndian)},
+		{"coordinates in Montgomery form, use ToBigIntRegular", words(q, montgomery)},
+	}
+}
+
+func frVariants(publicWitness []fr.Element) []encodingVariant {
+	variants := []encodingVariant{{problem: ""}, {problem: "little endian words, the EVM reads big endian"}, {problem: "inputs in Montgomery form, use ToBigIntRegular"}}
+	for i := range publicWitness {
+		b := publicWitness[i].Bytes()
+		variants[0].words = append(variants[0].words, publicWitness[i].ToBigIntRegular(new(big.Int)))
+		variants[1].words = append(variants[1].words, new(big.Int).SetBytes(reverse(b[:])))
+		variants[2].words = append(variants[2].words, publicWitness[i].ToBigInt(new(big.Int)))
+	}
+	return variants
+}
+
+func regular(e *fp.Element) *big.Int {
+	return e.ToBigIntRegular(new(big.Int))
+}
+
+func montgomery(e *fp.Element) *big.Int {
+	return e.ToBigInt(new(big.Int))
+}
+
+func littleEndian(e *fp.Element) *big.Int {
+	b := e.Bytes()
+	return new(big.Int).SetBytes(reverse(b[:]))
+}
+
+func reverse(b []byte) []byte {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+func equalWords(a, b []*big.Int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] == nil || a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func sameInputs(a, b *ProofForSolidity) bool {
+	return equalWords(a.A[:], b.A[:]) && equalWords(a.B[0][:], b.B[0][:]) && equalWords(a.B[1][:], b.B[1][:]) &&
+		equalWords(a.C[:], b.C[:]) && equalWords(a.Input, b.Input)
+}
@@ -0,0 +1,220 @@
+package ethereum
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"text/template"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Lang is a language the verifier can be exported to
+type Lang string
+
+// Verifier languages; the three verifiers have the same verifyProof ABI
+const (
+	// LangSolidity is gnark's Solidity verifier, with verifyAndRecord (see ExportSolidity)
+	LangSolidity Lang = "sol"
+	// LangYul is a Yul object calling the precompiles directly, cheaper to deploy and call
+	LangYul Lang = "yul"
+	// LangVyper is a Vyper contract
+	LangVyper Lang = "vyper"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var verifierTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"mul32": func(i int) int { return 32 * i },
+}).ParseFS(templates, "templates/*.tmpl"))
+
+// ExportVerifier writes the verifier of vk in lang
+func ExportVerifier(w io.Writer, vk groth16.VerifyingKey, lang Lang) error {
+	var name string
+	switch lang {
+	case LangSolidity:
+		return ExportSolidity(w, vk)
+	case LangYul:
+		name = "verifier.yul.tmpl"
+	case LangVyper:
+		name = "verifier.vy.tmpl"
+	default:
+		return fmt.Errorf("unknown verifier language %q (expected sol, yul or vyper)", lang)
+	}
+	return executeVerifierTemplate(w, vk, name)
+}
+
+// ExportSolidity writes gnark's Solidity verifier of vk, extended with verifyAndRecord: a
+// transaction verifying the proof and emitting ProofVerified(keccak256(input), msg.sender)
+func ExportSolidity(w io.Writer, vk groth16.VerifyingKey) error {
+	return exportSolidity(w, vk, false)
+}
+
+// exportSolidity is ExportSolidity, verifyAndRecord being restricted to recorders if owner is set
+func exportSolidity(w io.Writer, vk groth16.VerifyingKey, owner bool) error {
+	var buf bytes.Buffer
+	if err := vk.ExportSolidity(&buf); err != nil {
+		return err
+	}
+	n, err := NbPublicInputs(vk)
+	if err != nil {
+		return err
+	}
+	// the Verifier contract ends gnark's source
+	source := buf.String()
+	end := strings.LastIndex(source, "}")
+	if end == -1 || !strings.Contains(source, "contract Verifier") {
+		return errors.New("unexpected gnark Solidity verifier layout")
+	}
+	if _, err := io.WriteString(w, source[:end]); err != nil {
+		return err
+	}
+	if err := verifierTemplates.ExecuteTemplate(w, "recorder.sol.tmpl", verifierData{NbPublicInputs: n, Owner: owner}); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, source[end:])
+	return err
+}
+
+// ExportOptimizedSolidity writes a Solidity verifier of vk with the ABI of gnark's, in inline
+// assembly: it decodes the proof from calldata without copies and makes a single pairing call
+func ExportOptimizedSolidity(w io.Writer, vk groth16.VerifyingKey) error {
+	return executeVerifierTemplate(w, vk, "verifier.optimized.sol.tmpl")
+}
+
+// ExportInterface writes IVerifier.sol, the Solidity interface of the verifiers of circuits with
+// nbPublicInputs public inputs, for application contracts calling them
+func ExportInterface(w io.Writer, nbPublicInputs int) error {
+	return verifierTemplates.ExecuteTemplate(w, "IVerifier.sol.tmpl", verifierData{NbPublicInputs: nbPublicInputs})
+}
+
+// VerifierInterface returns the source written by ExportInterface
+func VerifierInterface(nbPublicInputs int) (string, error) {
+	var buf bytes.Buffer
+	if err := ExportInterface(&buf, nbPublicInputs); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// PackingBits is the size of the commitments of PublicInputs.pack, as circuit.PackingBits
+const PackingBits = 253
+
+// ExportPacking writes PublicInputs.sol, the library computing the commitment of the public inputs of
+// circuits packing them (see circuit.PackPublicInputs), for application contracts calling their verifier
+func ExportPacking(w io.Writer) error {
+	return verifierTemplates.ExecuteTemplate(w, "packing.sol.tmpl", struct{ PackingBits int }{PackingBits})
+}
+
+func executeVerifierTemplate(w io.Writer, vk groth16.VerifyingKey, name string) error {
+	data, err := newVerifierData(vk)
+	if err != nil {
+		return err
+	}
+	return verifierTemplates.ExecuteTemplate(w, name, data)
+}
+
+// g1Point is a G1 point in decimal
+type g1Point struct {
+	X, Y string
+}
+
+// g2Point is a G2 point in decimal, in the EVM precompiles order: x.A1, x.A0, y.A1, y.A0
+type g2Point [4]string
+
+// verifierData is the verifying key as the templates use it
+type verifierData struct {
+	NbPublicInputs int
+	// Owner restricts verifyAndRecord to recorders (recorder.sol.tmpl)
+	Owner bool
+	// Selector is the verifyProof selector, and CalldataSize the size of its calldata
+	Selector     string
+	CalldataSize int
+	R            string
+
+	Alpha                       g1Point
+	BetaNeg, GammaNeg, DeltaNeg g2Point
+	G2                          map[string]g2Point
+	IC                          []g1Point
+	// Inputs are the IC points multiplied by the public inputs
+	Inputs []inputPoint
+}
+
+// inputPoint is an IC point, multiplied by the input read at Offset in the calldata
+type inputPoint struct {
+	X, Y   string
+	Offset int
+}
+
+func newVerifierData(vk groth16.VerifyingKey) (*verifierData, error) {
+	var buf bytes.Buffer
+	if _, err := vk.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	// G1.Alpha, G1.Beta, G2.Beta, G2.Gamma, G1.Delta, G2.Delta, G1.K
+	var (
+		alpha, g1                   bn254.G1Affine
+		beta, gamma, delta          bn254.G2Affine
+		k                           []bn254.G1Affine
+		betaNeg, gammaNeg, deltaNeg bn254.G2Affine
+	)
+	dec := bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&alpha, &g1, &beta, &gamma, &g1, &delta, &k} {
+		if err := dec.Decode(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(k) == 0 {
+		return nil, errors.New("invalid verifying key: empty K")
+	}
+	verifierABI, err := VerifierABI(len(k) - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	betaNeg.Neg(&beta)
+	gammaNeg.Neg(&gamma)
+	deltaNeg.Neg(&delta)
+	d := &verifierData{
+		NbPublicInputs: len(k) - 1,
+		Selector:       hexutil.Encode(verifierABI.Methods["verifyProof"].ID),
+		// selector, a, b, c, input
+		CalldataSize: 4 + 32*(8+len(k)-1),
+		R:            fr.Modulus().String(),
+		Alpha:        newG1Point(&alpha),
+		BetaNeg:      newG2Point(&betaNeg),
+		GammaNeg:     newG2Point(&gammaNeg),
+		DeltaNeg:     newG2Point(&deltaNeg),
+		IC:           make([]g1Point, len(k)),
+	}
+	d.G2 = map[string]g2Point{"BETA_NEG": d.BetaNeg, "GAMMA_NEG": d.GammaNeg, "DELTA_NEG": d.DeltaNeg}
+	for i := range k {
+		d.IC[i] = newG1Point(&k[i])
+	}
+	d.Inputs = make([]inputPoint, len(k)-1)
+	for i := range d.Inputs {
+		d.Inputs[i] = inputPoint{X: d.IC[i+1].X, Y: d.IC[i+1].Y, Offset: 4 + 32*(8+i)}
+	}
+	return d, nil
+}
+
+func newG1Point(p *bn254.G1Affine) g1Point {
+	return g1Point{decimal(&p.X), decimal(&p.Y)}
+}
+
+func newG2Point(p *bn254.G2Affine) g2Point {
+	return g2Point{decimal(&p.X.A1), decimal(&p.X.A0), decimal(&p.Y.A1), decimal(&p.Y.A0)}
+}
+
+func decimal(e *fp.Element) string {
+	return e.ToBigIntRegular(new(big.Int)).String()
+}
@@ -0,0 +1,159 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	gethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrReceiptTimeout is returned by WaitReceipt when the transaction isn't mined in time
+var ErrReceiptTimeout = errors.New("timeout waiting for the transaction receipt")
+
+// defaultTip is the priority fee used when the node doesn't suggest one
+var defaultTip = big.NewInt(1e9)
+
+// Fees are the EIP-1559 fee parameters for the next blocks
+//
+// Transactions are typed (EIP-1559) ones paying Tip, with MaxFeePerGas as fee cap; signers that
+// only sign legacy transactions (Ledger) pay GasPrice, the base fee and the tip.
+type Fees struct {
+	// BaseFee is the base fee of the latest block, nil before London
+	BaseFee *big.Int
+	// Tip is the priority fee per gas paid to the block producer
+	Tip *big.Int
+}
+
+// SuggestFees queries the base fee of the latest block, and the node's priority fee suggestion
+// (eth_maxPriorityFeePerGas, 1 gwei if the node doesn't implement it)
+func SuggestFees(ctx context.Context, client *rpc.Client) (Fees, error) {
+	var head struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := client.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return Fees{}, err
+	}
+	fees := Fees{Tip: new(big.Int).Set(defaultTip)}
+	if head.BaseFee != nil {
+		fees.BaseFee = head.BaseFee.ToInt()
+	}
+	var tip hexutil.Big
+	if err := client.CallContext(ctx, &tip, "eth_maxPriorityFeePerGas"); err == nil {
+		fees.Tip = tip.ToInt()
+	}
+	return fees, nil
+}
+
+// GasPrice returns the gas price of a legacy transaction paying Tip on top of the base fee,
+// with room for the base fee to rise in the two next blocks (12.5% per full block)
+// Without base fee (chains before London), the gas price is the tip.
+func (f Fees) GasPrice() *big.Int {
+	if f.BaseFee == nil {
+		return new(big.Int).Set(f.Tip)
+	}
+	// baseFee · (9/8)² < baseFee · 1.27
+	price := new(big.Int).Mul(f.BaseFee, big.NewInt(127))
+	price.Div(price, big.NewInt(100))
+	return price.Add(price, f.Tip)
+}
+
+// MaxFeePerGas returns the fee cap of a typed (EIP-1559) transaction paying Tip: twice the base fee,
+// which covers six full blocks of base fee increase, plus the tip
+// Without base fee, it is the tip.
+func (f Fees) MaxFeePerGas() *big.Int {
+	if f.BaseFee == nil {
+		return new(big.Int).Set(f.Tip)
+	}
+	fee := new(big.Int).Lsh(f.BaseFee, 1)
+	return fee.Add(fee, f.Tip)
+}
+
+// TxData returns the transaction calling to with data: a typed (EIP-1559) one if typed is set and the
+// chain has a base fee, else a legacy one paying GasPrice
+func (f Fees) TxData(nonce uint64, to common.Address, gas uint64, data []byte, typed bool) types.TxData {
+	if typed && f.BaseFee != nil {
+		return &types.DynamicFeeTx{Nonce: nonce, GasTipCap: f.Tip, GasFeeCap: f.MaxFeePerGas(), Gas: gas, To: &to, Value: big.NewInt(0), Data: data}
+	}
+	return &types.LegacyTx{Nonce: nonce, GasPrice: f.GasPrice(), Gas: gas, To: &to, Value: big.NewInt(0), Data: data}
+}
+
+// String implements fmt.Stringer, in gwei
+func (f Fees) String() string {
+	gwei := func(v *big.Int) string {
+		if v == nil {
+			return "n/a"
+		}
+		return new(big.Float).Quo(new(big.Float).SetInt(v), big.NewFloat(1e9)).Text('f', 3)
+	}
+	return fmt.Sprintf("base fee %s gwei, tip %s gwei, max fee %s gwei", gwei(f.BaseFee), gwei(f.Tip), gwei(f.MaxFeePerGas()))
+}
+
+// Nonces hands out consecutive nonces to the accounts sending transactions, starting from their
+// pending nonce, so that several transactions can be sent without waiting for each other
+type Nonces struct {
+	backend bind.ContractTransactor
+	lock    sync.Mutex
+	next    map[common.Address]uint64
+}
+
+// NewNonces returns the nonces of the accounts sending through backend
+func NewNonces(backend bind.ContractTransactor) *Nonces {
+	return &Nonces{backend: backend, next: make(map[common.Address]uint64)}
+}
+
+// Next returns the nonce of the next transaction of account
+func (n *Nonces) Next(ctx context.Context, account common.Address) (uint64, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	nonce, ok := n.next[account]
+	if !ok {
+		var err error
+		if nonce, err = n.backend.PendingNonceAt(ctx, account); err != nil {
+			return 0, err
+		}
+	}
+	n.next[account] = nonce + 1
+	return nonce, nil
+}
+
+// Reset forgets the nonces of account, to be called when one of its transactions was not sent
+func (n *Nonces) Reset(account common.Address) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	delete(n.next, account)
+}
+
+// WaitReceipt polls the receipt of the transaction hash every interval, until it is mined or
+// timeout expires (ErrReceiptTimeout)
+func WaitReceipt(ctx context.Context, backend gethereum.TransactionReader, hash common.Hash, interval, timeout time.Duration) (*types.Receipt, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		receipt, err := backend.TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, gethereum.NotFound) && ctx.Err() == nil {
+			return nil, err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w %s after %s", ErrReceiptTimeout, hash.Hex(), timeout)
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestFeesTxData(t *testing.T) {
+	fees := Fees{BaseFee: big.NewInt(100e9), Tip: big.NewInt(2e9)}
+	to := common.HexToAddress("0x01")
+
+	tx := types.NewTx(fees.TxData(1, to, 21000, nil, true))
+	if tx.Type() != types.DynamicFeeTxType || tx.GasTipCap().Cmp(fees.Tip) != 0 || tx.GasFeeCap().Cmp(big.NewInt(202e9)) != 0 {
+		t.Fatalf("typed transaction of type %d, tip %s, fee cap %s", tx.Type(), tx.GasTipCap(), tx.GasFeeCap())
+	}
+	tx = types.NewTx(fees.TxData(1, to, 21000, nil, false))
+	if tx.Type() != types.LegacyTxType || tx.GasPrice().Cmp(big.NewInt(129e9)) != 0 {
+		t.Fatalf("legacy transaction of type %d, gas price %s", tx.Type(), tx.GasPrice())
+	}
+	// before London, typed or not, the transaction pays the tip as gas price
+	tx = types.NewTx(Fees{Tip: fees.Tip}.TxData(1, to, 21000, nil, true))
+	if tx.Type() != types.LegacyTxType || tx.GasPrice().Cmp(fees.Tip) != 0 {
+		t.Fatalf("transaction without base fee of type %d, gas price %s", tx.Type(), tx.GasPrice())
+	}
+}
+
+// TestDeployRawTypedTx checks that DeployRaw sends a typed transaction on a London chain, and a legacy
+// one when the gas price is set
+func TestDeployRawTypedTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(1337)
+	auth, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := backends.NewSimulatedBackend(core.GenesisAlloc{
+		auth.From: {Balance: new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))},
+	}, 8000029)
+	defer chain.Close()
+
+	// STOP: an empty contract
+	initcode := []byte{0x00}
+	ctx := context.Background()
+	for _, expected := range []uint8{types.DynamicFeeTxType, types.LegacyTxType} {
+		opts := *auth
+		if expected == types.LegacyTxType {
+			head, err := chain.HeaderByNumber(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts.GasPrice = new(big.Int).Mul(head.BaseFee, big.NewInt(2))
+		}
+		_, tx, err := DeployRaw(ctx, &opts, chain, initcode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain.Commit()
+		receipt, err := chain.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.Type() != expected || receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("transaction of type %d, status %d, expected a successful type %d", tx.Type(), receipt.Status, expected)
+		}
+	}
+}
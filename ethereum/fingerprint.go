@@ -0,0 +1,134 @@
+package ethereum
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// vkPoints are the points of a BN254 groth16 verifying key
+type vkPoints struct {
+	alpha              bn254.G1Affine
+	beta, gamma, delta bn254.G2Affine
+	k                  []bn254.G1Affine
+}
+
+func decodeVK(vk groth16.VerifyingKey) (*vkPoints, error) {
+	var buf bytes.Buffer
+	if _, err := vk.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	// G1.Alpha, G1.Beta, G2.Beta, G2.Gamma, G1.Delta, G2.Delta, G1.K
+	var (
+		p  vkPoints
+		g1 bn254.G1Affine
+	)
+	dec := bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&p.alpha, &g1, &p.beta, &p.gamma, &g1, &p.delta, &p.k} {
+		if err := dec.Decode(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.k) == 0 {
+		return nil, errors.New("invalid verifying key: empty K")
+	}
+	return &p, nil
+}
+
+// VKFingerprint returns the canonical hash of vk: the keccak256 of its points as the EVM reads them,
+// 32 bytes big endian words, α (x, y), β, γ, δ (x.A1, x.A0, y.A1, y.A0), then the points of K
+// (x, y). It doesn't depend on how vk was serialized (compressed or not, header), so two parties can
+// compare their keys by their fingerprints, and a contract can compute it.
+func VKFingerprint(vk groth16.VerifyingKey) (common.Hash, error) {
+	p, err := decodeVK(vk)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	words := make([]*fp.Element, 0, 14+2*len(p.k))
+	words = append(words, &p.alpha.X, &p.alpha.Y)
+	for _, q := range []*bn254.G2Affine{&p.beta, &p.gamma, &p.delta} {
+		words = append(words, &q.X.A1, &q.X.A0, &q.Y.A1, &q.Y.A0)
+	}
+	for i := range p.k {
+		words = append(words, &p.k[i].X, &p.k[i].Y)
+	}
+	data := make([]byte, 0, 32*len(words))
+	for _, w := range words {
+		b := w.Bytes()
+		data = append(data, b[:]...)
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// VKConstant is a coordinate of a verifying key point a deployed verifier must embed
+type VKConstant struct {
+	Name  string
+	Value *big.Int
+}
+
+// VKConstants returns the coordinates every verifier of vk embeds in its code, whatever its variant
+// (gnark's, optimized, Yul): the x coordinates, which negating a point leaves unchanged, of α, β, γ,
+// δ and the points of K
+func VKConstants(vk groth16.VerifyingKey) ([]VKConstant, error) {
+	p, err := decodeVK(vk)
+	if err != nil {
+		return nil, err
+	}
+	word := func(e *fp.Element) *big.Int { return e.ToBigIntRegular(new(big.Int)) }
+	constants := []VKConstant{{"alpha.x", word(&p.alpha.X)}}
+	for _, q := range []struct {
+		name string
+		p    *bn254.G2Affine
+	}{{"beta", &p.beta}, {"gamma", &p.gamma}, {"delta", &p.delta}} {
+		constants = append(constants, VKConstant{q.name + ".x.A1", word(&q.p.X.A1)}, VKConstant{q.name + ".x.A0", word(&q.p.X.A0)})
+	}
+	for i := range p.k {
+		constants = append(constants, VKConstant{fmt.Sprintf("IC[%d].x", i), word(&p.k[i].X)})
+	}
+	return constants, nil
+}
+
+// MissingVKConstants returns the names of the constants of vk (see VKConstants) that the runtime
+// code doesn't contain: none for a verifier of vk, nearly all of them for a verifier of another key
+func MissingVKConstants(code []byte, vk groth16.VerifyingKey) ([]string, error) {
+	constants, err := VKConstants(vk)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, c := range constants {
+		// solc pushes constants without their leading zero bytes
+		if !bytes.Contains(code, c.Value.Bytes()) {
+			missing = append(missing, c.Name)
+		}
+	}
+	return missing, nil
+}
+
+// RuntimeCodeMatches reports whether the runtime code deployed on chain is the one creation deploys,
+// the Solidity metadata (the CBOR suffix, which hashes the source file names too) excluded
+func RuntimeCodeMatches(runtime, creation []byte) bool {
+	code := stripMetadata(runtime)
+	return len(code) != 0 && bytes.Contains(creation, code)
+}
+
+// stripMetadata removes the CBOR metadata solc appends to code, its length in the last 2 bytes
+func stripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+	n := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	start := len(code) - 2 - n
+	// a CBOR map of 1 to 5 entries
+	if n == 0 || start < 0 || code[start] < 0xa1 || code[start] > 0xa5 {
+		return code
+	}
+	return code[:start]
+}
@@ -0,0 +1,47 @@
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// foundryData fills the Foundry templates
+type foundryData struct {
+	*ProofFixture
+	NbPublicInputs int
+	SolcVersion    string
+}
+
+// ExportFoundry writes a Foundry project in dir: foundry.toml, the Solidity verifier of vk and
+// IVerifier.sol in src, ProofConsumer.sol, an example application, and test/Verifier.t.sol, a test
+// verifying proof, hard-coded, with and without tampering; proof is also written to
+// test/fixtures/proof.json. foundry.toml pins solcVersion if set. It returns the files written.
+func ExportFoundry(dir string, vk groth16.VerifyingKey, proof *ProofForSolidity, solcVersion string) ([]string, error) {
+	n, err := NbPublicInputs(vk)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case n != len(proof.Input):
+		return nil, fmt.Errorf("%w: got %d, expected %d", ErrPublicInputsCount, len(proof.Input), n)
+	case n == 0:
+		// uint256[0] is not a valid Solidity type
+		return nil, errors.New("the circuit has no public input to test the verifier with")
+	}
+	fixture, err := proof.Fixture()
+	if err != nil {
+		return nil, err
+	}
+	data := foundryData{ProofFixture: fixture, NbPublicInputs: n, SolcVersion: solcVersion}
+	return writeProject(dir, []projectFile{
+		templateFile("foundry.toml", "foundry.toml.tmpl", data),
+		{"src/Verifier.sol", func(w io.Writer) error { return ExportSolidity(w, vk) }},
+		{"src/IVerifier.sol", func(w io.Writer) error { return ExportInterface(w, n) }},
+		templateFile("src/ProofConsumer.sol", "ProofConsumer.sol.tmpl", data),
+		templateFile("test/Verifier.t.sol", "Verifier.t.sol.tmpl", data),
+		jsonFile("test/fixtures/proof.json", fixture),
+	})
+}
@@ -0,0 +1,100 @@
+package ethereum
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/compiler"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// HardhatArtifact is a compiled contract in Hardhat's artifact format, as hre.artifacts reads it and
+// ethers' ContractFactory(abi, bytecode) deploys it
+type HardhatArtifact struct {
+	Format                 string                 `json:"_format"`
+	ContractName           string                 `json:"contractName"`
+	SourceName             string                 `json:"sourceName"`
+	ABI                    interface{}            `json:"abi"`
+	Bytecode               string                 `json:"bytecode"`
+	DeployedBytecode       string                 `json:"deployedBytecode"`
+	LinkReferences         map[string]interface{} `json:"linkReferences"`
+	DeployedLinkReferences map[string]interface{} `json:"deployedLinkReferences"`
+}
+
+// NewHardhatArtifact returns the artifact of the compiled contract name of sourceName
+func NewHardhatArtifact(name, sourceName string, contract *compiler.Contract) HardhatArtifact {
+	return HardhatArtifact{
+		Format:                 "hh-sol-artifact-1",
+		ContractName:           name,
+		SourceName:             sourceName,
+		ABI:                    contract.Info.AbiDefinition,
+		Bytecode:               hexutil.Encode(common.FromHex(contract.Code)),
+		DeployedBytecode:       hexutil.Encode(common.FromHex(contract.RuntimeCode)),
+		LinkReferences:         map[string]interface{}{},
+		DeployedLinkReferences: map[string]interface{}{},
+	}
+}
+
+// ExportHardhat compiles the Solidity verifier of vk (requires solc) and writes a TypeScript project
+// in dir: the source in contracts, its Hardhat artifact (ABI and bytecode) in artifacts, src/gnark.ts,
+// formatting gnark proofs into verifyProof arguments, and scripts/verify.ts, an ethers script
+// deploying the verifier and checking proof, written to fixtures in raw (proof.raw, public.bin) and
+// JSON (proof.json) encodings. It returns the files written.
+func ExportHardhat(dir string, vk groth16.VerifyingKey, proof groth16.Proof, publicWitness []fr.Element) ([]string, error) {
+	if err := CheckPublicWitness(vk, publicWitness); err != nil {
+		return nil, err
+	}
+	if len(publicWitness) == 0 {
+		return nil, errors.New("the circuit has no public input to call the verifier with")
+	}
+	var source bytes.Buffer
+	if err := ExportSolidity(&source, vk); err != nil {
+		return nil, err
+	}
+	contracts, err := CompileSolidity(source.String())
+	if err != nil {
+		return nil, err
+	}
+	contract, ok := contracts["<stdin>:Verifier"]
+	if !ok {
+		return nil, errors.New("contract Verifier not found in solc output")
+	}
+
+	var rawProof bytes.Buffer
+	if _, err := proof.WriteRawTo(&rawProof); err != nil {
+		return nil, err
+	}
+	inputs, err := ProofToSolidityInputs(proof, publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	fixture, err := inputs.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	data := verifierData{NbPublicInputs: len(publicWitness)}
+	return writeProject(dir, []projectFile{
+		templateFile("package.json", "package.json.tmpl", data),
+		templateFile("tsconfig.json", "tsconfig.json.tmpl", data),
+		{"contracts/Verifier.sol", bytesFile(source.Bytes())},
+		jsonFile("artifacts/contracts/Verifier.sol/Verifier.json", NewHardhatArtifact("Verifier", "contracts/Verifier.sol", contract)),
+		templateFile("src/gnark.ts", "gnark.ts.tmpl", data),
+		templateFile("scripts/verify.ts", "verify.ts.tmpl", data),
+		{"fixtures/proof.raw", bytesFile(rawProof.Bytes())},
+		{"fixtures/public.bin", bytesFile(EncodePublicWitness(publicWitness))},
+		jsonFile("fixtures/proof.json", fixture),
+	})
+}
+
+// bytesFile returns the write function of a projectFile holding data
+func bytesFile(data []byte) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
+}
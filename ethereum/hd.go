@@ -0,0 +1,67 @@
+package ethereum
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// ErrInvalidDerivation is returned for the (2⁻¹²⁷ likely) seeds and paths without a valid key
+var ErrInvalidDerivation = errors.New("invalid BIP-32 derivation, use another path")
+
+// DeriveKey derives the private key at path (BIP-32) from a BIP-39 mnemonic and its optional passphrase
+// The mnemonic is used as is, its words and checksum are not checked against the BIP-39 wordlist:
+// a typo derives another (valid, empty) account.
+func DeriveKey(mnemonic, passphrase string, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed := pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), 2048, 64, sha512.New)
+
+	n := crypto.S256().Params().N
+	i := hmacSHA512([]byte("Bitcoin seed"), seed)
+	k, chainCode := new(big.Int).SetBytes(i[:32]), i[32:]
+	if k.Sign() == 0 || k.Cmp(n) >= 0 {
+		return nil, ErrInvalidDerivation
+	}
+	for _, index := range path {
+		var data []byte
+		if index >= 0x80000000 {
+			// hardened child: derived from the private key
+			data = append([]byte{0}, math.PaddedBigBytes(k, 32)...)
+		} else {
+			key, err := crypto.ToECDSA(math.PaddedBigBytes(k, 32))
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&key.PublicKey)
+		}
+		data = append(data, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+		i = hmacSHA512(chainCode, data)
+		il := new(big.Int).SetBytes(i[:32])
+		if il.Cmp(n) >= 0 {
+			return nil, ErrInvalidDerivation
+		}
+		k = il.Add(il, k).Mod(il, n)
+		if k.Sign() == 0 {
+			return nil, ErrInvalidDerivation
+		}
+		chainCode = i[32:]
+	}
+	return crypto.ToECDSA(math.PaddedBigBytes(k, 32))
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
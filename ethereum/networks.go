@@ -0,0 +1,54 @@
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Network is a chain the verifiers can be deployed to
+type Network struct {
+	Name    string
+	ChainID int64
+	// RPCURL is a public, rate limited endpoint: use a provider URL for more than a workshop
+	RPCURL string
+	// Explorer is the block explorer URL, empty for local chains
+	Explorer string
+}
+
+// Networks are the known networks, selected by name with -network
+var Networks = []Network{
+	{Name: "sepolia", ChainID: 11155111, RPCURL: "https://rpc.sepolia.org", Explorer: "https://sepolia.etherscan.io"},
+	{Name: "holesky", ChainID: 17000, RPCURL: "https://ethereum-holesky-rpc.publicnode.com", Explorer: "https://holesky.etherscan.io"},
+	{Name: "local", ChainID: 31337, RPCURL: "http://localhost:8545"},
+}
+
+// LookupNetwork returns the network called name
+func LookupNetwork(name string) (Network, error) {
+	names := make([]string, len(Networks))
+	for i, n := range Networks {
+		if n.Name == name {
+			return n, nil
+		}
+		names[i] = n.Name
+	}
+	return Network{}, fmt.Errorf("unknown network %q (expected %s)", name, strings.Join(names, ", "))
+}
+
+// NetworkOf returns the known network of chainID
+func NetworkOf(chainID *big.Int) (Network, bool) {
+	for _, n := range Networks {
+		if chainID.Cmp(big.NewInt(n.ChainID)) == 0 {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
+
+// TxURL returns the explorer page of a transaction, or "" if the network has no explorer
+func (n Network) TxURL(hash string) string {
+	if n.Explorer == "" {
+		return ""
+	}
+	return n.Explorer + "/tx/" + hash
+}
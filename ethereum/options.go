@@ -0,0 +1,98 @@
+package ethereum
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// ErrInvalidOption is returned for Solidity export options that would produce an invalid source
+var ErrInvalidOption = errors.New("invalid solidity export option")
+
+// SolidityOptions customize the Solidity verifier; the zero value exports it as is
+// The ABI of verifyProof and verifyAndRecord doesn't change, so that bindings (abigen) generated for
+// the default verifier work with customized ones.
+type SolidityOptions struct {
+	// ContractName replaces Verifier as the name of the verifier contract
+	ContractName string
+	// License replaces the SPDX license identifier; the copyright notice of gnark's verifier is kept
+	License string
+	// Pragma replaces the solc version range, ^0.8.0; the verifiers need solc 0.8 or later
+	Pragma string
+	// Owner restricts verifyAndRecord to the recorders set by the owner, the deployer: see setRecorder
+	// and transferOwnership. verifyProof stays open, it is a view.
+	Owner bool
+}
+
+var (
+	reIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	reLicense    = regexp.MustCompile(`^[A-Za-z0-9.+\-() ]+$`)
+	rePragma     = regexp.MustCompile(`^[\^~<>=0-9. |]+$`)
+	reSPDX       = regexp.MustCompile(`(?m)^// SPDX-License-Identifier: .*$`)
+	rePragmaLine = regexp.MustCompile(`(?m)^pragma solidity [^;]+;`)
+)
+
+func (o SolidityOptions) check() error {
+	switch {
+	case o.ContractName != "" && !reIdentifier.MatchString(o.ContractName):
+		return fmt.Errorf("%w: contract name %q is not an identifier", ErrInvalidOption, o.ContractName)
+	case o.ContractName == "Pairing":
+		return fmt.Errorf("%w: Pairing is the name of the verifier's library", ErrInvalidOption)
+	case o.License != "" && !reLicense.MatchString(o.License):
+		return fmt.Errorf("%w: license %q is not an SPDX expression", ErrInvalidOption, o.License)
+	case o.Pragma != "" && !rePragma.MatchString(o.Pragma):
+		return fmt.Errorf("%w: pragma %q is not a version range", ErrInvalidOption, o.Pragma)
+	}
+	return nil
+}
+
+// ExportSolidityWithOptions is ExportSolidity, customized by opts
+func ExportSolidityWithOptions(w io.Writer, vk groth16.VerifyingKey, opts SolidityOptions) error {
+	if err := opts.check(); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := exportSolidity(&buf, vk, opts.Owner); err != nil {
+		return err
+	}
+	return writeCustomized(w, buf.String(), opts)
+}
+
+// ExportOptimizedSolidityWithOptions is ExportOptimizedSolidity, customized by opts; it has no
+// verifyAndRecord, so opts.Owner is refused
+func ExportOptimizedSolidityWithOptions(w io.Writer, vk groth16.VerifyingKey, opts SolidityOptions) error {
+	if err := opts.check(); err != nil {
+		return err
+	}
+	if opts.Owner {
+		return fmt.Errorf("%w: the optimized verifier has no verifyAndRecord to restrict", ErrInvalidOption)
+	}
+	var buf bytes.Buffer
+	if err := ExportOptimizedSolidity(&buf, vk); err != nil {
+		return err
+	}
+	return writeCustomized(w, buf.String(), opts)
+}
+
+// writeCustomized writes the verifier source with the name, license and pragma of opts
+func writeCustomized(w io.Writer, source string, opts SolidityOptions) error {
+	if opts.ContractName != "" {
+		if !strings.Contains(source, "contract Verifier {") {
+			return errors.New("unexpected Solidity verifier layout")
+		}
+		source = strings.Replace(source, "contract Verifier {", "contract "+opts.ContractName+" {", 1)
+	}
+	if opts.License != "" {
+		source = reSPDX.ReplaceAllLiteralString(source, "// SPDX-License-Identifier: "+opts.License)
+	}
+	if opts.Pragma != "" {
+		source = rePragmaLine.ReplaceAllLiteralString(source, "pragma solidity "+opts.Pragma+";")
+	}
+	_, err := io.WriteString(w, source)
+	return err
+}
@@ -0,0 +1,107 @@
+package ethereum
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PairingPrecompile is the address of the bn256 pairing check precompile (EIP-197)
+var PairingPrecompile = common.BytesToAddress([]byte{0x08})
+
+// pairingPairSize is the size of a (G1, G2) pair in the precompile input
+const pairingPairSize = 6 * 32
+
+// PairingInput returns the input of the pairing precompile checking proof against vk and its public
+// inputs, as the Solidity verifier builds it: e(-A, B)·e(α, β)·e(vk_x, γ)·e(C, δ) == 1, where
+// vk_x = K[0] + Σ input[i]·K[i+1]. Each pair is x, y of the G1 point then x.A1, x.A0, y.A1, y.A0 of
+// the G2 point, 32 bytes big endian words. The precompile returns the word 1 iff the proof is valid.
+func PairingInput(proof groth16.Proof, vk groth16.VerifyingKey, publicWitness []fr.Element) ([]byte, error) {
+	if err := CheckPublicWitness(vk, publicWitness); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := vk.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	// G1.Alpha, G1.Beta, G2.Beta, G2.Gamma, G1.Delta, G2.Delta, G1.K
+	var (
+		alpha, g1          bn254.G1Affine
+		beta, gamma, delta bn254.G2Affine
+		k                  []bn254.G1Affine
+	)
+	dec := bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&alpha, &g1, &beta, &gamma, &g1, &delta, &k} {
+		if err := dec.Decode(v); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.Reset()
+	if _, err := proof.WriteRawTo(&buf); err != nil {
+		return nil, err
+	}
+	var (
+		a, c bn254.G1Affine
+		b    bn254.G2Affine
+	)
+	dec = bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&a, &b, &c} {
+		if err := dec.Decode(v); err != nil {
+			return nil, ErrInvalidProof
+		}
+	}
+
+	var vkX bn254.G1Jac
+	vkX.FromAffine(&k[0])
+	for i := range publicWitness {
+		var term bn254.G1Affine
+		term.ScalarMultiplication(&k[i+1], publicWitness[i].ToBigIntRegular(new(big.Int)))
+		vkX.AddMixed(&term)
+	}
+	var vkXAffine, aNeg bn254.G1Affine
+	vkXAffine.FromJacobian(&vkX)
+	aNeg.Neg(&a)
+
+	input := make([]byte, 0, 4*pairingPairSize)
+	for _, pair := range []struct {
+		p *bn254.G1Affine
+		q *bn254.G2Affine
+	}{{&aNeg, &b}, {&alpha, &beta}, {&vkXAffine, &gamma}, {&c, &delta}} {
+		for _, e := range [][32]byte{pair.p.X.Bytes(), pair.p.Y.Bytes(), pair.q.X.A1.Bytes(), pair.q.X.A0.Bytes(), pair.q.Y.A1.Bytes(), pair.q.Y.A0.Bytes()} {
+			input = append(input, e[:]...)
+		}
+	}
+	return input, nil
+}
+
+// PairingCheck runs the pairing precompile on input, natively: it returns what the precompile
+// returns, without an Ethereum node, to tell an encoding issue from an invalid proof
+func PairingCheck(input []byte) (bool, error) {
+	if len(input)%pairingPairSize != 0 {
+		return false, errors.New("pairing input size is not a multiple of 192 bytes")
+	}
+	n := len(input) / pairingPairSize
+	p := make([]bn254.G1Affine, n)
+	q := make([]bn254.G2Affine, n)
+	word := func(i int) []byte { return input[32*i : 32*(i+1)] }
+	for i := 0; i < n; i++ {
+		w := 6 * i
+		p[i].X.SetBytes(word(w))
+		p[i].Y.SetBytes(word(w + 1))
+		q[i].X.A1.SetBytes(word(w + 2))
+		q[i].X.A0.SetBytes(word(w + 3))
+		q[i].Y.A1.SetBytes(word(w + 4))
+		q[i].Y.A0.SetBytes(word(w + 5))
+		if !p[i].IsOnCurve() || !q[i].IsOnCurve() || !q[i].IsInSubGroup() {
+			// the precompile fails the call
+			return false, errors.New("pairing input holds a point not on the curve")
+		}
+	}
+	return bn254.PairingCheck(p, q)
+}
@@ -0,0 +1,52 @@
+package ethereum
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// projectFile is a file of an exported project, its name relative to the project directory
+type projectFile struct {
+	name  string
+	write func(io.Writer) error
+}
+
+// templateFile returns the projectFile name written by the template tmpl, executed with data
+func templateFile(name, tmpl string, data interface{}) projectFile {
+	return projectFile{name, func(w io.Writer) error { return verifierTemplates.ExecuteTemplate(w, tmpl, data) }}
+}
+
+// writeProject writes files in dir, creating their directories, and returns their paths
+func writeProject(dir string, files []projectFile) ([]string, error) {
+	written := make([]string, 0, len(files))
+	for _, file := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(file.name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			return nil, err
+		}
+		f, err := os.Create(fileName)
+		if err != nil {
+			return nil, err
+		}
+		err = file.write(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+		written = append(written, fileName)
+	}
+	return written, nil
+}
+
+// jsonFile returns the projectFile name holding v, indented
+func jsonFile(name string, v interface{}) projectFile {
+	return projectFile{name, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}}
+}
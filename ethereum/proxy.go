@@ -0,0 +1,96 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/consensys/gnark/backend/groth16"
+	gethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ProxyContract is the name of the contract written by ExportProxy
+const ProxyContract = "VerifierProxy"
+
+// proxyABI is the part of the VerifierProxy ABI used without compiling it: upgradeTo
+const proxyABI = `[{"inputs":[{"internalType":"address","name":"newImplementation","type":"address"}],"name":"upgradeTo","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+// proxyImplementationSlot is the ERC-1967 implementation slot, where VerifierProxy stores its verifier
+var proxyImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// ExportProxy writes VerifierProxy, an upgradeable proxy in front of a verifier (see
+// proxy.sol.tmpl), as a standalone Solidity source
+func ExportProxy(w io.Writer) error {
+	if _, err := io.WriteString(w, "// SPDX-License-Identifier: Apache-2.0\n\npragma solidity ^0.8.0;\n\n"); err != nil {
+		return err
+	}
+	return verifierTemplates.ExecuteTemplate(w, "proxy.sol.tmpl", nil)
+}
+
+// ExportUpgradeable writes the Solidity verifier of vk followed by VerifierProxy, in one source
+func ExportUpgradeable(w io.Writer, vk groth16.VerifyingKey) error {
+	if err := ExportSolidity(w, vk); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+	return verifierTemplates.ExecuteTemplate(w, "proxy.sol.tmpl", nil)
+}
+
+// ProxySource returns the source written by ExportProxy
+func ProxySource() (string, error) {
+	var buf bytes.Buffer
+	if err := ExportProxy(&buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// DeployProxy compiles VerifierProxy (requires solc) and deploys it in front of the verifier at
+// implementation, administered by admin
+func DeployProxy(auth *bind.TransactOpts, backend bind.ContractBackend, implementation, admin common.Address) (common.Address, *types.Transaction, error) {
+	parsed, bytecode, err := proxyArtifact()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	address, tx, _, err := bind.DeployContract(auth, parsed, bytecode, backend, implementation, admin)
+	return address, tx, err
+}
+
+// UpgradeProxy sends the upgradeTo transaction pointing the proxy at the verifier at implementation;
+// auth must be the proxy admin
+func UpgradeProxy(auth *bind.TransactOpts, backend bind.ContractBackend, proxy, implementation common.Address) (*types.Transaction, error) {
+	parsed, err := abi.JSON(strings.NewReader(proxyABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(proxy, parsed, backend, backend, backend).Transact(auth, "upgradeTo", implementation)
+}
+
+// ProxyImplementation returns the verifier the proxy currently forwards to, read from its storage:
+// implementation() only answers the admin
+func ProxyImplementation(ctx context.Context, backend gethereum.ChainStateReader, proxy common.Address) (common.Address, error) {
+	slot, err := backend.StorageAt(ctx, proxy, proxyImplementationSlot, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(slot), nil
+}
+
+func proxyArtifact() (abi.ABI, []byte, error) {
+	source, err := ProxySource()
+	if err != nil {
+		return abi.ABI{}, nil, err
+	}
+	contracts, err := CompileSolidity(source)
+	if err != nil {
+		return abi.ABI{}, nil, err
+	}
+	return Artifact(contracts, ProxyContract)
+}
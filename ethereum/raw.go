@@ -0,0 +1,127 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	gethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ReadBytecode reads a hex encoded (optionally 0x prefixed) bytecode file, as written by -init
+func ReadBytecode(fileName string) ([]byte, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBytecode(data)
+}
+
+// DecodeBytecode decodes the content of a bytecode file, see ReadBytecode
+func DecodeBytecode(data []byte) ([]byte, error) {
+	hexCode := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(hexCode, "0x") {
+		hexCode = "0x" + hexCode
+	}
+	bytecode, err := hexutil.Decode(hexCode)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytecode) == 0 {
+		return nil, errors.New("empty bytecode")
+	}
+	return bytecode, nil
+}
+
+// InitCode returns the contract creation code: bytecode followed by the ABI encoded constructor arguments
+// constructor may be nil for contracts without constructor arguments.
+func InitCode(bytecode []byte, constructor abi.Arguments, args ...interface{}) ([]byte, error) {
+	if len(constructor) != len(args) {
+		return nil, errors.New("constructor arguments count mismatch")
+	}
+	initcode := append([]byte{}, bytecode...)
+	if len(args) == 0 {
+		return initcode, nil
+	}
+	packed, err := constructor.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	return append(initcode, packed...), nil
+}
+
+// DeployRaw signs and sends a contract creation transaction for initcode, without any generated binding
+// Nonce, fees and gas limit are taken from auth if set, else from the backend: the transaction is
+// a typed (EIP-1559) one unless auth sets GasPrice or the chain has no base fee.
+func DeployRaw(ctx context.Context, auth *bind.TransactOpts, backend bind.ContractBackend, initcode []byte) (common.Address, *types.Transaction, error) {
+	var err error
+
+	nonce := uint64(0)
+	if auth.Nonce != nil {
+		nonce = auth.Nonce.Uint64()
+	} else if nonce, err = backend.PendingNonceAt(ctx, auth.From); err != nil {
+		return common.Address{}, nil, err
+	}
+
+	gas := auth.GasLimit
+	if gas == 0 {
+		if gas, err = backend.EstimateGas(ctx, gethereum.CallMsg{From: auth.From, Data: initcode}); err != nil {
+			return common.Address{}, nil, err
+		}
+	}
+
+	txData, err := feeTxData(ctx, auth, backend, nonce, gas, initcode)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	tx, err := auth.Signer(auth.From, types.NewTx(txData))
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	if err := backend.SendTransaction(ctx, tx); err != nil {
+		return common.Address{}, nil, err
+	}
+	return crypto.CreateAddress(auth.From, nonce), tx, nil
+}
+
+// feeTxData returns the contract creation of initcode, as a typed (EIP-1559) transaction paying the
+// fees of auth, or suggested by the backend; a legacy one if auth sets GasPrice or the chain has no
+// base fee
+func feeTxData(ctx context.Context, auth *bind.TransactOpts, backend bind.ContractBackend, nonce, gas uint64, initcode []byte) (types.TxData, error) {
+	if auth.GasPrice == nil {
+		head, err := backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if head.BaseFee != nil {
+			fees := Fees{BaseFee: head.BaseFee, Tip: auth.GasTipCap}
+			if fees.Tip == nil {
+				if fees.Tip, err = backend.SuggestGasTipCap(ctx); err != nil {
+					return nil, err
+				}
+			}
+			feeCap := auth.GasFeeCap
+			if feeCap == nil {
+				feeCap = fees.MaxFeePerGas()
+			}
+			return &types.DynamicFeeTx{Nonce: nonce, GasTipCap: fees.Tip, GasFeeCap: feeCap, Gas: gas, Value: big.NewInt(0), Data: initcode}, nil
+		}
+	}
+
+	gasPrice := auth.GasPrice
+	if gasPrice == nil {
+		var err error
+		if gasPrice, err = backend.SuggestGasPrice(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return &types.LegacyTx{Nonce: nonce, GasPrice: gasPrice, Gas: gas, Value: big.NewInt(0), Data: initcode}, nil
+}
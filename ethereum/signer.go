@@ -0,0 +1,133 @@
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNoLedger is returned by OpenLedger when no Ledger device is connected
+var ErrNoLedger = errors.New("no Ledger device found: is it plugged in and unlocked?")
+
+// Signer signs the transactions of an account
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// SignsTypedTx reports whether s signs typed (EIP-1559) transactions: go-ethereum's Ledger driver only
+// signs legacy ones
+func SignsTypedTx(s Signer) bool {
+	_, ledger := s.(*Ledger)
+	return !ledger
+}
+
+// NewTransactor returns the options of the contract bindings sending transactions signed by s
+func NewTransactor(s Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: s.Address(),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != s.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.SignTx(tx, chainID)
+		},
+		Context: context.Background(),
+	}
+}
+
+// KeySigner signs with a private key held in memory
+type KeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKeySigner returns the signer of key
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+// KeystoreSigner decrypts a go-ethereum keystore file (geth account new, clef)
+func KeystoreSigner(fileName, passphrase string) (*KeySigner, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+// MnemonicSigner signs with the key at path derived from a BIP-39 mnemonic, see DeriveKey
+func MnemonicSigner(mnemonic, passphrase string, path accounts.DerivationPath) (*KeySigner, error) {
+	key, err := DeriveKey(mnemonic, passphrase, path)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(key), nil
+}
+
+// Address implements Signer
+func (s *KeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// SignTx implements Signer
+func (s *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// Ledger signs with the Ethereum app of a Ledger device; each transaction is confirmed on the device
+// Its transactions are legacy ones, see SignsTypedTx.
+type Ledger struct {
+	wallet  accounts.Wallet
+	account accounts.Account
+}
+
+// OpenLedger opens the first connected Ledger, and derives the account at path
+func OpenLedger(path accounts.DerivationPath) (*Ledger, error) {
+	hub, err := usbwallet.NewLedgerHub()
+	if err != nil {
+		return nil, err
+	}
+	wallets := hub.Wallets()
+	if len(wallets) == 0 {
+		return nil, ErrNoLedger
+	}
+	wallet := wallets[0]
+	if err := wallet.Open(""); err != nil {
+		return nil, err
+	}
+	account, err := wallet.Derive(path, true)
+	if err != nil {
+		wallet.Close()
+		return nil, err
+	}
+	return &Ledger{wallet: wallet, account: account}, nil
+}
+
+// Close releases the device
+func (l *Ledger) Close() error {
+	return l.wallet.Close()
+}
+
+// Address implements Signer
+func (l *Ledger) Address() common.Address {
+	return l.account.Address
+}
+
+// SignTx implements Signer
+func (l *Ledger) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return l.wallet.SignTx(l.account, tx, chainID)
+}
@@ -0,0 +1,88 @@
+package ethereum
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/compiler"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Solc is the solc binary used to compile contracts
+var Solc = "solc"
+
+// CompileSolidity compiles a Solidity source; contracts are keyed by "<stdin>:Name"
+func CompileSolidity(source string) (map[string]*compiler.Contract, error) {
+	return compiler.CompileSolidityString(Solc, source)
+}
+
+// Artifact returns the parsed ABI and the creation bytecode of the compiled contract name
+func Artifact(contracts map[string]*compiler.Contract, name string) (abi.ABI, []byte, error) {
+	contract, ok := contracts["<stdin>:"+name]
+	if !ok {
+		return abi.ABI{}, nil, fmt.Errorf("contract %s not found in solc output", name)
+	}
+	abiJSON, err := json.Marshal(contract.Info.AbiDefinition)
+	if err != nil {
+		return abi.ABI{}, nil, err
+	}
+	parsed, err := abi.JSON(bytes.NewReader(abiJSON))
+	if err != nil {
+		return abi.ABI{}, nil, err
+	}
+	return parsed, common.FromHex(contract.Code), nil
+}
+
+// DeployContract compiles source and deploys the contract called name with the constructor params
+func DeployContract(auth *bind.TransactOpts, backend bind.ContractBackend, source, name string, params ...interface{}) (common.Address, *bind.BoundContract, error) {
+	contracts, err := CompileSolidity(source)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	parsed, bytecode, err := Artifact(contracts, name)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	address, _, bound, err := bind.DeployContract(auth, parsed, bytecode, backend, params...)
+	return address, bound, err
+}
+
+// GenerateBindings returns the Go bindings of the compiled contracts, as `abigen --sol` would
+func GenerateBindings(contracts map[string]*compiler.Contract, pkg string) (string, error) {
+	// sort contracts so the generated code is stable
+	names := make([]string, 0, len(contracts))
+	for name := range contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		types []string
+		abis  []string
+		bins  []string
+		sigs  []map[string]string
+		libs  = make(map[string]string)
+	)
+	for _, name := range names {
+		contract := contracts[name]
+		abiJSON, err := json.Marshal(contract.Info.AbiDefinition)
+		if err != nil {
+			return "", err
+		}
+		nameParts := strings.Split(name, ":")
+		typeName := nameParts[len(nameParts)-1]
+
+		abis = append(abis, string(abiJSON))
+		bins = append(bins, contract.Code)
+		sigs = append(sigs, contract.Hashes)
+		types = append(types, typeName)
+		libs[crypto.Keccak256Hash([]byte(name)).String()[2:36]] = typeName
+	}
+	return bind.Bind(types, abis, bins, sigs, pkg, bind.LangGo, libs, nil)
+}
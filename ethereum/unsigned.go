@@ -0,0 +1,73 @@
+package ethereum
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// UnsignedTx is an unsigned typed (EIP-1559) transaction, in the JSON-RPC encoding of
+// eth_signTransaction, for offline signers and wallets
+type UnsignedTx struct {
+	Type    hexutil.Uint64  `json:"type"`
+	ChainID *hexutil.Big    `json:"chainId"`
+	From    *common.Address `json:"from,omitempty"`
+	// Nonce is only set when From is known
+	Nonce                *hexutil.Uint64 `json:"nonce,omitempty"`
+	To                   common.Address  `json:"to"`
+	Value                *hexutil.Big    `json:"value"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Data                 hexutil.Bytes   `json:"data"`
+}
+
+// NewUnsignedTx returns the transaction calling to with data on chainID, paying fees
+func NewUnsignedTx(chainID *big.Int, to common.Address, data []byte, gas uint64, fees Fees) *UnsignedTx {
+	return &UnsignedTx{
+		Type:                 2,
+		ChainID:              (*hexutil.Big)(chainID),
+		To:                   to,
+		Value:                (*hexutil.Big)(new(big.Int)),
+		Gas:                  hexutil.Uint64(gas),
+		MaxFeePerGas:         (*hexutil.Big)(fees.MaxFeePerGas()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(fees.Tip),
+		Data:                 data,
+	}
+}
+
+// SafeBatch is a batch of the Safe{Wallet} Transaction Builder app (its JSON import format): the
+// Safe owners sign and execute the calls from the Safe
+type SafeBatch struct {
+	Version      string            `json:"version"`
+	ChainID      string            `json:"chainId"`
+	CreatedAt    int64             `json:"createdAt"`
+	Meta         SafeBatchMeta     `json:"meta"`
+	Transactions []SafeTransaction `json:"transactions"`
+}
+
+// SafeBatchMeta describes a SafeBatch
+type SafeBatchMeta struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// SafeTransaction is a call of a SafeBatch, with raw calldata
+type SafeTransaction struct {
+	To    common.Address `json:"to"`
+	Value string         `json:"value"`
+	Data  hexutil.Bytes  `json:"data"`
+}
+
+// NewSafeBatch returns the batch of the transactions txs, which must be on the same chain
+func NewSafeBatch(name string, txs ...*UnsignedTx) *SafeBatch {
+	b := &SafeBatch{Version: "1.0", CreatedAt: time.Now().UnixNano() / int64(time.Millisecond), Meta: SafeBatchMeta{Name: name}}
+	for _, tx := range txs {
+		b.ChainID = strconv.FormatUint(tx.ChainID.ToInt().Uint64(), 10)
+		b.Transactions = append(b.Transactions, SafeTransaction{To: tx.To, Value: tx.Value.ToInt().String(), Data: tx.Data})
+	}
+	return b
+}
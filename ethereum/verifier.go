@@ -0,0 +1,203 @@
+package ethereum
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// ErrPublicInputsCount is returned when the number of public inputs doesn't match the verifying key
+var ErrPublicInputsCount = errors.New("number of public inputs doesn't match the verifying key")
+
+// verifierABITemplate is the ABI of the gnark exported verifier; %[1]d is the number of public inputs
+// verifyAndRecord and ProofVerified are added by ExportSolidity.
+const verifierABITemplate = `[{"inputs":[{"internalType":"uint256[2]","name":"a","type":"uint256[2]"},{"internalType":"uint256[2][2]","name":"b","type":"uint256[2][2]"},{"internalType":"uint256[2]","name":"c","type":"uint256[2]"},{"internalType":"uint256[%[1]d]","name":"input","type":"uint256[%[1]d]"}],"name":"verifyProof","outputs":[{"internalType":"bool","name":"r","type":"bool"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"internalType":"uint256[2]","name":"a","type":"uint256[2]"},{"internalType":"uint256[2][2]","name":"b","type":"uint256[2][2]"},{"internalType":"uint256[2]","name":"c","type":"uint256[2]"},{"internalType":"uint256[%[1]d]","name":"input","type":"uint256[%[1]d]"}],"name":"verifyAndRecord","outputs":[{"internalType":"bool","name":"r","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"publicInputHash","type":"bytes32"},{"indexed":false,"internalType":"address","name":"prover","type":"address"}],"name":"ProofVerified","type":"event"}]`
+
+// VerifierABI returns the ABI of the exported Solidity verifier of a circuit with nbPublicInputs public inputs
+func VerifierABI(nbPublicInputs int) (abi.ABI, error) {
+	return abi.JSON(strings.NewReader(fmt.Sprintf(verifierABITemplate, nbPublicInputs)))
+}
+
+// NbPublicInputs returns the number of public inputs expected by a (BN254) verifying key
+func NbPublicInputs(vk groth16.VerifyingKey) (int, error) {
+	var buf bytes.Buffer
+	if _, err := vk.WriteTo(&buf); err != nil {
+		return 0, err
+	}
+
+	// G1.Alpha, G1.Beta, G2.Beta, G2.Gamma, G1.Delta, G2.Delta, G1.K
+	var (
+		g1 bn254.G1Affine
+		g2 bn254.G2Affine
+		k  []bn254.G1Affine
+	)
+	dec := bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&g1, &g1, &g2, &g2, &g1, &g2, &k} {
+		if err := dec.Decode(v); err != nil {
+			return 0, err
+		}
+	}
+	if len(k) == 0 {
+		return 0, errors.New("invalid verifying key: empty K")
+	}
+
+	// K[0] is the constant term, one point per public input follows
+	return len(k) - 1, nil
+}
+
+// CheckPublicWitness returns ErrPublicInputsCount if publicWitness doesn't have the size vk expects
+func CheckPublicWitness(vk groth16.VerifyingKey, publicWitness []fr.Element) error {
+	n, err := NbPublicInputs(vk)
+	if err != nil {
+		return err
+	}
+	if n != len(publicWitness) {
+		return fmt.Errorf("%w: got %d, expected %d", ErrPublicInputsCount, len(publicWitness), n)
+	}
+	return nil
+}
+
+// Verifier is a handle on a deployed exported verifier, for any number of public inputs
+// Unlike the abigen wrapper, its verifyProof input size is set at runtime.
+type Verifier struct {
+	Address        common.Address
+	nbPublicInputs int
+	contract       *bind.BoundContract
+}
+
+// NewVerifier binds the verifier deployed at address
+func NewVerifier(address common.Address, backend bind.ContractBackend, nbPublicInputs int) (*Verifier, error) {
+	parsed, err := VerifierABI(nbPublicInputs)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{
+		Address:        address,
+		nbPublicInputs: nbPublicInputs,
+		contract:       bind.NewBoundContract(address, parsed, backend, backend, backend),
+	}, nil
+}
+
+// VerifyProof calls the verifyProof view function
+func (v *Verifier) VerifyProof(opts *bind.CallOpts, inputs *ProofForSolidity) (bool, error) {
+	if len(inputs.Input) != v.nbPublicInputs {
+		return false, ErrPublicInputsCount
+	}
+	var out []interface{}
+	if err := v.contract.Call(opts, &out, "verifyProof", inputs.A, inputs.B, inputs.C, inputs.Input); err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// VerifyAndRecord sends a verifyAndRecord transaction, which reverts if the proof is invalid
+// Verifiers exported before ExportSolidity added it don't have the method.
+func (v *Verifier) VerifyAndRecord(auth *bind.TransactOpts, inputs *ProofForSolidity) (*types.Transaction, error) {
+	if len(inputs.Input) != v.nbPublicInputs {
+		return nil, ErrPublicInputsCount
+	}
+	return v.contract.Transact(auth, "verifyAndRecord", inputs.A, inputs.B, inputs.C, inputs.Input)
+}
+
+// ProofVerified is a ProofVerified event, emitted by verifyAndRecord
+type ProofVerified struct {
+	// PublicInputHash is PublicInputHash of the public inputs of the proof
+	PublicInputHash common.Hash
+	Prover          common.Address
+	Raw             types.Log
+}
+
+// PublicInputHash returns keccak256(abi.encodePacked(input)), as recorded by verifyAndRecord
+func PublicInputHash(publicWitness []fr.Element) common.Hash {
+	return crypto.Keccak256Hash(packPublicWitness(publicWitness))
+}
+
+// FilterProofVerified returns the past ProofVerified events, in chain order
+func (v *Verifier) FilterProofVerified(opts *bind.FilterOpts) ([]ProofVerified, error) {
+	logs, sub, err := v.contract.FilterLogs(opts, "ProofVerified")
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+
+	var events []ProofVerified
+	for {
+		select {
+		case log := <-logs:
+			if e, ok := parseProofVerified(log); ok {
+				events = append(events, e)
+			}
+		case err := <-sub.Err():
+			if err != nil {
+				return nil, err
+			}
+			// the subscription ends once every log is buffered, logs is never closed
+			for {
+				select {
+				case log := <-logs:
+					if e, ok := parseProofVerified(log); ok {
+						events = append(events, e)
+					}
+				default:
+					return events, nil
+				}
+			}
+		}
+	}
+}
+
+// WatchProofVerified sends the new ProofVerified events to sink, until the subscription is cancelled
+func (v *Verifier) WatchProofVerified(opts *bind.WatchOpts, sink chan<- ProofVerified) (event.Subscription, error) {
+	logs, sub, err := v.contract.WatchLogs(opts, "ProofVerified")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				e, ok := parseProofVerified(log)
+				if !ok {
+					continue
+				}
+				select {
+				case sink <- e:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+func parseProofVerified(log types.Log) (ProofVerified, bool) {
+	// the hash is indexed, the prover is the data
+	if len(log.Topics) != 2 || len(log.Data) != 32 {
+		return ProofVerified{}, false
+	}
+	return ProofVerified{
+		PublicInputHash: log.Topics[1],
+		Prover:          common.BytesToAddress(log.Data),
+		Raw:             log,
+	}, true
+}
@@ -0,0 +1,75 @@
+package ethereum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PublicWitness returns the public inputs of a (full or public) circuit assignment,
+// in the order the verifier expects them
+// It is the single derivation of the public inputs: the public witness file, the JSON outputs
+// and the Solidity inputs are all encoded from its result.
+func PublicWitness(assignment frontend.Circuit) ([]fr.Element, error) {
+	var buf bytes.Buffer
+	if _, err := witness.WritePublicTo(&buf, ecc.BN254, assignment); err != nil {
+		return nil, err
+	}
+	return DecodePublicWitness(buf.Bytes())
+}
+
+// DecodePublicWitness decodes a binary public witness (as written by witness.WritePublicTo):
+// a big endian uint32, the number of elements, then the big endian fr.Bytes long field elements
+func DecodePublicWitness(data []byte) ([]fr.Element, error) {
+	if len(data) < 4 {
+		return nil, errors.New("public witness is missing its length prefix")
+	}
+	n := binary.BigEndian.Uint32(data[:4])
+	data = data[4:]
+	if uint64(len(data)) != uint64(n)*fr.Bytes {
+		return nil, fmt.Errorf("public witness of %d elements is %d bytes long, expected %d", n, len(data), uint64(n)*fr.Bytes)
+	}
+
+	publicWitness := make([]fr.Element, n)
+	for i := 0; i < len(publicWitness); i++ {
+		publicWitness[i].SetBytes(data[i*fr.Bytes : (i+1)*fr.Bytes])
+	}
+	return publicWitness, nil
+}
+
+// EncodePublicWitness returns the binary encoding of publicWitness, as written by
+// witness.WritePublicTo and read by DecodePublicWitness and groth16.ReadAndVerify
+func EncodePublicWitness(publicWitness []fr.Element) []byte {
+	data := make([]byte, 4, 4+len(publicWitness)*fr.Bytes)
+	binary.BigEndian.PutUint32(data, uint32(len(publicWitness)))
+	return append(data, packPublicWitness(publicWitness)...)
+}
+
+// packPublicWitness returns the concatenation of the 32 bytes big endian public inputs,
+// abi.encodePacked of the uint256 array
+func packPublicWitness(publicWitness []fr.Element) []byte {
+	data := make([]byte, 0, len(publicWitness)*fr.Bytes)
+	for i := range publicWitness {
+		b := publicWitness[i].Bytes()
+		data = append(data, b[:]...)
+	}
+	return data
+}
+
+// PublicWitnessHex returns publicWitness as 0x prefixed, 32 bytes big endian hex strings,
+// the uint256 words of the verifyProof calldata
+func PublicWitnessHex(publicWitness []fr.Element) []string {
+	words := make([]string, len(publicWitness))
+	for i := range publicWitness {
+		b := publicWitness[i].Bytes()
+		words[i] = hexutil.Encode(b[:])
+	}
+	return words
+}
@@ -0,0 +1,96 @@
+package ethereum
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+// publicCircuit has two public inputs and a secret one
+type publicCircuit struct {
+	X, Y frontend.Variable `gnark:",public"`
+	Z    frontend.Variable
+}
+
+func (c *publicCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Add(c.X, c.Y), c.Z)
+	return nil
+}
+
+func TestPublicWitness(t *testing.T) {
+	var assignment publicCircuit
+	assignment.X.Assign(3)
+	assignment.Y.Assign(4)
+	assignment.Z.Assign(7)
+
+	publicWitness, err := PublicWitness(&assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(publicWitness) != 2 {
+		t.Fatalf("got %d public inputs, expected 2", len(publicWitness))
+	}
+	if publicWitness[0].String() != "3" || publicWitness[1].String() != "4" {
+		t.Fatalf("got public inputs %s, %s, expected 3, 4", publicWitness[0].String(), publicWitness[1].String())
+	}
+}
+
+func TestDecodePublicWitnessLength(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		{0, 0, 0},
+		{0, 0, 0, 1},
+		append([]byte{0, 0, 0, 0}, make([]byte, 32)...),
+		append([]byte{0, 0, 0, 2}, make([]byte, 32)...),
+	} {
+		if _, err := DecodePublicWitness(data); err == nil {
+			t.Errorf("%x: decoded, expected an error", data)
+		}
+	}
+	publicWitness, err := DecodePublicWitness([]byte{0, 0, 0, 0})
+	if err != nil || len(publicWitness) != 0 {
+		t.Fatalf("empty public witness: got %d elements, %v", len(publicWitness), err)
+	}
+}
+
+func TestEncodePublicWitnessRoundTrip(t *testing.T) {
+	var assignment publicCircuit
+	assignment.X.Assign(3)
+	assignment.Y.Assign(4)
+	assignment.Z.Assign(7)
+
+	var expected bytes.Buffer
+	if _, err := witness.WritePublicTo(&expected, ecc.BN254, &assignment); err != nil {
+		t.Fatal(err)
+	}
+	publicWitness, err := DecodePublicWitness(expected.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := EncodePublicWitness(publicWitness)
+	if !bytes.Equal(data, expected.Bytes()) {
+		t.Fatalf("EncodePublicWitness: got %x, witness.WritePublicTo wrote %x", data, expected.Bytes())
+	}
+
+	// the encoding is the one groth16.ReadAndVerify reads
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &publicCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, &assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.ReadAndVerify(proof, vk, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gbotrel/gnark-workshop/exercises"
+)
+
+// runExercise grades an exercise of package exercises: its circuit must compile, then pass each check
+// Without argument, it lists the exercises.
+func runExercise(args []string) {
+	fs := flag.NewFlagSet("exercise", flag.ExitOnError)
+	assertNoError(fs.Parse(args))
+
+	if fs.NArg() == 0 {
+		var result []exerciseInfo
+		for _, name := range exercises.Names() {
+			e, err := exercises.Get(name)
+			assertNoError(err)
+			result = append(result, exerciseInfo{Name: name, Description: e.Description, Checks: len(e.Checks)})
+			if !jsonOutput() {
+				fmt.Printf("%s\t%s\n", name, e.Description)
+			}
+		}
+		emit("exercise", result)
+		return
+	}
+
+	e, err := exercises.Get(fs.Arg(0))
+	assertNoError(err)
+	log.Printf("compiling %s", e.Name)
+	results, err := e.Run()
+	if err != nil {
+		log.Fatal(err, "; fix it before the checks can run")
+	}
+
+	result := exerciseResult{Exercise: e.Name}
+	for _, r := range results {
+		check := exerciseCheck{Check: r.Check, Passed: r.Err == nil}
+		if r.Err != nil {
+			check.Error = r.Err.Error()
+		} else {
+			result.Passed++
+		}
+		result.Checks = append(result.Checks, check)
+	}
+	if !jsonOutput() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, c := range result.Checks {
+			verdict := "ok"
+			if !c.Passed {
+				verdict = "FAIL: " + c.Error
+			}
+			fmt.Fprintf(w, "%s\t%s\t\n", c.Check, verdict)
+		}
+		assertNoError(w.Flush())
+		fmt.Printf("%d/%d checks passed\n", result.Passed, len(result.Checks))
+	}
+	emit("exercise", result)
+	if result.Passed != len(result.Checks) {
+		os.Exit(1)
+	}
+}
+
+// exerciseInfo describes an exercise; the JSON output of exercise without argument is a list of them
+type exerciseInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Checks      int    `json:"checks"`
+}
+
+// exerciseResult is the JSON output of exercise <name>
+type exerciseResult struct {
+	Exercise string          `json:"exercise"`
+	Passed   int             `json:"passed"`
+	Checks   []exerciseCheck `json:"checks"`
+}
+
+type exerciseCheck struct {
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
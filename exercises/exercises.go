@@ -0,0 +1,106 @@
+// Package exercises holds the coding exercises of the workshop: circuits with their constraints left
+// to write, and the checks that grade them, run with
+//
+//	go run . exercise <name>
+//
+// The checks compile the circuit and solve it with assignments it must accept and assignments it must
+// reject, one per requirement; they don't show the assignments, only which requirement failed. The
+// reference solutions build with the solution tag:
+//
+//	go run -tags solution . exercise <name>
+package exercises
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrUnknownExercise is returned by Get for names no exercise is registered under
+var ErrUnknownExercise = errors.New("unknown exercise")
+
+// Exercise is a circuit to complete, and the checks grading it, in order
+type Exercise struct {
+	Name        string
+	Description string
+	// Circuit returns a circuit to compile, with its constraints as written by the attendee
+	Circuit func() frontend.Circuit
+	Checks  []Check
+}
+
+// Check is a requirement of an exercise: the compiled circuit accepts (Valid) or rejects the
+// assignment returned by Witness
+type Check struct {
+	Name    string
+	Valid   bool
+	Witness func() (frontend.Circuit, error)
+}
+
+// Result is the outcome of a check; Err is nil for passed checks
+type Result struct {
+	Check string
+	Err   error
+}
+
+var exercises = make(map[string]*Exercise)
+
+// Register registers e under its name
+func Register(e *Exercise) {
+	if _, ok := exercises[e.Name]; ok {
+		panic("exercise " + e.Name + " registered twice")
+	}
+	exercises[e.Name] = e
+}
+
+// Get returns the exercise registered under name
+func Get(name string) (*Exercise, error) {
+	e, ok := exercises[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (known: %v)", ErrUnknownExercise, name, Names())
+	}
+	return e, nil
+}
+
+// Names returns the names of the registered exercises, sorted
+func Names() []string {
+	names := make([]string, 0, len(exercises))
+	for name := range exercises {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run compiles the circuit of e and runs its checks, in order; an error is returned if the circuit
+// doesn't compile, in which case no check runs
+func (e *Exercise) Run() ([]Result, error) {
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, e.Circuit())
+	if err != nil {
+		return nil, fmt.Errorf("compiling %s: %w", e.Name, err)
+	}
+	results := make([]Result, len(e.Checks))
+	for i, check := range e.Checks {
+		results[i] = Result{Check: check.Name, Err: run(r1cs, check)}
+	}
+	return results, nil
+}
+
+func run(r1cs frontend.CompiledConstraintSystem, check Check) error {
+	witness, err := check.Witness()
+	if err != nil {
+		return err
+	}
+	err = groth16.IsSolved(r1cs, witness)
+	switch {
+	case check.Valid && err != nil:
+		return fmt.Errorf("a valid assignment is rejected: %w", err)
+	case !check.Valid && err == nil:
+		return errors.New("an invalid assignment is accepted")
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build solution
+// +build solution
+
+package exercises
+
+import "testing"
+
+// TestSolutions grades the reference solutions: go test -tags solution ./exercises
+// Without the tag, the exercises are the attendees' and are graded by go run . exercise <name>.
+func TestSolutions(t *testing.T) {
+	for _, name := range Names() {
+		e, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results, err := e.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				t.Errorf("%s: %s: %v", name, r.Check, r.Err)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package exercises
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Sudoku defines the knowledge of the solution of a public Sudoku puzzle
+// Empty cells of the puzzle are 0. The solution has a digit 1..9 in each cell, different digits in
+// each row, column and 3x3 box, and agrees with the given cells of the puzzle.
+//
+// Its Define method is the exercise (sudoku_exercise.go), see sudoku_solution.go for the answer.
+type Sudoku struct {
+	Puzzle   [9][9]frontend.Variable `gnark:",public"`
+	Solution [9][9]frontend.Variable
+}
+
+// Grid is a Sudoku grid, 0 for empty cells
+type Grid [9][9]int
+
+var (
+	// ErrInvalidGrid is returned for grids that are not a Sudoku grid, or not a solution
+	ErrInvalidGrid = errors.New("invalid sudoku grid")
+
+	// ErrNotASolution is returned for solutions that don't agree with the puzzle
+	ErrNotASolution = errors.New("the solution doesn't solve the puzzle")
+)
+
+// ParseGrid parses a grid written as 81 digits, row by row, 0 or . for empty cells; whitespace and
+// the |, -, + separators are ignored
+func ParseGrid(s string) (Grid, error) {
+	var g Grid
+	n := 0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune(" \t\r\n|-+", r):
+			continue
+		case n == 81:
+			return g, fmt.Errorf("%w: more than 81 cells", ErrInvalidGrid)
+		case r == '.':
+			g[n/9][n%9] = 0
+		case r >= '0' && r <= '9':
+			g[n/9][n%9] = int(r - '0')
+		default:
+			return g, fmt.Errorf("%w: unexpected %q", ErrInvalidGrid, r)
+		}
+		n++
+	}
+	if n != 81 {
+		return g, fmt.Errorf("%w: %d cells, expected 81", ErrInvalidGrid, n)
+	}
+	return g, nil
+}
+
+func (g Grid) String() string {
+	var sb strings.Builder
+	for i, row := range g {
+		for _, d := range row {
+			sb.WriteByte(byte('0' + d))
+		}
+		if i != 8 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// IsSolution reports whether g is a complete, valid Sudoku grid
+func (g Grid) IsSolution() bool {
+	for i := 0; i < 9; i++ {
+		var row, column, box [10]bool
+		for j := 0; j < 9; j++ {
+			for _, c := range []struct {
+				d    int
+				seen *[10]bool
+			}{{g[i][j], &row}, {g[j][i], &column}, {g[3*(i/3)+j/3][3*(i%3)+j%3], &box}} {
+				if c.d < 1 || c.d > 9 || c.seen[c.d] {
+					return false
+				}
+				c.seen[c.d] = true
+			}
+		}
+	}
+	return true
+}
+
+// NewSudokuWitness returns the assignment of the solution of puzzle
+func NewSudokuWitness(puzzle, solution Grid) (*Sudoku, error) {
+	if !solution.IsSolution() {
+		return nil, ErrInvalidGrid
+	}
+	for i := range puzzle {
+		for j, d := range puzzle[i] {
+			if d < 0 || d > 9 {
+				return nil, ErrInvalidGrid
+			}
+			if d != 0 && d != solution[i][j] {
+				return nil, fmt.Errorf("%w: cell (%d, %d) is %d, not %d", ErrNotASolution, i, j, solution[i][j], d)
+			}
+		}
+	}
+	return sudokuAssignment(puzzle, solution), nil
+}
+
+// sudokuAssignment assigns puzzle and solution as is, valid or not
+func sudokuAssignment(puzzle, solution Grid) *Sudoku {
+	var witness Sudoku
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			witness.Puzzle[i][j].Assign(puzzle[i][j])
+			witness.Solution[i][j].Assign(solution[i][j])
+		}
+	}
+	return &witness
+}
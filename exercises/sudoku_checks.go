@@ -0,0 +1,94 @@
+package exercises
+
+import "github.com/consensys/gnark/frontend"
+
+func init() {
+	Register(&Exercise{
+		Name:        "sudoku",
+		Description: "prove the knowledge of the solution of a public Sudoku puzzle (exercises.Sudoku)",
+		Circuit:     func() frontend.Circuit { return new(Sudoku) },
+		Checks: []Check{
+			{Name: "accepts the solution of a puzzle", Valid: true, Witness: func() (frontend.Circuit, error) {
+				solution := solvedGrid()
+				return NewSudokuWitness(puzzleOf(solution), solution)
+			}},
+			{Name: "accepts a solution of the empty puzzle", Valid: true, Witness: func() (frontend.Circuit, error) {
+				return NewSudokuWitness(Grid{}, relabel(solvedGrid()))
+			}},
+			{Name: "rejects digits above 9", Witness: func() (frontend.Circuit, error) {
+				return sudokuAssignment(Grid{}, shift(solvedGrid(), 9)), nil
+			}},
+			{Name: "rejects zeros", Witness: func() (frontend.Circuit, error) {
+				return sudokuAssignment(Grid{}, shift(solvedGrid(), -1)), nil
+			}},
+			{Name: "rejects a solution of another puzzle", Witness: func() (frontend.Circuit, error) {
+				return sudokuAssignment(puzzleOf(solvedGrid()), relabel(solvedGrid())), nil
+			}},
+			{Name: "rejects repeated digits in a row", Witness: func() (frontend.Circuit, error) {
+				// same column, same box: only rows 0 and 1 break
+				g := solvedGrid()
+				g[0][0], g[1][0] = g[1][0], g[0][0]
+				return sudokuAssignment(Grid{}, g), nil
+			}},
+			{Name: "rejects repeated digits in a column", Witness: func() (frontend.Circuit, error) {
+				// same row, same box: only columns 0 and 1 break
+				g := solvedGrid()
+				g[0][0], g[0][1] = g[0][1], g[0][0]
+				return sudokuAssignment(Grid{}, g), nil
+			}},
+			{Name: "rejects repeated digits in a box", Witness: func() (frontend.Circuit, error) {
+				// a latin square: rows and columns are fine, boxes aren't
+				var g Grid
+				for i := range g {
+					for j := range g[i] {
+						g[i][j] = (i+j)%9 + 1
+					}
+				}
+				return sudokuAssignment(Grid{}, g), nil
+			}},
+		},
+	})
+}
+
+// solvedGrid returns a valid Sudoku grid: each row shifts the previous one by 3, or by 1 between bands
+func solvedGrid() Grid {
+	var g Grid
+	for i := range g {
+		for j := range g[i] {
+			g[i][j] = ((i%3)*3+i/3+j)%9 + 1
+		}
+	}
+	return g
+}
+
+// puzzleOf returns solution with a third of its cells emptied
+func puzzleOf(solution Grid) Grid {
+	for i := range solution {
+		for j := range solution[i] {
+			if (i+j)%3 == 0 {
+				solution[i][j] = 0
+			}
+		}
+	}
+	return solution
+}
+
+// relabel returns g with each digit d replaced by d+1, 9 by 1: another solution
+func relabel(g Grid) Grid {
+	for i := range g {
+		for j := range g[i] {
+			g[i][j] = g[i][j]%9 + 1
+		}
+	}
+	return g
+}
+
+// shift adds delta to each cell of g: distinct digits stay distinct, but not in 1..9
+func shift(g Grid, delta int) Grid {
+	for i := range g {
+		for j := range g[i] {
+			g[i][j] += delta
+		}
+	}
+	return g
+}
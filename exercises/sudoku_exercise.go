@@ -0,0 +1,23 @@
+//go:build !solution
+// +build !solution
+
+package exercises
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Define declares the circuit's constraints: your turn! Run go run . exercise sudoku to check them.
+func (circuit *Sudoku) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	// 1. each cell of the solution is a digit, 1 to 9
+	// hint: cs.AssertIsLessOrEqual, and a zero has no inverse (cs.Inverse)
+
+	// 2. the solution agrees with the given cells of the puzzle, 0 for empty cells
+	// hint: (puzzle - solution) * puzzle == 0, with cs.Mul, cs.Sub and cs.AssertIsEqual
+
+	// 3. the digits of each row, each column and each 3x3 box are different
+	// hint: two digits are different if their difference has an inverse
+
+	return nil
+}
@@ -0,0 +1,43 @@
+//go:build solution
+// +build solution
+
+package exercises
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Define declares the circuit's constraints
+func (circuit *Sudoku) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			// 1. each cell of the solution is a digit, 1 to 9
+			cell := circuit.Solution[i][j]
+			cs.AssertIsLessOrEqual(cell, 9)
+			cs.Inverse(cell)
+
+			// 2. the solution agrees with the given cells of the puzzle
+			given := circuit.Puzzle[i][j]
+			cs.AssertIsEqual(cs.Mul(cs.Sub(given, cell), given), 0)
+		}
+	}
+
+	// 3. the digits of each row, each column and each 3x3 box are different
+	for i := 0; i < 9; i++ {
+		var row, column, box [9]frontend.Variable
+		for j := 0; j < 9; j++ {
+			row[j] = circuit.Solution[i][j]
+			column[j] = circuit.Solution[j][i]
+			box[j] = circuit.Solution[3*(i/3)+j/3][3*(i%3)+j%3]
+		}
+		for _, group := range [][9]frontend.Variable{row, column, box} {
+			for a := 0; a < 9; a++ {
+				for b := a + 1; b < 9; b++ {
+					cs.Inverse(cs.Sub(group[a], group[b]))
+				}
+			}
+		}
+	}
+	return nil
+}
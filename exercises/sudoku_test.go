@@ -0,0 +1,101 @@
+package exercises
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseGrid(t *testing.T) {
+	g := solvedGrid()
+	parsed, err := ParseGrid(g.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != g {
+		t.Fatalf("parsed\n%s\nexpected\n%s", parsed, g)
+	}
+
+	// separators are ignored, . is an empty cell
+	boxed := strings.Repeat("+-------+-------+-------+\n"+strings.Repeat("| 5 3 . | . 7 . | . . . |\n", 3), 3)
+	if parsed, err = ParseGrid(boxed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed[8] != [9]int{5, 3, 0, 0, 7, 0, 0, 0, 0} {
+		t.Fatalf("last row %v", parsed[8])
+	}
+
+	for name, s := range map[string]string{
+		"short":      strings.Repeat("1", 80),
+		"long":       strings.Repeat("1", 82),
+		"unexpected": strings.Repeat("1", 80) + "x",
+	} {
+		if _, err := ParseGrid(s); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("%s: got %v, expected ErrInvalidGrid", name, err)
+		}
+	}
+}
+
+func TestIsSolution(t *testing.T) {
+	if !solvedGrid().IsSolution() {
+		t.Fatal("solvedGrid is not a solution")
+	}
+	if !relabel(solvedGrid()).IsSolution() {
+		t.Fatal("a relabeled solution is not a solution")
+	}
+	if puzzleOf(solvedGrid()).IsSolution() {
+		t.Fatal("a puzzle with empty cells is a solution")
+	}
+	if shift(solvedGrid(), 9).IsSolution() {
+		t.Fatal("a grid of digits above 9 is a solution")
+	}
+	g := solvedGrid()
+	g[0][0], g[1][0] = g[1][0], g[0][0]
+	if g.IsSolution() {
+		t.Fatal("a grid with repeated digits in a row is a solution")
+	}
+}
+
+func TestNewSudokuWitness(t *testing.T) {
+	solution := solvedGrid()
+	if _, err := NewSudokuWitness(puzzleOf(solution), solution); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSudokuWitness(puzzleOf(solution), relabel(solution)); !errors.Is(err, ErrNotASolution) {
+		t.Fatalf("solution of another puzzle: got %v, expected ErrNotASolution", err)
+	}
+	if _, err := NewSudokuWitness(Grid{}, puzzleOf(solution)); !errors.Is(err, ErrInvalidGrid) {
+		t.Fatalf("incomplete solution: got %v, expected ErrInvalidGrid", err)
+	}
+	puzzle := puzzleOf(solution)
+	puzzle[0][1] = 10
+	if _, err := NewSudokuWitness(puzzle, solution); !errors.Is(err, ErrInvalidGrid) {
+		t.Fatalf("puzzle with a 10: got %v, expected ErrInvalidGrid", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	if _, err := Get("sudoku"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get("chess"); !errors.Is(err, ErrUnknownExercise) {
+		t.Fatalf("got %v, expected ErrUnknownExercise", err)
+	}
+}
+
+// the exercises compile as handed out, and run all their checks
+func TestRun(t *testing.T) {
+	for _, name := range Names() {
+		e, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results, err := e.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != len(e.Checks) {
+			t.Errorf("%s: %d results for %d checks", name, len(results), len(e.Checks))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/solc"
+)
+
+// runExportFoundry writes a Foundry project testing the Solidity verifier of the selected circuit
+// against its last proof (see ethereum.ExportFoundry), to iterate on contracts with forge
+func runExportFoundry(args []string) {
+	fs := flag.NewFlagSet("export-foundry", flag.ExitOnError)
+	fDir := fs.String("dir", "onchain", "project directory")
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	fProof := fs.String("proof", files.proof, "proof file, hard-coded in the test")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	pinned := solc.DefaultVersion
+	if *fSolcVersion != "" {
+		pinned = *fSolcVersion
+	}
+	fSolc := fs.String("solc", pinned, "solc version pinned in foundry.toml, none if empty")
+	fForce := fs.Bool("force", false, "overwrite the generated files of an existing project")
+	assertNoError(fs.Parse(args))
+
+	if _, err := os.Stat(filepath.Join(*fDir, "foundry.toml")); err == nil && !*fForce {
+		log.Fatalf("%s is already a Foundry project, use -force to regenerate it (ProofConsumer.sol included)", *fDir)
+	}
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+	proof := groth16.NewProof(ecc.BN254)
+	deserialize(proof, *fProof)
+	inputs, err := ethereum.ProofToSolidityInputs(proof, readPublicWitness(*fPublic, *fVK))
+	assertNoError(err)
+
+	written, err := ethereum.ExportFoundry(*fDir, vk, inputs, *fSolc)
+	assertNoError(err)
+	for _, fileName := range written {
+		log.Println("wrote", fileName)
+	}
+	log.Printf("run the tests with: cd %s && forge test", *fDir)
+	emit("export-foundry", exportProjectResult{Circuit: *fCircuit, Dir: *fDir, Files: written})
+}
+
+// exportProjectResult is the JSON output of export-foundry and export-hardhat
+type exportProjectResult struct {
+	Circuit string   `json:"circuit"`
+	Dir     string   `json:"dir"`
+	Files   []string `json:"files"`
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runExportHardhat writes the verifier of the selected circuit, its Hardhat artifact, a TypeScript
+// helper formatting proofs and an ethers script checking the last proof (see ethereum.ExportHardhat)
+func runExportHardhat(args []string) {
+	fs := flag.NewFlagSet("export-hardhat", flag.ExitOnError)
+	fDir := fs.String("dir", "hardhat", "project directory")
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	fProof := fs.String("proof", files.proof, "proof file, written to the fixtures")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	fForce := fs.Bool("force", false, "overwrite the generated files of an existing project")
+	assertNoError(fs.Parse(args))
+
+	if _, err := os.Stat(filepath.Join(*fDir, "package.json")); err == nil && !*fForce {
+		log.Fatalf("%s already has a package.json, use -force to regenerate it", *fDir)
+	}
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+	proof := groth16.NewProof(ecc.BN254)
+	deserialize(proof, *fProof)
+
+	written, err := ethereum.ExportHardhat(*fDir, vk, proof, readPublicWitness(*fPublic, *fVK))
+	assertNoError(err)
+	for _, fileName := range written {
+		log.Println("wrote", fileName)
+	}
+	log.Printf("check the proof on a local node with: cd %s && npm install && npm run verify", *fDir)
+	emit("export-hardhat", exportProjectResult{Circuit: *fCircuit, Dir: *fDir, Files: written})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/r1cs"
+)
+
+// runExportR1CS compiles the selected circuit and writes its constraints, as snarkjs JSON or
+// circom binary .r1cs (see package r1cs)
+func runExportR1CS(args []string) {
+	fs := flag.NewFlagSet("export-r1cs", flag.ExitOnError)
+	fFormat := fs.String("format", "json", "json (snarkjs r1cs export json) or bin (circom .r1cs)")
+	fOut := fs.String("o", "", "output file (default stdout)")
+	assertNoError(fs.Parse(args))
+	if *fFormat != "json" && *fFormat != "bin" {
+		log.Fatalf("unknown r1cs format %q (expected json or bin)", *fFormat)
+	}
+
+	circuit, err := circuits.Get(*fCircuit)
+	assertNoError(err)
+	ccs, err := frontend.Compile(ecc.BN254, backend.GROTH16, circuit)
+	assertNoError(err)
+	public, secret := circuits.Wires(circuit)
+	exported, err := r1cs.FromCompiled(ccs, public, secret)
+	assertNoError(err)
+
+	var out io.Writer = os.Stdout
+	if *fOut != "" {
+		f, err := os.Create(*fOut)
+		assertNoError(err)
+		defer f.Close()
+		out = f
+	}
+	if *fFormat == "bin" {
+		assertNoError(exported.WriteBinary(out))
+	} else {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		assertNoError(enc.Encode(exported))
+	}
+	if *fOut != "" {
+		log.Printf("%d constraints, %d wires written to %s", exported.NConstraint, exported.NVars, *fOut)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"log"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	gethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runExportTx writes the verification transaction of a proof, unsigned, for a multisig or offline
+// signer to broadcast: the node is only read, for the chain ID, fees, gas and nonce
+func runExportTx(args []string) {
+	fs := flag.NewFlagSet("export-tx", flag.ExitOnError)
+	fNodeFlags := addNodeFlags(fs)
+	fTo := fs.String("to", "", "verifier contract address, read from the deployments file if not set")
+	fDeployments := fs.String("deployments", deploymentsPath, "deployments file")
+	fMethod := fs.String("method", "verifyAndRecord", "verifier method called: verifyAndRecord (recording the proof on chain) or verifyProof")
+	fProof := fs.String("proof", files.proof, "proof file")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	fCalldata := fs.String("calldata", "", "hex encoded calldata file (see calldata), instead of -proof, -public and -method")
+	fFrom := fs.String("from", "", "address sending the transaction (e.g. the Safe), to estimate gas from it and set the nonce")
+	fGas := fs.Uint64("gas", 0, "gas limit, estimated if 0")
+	fSafe := fs.Bool("safe", false, "write a Safe Transaction Builder batch instead of a transaction")
+	fOut := fs.String("out", "tx.json", "output file")
+	assertNoError(fs.Parse(args))
+
+	var calldata []byte
+	if *fCalldata != "" {
+		data, err := ioutil.ReadFile(*fCalldata)
+		assertNoError(err)
+		calldata, err = ethereum.DecodeBytecode(data)
+		assertNoError(err)
+	} else {
+		proof := groth16.NewProof(ecc.BN254)
+		deserialize(proof, *fProof)
+		data, err := ioutil.ReadFile(*fPublic)
+		assertNoError(err)
+		publicWitness, err := ethereum.DecodePublicWitness(data)
+		assertNoError(err)
+		inputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+		assertNoError(err)
+		verifierABI, err := ethereum.VerifierABI(len(inputs.Input))
+		assertNoError(err)
+		calldata, err = verifierABI.Pack(*fMethod, inputs.A, inputs.B, inputs.C, inputs.Input)
+		assertNoError(err)
+	}
+
+	ctx := mainCtx
+	client := fNodeFlags.dial(ctx)
+	defer client.Close()
+	to := verifierAddress(*fTo, *fDeployments, client.chainID)
+	msg := gethereum.CallMsg{To: &to, Data: calldata}
+	if *fFrom != "" {
+		if !common.IsHexAddress(*fFrom) {
+			log.Fatal("invalid -from address ", *fFrom)
+		}
+		msg.From = common.HexToAddress(*fFrom)
+	}
+
+	// estimating also checks the call doesn't revert, e.g. with an invalid proof
+	gas := *fGas
+	if gas == 0 {
+		estimated, err := client.EstimateGas(ctx, msg)
+		if err != nil {
+			log.Fatalf("estimating gas: %v (an invalid proof reverts)", err)
+		}
+		gas = estimated
+	}
+	tx := ethereum.NewUnsignedTx(client.chainID, to, calldata, gas, client.fees(ctx))
+	if *fFrom != "" {
+		nonce, err := client.PendingNonceAt(ctx, msg.From)
+		assertNoError(err)
+		tx.From, tx.Nonce = &msg.From, (*hexutil.Uint64)(&nonce)
+	}
+
+	var out interface{} = tx
+	if *fSafe {
+		out = ethereum.NewSafeBatch(*fCircuit+" proof verification", tx)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	assertNoError(err)
+	assertNoError(ioutil.WriteFile(*fOut, append(data, '\n'), 0644))
+	log.Printf("unsigned transaction to %s (chain %s, gas %d) written to %s", to.Hex(), client.chainID, gas, *fOut)
+	emit("export-tx", out)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runExportVerifier writes the verifier contract of the verifying key in Solidity, Yul or Vyper
+func runExportVerifier(args []string) {
+	fs := flag.NewFlagSet("export-verifier", flag.ExitOnError)
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	fLang := fs.String("lang", string(ethereum.LangSolidity), "sol, yul or vyper")
+	fOptimized := fs.Bool("optimized", false, "with -lang sol, export the inline assembly verifier (same ABI, less gas)")
+	fInterface := fs.Bool("interface", false, "export IVerifier.sol, the interface of the verifier for application contracts")
+	fPacking := fs.Bool("packing", false, "export PublicInputs.sol, the library packing public inputs for circuits exposing their commitment (see batch-packed)")
+	fUpgradeable := fs.Bool("upgradeable", false, "with -lang sol, export the verifier followed by VerifierProxy, an upgradeable proxy (see rotate-vk)")
+	fBindings := fs.String("bindings", "", "with -upgradeable, also write the Go bindings of the verifier and proxy to this file (requires solc)")
+	fPkg := fs.String("pkg", "main", "package of the Go bindings")
+	fOut := fs.String("o", "", "output file (default stdout)")
+	var opts ethereum.SolidityOptions
+	fs.StringVar(&opts.ContractName, "name", "", "with -lang sol, name of the verifier contract (default Verifier)")
+	fs.StringVar(&opts.License, "license", "", "with -lang sol, SPDX license identifier of the source")
+	fs.StringVar(&opts.Pragma, "pragma", "", "with -lang sol, solc version range (default ^0.8.0)")
+	fs.BoolVar(&opts.Owner, "owner", false, "with -lang sol, restrict verifyAndRecord to recorders set by the deployer (setRecorder)")
+	assertNoError(fs.Parse(args))
+	customized := opts != (ethereum.SolidityOptions{})
+	if customized && (ethereum.Lang(*fLang) != ethereum.LangSolidity || *fInterface || *fUpgradeable || *fPacking) {
+		log.Fatal("-name, -license, -pragma and -owner apply to the Solidity verifier only")
+	}
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+
+	var out io.Writer = os.Stdout
+	if *fOut != "" {
+		f, err := os.Create(*fOut)
+		assertNoError(err)
+		defer f.Close()
+		out = f
+	}
+	switch {
+	case *fPacking:
+		assertNoError(ethereum.ExportPacking(out))
+	case *fInterface:
+		n, err := ethereum.NbPublicInputs(vk)
+		assertNoError(err)
+		assertNoError(ethereum.ExportInterface(out, n))
+	case *fUpgradeable:
+		if ethereum.Lang(*fLang) != ethereum.LangSolidity || *fOptimized {
+			log.Fatal("-upgradeable applies to the Solidity verifier only")
+		}
+		var source bytes.Buffer
+		assertNoError(ethereum.ExportUpgradeable(&source, vk))
+		_, err := out.Write(source.Bytes())
+		assertNoError(err)
+		if *fBindings != "" {
+			contracts, err := ethereum.CompileSolidity(source.String())
+			assertNoError(err)
+			bindings, err := ethereum.GenerateBindings(contracts, *fPkg)
+			assertNoError(err)
+			assertNoError(ioutil.WriteFile(*fBindings, []byte(bindings), 0644))
+			log.Println("Go bindings written to", *fBindings)
+		}
+	case *fOptimized:
+		if ethereum.Lang(*fLang) != ethereum.LangSolidity {
+			log.Fatal("-optimized applies to the Solidity verifier only")
+		}
+		assertNoError(ethereum.ExportOptimizedSolidityWithOptions(out, vk, opts))
+	case customized:
+		assertNoError(ethereum.ExportSolidityWithOptions(out, vk, opts))
+	default:
+		assertNoError(ethereum.ExportVerifier(out, vk, ethereum.Lang(*fLang)))
+	}
+	if *fOut != "" {
+		log.Println("verifier written to", *fOut)
+	}
+}
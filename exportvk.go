@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/snarkjs"
+)
+
+// runExportVK writes the verifying key in another format: json (snarkjs verification_key.json)
+// or solidity (the verifier contract -init exports)
+func runExportVK(args []string) {
+	fs := flag.NewFlagSet("export-vk", flag.ExitOnError)
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	fFormat := fs.String("format", "json", "json (snarkjs) or solidity")
+	fOut := fs.String("o", "", "output file (default stdout)")
+	assertNoError(fs.Parse(args))
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+
+	var out io.Writer = os.Stdout
+	if *fOut != "" {
+		f, err := os.Create(*fOut)
+		assertNoError(err)
+		defer f.Close()
+		out = f
+	}
+
+	switch *fFormat {
+	case "json":
+		snarkjsVK, err := snarkjs.FromVerifyingKey(vk)
+		assertNoError(err)
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		assertNoError(enc.Encode(snarkjsVK))
+	case "solidity":
+		assertNoError(ethereum.ExportSolidity(out, vk))
+	default:
+		log.Fatalf("unknown verifying key format %q (expected json or solidity)", *fFormat)
+	}
+	if *fOut != "" {
+		log.Println("verifying key written to", *fOut)
+	}
+}
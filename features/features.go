@@ -0,0 +1,73 @@
+// Package features declares experimental gnark options a circuit can opt into.
+//
+// A circuit opts in by implementing Requirer; the features it requires are recorded in the
+// artifacts manifest at setup time and checked again before proving, so that artifacts
+// built with a feature are never used by a binary lacking it.
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Flag names an experimental feature
+type Flag string
+
+const (
+	// Groth16Commitment is Groth16 with (Pedersen) commitments to a subset of the witness
+	Groth16Commitment Flag = "groth16-commitment"
+	// GPU delegates MSM / FFT to an icicle accelerator
+	GPU Flag = "icicle"
+)
+
+// known lists all the flags a manifest may contain
+var known = map[Flag]bool{
+	Groth16Commitment: true,
+	GPU:               true,
+}
+
+// available lists the flags this binary supports; none of them is supported by the gnark
+// version this module is pinned to, build-tag gated files may enable them.
+var available = map[Flag]bool{}
+
+// Requirer is implemented by circuits depending on experimental features
+type Requirer interface {
+	Features() []Flag
+}
+
+// Of returns the (sorted) features required by circuit, if it implements Requirer
+func Of(circuit interface{}) []Flag {
+	r, ok := circuit.(Requirer)
+	if !ok {
+		return nil
+	}
+	flags := append([]Flag{}, r.Features()...)
+	sort.Slice(flags, func(i, j int) bool { return flags[i] < flags[j] })
+	return flags
+}
+
+// Available returns true if this binary supports flag
+func Available(flag Flag) bool {
+	return available[flag]
+}
+
+// Check returns an error listing the unknown or unsupported flags
+func Check(flags []Flag) error {
+	var unknown, missing []string
+	for _, f := range flags {
+		switch {
+		case !known[f]:
+			unknown = append(unknown, string(f))
+		case !available[f]:
+			missing = append(missing, string(f))
+		}
+	}
+	if len(unknown) != 0 {
+		return fmt.Errorf("unknown features: %s", strings.Join(unknown, ", "))
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("artifacts require features this binary lacks: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
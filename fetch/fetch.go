@@ -0,0 +1,202 @@
+// Package fetch downloads artifacts given as URLs (https://, s3://, ipfs://) into a local cache,
+// checking their sha256, so that large keys can be hosted centrally.
+//
+// The expected digest is given as the URL fragment, 64 lowercase hex characters; it is required:
+//
+//	https://example.com/mimc/circuit.pk#sha256=9f86d0...
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// IPFSGatewayEnv is the environment variable naming the gateway ipfs:// URLs are fetched from,
+	// DefaultIPFSGateway if not set
+	IPFSGatewayEnv = "GNARK_WORKSHOP_IPFS_GATEWAY"
+	// S3EndpointEnv is the environment variable naming an S3 compatible endpoint (MinIO, R2, ...)
+	// s3:// URLs are fetched from, path style; AWS if not set, in AWS_REGION
+	S3EndpointEnv = "GNARK_WORKSHOP_S3_ENDPOINT"
+)
+
+// DefaultIPFSGateway is the gateway of ipfs:// URLs if IPFSGatewayEnv is not set
+const DefaultIPFSGateway = "https://ipfs.io"
+
+var (
+	// ErrNoDigest is returned for URLs without a #sha256= fragment: their content couldn't be checked
+	ErrNoDigest = errors.New("fetch: no #sha256=<hex digest> fragment")
+	// ErrInvalidDigest is returned for fragments that aren't a sha256 digest, 64 lowercase hex characters
+	ErrInvalidDigest = errors.New("fetch: invalid sha256 digest")
+	// ErrDigestMismatch is returned when the downloaded content doesn't have the expected digest
+	ErrDigestMismatch = errors.New("fetch: sha256 mismatch")
+)
+
+// IsURL reports whether name is a URL Local fetches, rather than a local file
+func IsURL(name string) bool {
+	for _, scheme := range []string{"https://", "http://", "s3://", "ipfs://"} {
+		if strings.HasPrefix(name, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheDir returns the directory downloaded artifacts are cached in
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gnark-workshop", "artifacts"), nil
+}
+
+// Local returns name if it's a local file, or the cached copy of the URL name, downloaded first if
+// needed
+// The URL must end with a #sha256= fragment: the download is checked against the digest, and the
+// cache entry is the digest, hashed again on every hit, so that a corrupted or replaced entry is
+// downloaded again.
+func Local(ctx context.Context, name string) (string, error) {
+	if !IsURL(name) {
+		return name, nil
+	}
+	u, err := url.Parse(name)
+	if err != nil {
+		return "", err
+	}
+	expected, err := digestOf(u)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	u.Fragment = ""
+	source, err := httpURL(u)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	fileName := filepath.Join(dir, expected, path.Base(u.Path))
+	if digest, err := hashFile(fileName); err == nil {
+		if digest == expected {
+			return fileName, nil
+		}
+		log.Printf("%s: cached copy doesn't match its digest, downloading it again", name)
+	}
+	log.Printf("downloading %s", source)
+	if err := download(ctx, source, fileName, expected); err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return fileName, nil
+}
+
+// digestOf returns the sha256 digest of the fragment of u
+func digestOf(u *url.URL) (string, error) {
+	if u.Fragment == "" {
+		return "", ErrNoDigest
+	}
+	if !strings.HasPrefix(u.Fragment, "sha256=") {
+		return "", fmt.Errorf("unsupported fragment, expected #sha256=<hex digest>")
+	}
+	digest := strings.TrimPrefix(u.Fragment, "sha256=")
+	if len(digest) != 2*sha256.Size {
+		return "", ErrInvalidDigest
+	}
+	for _, c := range digest {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return "", ErrInvalidDigest
+		}
+	}
+	return digest, nil
+}
+
+// hashFile returns the hex encoded sha256 of fileName
+func hashFile(fileName string) (string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// httpURL returns the HTTP URL u is fetched from
+func httpURL(u *url.URL) (string, error) {
+	switch u.Scheme {
+	case "https", "http":
+		return u.String(), nil
+	case "s3":
+		// public objects, or an endpoint signing the requests itself: private objects are fetched
+		// with a presigned https:// URL
+		key := strings.TrimPrefix(u.Path, "/")
+		if endpoint := os.Getenv(S3EndpointEnv); endpoint != "" {
+			return strings.TrimSuffix(endpoint, "/") + "/" + u.Host + "/" + key, nil
+		}
+		if region := os.Getenv("AWS_REGION"); region != "" {
+			return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.Host, region, key), nil
+		}
+		return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", u.Host, key), nil
+	case "ipfs":
+		gateway := os.Getenv(IPFSGatewayEnv)
+		if gateway == "" {
+			gateway = DefaultIPFSGateway
+		}
+		return strings.TrimSuffix(gateway, "/") + "/ipfs/" + u.Host + u.Path, nil
+	}
+	return "", fmt.Errorf("unsupported scheme %q", u.Scheme)
+}
+
+// download writes source to fileName, if its sha256 is expected
+func download(ctx context.Context, source, fileName, expected string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", source, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(fileName), filepath.Base(fileName)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if digest := hex.EncodeToString(h.Sum(nil)); digest != expected {
+		return fmt.Errorf("%w (got %s, expected %s)", ErrDigestMismatch, digest, expected)
+	}
+	return os.Rename(f.Name(), fileName)
+}
@@ -0,0 +1,174 @@
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// serve serves content at /circuit.pk, counting the requests, and sets a temporary cache directory
+func serve(t *testing.T, content []byte) (*httptest.Server, *int) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	requests := new(int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.URL.Path != "/circuit.pk" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func digest(content []byte) string {
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:])
+}
+
+func TestLocal(t *testing.T) {
+	content := []byte("proving key")
+	server, requests := serve(t, content)
+	name := server.URL + "/circuit.pk#sha256=" + digest(content)
+
+	fileName, err := Local(context.Background(), name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadFile(fileName); err != nil || string(data) != string(content) {
+		t.Fatalf("cached %q, %v", data, err)
+	}
+	dir, err := CacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(fileName, dir+string(filepath.Separator)) {
+		t.Fatalf("%s is not in the cache %s", fileName, dir)
+	}
+
+	// a hit is served from the cache
+	if _, err := Local(context.Background(), name); err != nil {
+		t.Fatal(err)
+	}
+	if *requests != 1 {
+		t.Fatalf("%d requests, expected 1", *requests)
+	}
+
+	// a corrupted entry is downloaded again
+	if err := ioutil.WriteFile(fileName, []byte("tampered key"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Local(context.Background(), name); err != nil {
+		t.Fatal(err)
+	}
+	if *requests != 2 {
+		t.Fatalf("%d requests, expected 2", *requests)
+	}
+	if data, err := ioutil.ReadFile(fileName); err != nil || string(data) != string(content) {
+		t.Fatalf("cached %q after the corruption, %v", data, err)
+	}
+
+	if local, err := Local(context.Background(), "circuit.pk"); err != nil || local != "circuit.pk" {
+		t.Fatalf("local file: %s, %v", local, err)
+	}
+}
+
+func TestLocalMismatch(t *testing.T) {
+	server, _ := serve(t, []byte("proving key"))
+	_, err := Local(context.Background(), server.URL+"/circuit.pk#sha256="+digest([]byte("another key")))
+	if !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("got %v, expected ErrDigestMismatch", err)
+	}
+	dir, err := CacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := ioutil.ReadDir(filepath.Join(dir, digest([]byte("another key")))); len(entries) != 0 {
+		t.Fatalf("the mismatching download was left in the cache: %v", entries)
+	}
+}
+
+func TestLocalDigest(t *testing.T) {
+	server, requests := serve(t, []byte("proving key"))
+	valid := digest([]byte("proving key"))
+	for name, tc := range map[string]struct {
+		fragment string
+		expected error
+	}{
+		"no fragment":     {"", ErrNoDigest},
+		"empty digest":    {"#sha256=", ErrInvalidDigest},
+		"short digest":    {"#sha256=" + valid[:63], ErrInvalidDigest},
+		"long digest":     {"#sha256=" + valid + "0", ErrInvalidDigest},
+		"uppercase":       {"#sha256=" + strings.ToUpper(valid), ErrInvalidDigest},
+		"path traversal":  {"#sha256=../../" + valid[:58], ErrInvalidDigest},
+		"escaped slashes": {"#sha256=" + url.PathEscape("../../x"), ErrInvalidDigest},
+	} {
+		if _, err := Local(context.Background(), server.URL+"/circuit.pk"+tc.fragment); !errors.Is(err, tc.expected) {
+			t.Errorf("%s: got %v, expected %v", name, err, tc.expected)
+		}
+	}
+	if _, err := Local(context.Background(), server.URL+"/circuit.pk#md5=00"); err == nil {
+		t.Error("another hash was accepted")
+	}
+	if *requests != 0 {
+		t.Fatalf("%d requests for invalid digests", *requests)
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"https://example.com/circuit.pk": true,
+		"http://example.com/circuit.pk":  true,
+		"s3://bucket/circuit.pk":         true,
+		"ipfs://bafy/circuit.pk":         true,
+		"circuits/mimc/circuit.pk":       false,
+		"/tmp/circuit.pk":                false,
+		"file:///tmp/circuit.pk":         false,
+	} {
+		if IsURL(name) != expected {
+			t.Errorf("IsURL(%q) = %v", name, !expected)
+		}
+	}
+}
+
+func TestHTTPURL(t *testing.T) {
+	t.Setenv(S3EndpointEnv, "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv(IPFSGatewayEnv, "")
+	for name, expected := range map[string]string{
+		"https://example.com/mimc/circuit.pk": "https://example.com/mimc/circuit.pk",
+		"s3://keys/mimc/circuit.pk":           "https://keys.s3.amazonaws.com/mimc/circuit.pk",
+		"ipfs://bafy/circuit.pk":              DefaultIPFSGateway + "/ipfs/bafy/circuit.pk",
+	} {
+		u, err := url.Parse(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := httpURL(u); err != nil || got != expected {
+			t.Errorf("%s: got %s, %v, expected %s", name, got, err, expected)
+		}
+	}
+
+	t.Setenv("AWS_REGION", "eu-west-1")
+	u, _ := url.Parse("s3://keys/circuit.pk")
+	if got, _ := httpURL(u); got != "https://keys.s3.eu-west-1.amazonaws.com/circuit.pk" {
+		t.Errorf("s3 in a region: %s", got)
+	}
+	t.Setenv(S3EndpointEnv, "http://localhost:9000/")
+	if got, _ := httpURL(u); got != "http://localhost:9000/keys/circuit.pk" {
+		t.Errorf("s3 endpoint: %s", got)
+	}
+	if _, err := httpURL(&url.URL{Scheme: "ftp"}); err == nil {
+		t.Error("ftp is supported")
+	}
+}
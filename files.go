@@ -0,0 +1,132 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits"
+)
+
+// defaultCircuit is the circuit of the workshop; its verifier has generated Go bindings (wrapperPath)
+const defaultCircuit = "mimc"
+
+const (
+	// circuitsDir holds a directory per circuit, see circuitDir
+	circuitsDir     = "circuits"
+	deploymentsPath = "deployments.json"
+)
+
+// wrapperPath is the Go bindings of the verifier of the default circuit, in its directory (package mimc)
+var wrapperPath = filepath.Join(circuitDir(defaultCircuit), "verifier.go")
+
+// store holds the R1CS, keys and manifest of each setup, see artifacts.Store
+var store = artifacts.Store{Root: filepath.Join(circuitsDir, "store")}
+
+// ceremonyDir returns the default directory of the contribution files of the ceremony of name
+func ceremonyDir(name string) string {
+	return filepath.Join(circuitDir(name), "ceremony")
+}
+
+// circuitFiles are the artifacts of a registered circuit
+// The setup artifacts (r1cs, pk, vk, manifest) are in the store directory of the current setup;
+// the others are in the directory of the circuit (circuitDir).
+type circuitFiles struct {
+	r1cs, pk, vk          string
+	solidity, verifierBin string
+	verifierABI           string
+	manifest              string
+	proof, publicWitness  string
+}
+
+// circuitDir returns the directory owning the artifacts of the circuit (or instance) name:
+// circuits/<circuit> for a circuit and the instance of its defaults, circuits/<circuit>/<params> for
+// its other instances, so that adding a circuit or an instance never overwrites the files of another.
+// Circuits with their own package (circuits/age...) share the directory with their sources.
+func circuitDir(name string) string {
+	if i := strings.Index(name, circuits.ParamsSeparator); i != -1 {
+		return filepath.Join(circuitsDir, name[:i], name[i+len(circuits.ParamsSeparator):])
+	}
+	return filepath.Join(circuitsDir, name)
+}
+
+// filesOf returns the artifacts paths of the circuit registered under name
+func filesOf(name string) circuitFiles {
+	dir := circuitDir(name)
+	cf := circuitFiles{
+		solidity:      filepath.Join(dir, "verifier.sol"),
+		verifierBin:   filepath.Join(dir, "verifier.bin"),
+		verifierABI:   filepath.Join(dir, "verifier.abi"),
+		proof:         filepath.Join(dir, "circuit.proof"),
+		publicWitness: filepath.Join(dir, "circuit.public"),
+	}
+	moveLegacyFiles(name, cf)
+	moveLegacy(filepath.Join("circuit", "store"), store.Root)
+	moveLegacy(filepath.Join("circuit", "ceremony", name), ceremonyDir(name))
+	id, err := store.Current(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if id == "" {
+		// set up before the store existed (or not set up at all)
+		cf.r1cs = filepath.Join(dir, "circuit.r1cs")
+		cf.pk = filepath.Join(dir, "circuit.pk")
+		cf.vk = filepath.Join(dir, "circuit.vk")
+		cf.manifest = filepath.Join(dir, artifacts.ManifestFile)
+		moveLegacyFiles(name, cf)
+		return cf
+	}
+	cf.setStoreDir(store.Dir(id))
+	return cf
+}
+
+// moveLegacyFiles moves the artifacts of name written next to the circuit sources, circuit/<name>.*,
+// before each circuit had its directory, to their paths in cf (empty ones are skipped)
+func moveLegacyFiles(name string, cf circuitFiles) {
+	base := filepath.Join("circuit", name)
+	for legacy, path := range map[string]string{
+		base + "_verifier.sol":  cf.solidity,
+		base + "_verifier.bin":  cf.verifierBin,
+		base + "_verifier.abi":  cf.verifierABI,
+		base + ".proof":         cf.proof,
+		base + ".public":        cf.publicWitness,
+		base + ".r1cs":          cf.r1cs,
+		base + ".pk":            cf.pk,
+		base + ".vk":            cf.vk,
+		base + ".manifest.json": cf.manifest,
+	} {
+		if path != "" {
+			moveLegacy(legacy, path)
+		}
+	}
+}
+
+// moveLegacy moves the file or directory legacy, from the circuit/ tree of earlier versions, to path
+func moveLegacy(legacy, path string) {
+	if _, err := os.Stat(legacy); err != nil {
+		return
+	}
+	if _, err := os.Stat(path); err == nil {
+		return // both exist: leave the legacy file, the new one is the current one
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.Rename(legacy, path); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("moved %s to %s", legacy, path)
+}
+
+// setStoreDir points the setup artifacts to the store directory dir
+func (cf *circuitFiles) setStoreDir(dir string) {
+	cf.r1cs = filepath.Join(dir, artifacts.R1CSFile)
+	cf.pk = filepath.Join(dir, artifacts.PKFile)
+	cf.vk = filepath.Join(dir, artifacts.VKFile)
+	cf.manifest = filepath.Join(dir, artifacts.ManifestFile)
+}
+
+// files are the artifacts of the circuit selected with -circuit
+var files circuitFiles
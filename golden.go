@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/golden"
+)
+
+// runGolden regenerates the test vectors of the circuits given as arguments (all registered
+// circuits if none is) and compares them with the stored ones, or stores them with -update
+func runGolden(args []string) {
+	fs := flag.NewFlagSet("golden", flag.ExitOnError)
+	fDir := fs.String("dir", golden.DefaultRoot, "directory of the test vectors")
+	fUpdate := fs.Bool("update", false, "store the regenerated vectors instead of comparing them")
+	assertNoError(fs.Parse(args))
+
+	names := fs.Args()
+	if len(names) == 0 {
+		names = circuits.Names()
+	}
+
+	var results []goldenResult
+	failed := false
+	for _, name := range names {
+		for _, curve := range golden.Curves {
+			curveName := strings.ToLower(curve.String())
+			log.Printf("generating %s vectors on %s", name, curveName)
+			v, err := golden.Generate(name, curve)
+			assertNoError(err)
+			dir := golden.Dir(*fDir, name, curve)
+			result := goldenResult{Circuit: name, Curve: curveName, Dir: dir}
+
+			if *fUpdate {
+				assertNoError(v.Write(dir))
+				log.Println("vectors written to", dir)
+				results = append(results, result)
+				continue
+			}
+			result.Files, err = v.Compare(dir)
+			assertNoError(err)
+			for _, r := range result.Files {
+				if r.Status != golden.Match {
+					failed = true
+				}
+				if !jsonOutput() {
+					fmt.Printf("%-10s %s/%s\n", r.Status, dir, r.File)
+				}
+			}
+			results = append(results, result)
+		}
+	}
+	emit("golden", results)
+
+	if failed {
+		log.Printf("vectors differ from %s: check the change, then run golden -update", *fDir)
+		os.Exit(1)
+	}
+}
+
+// goldenResult is the JSON output of golden, for a circuit and curve; Files is empty with -update
+type goldenResult struct {
+	Circuit string          `json:"circuit"`
+	Curve   string          `json:"curve"`
+	Dir     string          `json:"dir"`
+	Files   []golden.Result `json:"files,omitempty"`
+}
@@ -0,0 +1,199 @@
+// Package golden maintains the test vectors of the registered circuits: for each circuit and
+// curve, a proof of the circuit example, its verifying key (gnark binary and snarkjs JSON), the
+// Solidity verifier and calldata. Setup and proof randomness is derived from the circuit name
+// (see internal/seeded), so regenerating the vectors gives the same bytes unless serialization,
+// export or the circuit changed.
+//
+// Vectors are stored in <root>/<circuit>/<curve>/, one file per vector.
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/internal/seeded"
+	"github.com/gbotrel/gnark-workshop/snarkjs"
+)
+
+// DefaultRoot is where the workshop stores its vectors, from the root of the repository: the
+// testdata of this package, which its tests compare with (the go tool ignores testdata directories)
+const DefaultRoot = "golden/testdata"
+
+// Curves are the curves vectors are generated for
+// The Solidity, snarkjs and calldata vectors only exist on BN254, the curve of the EVM precompiles,
+// and the circuit examples are BN254 assignments: it is the only curve for now.
+var Curves = []ecc.ID{ecc.BN254}
+
+// Vectors are the contents of the vector files of a circuit on a curve, by file name
+type Vectors map[string][]byte
+
+// Status of a vector file compared with its stored version
+type Status string
+
+const (
+	Match      Status = "match"
+	Mismatch   Status = "mismatch"
+	Missing    Status = "missing"
+	Unexpected Status = "unexpected"
+)
+
+// Result is the comparison of a vector file with its stored version
+type Result struct {
+	File   string `json:"file"`
+	Status Status `json:"status"`
+}
+
+// Dir returns the directory of the vectors of circuit on curve
+func Dir(root, circuit string, curve ecc.ID) string {
+	return filepath.Join(root, circuit, strings.ToLower(curve.String()))
+}
+
+// Generate builds the vectors of the circuit registered under name, on curve
+func Generate(name string, curve ecc.ID) (Vectors, error) {
+	if curve != ecc.BN254 {
+		return nil, fmt.Errorf("golden: unsupported curve %s", curve)
+	}
+	c, err := circuits.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	r1cs, err := frontend.Compile(curve, backend.GROTH16, c)
+	if err != nil {
+		return nil, err
+	}
+
+	// the example is built inside seeded.Run too, some examples generate keys
+	var (
+		pk      groth16.ProvingKey
+		vk      groth16.VerifyingKey
+		proof   groth16.Proof
+		witness frontend.Circuit
+	)
+	err = seeded.Run([]byte("gnark-workshop golden "+name), func() (err error) {
+		if witness, err = circuits.Example(name); err != nil {
+			return err
+		}
+		if pk, vk, err = groth16.Setup(r1cs); err != nil {
+			return err
+		}
+		proof, err = groth16.Prove(r1cs, pk, witness)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := groth16.Verify(proof, vk, witness); err != nil {
+		return nil, err
+	}
+
+	v := make(Vectors)
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	v["proof.bin"] = append([]byte(nil), buf.Bytes()...)
+	buf.Reset()
+	if _, err := vk.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	v["vk.bin"] = append([]byte(nil), buf.Bytes()...)
+	buf.Reset()
+	if err := ethereum.ExportSolidity(&buf, vk); err != nil {
+		return nil, err
+	}
+	v["verifier.sol"] = append([]byte(nil), buf.Bytes()...)
+
+	publicWitness, err := ethereum.PublicWitness(witness)
+	if err != nil {
+		return nil, err
+	}
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := solidityInputs.Calldata()
+	if err != nil {
+		return nil, err
+	}
+	v["calldata.hex"] = []byte(hexutil.Encode(calldata) + "\n")
+
+	snarkjsVK, err := snarkjs.FromVerifyingKey(vk)
+	if err != nil {
+		return nil, err
+	}
+	snarkjsProof, err := snarkjs.FromProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	for fileName, o := range map[string]interface{}{
+		"vk.json":     snarkjsVK,
+		"proof.json":  snarkjsProof,
+		"public.json": snarkjs.FromPublicWitness(publicWitness),
+	} {
+		data, err := json.MarshalIndent(o, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		v[fileName] = append(data, '\n')
+	}
+	return v, nil
+}
+
+// Write replaces the vectors stored in dir with v
+func (v Vectors) Write(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for fileName, data := range v {
+		if err := ioutil.WriteFile(filepath.Join(dir, fileName), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compare compares v with the vectors stored in dir, file by file, sorted by name
+func (v Vectors) Compare(dir string) ([]Result, error) {
+	var results []Result
+	for fileName, data := range v {
+		stored, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+		switch {
+		case os.IsNotExist(err):
+			results = append(results, Result{File: fileName, Status: Missing})
+		case err != nil:
+			return nil, err
+		case bytes.Equal(stored, data):
+			results = append(results, Result{File: fileName, Status: Match})
+		default:
+			results = append(results, Result{File: fileName, Status: Mismatch})
+		}
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if _, ok := v[e.Name()]; !ok {
+			results = append(results, Result{File: e.Name(), Status: Unexpected})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].File < results[j].File })
+	return results, nil
+}
@@ -0,0 +1,52 @@
+package golden
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/gbotrel/gnark-workshop/circuits"
+	_ "github.com/gbotrel/gnark-workshop/circuits/all"
+)
+
+var (
+	update = flag.Bool("update", false, "store the regenerated vectors in testdata instead of comparing them")
+	slow   = flag.Bool("slow", false, "also regenerate the vectors of the slow circuits")
+)
+
+// slowCircuits take minutes to set up: batch-packed proves mimc hashes and their keccak256 packing
+var slowCircuits = map[string]bool{"batch-packed": true}
+
+// TestGolden regenerates the vectors of the registered circuits and compares them with testdata;
+// go test ./golden -update stores them, -slow includes the slow circuits
+func TestGolden(t *testing.T) {
+	for _, name := range circuits.Names() {
+		for _, curve := range Curves {
+			name, curve := name, curve
+			t.Run(name+"/"+curve.String(), func(t *testing.T) {
+				if slowCircuits[name] && !*slow {
+					t.Skip("slow circuit, run with -slow")
+				}
+				v, err := Generate(name, curve)
+				if err != nil {
+					t.Fatal(err)
+				}
+				dir := Dir("testdata", name, curve)
+				if *update {
+					if err := v.Write(dir); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				results, err := v.Compare(dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, r := range results {
+					if r.Status != Match {
+						t.Errorf("%s/%s: %s, run go test ./golden -update if the change is expected", dir, r.File, r.Status)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package seeded makes gnark's randomness reproducible, for the deterministic setups of the CLI and
+// the golden vectors.
+//
+// gnark v0.5 takes no io.Reader: it samples crypto/rand.Reader, which Run replaces for the whole
+// process. The package is internal so that no importer of the workshop packages can do that
+// behind its users' back.
+package seeded
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// lock serializes Run calls, which swap crypto/rand.Reader
+var lock sync.Mutex
+
+// Setup runs groth16.Setup with its randomness derived from seed, so that the same seed and
+// circuit give the same keys (and Solidity verifier) byte for byte. For tests and exercises only:
+// the seed determines the toxic waste, anyone knowing it can forge proofs.
+func Setup(r1cs frontend.CompiledConstraintSystem, seed []byte) (pk groth16.ProvingKey, vk groth16.VerifyingKey, err error) {
+	err = Run(seed, func() error {
+		pk, vk, err = groth16.Setup(r1cs)
+		return err
+	})
+	return pk, vk, err
+}
+
+// Run runs fn with crypto/rand.Reader derived from seed: setups, proofs (their blinding values)
+// and keys generated by fn are the same from one run to the other. For tests only.
+//
+// gnark samples its randomness from crypto/rand.Reader, which is replaced while fn runs:
+// nothing else may read it concurrently.
+func Run(seed []byte, fn func() error) error {
+	lock.Lock()
+	defer lock.Unlock()
+	reader := rand.Reader
+	rand.Reader = &Reader{seed: seed}
+	defer func() {
+		rand.Reader = reader
+	}()
+	return fn()
+}
+
+// Reader reads the blocks sha256(seed || counter) for counter = 0, 1, ...
+type Reader struct {
+	seed    []byte
+	counter uint64
+	block   []byte
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.block) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], r.counter)
+			r.counter++
+			h := sha256.New()
+			h.Write(r.seed)
+			h.Write(counter[:])
+			r.block = h.Sum(nil)
+		}
+		c := copy(p[n:], r.block)
+		r.block = r.block[c:]
+		n += c
+	}
+	return n, nil
+}
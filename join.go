@@ -0,0 +1,184 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/session"
+)
+
+// runJoin joins a facilitator-run session: it downloads the session artifacts, proves the
+// circuit example locally and checks the proof against the verifier deployed on the session chain.
+// No solc, no node, no setup on the attendee side.
+func runJoin(args []string) {
+	fs := flag.NewFlagSet("join", flag.ExitOnError)
+	fSession := fs.String("session", "", "URL of the session, as given by the facilitator")
+	fDir := fs.String("dir", "sessions", "directory where session artifacts are downloaded")
+	fTx := fs.Bool("tx", false, "also send the verifyProof call as a transaction")
+	fSigner := addSignerFlags(fs, "sender (with -tx)")
+	assertNoError(fs.Parse(args))
+	if *fSession == "" {
+		log.Fatal("join: -session is required")
+	}
+
+	ctx := mainCtx
+	s, err := session.Fetch(ctx, *fSession)
+	assertNoError(err)
+	c, ok := s.Circuits[*fCircuit]
+	if !ok {
+		log.Fatalf("session %q doesn't serve circuit %q", s.Name, *fCircuit)
+	}
+	assertNoError(c.Manifest.CheckFeatures())
+
+	// download the artifacts, the session name is only used as a directory name
+	dir := filepath.Join(*fDir, filepath.Base(filepath.Clean("/"+s.Name)))
+	r1csFile := filepath.Join(dir, *fCircuit+".r1cs")
+	pkFile := filepath.Join(dir, *fCircuit+".pk")
+	vkFile := filepath.Join(dir, *fCircuit+".vk")
+	for fileName, a := range map[string]session.Artifact{r1csFile: c.R1CS, pkFile: c.PK, vkFile: c.VK} {
+		log.Println("downloading", fileName)
+		assertNoError(s.Download(ctx, a, fileName))
+	}
+
+	r1cs := groth16.NewCS(ecc.BN254)
+	pk := groth16.NewProvingKey(ecc.BN254)
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(r1cs, r1csFile)
+	deserialize(pk, pkFile)
+	deserialize(vk, vkFile)
+
+	// prove the circuit example, and check it locally first
+	witness, err := circuits.Example(*fCircuit)
+	assertNoError(err)
+	log.Println("creating proof")
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	assertNoError(err)
+	assertNoError(groth16.Verify(proof, vk, witness))
+
+	publicWitness, err := ethereum.PublicWitness(witness)
+	assertNoError(err)
+	assertNoError(ethereum.CheckPublicWitness(vk, publicWitness))
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	assertNoError(err)
+
+	// verify on the session chain
+	client := dialNode(ctx, s.Network.RPCURL, s.Network.ChainID)
+	defer client.Close()
+	chainID := client.chainID
+	nbPublicInputs, err := ethereum.NbPublicInputs(vk)
+	assertNoError(err)
+	verifier, err := ethereum.NewVerifier(c.Verifier, client, nbPublicInputs)
+	assertNoError(err)
+	valid, err := verifier.VerifyProof(&bind.CallOpts{Context: ctx}, solidityInputs)
+	assertNoError(err)
+	log.Printf("verifyProof on %s (chain %s) returned %t", c.Verifier.Hex(), chainID, valid)
+
+	result := joinResult{Session: s.Name, Circuit: *fCircuit, ChainID: chainID.Int64(), Verifier: c.Verifier, Valid: valid}
+	if *fTx {
+		calldata, err := solidityInputs.Calldata()
+		assertNoError(err)
+		signer, closeSigner := fSigner.signer()
+		defer closeSigner()
+		receipt := client.sendCall(ctx, signer, c.Verifier, calldata, 0)
+		log.Printf("transaction mined in block %d (status: %d, gas used: %d)", receipt.BlockNumber, receipt.Status, receipt.GasUsed)
+		result.Transaction = newTxResult(receipt)
+	}
+	emit("join", result)
+
+	if !valid {
+		os.Exit(1)
+	}
+}
+
+// runServeSession is the facilitator side of join: it serves the session document and the
+// artifacts of every registered circuit that was initialized and deployed on the session chain.
+func runServeSession(args []string) {
+	fs := flag.NewFlagSet("serve-session", flag.ExitOnError)
+	fAddr := fs.String("addr", ":8080", "address to listen on")
+	fName := fs.String("name", "gnark-workshop", "session name")
+	fRPCURL := fs.String("rpc-url", "http://localhost:8545", "JSON-RPC endpoint of the session chain, as reachable by attendees")
+	fChainID := fs.Int64("chain-id", 0, "chain ID, queried from the node if not set")
+	fDeployments := fs.String("deployments", deploymentsPath, "deployments file")
+	assertNoError(fs.Parse(args))
+
+	client, chainID := dial(mainCtx, *fRPCURL, *fChainID)
+	client.Close()
+
+	deployments, err := ethereum.ReadDeployments(*fDeployments)
+	assertNoError(err)
+
+	s := &session.Session{
+		Name:     *fName,
+		Network:  session.Network{RPCURL: *fRPCURL, ChainID: chainID.Int64()},
+		Circuits: make(map[string]session.Circuit),
+	}
+	for _, name := range circuits.Names() {
+		deployment, ok := deployments.Get(chainID, name)
+		if !ok {
+			continue
+		}
+		c, err := sessionCircuit(name, deployment)
+		if err != nil {
+			log.Printf("skipping circuit %s: %v", name, err)
+			continue
+		}
+		s.Circuits[name] = c
+		log.Printf("serving circuit %s (verifier %s)", name, deployment.Verifier.Hex())
+	}
+	if len(s.Circuits) == 0 {
+		log.Fatalf("no circuit deployed on chain %s in %s, run deploy first", chainID, *fDeployments)
+	}
+
+	emit("serve-session", s)
+	log.Printf("session %q listening on %s, attendees run: go run . join -session http://<host>%s", s.Name, *fAddr, *fAddr)
+	log.Fatal(http.ListenAndServe(*fAddr, session.Handler(s, artifactsRoot)))
+}
+
+// artifactsRoot is the directory served under artifacts/ by serve-session: the store, and the
+// directories of the circuits set up before it
+const artifactsRoot = circuitsDir
+
+// sessionCircuit describes the local artifacts of circuit name, served under artifacts/
+func sessionCircuit(name string, deployment ethereum.Deployment) (session.Circuit, error) {
+	cf := filesOf(name)
+	manifest, err := artifacts.ReadManifest(cf.manifest)
+	if err != nil {
+		return session.Circuit{}, err
+	}
+	c := session.Circuit{Verifier: deployment.Verifier, Manifest: *manifest}
+	for _, a := range []struct {
+		dst      *session.Artifact
+		fileName string
+	}{{&c.R1CS, cf.r1cs}, {&c.PK, cf.pk}, {&c.VK, cf.vk}} {
+		rel, err := filepath.Rel(artifactsRoot, a.fileName)
+		if err != nil {
+			return session.Circuit{}, err
+		}
+		*a.dst, err = session.NewArtifact(a.fileName, "artifacts/"+filepath.ToSlash(rel))
+		if err != nil {
+			return session.Circuit{}, fmt.Errorf("%s: %w", a.fileName, err)
+		}
+	}
+	return c, nil
+}
+
+// joinResult is the JSON output of join
+type joinResult struct {
+	Session     string         `json:"session"`
+	Circuit     string         `json:"circuit"`
+	ChainID     int64          `json:"chainId"`
+	Verifier    common.Address `json:"verifier"`
+	Valid       bool           `json:"valid"`
+	Transaction *txResult      `json:"transaction,omitempty"`
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/session"
+)
+
+// chdir runs the test in a new working directory, the paths of files.go being relative
+func chdir(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		content := name
+		if name == artifacts.ManifestFile {
+			content = `{"circuit": "mimc"}`
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestSessionCircuit checks that serve-session serves the setup artifacts where the session document
+// says, in the store and in the directory of a circuit set up before it
+func TestSessionCircuit(t *testing.T) {
+	keyFiles := []string{artifacts.R1CSFile, artifacts.PKFile, artifacts.VKFile, artifacts.ManifestFile}
+	for name, tc := range map[string]struct {
+		setup func(t *testing.T)
+		dir   string
+	}{
+		"store": {
+			setup: func(t *testing.T) {
+				writeFiles(t, store.Dir("0123456789abcdef"), keyFiles...)
+				if err := store.SetCurrent("mimc", "0123456789abcdef"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			dir: "store/0123456789abcdef",
+		},
+		"legacy store": {
+			setup: func(t *testing.T) {
+				legacy := filepath.Join("circuit", "store")
+				writeFiles(t, filepath.Join(legacy, "0123456789abcdef"), keyFiles...)
+				if err := ioutil.WriteFile(filepath.Join(legacy, "mimc.current"), []byte("0123456789abcdef\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			dir: "store/0123456789abcdef",
+		},
+		"before the store": {
+			setup: func(t *testing.T) {
+				writeFiles(t, circuitDir("mimc"), "circuit.r1cs", "circuit.pk", "circuit.vk", artifacts.ManifestFile)
+			},
+			dir: "mimc",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			chdir(t)
+			tc.setup(t)
+			c, err := sessionCircuit("mimc", ethereum.Deployment{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := httptest.NewServer(session.Handler(&session.Session{}, artifactsRoot))
+			defer server.Close()
+			for _, a := range []session.Artifact{c.R1CS, c.PK, c.VK} {
+				if !strings.HasPrefix(a.URL, "artifacts/"+tc.dir+"/") {
+					t.Fatalf("%s is not served from %s", a.URL, tc.dir)
+				}
+				resp, err := server.Client().Get(server.URL + "/" + a.URL)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != 200 {
+					t.Fatalf("GET %s: %s", a.URL, resp.Status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+// #include <stdint.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"errors"
+	"sync"
+	"unsafe"
+
+	"github.com/gbotrel/gnark-workshop/mobile"
+)
+
+// The buffer API takes and returns raw bytes instead of JSON and file paths, for callers that
+// already hold the artifacts in memory. Functions return NULL on success, or an error message to
+// release with FreeString; output buffers are allocated with malloc, release them with FreeBuffer.
+//
+//	uint64_t prover;
+//	char *err = LoadProver(r1cs, r1cs_len, pk, pk_len, &prover);
+//	err = ProveBuffer(prover, secret, secret_len, &proof, &proof_len, &calldata, &calldata_len);
+//	ReleaseProver(prover);
+
+// provers are the provers loaded by LoadProver, by handle
+var (
+	proversLock sync.Mutex
+	provers     = make(map[uint64]*mobile.Prover)
+	lastHandle  uint64
+)
+
+var errUnknownProver = errors.New("unknown prover handle")
+
+// LoadProver loads a serialized R1CS and proving key, and sets *handle to the prover to pass to ProveBuffer
+//
+//export LoadProver
+func LoadProver(r1cs *C.uchar, r1csLen C.size_t, pk *C.uchar, pkLen C.size_t, handle *C.uint64_t) *C.char {
+	p, err := mobile.NewProver(goBytes(r1cs, r1csLen), goBytes(pk, pkLen))
+	if err != nil {
+		return C.CString(err.Error())
+	}
+	proversLock.Lock()
+	defer proversLock.Unlock()
+	lastHandle++
+	provers[lastHandle] = p
+	*handle = C.uint64_t(lastHandle)
+	return nil
+}
+
+// ReleaseProver releases a prover loaded by LoadProver
+//
+//export ReleaseProver
+func ReleaseProver(handle C.uint64_t) {
+	proversLock.Lock()
+	defer proversLock.Unlock()
+	delete(provers, uint64(handle))
+}
+
+// ProveBuffer creates a proof of knowledge of the pre-image of mimc(secret) with a loaded prover;
+// it returns the gnark proof and the verifyProof calldata
+//
+//export ProveBuffer
+func ProveBuffer(handle C.uint64_t, secret *C.uchar, secretLen C.size_t,
+	proof **C.uchar, proofLen *C.size_t, calldata **C.uchar, calldataLen *C.size_t) *C.char {
+	proversLock.Lock()
+	p, ok := provers[uint64(handle)]
+	proversLock.Unlock()
+	if !ok {
+		return C.CString(errUnknownProver.Error())
+	}
+
+	result, err := p.Prove(goBytes(secret, secretLen))
+	if err != nil {
+		return C.CString(err.Error())
+	}
+	*proof, *proofLen = cBytes(result.Proof)
+	*calldata, *calldataLen = cBytes(result.Calldata)
+	return nil
+}
+
+// VerifyBuffer checks a proof against a serialized verifying key and the public input hash, and
+// sets *valid to 1 if the proof is valid, 0 otherwise
+//
+//export VerifyBuffer
+func VerifyBuffer(vk *C.uchar, vkLen C.size_t, proof *C.uchar, proofLen C.size_t,
+	hash *C.uchar, hashLen C.size_t, valid *C.int) *C.char {
+	ok, err := mobile.Verify(goBytes(vk, vkLen), goBytes(proof, proofLen), goBytes(hash, hashLen))
+	if err != nil {
+		return C.CString(err.Error())
+	}
+	*valid = 0
+	if ok {
+		*valid = 1
+	}
+	return nil
+}
+
+// FreeBuffer releases a buffer returned by this library
+//
+//export FreeBuffer
+func FreeBuffer(b *C.uchar) {
+	C.free(unsafe.Pointer(b))
+}
+
+// goBytes copies a C buffer, so that the caller may release it as soon as the call returns
+func goBytes(b *C.uchar, n C.size_t) []byte {
+	if b == nil || n == 0 {
+		return nil
+	}
+	return C.GoBytes(unsafe.Pointer(b), C.int(n))
+}
+
+func cBytes(b []byte) (*C.uchar, C.size_t) {
+	return (*C.uchar)(C.CBytes(b)), C.size_t(len(b))
+}
@@ -0,0 +1,240 @@
+// Command libgnarkworkshop is built as a C shared library exposing the workshop pipeline
+//
+//	go build -buildmode=c-shared -o libgnarkworkshop.so ./libgnarkworkshop
+//
+// HashMiMC, Prove and Verify take a JSON request (NUL terminated UTF-8 string) and return a
+// JSON response allocated with malloc; callers must release it with FreeString.
+// On failure, the response is {"error": "..."}. The buffer API (see buffers.go) takes and returns
+// raw bytes instead.
+package main
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+func main() {}
+
+type hashRequest struct {
+	Data string `json:"data"` // hex encoded
+}
+
+type hashResponse struct {
+	Hash string `json:"hash"` // hex encoded
+}
+
+type proveRequest struct {
+	R1CS   string `json:"r1cs"`   // path to the serialized R1CS
+	PK     string `json:"pk"`     // path to the serialized proving key
+	Secret string `json:"secret"` // hex encoded pre-image
+}
+
+type proveResponse struct {
+	Proof    string `json:"proof"`    // hex encoded gnark proof
+	Hash     string `json:"hash"`     // hex encoded public input
+	Calldata string `json:"calldata"` // hex encoded verifyProof calldata
+}
+
+type verifyRequest struct {
+	VK    string `json:"vk"`    // path to the serialized verifying key
+	Proof string `json:"proof"` // hex encoded gnark proof
+	Hash  string `json:"hash"`  // hex encoded public input
+}
+
+type verifyResponse struct {
+	Valid bool `json:"valid"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// HashMiMC returns mimc(data), as computed by the circuit
+//
+//export HashMiMC
+func HashMiMC(request *C.char) *C.char {
+	var req hashRequest
+	if err := json.Unmarshal([]byte(C.GoString(request)), &req); err != nil {
+		return respondError(err)
+	}
+	data, err := hex.DecodeString(req.Data)
+	if err != nil {
+		return respondError(err)
+	}
+	hash, err := circuit.Hash(data)
+	if err != nil {
+		return respondError(err)
+	}
+	return respond(hashResponse{Hash: hex.EncodeToString(hash)})
+}
+
+// Prove creates a proof of knowledge of the pre-image of mimc(secret)
+//
+//export Prove
+func Prove(request *C.char) *C.char {
+	var req proveRequest
+	if err := json.Unmarshal([]byte(C.GoString(request)), &req); err != nil {
+		return respondError(err)
+	}
+	secret, err := hex.DecodeString(req.Secret)
+	if err != nil {
+		return respondError(err)
+	}
+
+	r1cs, pk, err := loadProvingArtifacts(req.R1CS, req.PK)
+	if err != nil {
+		return respondError(err)
+	}
+
+	witness, err := circuit.NewWitness(secret)
+	if err != nil {
+		return respondError(err)
+	}
+	hash, err := circuit.Hash(secret)
+	if err != nil {
+		return respondError(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	if err != nil {
+		return respondError(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return respondError(err)
+	}
+
+	publicWitness, err := ethereum.PublicWitness(witness)
+	if err != nil {
+		return respondError(err)
+	}
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	if err != nil {
+		return respondError(err)
+	}
+	calldata, err := solidityInputs.Calldata()
+	if err != nil {
+		return respondError(err)
+	}
+
+	return respond(proveResponse{
+		Proof:    hex.EncodeToString(buf.Bytes()),
+		Hash:     hex.EncodeToString(hash),
+		Calldata: hex.EncodeToString(calldata),
+	})
+}
+
+// Verify checks a proof against a verifying key and its public input
+//
+//export Verify
+func Verify(request *C.char) *C.char {
+	var req verifyRequest
+	if err := json.Unmarshal([]byte(C.GoString(request)), &req); err != nil {
+		return respondError(err)
+	}
+	proofBytes, err := hex.DecodeString(req.Proof)
+	if err != nil {
+		return respondError(err)
+	}
+	hash, err := hex.DecodeString(req.Hash)
+	if err != nil {
+		return respondError(err)
+	}
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if err := readFile(vk, req.VK); err != nil {
+		return respondError(err)
+	}
+	proof := groth16.NewProof(ecc.BN254)
+	if _, err := artifacts.Read(bytes.NewReader(proofBytes), header, proof); err != nil {
+		return respondError(err)
+	}
+
+	var publicWitness circuit.Circuit
+	publicWitness.Hash.Assign(hash)
+
+	// an invalid proof is a valid answer, not an error
+	return respond(verifyResponse{Valid: groth16.Verify(proof, vk, &publicWitness) == nil})
+}
+
+// FreeString releases a response returned by this library
+//
+//export FreeString
+func FreeString(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// loaded R1CS and proving keys, keyed by file path, so that repeated Prove calls don't re-read them
+var (
+	artifactsLock sync.Mutex
+	r1csCache     = make(map[string]frontend.CompiledConstraintSystem)
+	pkCache       = make(map[string]groth16.ProvingKey)
+)
+
+func loadProvingArtifacts(r1csPath, pkPath string) (frontend.CompiledConstraintSystem, groth16.ProvingKey, error) {
+	artifactsLock.Lock()
+	defer artifactsLock.Unlock()
+
+	r1cs, ok := r1csCache[r1csPath]
+	if !ok {
+		r1cs = groth16.NewCS(ecc.BN254)
+		if err := readFile(r1cs, r1csPath); err != nil {
+			return nil, nil, err
+		}
+		r1csCache[r1csPath] = r1cs
+	}
+
+	pk, ok := pkCache[pkPath]
+	if !ok {
+		pk = groth16.NewProvingKey(ecc.BN254)
+		if err := readFile(pk, pkPath); err != nil {
+			return nil, nil, err
+		}
+		pkCache[pkPath] = pk
+	}
+
+	return r1cs, pk, nil
+}
+
+// header is the expected header of the files written by the workshop binary
+var header = artifacts.Header{GnarkVersion: artifacts.GnarkVersion(), Curve: ecc.BN254, Backend: backend.GROTH16}
+
+// readFile deserializes a gnark object from given file
+func readFile(gnarkObject io.ReaderFrom, fileName string) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = artifacts.Read(f, header, gnarkObject)
+	return err
+}
+
+func respond(v interface{}) *C.char {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return respondError(err)
+	}
+	return C.CString(string(data))
+}
+
+func respondError(err error) *C.char {
+	data, _ := json.Marshal(errorResponse{Error: err.Error()})
+	return C.CString(string(data))
+}
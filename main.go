@@ -2,64 +2,312 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"io"
+	"io/ioutil"
 	"log"
 	"math/big"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
-	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
-	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/accel"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/ceremony"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	_ "github.com/gbotrel/gnark-workshop/circuits/all" // registers the workshop circuits
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/features"
+	"github.com/gbotrel/gnark-workshop/fetch"
+	"github.com/gbotrel/gnark-workshop/internal/seeded"
+	"github.com/gbotrel/gnark-workshop/prover"
+	"github.com/gbotrel/gnark-workshop/solc"
+	"github.com/gbotrel/gnark-workshop/testchain"
+	"github.com/gbotrel/gnark-workshop/workshop"
 )
 
-var fInit = flag.Bool("init", false, "set to true to run circuit Setup and export solidity Verifier")
-
-const (
-	r1csPath     = "circuit/mimc.r1cs"
-	pkPath       = "circuit/mimc.pk"
-	vkPath       = "circuit/mimc.vk"
-	solidityPath = "circuit/mimc_verifier.sol"
+var (
+	fInit        = flag.Bool("init", false, "set to true to run circuit Setup and export solidity Verifier")
+	fAutoInit    = flag.Bool("auto-init", false, "set to true to run -init first if the circuit has no setup, or changed since its setup")
+	fSecretStdin = flag.Bool("secret-stdin", false, "set to true to prove the knowledge of a secret read from stdin instead of the example's (or set "+SecretEnv+")")
+	fSigningKey  = flag.String("signing-key", "", "if set, file of the hex ed25519 seed -init signs the artifacts manifest with (see sign-manifest)")
+	fDryRun      = flag.Bool("dry-run", false, "set to true to estimate the proving time and memory of the circuit on this machine, without proving")
+	fAccumulator = flag.Bool("accumulator", false, "set to true to run the accumulate → aggregate → settle demo (requires solc)")
+	fRollup      = flag.Bool("rollup", false, "set to true to run the toy zk-rollup demo (requires solc)")
+	fMixer       = flag.Bool("mixer", false, "set to true to run the private deposit / withdraw demo (requires solc)")
+	fVoting      = flag.Bool("voting", false, "set to true to run the private voting demo (requires solc)")
+	fRecursion   = flag.Bool("recursion", false, "set to true to run the proof recursion demo (BLS12-377 proof verified in a BW6-761 circuit)")
+	fCircuit     = flag.String("circuit", defaultCircuit, "name of the registered circuit to use")
+	fParams      = flag.String("params", "", "compile parameters of a parameterized circuit, e.g. depth=8 for merkle (or -circuit merkle@depth=8)")
+	fNode        = flag.String("node", "", "JSON-RPC URL of a local anvil or hardhat node to run the demos on, instead of the simulated backend")
+	// fDeterministicSetup is the toxic waste: test only
+	fDeterministicSetup = flag.String("deterministic-setup", "", "test only: seed of -init's setup randomness, for reproducible keys and verifier (insecure)")
+	fBeacon             = flag.String("beacon", "", "if set, public random beacon (a block hash, the randomness of a drand round) mixed into -init's setup last, recorded in the manifest")
+	fProcs              = flag.Int("procs", 0, "if set, number of cores the proofs run on, as GOMAXPROCS")
+	fCPUs               = flag.String("cpus", "", "linux: if set, CPUs to run on, e.g. the cpulist of a NUMA node (0-7,16-23)")
+	fSolcVersion        = flag.String("solc-version", "", "if set, solc release to download, cache and compile with (e.g. "+solc.DefaultVersion+"), instead of the solc in PATH")
 )
 
 /*
 	Need:
-	* install abigen
+	* install solc
 	* if fInit is set, run circuit Setup and export solidity verifier.
 */
 func main() {
 	flag.Parse()
+	// Ctrl-C cancels mainCtx: long operations stop waiting and report what was done
+	var stop context.CancelFunc
+	mainCtx, stop = signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	jsonOutput() // validates -output
+	// parameterized circuits are named by their instance, which names their artifacts
+	params, err := circuits.ParseParams(*fParams)
+	assertNoError(err)
+	if *fCircuit, err = circuits.Instance(*fCircuit, params); err != nil {
+		log.Fatal(err)
+	}
+	if _, err := circuits.Get(*fCircuit); err != nil {
+		log.Fatal(err)
+	}
+	files = filesOf(*fCircuit)
+	assertNoError(os.MkdirAll(circuitDir(*fCircuit), 0755))
+	if *fSolcVersion != "" {
+		path, err := solc.Install(mainCtx, *fSolcVersion, "")
+		assertNoError(err)
+		ethereum.Solc = path
+	}
+	if *fCPUs != "" {
+		cpus, err := parseCPUList(*fCPUs)
+		assertNoError(err)
+		assertNoError(setAffinity(cpus))
+		if *fProcs == 0 {
+			*fProcs = len(cpus)
+		}
+	}
+	if *fProcs != 0 {
+		runtime.GOMAXPROCS(*fProcs)
+	}
+
+	switch flag.Arg(0) {
+	case "chunk-pk":
+		runChunkPK(flag.Args()[1:])
+		return
+	case "sign-manifest":
+		runSignManifest(flag.Args()[1:])
+		return
+	case "encrypt-keys":
+		runEncryptKeys(flag.Args()[1:])
+		return
+	case "verify":
+		runVerify(flag.Args()[1:])
+		return
+	case "verify-onchain":
+		runVerifyOnChain(flag.Args()[1:])
+		return
+	case "vk-fingerprint":
+		runVKFingerprint(flag.Args()[1:])
+		return
+	case "check-deployment":
+		runCheckDeployment(flag.Args()[1:])
+		return
+	case "verify-batch":
+		runVerifyBatch(flag.Args()[1:])
+		return
+	case "calldata":
+		runCalldata(flag.Args()[1:])
+		return
+	case "pairing-input":
+		runPairingInput(flag.Args()[1:])
+		return
+	case "check-solidity-encoding":
+		runCheckSolidityEncoding(flag.Args()[1:])
+		return
+	case "deploy":
+		runDeploy(flag.Args()[1:])
+		return
+	case "rotate-vk":
+		runRotateVK(flag.Args()[1:])
+		return
+	case "migrate":
+		runMigrate(flag.Args()[1:])
+		return
+	case "export-tx":
+		runExportTx(flag.Args()[1:])
+		return
+	case "submit":
+		runSubmit(flag.Args()[1:])
+		return
+	case "join":
+		runJoin(flag.Args()[1:])
+		return
+	case "serve-session":
+		runServeSession(flag.Args()[1:])
+		return
+	case "race-start":
+		runRaceStart(flag.Args()[1:])
+		return
+	case "race":
+		runRace(flag.Args()[1:])
+		return
+	case "race-board":
+		runRaceBoard(flag.Args()[1:])
+		return
+	case "serve-prover":
+		runServeProver(flag.Args()[1:])
+		return
+	case "bundle":
+		runBundle(flag.Args()[1:])
+		return
+	case "prove-batch":
+		runProveBatch(flag.Args()[1:])
+		return
+	case "prove-remote":
+		runProveRemote(flag.Args()[1:])
+		return
+	case "bench-gas":
+		runBenchGas(flag.Args()[1:])
+		return
+	case "bench":
+		runBench(flag.Args()[1:])
+		return
+	case "export-vk":
+		runExportVK(flag.Args()[1:])
+		return
+	case "record":
+		runRecord(flag.Args()[1:])
+		return
+	case "registry":
+		runRegistry(flag.Args()[1:])
+		return
+	case "commit-reveal":
+		runCommitReveal(flag.Args()[1:])
+		return
+	case "export-verifier":
+		runExportVerifier(flag.Args()[1:])
+		return
+	case "export-foundry":
+		runExportFoundry(flag.Args()[1:])
+		return
+	case "export-hardhat":
+		runExportHardhat(flag.Args()[1:])
+		return
+	case "export-r1cs":
+		runExportR1CS(flag.Args()[1:])
+		return
+	case "export-proof":
+		runExportProof(flag.Args()[1:])
+		return
+	case "import-proof":
+		runImportProof(flag.Args()[1:])
+		return
+	case "ceremony":
+		runCeremony(flag.Args()[1:])
+		return
+	case "srs":
+		runSRS(flag.Args()[1:])
+		return
+	case "debug-witness":
+		runDebugWitness(flag.Args()[1:])
+		return
+	case "profile":
+		runProfile(flag.Args()[1:])
+		return
+	case "golden":
+		runGolden(flag.Args()[1:])
+		return
+	case "negative":
+		runNegative(flag.Args()[1:])
+		return
+	case "exercise":
+		runExercise(flag.Args()[1:])
+		return
+	case "storage-witness":
+		runStorageWitness(flag.Args()[1:])
+		return
+	case "solc":
+		runSolc(flag.Args()[1:])
+		return
+	}
+	if *fDryRun {
+		runDryRun()
+		return
+	}
 	if *fInit {
 		initCircuit()
 		return
 	}
+	if *fAccumulator {
+		runAccumulator()
+		return
+	}
+	if *fRollup {
+		runRollup()
+		return
+	}
+	if *fMixer {
+		runMixer()
+		return
+	}
+	if *fVoting {
+		runVoting()
+		return
+	}
+	if *fRecursion {
+		runRecursion()
+		return
+	}
 
-	// check that init was performed
-	if _, err := os.Stat(r1csPath); os.IsNotExist(err) {
-		log.Fatal("please run with -init flag first to serialize circuit, keys and solidity contract")
+	// print the cost of each stage at the end of the run
+	defer report.print()
+
+	// check that init was performed for the current circuit, before deploying its verifier, or that
+	// the binary embeds the artifacts
+	fromBundle := hasEmbedded(*fCircuit)
+	if !fromBundle {
+		checkOrInit()
 	}
 
 	// setup geth simulated backend, deploy smart contract
-	verifierContract, err := deploySolidity()
+	done := report.track("deploy")
+	verifierAddress, chain, err := deploySolidity()
 	assertNoError(err)
+	done()
 
 	// read R1CS, proving key and verifying keys
+	done = report.track("deserialize")
 	r1cs := groth16.NewCS(ecc.BN254)
 	pk := groth16.NewProvingKey(ecc.BN254)
 	vk := groth16.NewVerifyingKey(ecc.BN254)
-	deserialize(r1cs, r1csPath)
-	deserialize(pk, pkPath)
-	deserialize(vk, vkPath)
+	if fromBundle {
+		log.Println("using the artifacts embedded in the binary")
+		keys, err := prover.FSLoader(embedded)(*fCircuit)
+		assertNoError(err)
+		r1cs, pk, vk = keys.R1CS, keys.PK, keys.VK
+	} else {
+		deserialize(r1cs, files.r1cs)
+		deserialize(pk, files.pk)
+		deserialize(vk, files.vk)
+	}
+	done()
+
+	// the number of public inputs of the circuit is given by the verifying key
+	nbPublicInputs, err := ethereum.NbPublicInputs(vk)
+	assertNoError(err)
+	verifierContract, err := ethereum.NewVerifier(verifierAddress, chain, nbPublicInputs)
+	assertNoError(err)
 
 	// Now we want to create a valid proof
 	// 1. We compute our secret, and the hash of our secret
@@ -67,61 +315,67 @@ func main() {
 	// 3. Then, we ensure the proof verifies in plain Go
 	// 4. Finally, we build the solidity input and submit the transaction to the blockchain.
 
-	// pick a secret
-	const secret = "secret"
-	// hash it with mimc
-	var hash []byte
-	hFunc := mimc.NewMiMC("seed")
-	hFunc.Write([]byte(secret))
-	hash = hFunc.Sum(hash)
-
-	// assign values to witness
-	var witness circuit.Circuit
-	witness.Hash.Assign(hash)
-	witness.Secret.Assign([]byte(secret))
+	// the circuit builds its example assignment; for mimc, the secret "secret" and its mimc hash,
+	// unless the user gives a secret (-secret-stdin, or the environment), never logged
+	secret, hasSecret, err := readSecret()
+	assertNoError(err)
+	var witness frontend.Circuit
+	if hasSecret {
+		witness, err = circuits.WithSecret(*fCircuit, secret)
+	} else {
+		witness, err = circuits.Example(*fCircuit)
+	}
+	assertNoError(err)
 
 	// create the proof
 	log.Println("creating proof")
-	proof, err := groth16.Prove(r1cs, pk, &witness)
-	assertNoError(err)
+	done = report.track("prove")
+	progress := newProgressLine()
+	proof, err := prover.ProveWithProgress(mainCtx, progress, func() (groth16.Proof, error) {
+		return accel.Prove(r1cs, pk, vk, witness)
+	})
+	progress.end()
+	assertCompleted(err)
+	done()
 
 	// ensure gnark (Go) code verifies it
-	err = groth16.Verify(proof, vk, &witness)
+	done = report.track("verify")
+	assertCompleted(workshop.Run(mainCtx, func() error {
+		return groth16.Verify(proof, vk, witness)
+	}))
+	done()
+
+	// public witness, the hash of the secret is on chain
+	publicWitness, err := ethereum.PublicWitness(witness)
 	assertNoError(err)
+	assertNoError(ethereum.CheckPublicWitness(vk, publicWitness))
+
+	// the witness references the secret, done with: wipe it
+	wipe(secret)
+
+	// serialize the proof and the public witness, so that `verify` can check them offline; not
+	// with embedded artifacts, which run without the filesystem
+	if !fromBundle {
+		done = report.track("serialize")
+		log.Println("serialize proof", files.proof)
+		serialize(proof, files.proof)
+
+		log.Println("serialize public witness", files.publicWitness)
+		data := ethereum.EncodePublicWitness(publicWitness)
+		assertNoError(ioutil.WriteFile(files.publicWitness, data, 0644))
+		atomic.AddInt64(&ioWritten, int64(len(data)))
+		done()
+	}
 
 	// solidity contract inputs
-	// a, b and c are the 3 ecc points in the proof we feed to the pairing
-	// they are stored in the same order in the golang data structure
-	// each coordinate is a field element, of size fp.Bytes bytes
-	var (
-		a     [2]*big.Int
-		b     [2][2]*big.Int
-		c     [2]*big.Int
-		input [1]*big.Int
-	)
-
-	// get proof bytes
-	var buf bytes.Buffer
-	proof.WriteRawTo(&buf)
-	proofBytes := buf.Bytes()
-
-	// proof.Ar, proof.Bs, proof.Krs
-	const fpSize = fp.Bytes
-	a[0] = new(big.Int).SetBytes(proofBytes[fpSize*0 : fpSize*1])
-	a[1] = new(big.Int).SetBytes(proofBytes[fpSize*1 : fpSize*2])
-	b[0][0] = new(big.Int).SetBytes(proofBytes[fpSize*2 : fpSize*3])
-	b[0][1] = new(big.Int).SetBytes(proofBytes[fpSize*3 : fpSize*4])
-	b[1][0] = new(big.Int).SetBytes(proofBytes[fpSize*4 : fpSize*5])
-	b[1][1] = new(big.Int).SetBytes(proofBytes[fpSize*5 : fpSize*6])
-	c[0] = new(big.Int).SetBytes(proofBytes[fpSize*6 : fpSize*7])
-	c[1] = new(big.Int).SetBytes(proofBytes[fpSize*7 : fpSize*8])
-
-	// public witness, the hash of the secret is on chain
-	input[0] = new(big.Int).SetBytes(hash)
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	assertNoError(err)
 
 	// call the contract
-	res, err := verifierContract.VerifyProof(nil, a, b, c, input)
+	done = report.track("submit")
+	res, err := verifierContract.VerifyProof(&bind.CallOpts{Context: mainCtx}, solidityInputs)
 	assertNoError(err)
+	done()
 
 	if !res {
 		log.Fatal("calling the verifier on chain didn't succeed, but should have")
@@ -129,98 +383,387 @@ func main() {
 	log.Println("successfully verified proof on-chain")
 
 	// (wrong) public witness
-	input[0] = new(big.Int).SetUint64(42)
+	solidityInputs.Input[0] = new(big.Int).SetUint64(42)
 
 	// call the contract should fail
-	res, err = verifierContract.VerifyProof(nil, a, b, c, input)
+	res, err = verifierContract.VerifyProof(nil, solidityInputs)
 	assertNoError(err)
 	if res {
 		log.Println("calling the verifier suceeded, but shouldn't have")
 	}
 
+	emit("run", runResult{
+		Circuit:           *fCircuit,
+		Verifier:          verifierAddress,
+		Valid:             true,
+		RejectsWrongInput: !res,
+		Stages:            report.stages,
+	})
+}
+
+// runResult is the JSON output of the end-to-end demo
+type runResult struct {
+	Circuit           string         `json:"circuit"`
+	Verifier          common.Address `json:"verifier"`
+	Valid             bool           `json:"valid"`
+	RejectsWrongInput bool           `json:"rejectsWrongInput"`
+	Stages            []stageStats   `json:"stages"`
+}
+
+// initResult is the JSON output of -init
+type initResult struct {
+	Circuit  string             `json:"circuit"`
+	Manifest artifacts.Manifest `json:"manifest"`
+	Files    map[string]string  `json:"files"`
+	Stages   []stageStats       `json:"stages"`
 }
 
-func deploySolidity() (*circuit.Verifier, error) {
-	const gasLimit uint64 = 8000029
-	key, err := crypto.GenerateKey()
+func deploySolidity() (common.Address, ethereum.Backend, error) {
+	auth, chain, err := newBackend()
 	if err != nil {
-		return nil, err
-	}
-	auth := bind.NewKeyedTransactor(key)
-	genesis := map[common.Address]core.GenesisAccount{
-		auth.From: {Balance: big.NewInt(10000000000)},
+		return common.Address{}, nil, err
 	}
-	simulatedBackend := backends.NewSimulatedBackend(genesis, gasLimit)
 
-	// deploy verifier contract
+	// deploy verifier contract from its creation bytecode, so that any registered circuit can be deployed
 	log.Println("deploying verifier contract on chain")
-	_, _, verifierContract, err := circuit.DeployVerifier(auth, simulatedBackend)
+	bytecode, err := verifierBytecode()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	verifierAddress, _, err := ethereum.DeployRaw(mainCtx, auth, chain, bytecode)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	chain.Commit()
+	return verifierAddress, chain, nil
+}
+
+// newBackend returns the chain the demos run on, and a funded account: a testchain simulated backend,
+// or the anvil / hardhat node at -node
+func newBackend() (*bind.TransactOpts, ethereum.Backend, error) {
+	if *fNode != "" {
+		node, auth, err := ethereum.DialDevNode(mainCtx, *fNode)
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Printf("using %s (chain %s) at %s, account %s", node.ClientVersion, node.ChainID, *fNode, auth.From.Hex())
+		auth.Context = mainCtx
+		return auth, node, nil
+	}
+
+	chain, err := testchain.New(testchain.Genesis{Accounts: []string{"deployer"}})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	simulatedBackend.Commit()
-	return verifierContract, nil
+	auth := chain.Transactor("deployer")
+	auth.Context = mainCtx
+	return auth, chain, nil
 }
 
 func initCircuit() {
-	_, err := exec.LookPath("abigen")
+	_, err := exec.LookPath(ethereum.Solc)
 	if err != nil {
-		log.Fatal("please install abigen", err)
+		log.Fatal("please install solc", err)
 	}
 
-	var circuit circuit.Circuit
+	defer report.print()
 
-	// compile circuit
-	log.Println("compiling circuit")
-	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	circuit, r1cs, pk, vk, beacon := setupCircuit()
+	emit("init", saveSetup(circuit, r1cs, pk, vk, beacon, false))
+}
+
+// checkOrInit refuses artifacts of the selected circuit built with features this binary doesn't
+// support, or for another version of the circuit; with -auto-init, a circuit without setup or
+// changed since its setup is set up again instead
+func checkOrInit() {
+	if _, err := os.Stat(files.r1cs); os.IsNotExist(err) {
+		if !*fAutoInit {
+			log.Fatal("please run with -init flag first to serialize circuit, keys and solidity contract")
+		}
+		log.Printf("%s has no setup, running -init (-auto-init)", *fCircuit)
+		autoInit()
+		return
+	}
+	manifest, err := artifacts.ReadManifest(files.manifest)
 	assertNoError(err)
+	assertNoError(manifest.CheckFeatures())
+	// the demo deploys the verifier bytecode of the setup
+	assertNoError(checkManifest(manifest, files, filepath.Base(files.verifierBin)))
+	err = checkSetup(*fCircuit, manifest, files)
+	switch {
+	case errors.Is(err, artifacts.ErrCircuitMismatch) && *fAutoInit:
+		log.Printf("%s changed since its setup, running -init again (-auto-init)", *fCircuit)
+		autoInit()
+	case errors.Is(err, artifacts.ErrCircuitMismatch):
+		log.Fatalf("%v (or run with -auto-init)", err)
+	default:
+		assertNoError(err)
+	}
+}
+
+// autoInit runs -init for the selected circuit before the demo proves with its new setup
+func autoInit() {
+	if _, err := exec.LookPath(ethereum.Solc); err != nil {
+		log.Fatal("please install solc", err)
+	}
+	circuit, r1cs, pk, vk, beacon := setupCircuit()
+	saveSetup(circuit, r1cs, pk, vk, beacon, false)
+}
+
+// setupCircuit compiles the selected circuit and runs the groth16 trusted setup, then mixes the
+// -beacon into the keys; beacon is its record, nil without -beacon
+func setupCircuit() (circuit frontend.Circuit, r1cs frontend.CompiledConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey, beacon *artifacts.Beacon) {
+	circuit, err := circuits.Get(*fCircuit)
+	assertNoError(err)
+
+	// compile circuit
+	log.Println("compiling circuit", *fCircuit)
+	done := report.track("compile")
+	assertCompleted(workshop.Run(mainCtx, func() (err error) {
+		r1cs, err = frontend.Compile(ecc.BN254, backend.GROTH16, circuit)
+		return
+	}))
+	done()
 
 	// run groth16 trusted setup
 	log.Println("running groth16.Setup")
-	pk, vk, err := groth16.Setup(r1cs)
+	done = report.track("setup")
+	assertCompleted(workshop.Run(mainCtx, func() (err error) {
+		if *fDeterministicSetup != "" {
+			log.Println("deterministic setup: anyone knowing the seed can forge proofs, don't deploy these keys")
+			pk, vk, err = seeded.Setup(r1cs, []byte(*fDeterministicSetup))
+			return
+		}
+		pk, vk, err = groth16.Setup(r1cs)
+		return
+	}))
+	done()
+
+	// the beacon, public, is mixed in last: whoever ran the setup couldn't choose the keys knowing it
+	if *fBeacon != "" {
+		log.Printf("mixing the beacon %q into the keys", *fBeacon)
+		var c *ceremony.Contribution
+		vk, c, err = ceremony.ContributeBeacon(pk, vk, *fBeacon)
+		assertNoError(err)
+		beacon = beaconOf(c)
+	}
+
+	return circuit, r1cs, pk, vk, beacon
+}
+
+// beaconOf returns the manifest record of the beacon contribution c
+func beaconOf(c *ceremony.Contribution) *artifacts.Beacon {
+	return &artifacts.Beacon{Value: c.Beacon, X1: c.X1, PrevVK: c.PrevVK}
+}
+
+// saveSetup stores the R1CS and keys of the selected circuit as its current setup, records it in
+// the version history of the circuit, then exports its Solidity verifier (and Go wrapper, for the
+// default circuit)
+// beacon is the record of the beacon mixed last into the keys, nil if none.
+// A setup of an unchanged circuit replaces the keys of its latest version, unless newVersion is set
+// (migrate); a changed circuit always gets a new version. Either way the setup has its own store
+// directory, named after its ID (see artifacts.ID).
+func saveSetup(circuit frontend.Circuit, r1cs frontend.CompiledConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey, beacon *artifacts.Beacon, newVersion bool) initResult {
+	// the artifacts are stored under the ID of the compiled circuit and of the keys
+	circuitHash, err := artifacts.Hash(r1cs)
+	assertNoError(err)
+	params, err := circuits.ParamsOf(*fCircuit)
+	assertNoError(err)
+	manifest := artifacts.Manifest{
+		Circuit:      *fCircuit,
+		Params:       params,
+		Curve:        ecc.BN254.String(),
+		Backend:      backend.GROTH16.String(),
+		Features:     features.Of(circuit),
+		GnarkVersion: artifacts.GnarkVersion(),
+		CircuitHash:  circuitHash,
+
+		DeterministicSetup: *fDeterministicSetup != "",
+		Beacon:             beacon,
+	}
+	assertNoError(manifest.CheckFeatures())
+	history, err := store.History(*fCircuit)
 	assertNoError(err)
+	version := artifacts.Version{Version: 1}
+	if latest := history.Latest(); latest != nil {
+		if newVersion || latest.CircuitHash != circuitHash {
+			version = artifacts.Version{Version: latest.Version + 1, Previous: latest.Version}
+		} else {
+			// the keys change: the verifiers of the replaced keys don't verify the new proofs
+			version.Version, version.Previous = latest.Version, latest.Previous
+		}
+	}
+	manifest.Version = version.Version
+
+	// the keys are written to a staging directory first: their hashes are part of the store ID
+	assertNoError(os.MkdirAll(store.Root, 0755))
+	staging, err := ioutil.TempDir(store.Root, ".setup-")
+	assertNoError(err)
+	defer os.RemoveAll(staging)
+	assertNoError(os.Chmod(staging, 0755))
+	files.setStoreDir(staging)
 
 	// serialize R1CS, proving & verifying key
-	log.Println("serialize R1CS (circuit)", r1csPath)
-	serialize(r1cs, r1csPath)
+	done := report.track("serialize")
+	log.Println("serialize R1CS (circuit)", files.r1cs)
+	serialize(r1cs, files.r1cs)
 
-	log.Println("serialize proving key", pkPath)
-	serialize(pk, pkPath)
+	log.Println("serialize proving key", files.pk)
+	serialize(pk, files.pk)
 
-	log.Println("serialize verifying key", vkPath)
-	serialize(vk, vkPath)
+	log.Println("serialize verifying key", files.vk)
+	serialize(vk, files.vk)
+	done()
+
+	// record how the artifacts were built, and the hashes of the keys
+	log.Println("write artifacts manifest", files.manifest)
+	manifest.PKHash, err = artifacts.HashFile(files.pk)
+	assertNoError(err)
+	manifest.VKHash, err = artifacts.HashFile(files.vk)
+	assertNoError(err)
+	id := artifacts.ID(&manifest)
+	// the same keys are already stored if the setup is deterministic
+	assertNoError(os.RemoveAll(store.Dir(id)))
+	assertNoError(os.Rename(staging, store.Dir(id)))
+	files.setStoreDir(store.Dir(id))
+	assertNoError(manifest.Save(files.manifest))
+	assertNoError(store.SetCurrent(*fCircuit, id))
+	version.ID, version.CircuitHash, version.VKHash, version.CreatedAt = id, circuitHash, manifest.VKHash, time.Now().UTC()
+	assertNoError(store.SaveHistory(*fCircuit, history.Set(version)))
+	log.Printf("%s version %d, store %s", *fCircuit, version.Version, id)
 
 	// export verifying key to solidity
-	log.Println("export solidity verifier", solidityPath)
-	f, err := os.Create(solidityPath)
+	log.Println("export solidity verifier", files.solidity)
+	var solidity bytes.Buffer
+	err = ethereum.ExportSolidity(&solidity, vk)
 	assertNoError(err)
-	err = vk.ExportSolidity(f)
+	err = ioutil.WriteFile(files.solidity, solidity.Bytes(), 0644)
 	assertNoError(err)
 
-	// run abigen to generate go wrapper
-	// abigen --sol circuit/mimc_verifier.sol --pkg circuit --out circuit/wrapper.go
-	cmd := exec.Command("abigen", "--sol", solidityPath, "--pkg", "circuit", "--out", "circuit/wrapper.go")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
+	contracts, err := ethereum.CompileSolidity(solidity.String())
 	assertNoError(err)
+	verifier, ok := contracts["<stdin>:Verifier"]
+	if !ok {
+		log.Fatal("contract Verifier not found in solc output")
+	}
+	assertNoError(solc.CheckSize("Verifier", verifier))
+
+	// the go wrapper (package mimc) binds the default circuit verifier only, other circuits
+	// verifiers are deployed from their creation bytecode and called through ethereum.Verifier.
+	if *fCircuit == defaultCircuit {
+		// generate the go wrapper, as
+		// abigen --sol circuits/mimc/verifier.sol --pkg mimc --out circuits/mimc/verifier.go
+		// would, without requiring abigen
+		log.Println("generate go wrapper", wrapperPath)
+		wrapper, err := ethereum.GenerateBindings(contracts, defaultCircuit)
+		assertNoError(err)
+		err = ioutil.WriteFile(wrapperPath, []byte(wrapper), 0644)
+		assertNoError(err)
+	}
+
+	// store the ABI and creation bytecode, for deployments that don't go through the wrapper
+	// (deploy -raw) and for abigen --abi --bin
+	log.Println("export verifier ABI and creation bytecode", files.verifierABI, files.verifierBin)
+	assertNoError(solc.WriteArtifacts(strings.TrimSuffix(files.verifierBin, ".bin"), verifier))
+
+	// the digests of every artifact, signed with -signing-key
+	assertNoError(recordDigests(&manifest, files))
+	assertNoError(signManifest(&manifest))
+	assertNoError(manifest.Save(files.manifest))
+
+	return initResult{
+		Circuit:  *fCircuit,
+		Manifest: manifest,
+		Files: map[string]string{
+			"r1cs":        files.r1cs,
+			"pk":          files.pk,
+			"vk":          files.vk,
+			"solidity":    files.solidity,
+			"verifierBin": files.verifierBin,
+			"verifierABI": files.verifierABI,
+			"manifest":    files.manifest,
+		},
+		Stages: report.stages,
+	}
+}
+
+// checkSetup refuses to prove with keys that were not set up for the registered circuit name:
+// it checks the signature of the manifest and the R1CS digest, compiles the circuit and compares its
+// hash, then the key hashes, with the manifest
+func checkSetup(name string, manifest *artifacts.Manifest, cf circuitFiles) error {
+	if err := checkManifest(manifest, cf, filepath.Base(cf.r1cs)); err != nil {
+		return err
+	}
+	if manifest.CircuitHash == "" {
+		log.Printf("%s artifacts predate integrity checks, run -init to record their hashes", name)
+		return nil
+	}
+	if manifest.DeterministicSetup {
+		log.Printf("%s keys come from -deterministic-setup, for tests only", name)
+	}
+	if err := checkCircuit(name, manifest); err != nil {
+		return err
+	}
+	return manifest.CheckKeys(cf.pk, cf.vk)
 }
 
+// checkCircuit compiles the circuit registered under name and returns artifacts.ErrCircuitMismatch
+// if it changed since the setup of manifest
+func checkCircuit(name string, manifest *artifacts.Manifest) error {
+	circuit, err := circuits.Get(name)
+	if err != nil {
+		return err
+	}
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, circuit)
+	if err != nil {
+		return err
+	}
+	circuitHash, err := artifacts.Hash(r1cs)
+	if err != nil {
+		return err
+	}
+	return manifest.CheckCircuit(circuitHash)
+}
+
+// header is written before every serialized gnark object, see artifacts.Header
+var header = artifacts.Header{GnarkVersion: artifacts.GnarkVersion(), Curve: ecc.BN254, Backend: backend.GROTH16}
+
 // serialize gnark object to given file
 func serialize(gnarkObject io.WriterTo, fileName string) {
 	f, err := os.Create(fileName)
 	assertNoError(err)
 
-	_, err = gnarkObject.WriteTo(f)
+	n, err := artifacts.Write(f, header, gnarkObject)
 	assertNoError(err)
+	atomic.AddInt64(&ioWritten, n)
 }
 
 // deserialize gnark object from given file
+// fileName may be a URL (see fetch.Local), downloaded into the cache first
 func deserialize(gnarkObject io.ReaderFrom, fileName string) {
-	f, err := os.Open(fileName)
+	fileName, err := fetch.Local(mainCtx, fileName)
+	assertNoError(err)
+	f, err := artifacts.Open(fileName)
 	assertNoError(err)
 
-	_, err = gnarkObject.ReadFrom(f)
+	n, err := artifacts.Read(f, header, gnarkObject)
+	if err != nil {
+		log.Fatalf("%s: %v", fileName, err)
+	}
+	atomic.AddInt64(&ioRead, n)
+}
+
+// mainCtx is cancelled on Ctrl-C
+var mainCtx = context.Background()
+
+// assertCompleted is assertNoError for the tracked stages: if the run was interrupted, it
+// prints the stages completed so far before exiting
+func assertCompleted(err error) {
+	if errors.Is(err, context.Canceled) {
+		log.Println("interrupted")
+		report.print()
+		os.Exit(130)
+	}
 	assertNoError(err)
 }
 
@@ -0,0 +1,115 @@
+// Package metrics collects the duration and the errors of the proving phases, and exposes them
+// in the Prometheus text format, without depending on a Prometheus client.
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds of the duration histograms, in seconds: from a small
+// circuit verification to a large circuit proof
+var DefaultBuckets = []float64{0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// Phases collects per phase (compile, setup, load, prove, verify...) and per circuit metrics
+//
+//	gnark_workshop_phase_duration_seconds{phase, circuit}  histogram
+//	gnark_workshop_phase_errors_total{phase, circuit}      counter
+type Phases struct {
+	buckets []float64
+
+	lock   sync.Mutex
+	series map[labels]*series
+}
+
+type labels struct {
+	phase, circuit string
+}
+
+type series struct {
+	buckets []uint64 // cumulative counts are computed on export
+	count   uint64
+	sum     float64
+	errors  uint64
+}
+
+// NewPhases returns an empty collector with DefaultBuckets
+func NewPhases() *Phases {
+	return &Phases{buckets: DefaultBuckets, series: make(map[labels]*series)}
+}
+
+// Observe records one run of phase for circuit, which took d and failed if err is not nil
+// Failed runs count in the duration histogram too.
+func (p *Phases) Observe(phase, circuit string, d time.Duration, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	l := labels{phase, circuit}
+	s, ok := p.series[l]
+	if !ok {
+		s = &series{buckets: make([]uint64, len(p.buckets))}
+		p.series[l] = s
+	}
+	seconds := d.Seconds()
+	for i, le := range p.buckets {
+		if seconds <= le {
+			s.buckets[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += seconds
+	if err != nil {
+		s.errors++
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (p *Phases) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	keys := make([]labels, 0, len(p.series))
+	for l := range p.series {
+		keys = append(keys, l)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].phase != keys[j].phase {
+			return keys[i].phase < keys[j].phase
+		}
+		return keys[i].circuit < keys[j].circuit
+	})
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	var b strings.Builder
+	b.WriteString("# HELP gnark_workshop_phase_duration_seconds Duration of the proving phases.\n")
+	b.WriteString("# TYPE gnark_workshop_phase_duration_seconds histogram\n")
+	for _, l := range keys {
+		s := p.series[l]
+		cumulative := uint64(0)
+		for i, le := range p.buckets {
+			cumulative += s.buckets[i]
+			fmt.Fprintf(&b, "gnark_workshop_phase_duration_seconds_bucket{%s,le=%q} %d\n", l, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "gnark_workshop_phase_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", l, s.count)
+		fmt.Fprintf(&b, "gnark_workshop_phase_duration_seconds_sum{%s} %g\n", l, s.sum)
+		fmt.Fprintf(&b, "gnark_workshop_phase_duration_seconds_count{%s} %d\n", l, s.count)
+	}
+	b.WriteString("# HELP gnark_workshop_phase_errors_total Failed runs of the proving phases.\n")
+	b.WriteString("# TYPE gnark_workshop_phase_errors_total counter\n")
+	for _, l := range keys {
+		fmt.Fprintf(&b, "gnark_workshop_phase_errors_total{%s} %d\n", l, p.series[l].errors)
+	}
+	_, _ = w.Write([]byte(b.String()))
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// String formats the labels for the exposition format
+func (l labels) String() string {
+	return fmt.Sprintf(`phase="%s",circuit="%s"`, escaper.Replace(l.phase), escaper.Replace(l.circuit))
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runMigrate sets up a new version of the selected circuit, typically after a change of its
+// sources: it runs the setup, regenerates the verifier, deploys it (and upgrades the proxy of an
+// upgradeable verifier), and records the verifiers of the old and new versions in the version
+// history, so that proofs of the old version are still verified with the right key
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fTxFlags := addTxFlags(fs)
+	fSigner := addSignerFlags(fs, "(funded) deployer account, the proxy admin of an upgradeable verifier")
+	fDeployments := fs.String("deployments", deploymentsPath, "deployments file to update")
+	fNoDeploy := fs.Bool("no-deploy", false, "only set up the new version, deploy its verifier later with deploy or rotate-vk")
+	assertNoError(fs.Parse(args))
+
+	if _, err := exec.LookPath(ethereum.Solc); err != nil {
+		log.Fatal("please install solc", err)
+	}
+	history, err := store.History(*fCircuit)
+	assertNoError(err)
+	from := 0
+	if latest := history.Latest(); latest != nil {
+		from = latest.Version
+	}
+
+	circuit, r1cs, pk, vk, beacon := setupCircuit()
+	setup := saveSetup(circuit, r1cs, pk, vk, beacon, true)
+	report.print()
+	result := migrateResult{Circuit: *fCircuit, From: from, To: setup.Manifest.Version, ID: artifacts.ID(&setup.Manifest)}
+	log.Printf("%s migrated from version %d to %d", *fCircuit, result.From, result.To)
+	if *fNoDeploy {
+		emit("migrate", result)
+		return
+	}
+
+	signer, closeSigner := fSigner.signer()
+	defer closeSigner()
+
+	ctx := mainCtx
+	client := fTxFlags.dial(ctx)
+	defer client.Close()
+	chainID := client.chainID
+
+	// the verifier has no constructor arguments: initcode is the creation bytecode
+	bytecode, err := ethereum.ReadBytecode(files.verifierBin)
+	assertNoError(err)
+	_, tx, err := ethereum.DeployRaw(ctx, client.transactor(ctx, signer), client, bytecode)
+	assertNoError(err)
+	receipt := client.wait(ctx, tx)
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		log.Fatalf("deployment transaction %s reverted", tx.Hash().Hex())
+	}
+	result.Verifier = receipt.ContractAddress
+	log.Printf("version %d verifier deployed at %s", result.To, result.Verifier.Hex())
+
+	deployments, err := ethereum.ReadDeployments(*fDeployments)
+	assertNoError(err)
+	previous, deployed := deployments.Get(chainID, *fCircuit)
+	deployment := ethereum.Deployment{Verifier: result.Verifier, TxHash: tx.Hash(), BlockNumber: receipt.BlockNumber.Uint64()}
+	if deployed && previous.Implementation != (common.Address{}) {
+		// applications call the proxy: point it at the new verifier
+		tx, err = ethereum.UpgradeProxy(client.transactor(ctx, signer), client, previous.Verifier, result.Verifier)
+		assertNoError(err)
+		receipt = client.wait(ctx, tx)
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			log.Fatalf("upgradeTo transaction %s reverted (is %s the proxy admin?)", tx.Hash().Hex(), signer.Address().Hex())
+		}
+		log.Printf("proxy %s now forwards to %s (was %s)", previous.Verifier.Hex(), result.Verifier.Hex(), previous.Implementation.Hex())
+		result.Proxy = previous.Verifier
+		deployment = ethereum.Deployment{Verifier: previous.Verifier, Implementation: result.Verifier, TxHash: tx.Hash(), BlockNumber: receipt.BlockNumber.Uint64()}
+	}
+	deployments.Set(chainID, *fCircuit, deployment)
+	assertNoError(deployments.Save(*fDeployments))
+	log.Println("recorded deployment in", *fDeployments)
+
+	// record the verifier of each version: the implementation, as the proxy moves on. A deployment
+	// made before the history recorded verifiers is the one of the previous version.
+	history, err = store.History(*fCircuit)
+	assertNoError(err)
+	chain := chainID.String()
+	if old, ok := history.Get(from); ok && deployed && old.Verifiers[chain] == "" {
+		result.PreviousVerifier = previous.Verifier
+		if previous.Implementation != (common.Address{}) {
+			result.PreviousVerifier = previous.Implementation
+		}
+		setVerifier(old, chain, result.PreviousVerifier)
+	} else if ok {
+		result.PreviousVerifier = common.HexToAddress(old.Verifiers[chain])
+	}
+	current, _ := history.Get(result.To)
+	setVerifier(current, chain, result.Verifier)
+	assertNoError(store.SaveHistory(*fCircuit, history))
+
+	result.ChainID = chainID.Int64()
+	result.Transaction = newTxResult(receipt)
+	emit("migrate", result)
+}
+
+func setVerifier(v *artifacts.Version, chainID string, address common.Address) {
+	if v.Verifiers == nil {
+		v.Verifiers = make(map[string]string)
+	}
+	v.Verifiers[chainID] = address.Hex()
+}
+
+// versionOf returns the version of the selected circuit numbered version
+func versionOf(version int) *artifacts.Version {
+	history, err := store.History(*fCircuit)
+	assertNoError(err)
+	v, ok := history.Get(version)
+	if !ok {
+		log.Fatalf("%s has no version %d (see %s)", *fCircuit, version, filepath.Join(store.Root, *fCircuit+".versions.json"))
+	}
+	return v
+}
+
+// migrateResult is the JSON output of migrate
+type migrateResult struct {
+	Circuit          string         `json:"circuit"`
+	From             int            `json:"from"`
+	To               int            `json:"to"`
+	ID               string         `json:"id"`
+	ChainID          int64          `json:"chainId,omitempty"`
+	Verifier         common.Address `json:"verifier"`
+	PreviousVerifier common.Address `json:"previousVerifier"`
+	Proxy            common.Address `json:"proxy"`
+	Transaction      *txResult      `json:"transaction,omitempty"`
+}
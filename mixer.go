@@ -0,0 +1,97 @@
+package main
+
+import (
+	"log"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gbotrel/gnark-workshop/mixer"
+)
+
+// runMixer runs the deposit → prove → withdraw cycle of the mixer on the simulated backend
+// 1. two notes are deposited, their commitments are inserted in the on-chain tree
+// 2. the tree is rebuilt from the Deposit events and a withdrawal proof is created for one note
+// 3. the withdrawal pays a fresh recipient; withdrawing the same note twice is refused
+func runMixer() {
+	var c mixer.Circuit
+	log.Println("compiling withdraw circuit")
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &c)
+	assertNoError(err)
+
+	log.Println("running groth16.Setup")
+	pk, vk, err := groth16.Setup(r1cs)
+	assertNoError(err)
+
+	auth, chain, err := newBackend()
+	assertNoError(err)
+	ctx := mainCtx
+
+	log.Println("deploying withdraw verifier and mixer contracts on chain")
+	m, err := mixer.Deploy(auth, chain, vk, big.NewInt(1000000))
+	assertNoError(err)
+	chain.Commit()
+
+	// deposit
+	var notes []*mixer.Note
+	for i := 0; i < 2; i++ {
+		note, err := mixer.NewNote(nil)
+		assertNoError(err)
+		tx, err := m.Deposit(auth, note)
+		assertNoError(err)
+		chain.Commit()
+		receipt, err := chain.TransactionReceipt(ctx, tx.Hash())
+		assertNoError(err)
+		if receipt.Status != 1 {
+			log.Fatal("deposit reverted, but shouldn't have")
+		}
+		log.Printf("deposited note %d (gas used: %d)", i, receipt.GasUsed)
+		notes = append(notes, note)
+	}
+
+	// prove, from the on-chain tree
+	commitments, err := m.Commitments(nil)
+	assertNoError(err)
+	tree := mixer.Tree{Leaves: commitments}
+	key, err := crypto.GenerateKey()
+	assertNoError(err)
+	recipient := crypto.PubkeyToAddress(key.PublicKey)
+
+	log.Println("creating withdrawal proof")
+	witness, err := tree.Witness(notes[1], recipient)
+	assertNoError(err)
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	assertNoError(err)
+
+	// withdraw, then try again; the gas limit is set as estimating a reverting call fails
+	result := mixerResult{}
+	opts := *auth
+	opts.GasLimit = 2000000
+	for i, expected := range []uint64{1, 0} {
+		tx, err := m.Withdraw(&opts, proof, tree.Root(), notes[1].NullifierHash(), recipient)
+		assertNoError(err)
+		chain.Commit()
+		receipt, err := chain.TransactionReceipt(ctx, tx.Hash())
+		assertNoError(err)
+		if receipt.Status != expected {
+			log.Fatalf("withdrawal %d: status %d, expected %d", i, receipt.Status, expected)
+		}
+		result.Withdrawals = append(result.Withdrawals, newTxResult(receipt))
+	}
+
+	balance, err := chain.BalanceAt(ctx, recipient, nil)
+	assertNoError(err)
+	if balance.Cmp(m.Denomination) != 0 {
+		log.Fatal("recipient wasn't paid the deposit")
+	}
+	log.Printf("withdrew %s wei to %s, double spend refused", balance, recipient.Hex())
+	emit("mixer", result)
+}
+
+// mixerResult is the JSON output of -mixer; the second withdrawal is the refused double spend
+type mixerResult struct {
+	Withdrawals []*txResult `json:"withdrawals"`
+}
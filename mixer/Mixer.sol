@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+pragma solidity ^0.8.0;
+
+// IWithdrawVerifier is the gnark exported verifier of the mixer.Circuit circuit
+interface IWithdrawVerifier {
+    function verifyProof(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c,
+        uint256[3] memory input
+    ) external view returns (bool r);
+}
+
+// Mixer takes fixed-size deposits, each identified by a commitment inserted in
+// an incremental Merkle tree, and pays them out to any recipient on a proof of
+// knowledge of a committed note whose nullifier wasn't spent yet. Nothing links
+// a withdrawal to its deposit but the anonymity set.
+contract Mixer {
+    uint256 public constant DEPTH = 8;
+
+    IWithdrawVerifier public immutable verifier;
+    uint256 public immutable denomination;
+
+    // incremental Merkle tree: left siblings of the next insertion path and empty subtrees
+    uint256[DEPTH] private filledSubtrees;
+    uint256[DEPTH] private zeros;
+    uint256 public nextIndex;
+    uint256 public root;
+    mapping(uint256 => bool) public knownRoots;
+
+    mapping(uint256 => bool) public nullifierSpent;
+
+    event Deposit(uint256 indexed commitment, uint256 leafIndex);
+    event Withdrawal(address recipient, uint256 nullifierHash);
+
+    constructor(IWithdrawVerifier _verifier, uint256 _denomination) {
+        verifier = _verifier;
+        denomination = _denomination;
+
+        uint256 zero = 0;
+        for (uint256 i = 0; i < DEPTH; i++) {
+            zeros[i] = zero;
+            filledSubtrees[i] = zero;
+            zero = Poseidon.hash2(zero, zero);
+        }
+        root = zero;
+        knownRoots[root] = true;
+    }
+
+    function deposit(uint256 commitment) external payable {
+        require(msg.value == denomination, "mixer-invalid-amount");
+        require(commitment < Poseidon.Q, "mixer-commitment-gte-q");
+        require(nextIndex < 2**DEPTH, "mixer-full");
+
+        uint256 index = nextIndex;
+        uint256 node = commitment;
+        for (uint256 i = 0; i < DEPTH; i++) {
+            if (index % 2 == 0) {
+                filledSubtrees[i] = node;
+                node = Poseidon.hash2(node, zeros[i]);
+            } else {
+                node = Poseidon.hash2(filledSubtrees[i], node);
+            }
+            index /= 2;
+        }
+        root = node;
+        knownRoots[node] = true;
+
+        emit Deposit(commitment, nextIndex);
+        nextIndex++;
+    }
+
+    function withdraw(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c,
+        uint256 _root,
+        uint256 nullifierHash,
+        address payable recipient
+    ) external {
+        require(!nullifierSpent[nullifierHash], "mixer-already-spent");
+        require(knownRoots[_root], "mixer-unknown-root");
+        require(
+            verifier.verifyProof(a, b, c, [_root, nullifierHash, uint256(uint160(address(recipient)))]),
+            "mixer-invalid-proof"
+        );
+
+        nullifierSpent[nullifierHash] = true;
+        recipient.transfer(denomination);
+        emit Withdrawal(recipient, nullifierHash);
+    }
+}
@@ -0,0 +1,60 @@
+package mixer
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/nullifier"
+	"github.com/gbotrel/gnark-workshop/poseidon"
+)
+
+// Depth is the depth of the deposits tree, as DEPTH in Mixer.sol
+const Depth = 8
+
+// Circuit defines a withdrawal
+// mimc(nullifier, secret) is a leaf of the public root, and nullifierHash = mimc(nullifier)
+//
+// The recipient is a public input so that a proof can't be replayed for another recipient
+// by someone watching the mempool.
+type Circuit struct {
+	Root          frontend.Variable `gnark:",public"`
+	NullifierHash frontend.Variable `gnark:",public"`
+	Recipient     frontend.Variable `gnark:",public"`
+
+	Nullifier frontend.Variable
+	Secret    frontend.Variable
+	Index     frontend.Variable
+	Path      [Depth]frontend.Variable
+}
+
+// Define declares the circuit's constraints
+func (c *Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	// the tree is hashed with the workshop poseidon, defined over BN254 only
+	if curveID != ecc.BN254 {
+		return errUnsupportedCurve
+	}
+
+	notes, err := nullifier.NewGadget(curveID, cs, nullifier.MiMC)
+	if err != nil {
+		return err
+	}
+
+	// nullifierHash = mimc(nullifier)
+	cs.AssertIsEqual(notes.NullifierHash(c.Nullifier), c.NullifierHash)
+
+	// commitment = mimc(nullifier, secret)
+	node := notes.Commitment(c.Nullifier, c.Secret)
+
+	// commitment is a leaf of root, at index
+	directions := cs.ToBinary(c.Index, Depth)
+	for i := range c.Path {
+		left := cs.Select(directions[i], c.Path[i], node)
+		right := cs.Select(directions[i], node, c.Path[i])
+		node = poseidon.HashInCircuit(cs, left, right)
+	}
+	cs.AssertIsEqual(node, c.Root)
+
+	// bind the recipient to the proof
+	cs.Mul(c.Recipient, c.Recipient)
+
+	return nil
+}
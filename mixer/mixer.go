@@ -0,0 +1,107 @@
+// Package mixer is a Tornado-style private deposit / withdraw example: deposits are MiMC
+// commitments inserted in an on-chain incremental Merkle tree, withdrawals prove the knowledge
+// of the note behind one of them, and reveal its nullifier hash to prevent double spends.
+//
+// The tree hashes with the workshop Poseidon (see package poseidon): its parameters are known,
+// so the contract can compute it, while gnark's MiMC constants are not exported.
+package mixer
+
+import (
+	"bytes"
+	_ "embed"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/poseidon"
+)
+
+//go:embed Mixer.sol
+var mixerSol string
+
+// Mixer is a handle on a deployed Mixer contract
+type Mixer struct {
+	Address      common.Address
+	Verifier     common.Address
+	Denomination *big.Int
+	contract     *bind.BoundContract
+}
+
+// Deploy exports vk (a mixer.Circuit verifying key) to solidity, deploys it, then deploys a
+// Mixer taking deposits of denomination wei.
+// Requires solc in PATH; caller is responsible for committing / mining the transactions.
+func Deploy(auth *bind.TransactOpts, backend bind.ContractBackend, vk groth16.VerifyingKey, denomination *big.Int) (*Mixer, error) {
+	var buf bytes.Buffer
+	if err := vk.ExportSolidity(&buf); err != nil {
+		return nil, err
+	}
+	verifierAddress, _, err := ethereum.DeployContract(auth, backend, buf.String(), "Verifier")
+	if err != nil {
+		return nil, err
+	}
+
+	address, contract, err := ethereum.DeployContract(auth, backend, mixerSol+poseidon.Solidity(), "Mixer", verifierAddress, denomination)
+	if err != nil {
+		return nil, err
+	}
+	return &Mixer{Address: address, Verifier: verifierAddress, Denomination: denomination, contract: contract}, nil
+}
+
+// Deposit sends denomination wei with the commitment of note
+func (m *Mixer) Deposit(auth *bind.TransactOpts, note *Note) (*types.Transaction, error) {
+	opts := *auth
+	opts.Value = m.Denomination
+	return m.contract.Transact(&opts, "deposit", toBig(note.Commitment()))
+}
+
+// Commitments indexes the Deposit events, in insertion order: the leaves of the deposits tree
+func (m *Mixer) Commitments(opts *bind.FilterOpts) ([]fr.Element, error) {
+	logs, sub, err := m.contract.FilterLogs(opts, "Deposit")
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+
+	var commitments []fr.Element
+	add := func(log types.Log) {
+		if len(log.Topics) != 2 {
+			return
+		}
+		var c fr.Element
+		c.SetBytes(log.Topics[1].Bytes())
+		commitments = append(commitments, c)
+	}
+	for {
+		select {
+		case log := <-logs:
+			add(log)
+		case err := <-sub.Err():
+			if err != nil {
+				return nil, err
+			}
+			// the subscription ends once every log is buffered, logs is never closed
+			for {
+				select {
+				case log := <-logs:
+					add(log)
+				default:
+					return commitments, nil
+				}
+			}
+		}
+	}
+}
+
+// Withdraw pays the deposit proven by proof out to recipient
+// Anyone can send it (e.g. a relayer): the proof is bound to the recipient.
+func (m *Mixer) Withdraw(auth *bind.TransactOpts, proof groth16.Proof, root, nullifierHash fr.Element, recipient common.Address) (*types.Transaction, error) {
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, nil)
+	if err != nil {
+		return nil, err
+	}
+	return m.contract.Transact(auth, "withdraw", solidityInputs.A, solidityInputs.B, solidityInputs.C, toBig(root), toBig(nullifierHash), recipient)
+}
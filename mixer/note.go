@@ -0,0 +1,142 @@
+package mixer
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gbotrel/gnark-workshop/nullifier"
+	"github.com/gbotrel/gnark-workshop/poseidon"
+)
+
+var (
+	errUnsupportedCurve = errors.New("mixer: the withdraw circuit is defined over BN254 only")
+
+	// ErrUnknownCommitment is returned when proving the withdrawal of a note that isn't in the tree
+	ErrUnknownCommitment = errors.New("commitment not found in the deposits tree")
+)
+
+// Note is what a depositor keeps to withdraw later: losing it loses the deposit,
+// leaking it lets anyone withdraw it
+type Note struct {
+	Nullifier fr.Element
+	Secret    fr.Element
+}
+
+// NewNote returns a note with random nullifier and secret, read from r (crypto/rand if nil)
+func NewNote(r io.Reader) (*Note, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	var n Note
+	for _, e := range []*fr.Element{&n.Nullifier, &n.Secret} {
+		var err error
+		if *e, err = nullifier.NewSecret(r); err != nil {
+			return nil, err
+		}
+	}
+	return &n, nil
+}
+
+// Commitment returns mimc(nullifier, secret), the value deposited on-chain
+func (n *Note) Commitment() fr.Element {
+	return nullifier.Commitment(nullifier.MiMC, n.Nullifier, n.Secret)
+}
+
+// NullifierHash returns mimc(nullifier), revealed on withdrawal to prevent double spends
+func (n *Note) NullifierHash() fr.Element {
+	return nullifier.NullifierHash(nullifier.MiMC, n.Nullifier)
+}
+
+// Tree mirrors the incremental Merkle tree of the Mixer contract
+type Tree struct {
+	Leaves []fr.Element
+}
+
+// Root returns the root of the tree, as Mixer.root
+func (t *Tree) Root() fr.Element {
+	level := t.Leaves
+	var zero fr.Element
+	for d := 0; d < Depth; d++ {
+		level, zero = nextLevel(level, zero)
+	}
+	if len(level) == 0 {
+		return zero
+	}
+	return level[0]
+}
+
+// Path returns the siblings of leaf index, from the bottom up
+func (t *Tree) Path(index int) ([Depth]fr.Element, error) {
+	var path [Depth]fr.Element
+	if index < 0 || index >= len(t.Leaves) {
+		return path, fmt.Errorf("leaf index %d out of range", index)
+	}
+	level := t.Leaves
+	var zero fr.Element
+	for d := 0; d < Depth; d++ {
+		if sibling := index ^ 1; sibling < len(level) {
+			path[d] = level[sibling]
+		} else {
+			path[d] = zero
+		}
+		level, zero = nextLevel(level, zero)
+		index >>= 1
+	}
+	return path, nil
+}
+
+// nextLevel hashes the nodes of a level by pairs, missing nodes being the empty subtree zero
+// It returns the next level, and its empty subtree.
+func nextLevel(level []fr.Element, zero fr.Element) ([]fr.Element, fr.Element) {
+	next := make([]fr.Element, (len(level)+1)/2)
+	for i := range next {
+		right := zero
+		if 2*i+1 < len(level) {
+			right = level[2*i+1]
+		}
+		next[i] = poseidon.Hash(level[2*i], right)
+	}
+	return next, poseidon.Hash(zero, zero)
+}
+
+// Witness returns the withdrawal assignment of note to recipient
+func (t *Tree) Witness(note *Note, recipient common.Address) (*Circuit, error) {
+	commitment := note.Commitment()
+	index := -1
+	for i := range t.Leaves {
+		if t.Leaves[i].Equal(&commitment) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, ErrUnknownCommitment
+	}
+	path, err := t.Path(index)
+	if err != nil {
+		return nil, err
+	}
+
+	var witness Circuit
+	root := t.Root()
+	nullifierHash := note.NullifierHash()
+	witness.Root.Assign(toBig(root))
+	witness.NullifierHash.Assign(toBig(nullifierHash))
+	witness.Recipient.Assign(recipient.Hash().Big())
+	witness.Nullifier.Assign(toBig(note.Nullifier))
+	witness.Secret.Assign(toBig(note.Secret))
+	witness.Index.Assign(index)
+	for i := range path {
+		witness.Path[i].Assign(toBig(path[i]))
+	}
+	return &witness, nil
+}
+
+func toBig(e fr.Element) *big.Int {
+	return e.ToBigIntRegular(new(big.Int))
+}
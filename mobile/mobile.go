@@ -0,0 +1,108 @@
+// Package mobile is the API of the MiMC pre-image circuit for iOS and Android apps, built with
+// gomobile bind:
+//
+//	gomobile bind -target=android -o workshop.aar ./mobile
+//	gomobile bind -target=ios -o Workshop.xcframework ./mobile
+//
+// It only uses types gomobile can bind: byte slices in and out, no file paths. Apps ship the R1CS
+// and proving key as resources (as written by the workshop binary, see artifacts.Write) and load
+// them once with NewProver; proving then runs on-device.
+package mobile
+
+import (
+	"bytes"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// header is the expected header of the artifacts written by the workshop binary
+var header = artifacts.Header{GnarkVersion: artifacts.GnarkVersion(), Curve: ecc.BN254, Backend: backend.GROTH16}
+
+// Prover holds a loaded R1CS and proving key; it is safe for concurrent use
+type Prover struct {
+	r1cs frontend.CompiledConstraintSystem
+	pk   groth16.ProvingKey
+}
+
+// Proof is the result of Prover.Prove
+type Proof struct {
+	// Proof is the gnark proof, as read by Verify
+	Proof []byte
+	// Hash is the public input, mimc(secret)
+	Hash []byte
+	// Calldata is the verifyProof calldata, ready to be sent to the Solidity verifier
+	Calldata []byte
+}
+
+// NewProver loads the serialized R1CS and proving key of the circuit
+func NewProver(r1cs, pk []byte) (*Prover, error) {
+	p := &Prover{r1cs: groth16.NewCS(ecc.BN254), pk: groth16.NewProvingKey(ecc.BN254)}
+	if _, err := artifacts.Read(bytes.NewReader(r1cs), header, p.r1cs); err != nil {
+		return nil, err
+	}
+	if _, err := artifacts.Read(bytes.NewReader(pk), header, p.pk); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Prove creates a proof of knowledge of the pre-image of mimc(secret)
+func (p *Prover) Prove(secret []byte) (*Proof, error) {
+	witness, err := circuit.NewWitness(secret)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := circuit.Hash(secret)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := groth16.Prove(p.r1cs, p.pk, witness)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	publicWitness, err := ethereum.PublicWitness(witness)
+	if err != nil {
+		return nil, err
+	}
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := solidityInputs.Calldata()
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{Proof: buf.Bytes(), Hash: hash, Calldata: calldata}, nil
+}
+
+// Verify checks proof against the serialized verifying key vk and the public input hash
+// An invalid proof returns false, errors are for inputs that can't be decoded.
+func Verify(vk, proof, hash []byte) (bool, error) {
+	v := groth16.NewVerifyingKey(ecc.BN254)
+	if _, err := artifacts.Read(bytes.NewReader(vk), header, v); err != nil {
+		return false, err
+	}
+	p := groth16.NewProof(ecc.BN254)
+	if _, err := artifacts.Read(bytes.NewReader(proof), header, p); err != nil {
+		return false, err
+	}
+	var publicWitness circuit.Circuit
+	publicWitness.Hash.Assign(hash)
+	return groth16.Verify(p, v, &publicWitness) == nil, nil
+}
+
+// Hash returns mimc(data), as computed by the circuit, e.g. to display the public input before proving
+func Hash(data []byte) ([]byte, error) {
+	return circuit.Hash(data)
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	gethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runNegative breaks a valid proof of the selected circuit in several ways, and checks that both
+// the Go verifier and the deployed Solidity verifier reject each of them
+// It runs on the artifacts of -init, on the simulated backend or the -node dev node.
+func runNegative(args []string) {
+	fs := flag.NewFlagSet("negative", flag.ExitOnError)
+	fDir := fs.String("dir", "", "if set, directory where the rejected proofs and public witnesses are written, for verify -expect-fail")
+	assertNoError(fs.Parse(args))
+
+	r1cs := groth16.NewCS(ecc.BN254)
+	pk := groth16.NewProvingKey(ecc.BN254)
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(r1cs, files.r1cs)
+	deserialize(pk, files.pk)
+	deserialize(vk, files.vk)
+
+	witness, err := circuits.Example(*fCircuit)
+	assertNoError(err)
+	log.Println("creating a valid proof")
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	assertNoError(err)
+	publicWitness, err := ethereum.PublicWitness(witness)
+	assertNoError(err)
+
+	address, chain, err := deploySolidity()
+	assertNoError(err)
+
+	// the reference: the valid proof is accepted by both verifiers
+	if err := verifyPublic(proof, vk, publicWitness); err != nil {
+		log.Fatal("the valid proof is rejected by the Go verifier: ", err)
+	}
+	inputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	assertNoError(err)
+	calldata, err := inputs.Calldata()
+	assertNoError(err)
+	if rejected, how := callRaw(mainCtx, chain, address, calldata); rejected {
+		log.Fatal("the valid proof is rejected by the Solidity verifier: ", how)
+	}
+
+	log.Println("breaking it, with a second setup for the mismatched keys case")
+	broken, err := breakProof(r1cs, vk, witness, proof, publicWitness)
+	assertNoError(err)
+	var cases []negativeCase
+	for _, b := range broken {
+		c := negativeCase{Name: b.name, Description: b.description, GoRejected: b.goErr != nil}
+		if b.goErr != nil {
+			c.Go = b.goErr.Error()
+		}
+		c.ChainRejected, c.Chain = callRaw(mainCtx, chain, address, b.calldata)
+		cases = append(cases, c)
+
+		if *fDir != "" && b.proof != nil {
+			assertNoError(os.MkdirAll(*fDir, 0755))
+			serialize(b.proof, filepath.Join(*fDir, b.name+".proof"))
+			assertNoError(ioutil.WriteFile(filepath.Join(*fDir, b.name+".public"), ethereum.EncodePublicWitness(b.publicWitness), 0644))
+		}
+	}
+
+	failed := false
+	for _, c := range cases {
+		failed = failed || !c.GoRejected || !c.ChainRejected
+	}
+	if !jsonOutput() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "case\tGo verifier\tSolidity verifier\t")
+		for _, c := range cases {
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", c.Name, verdict(c.GoRejected), verdict(c.ChainRejected)+" ("+c.Chain+")")
+		}
+		assertNoError(w.Flush())
+		if *fDir != "" {
+			fmt.Printf("\ncheck them again with: go run . verify -expect-fail -proof %s -public %s\n",
+				filepath.Join(*fDir, "tampered-proof.proof"), filepath.Join(*fDir, "tampered-proof.public"))
+		}
+	}
+	emit("negative", cases)
+	if failed {
+		log.Fatal("a broken proof was accepted")
+	}
+}
+
+// negativeCase is a broken proof, and how the verifiers treated it; part of the JSON output of negative
+type negativeCase struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	GoRejected    bool   `json:"goRejected"`
+	Go            string `json:"go,omitempty"`
+	ChainRejected bool   `json:"chainRejected"`
+	Chain         string `json:"chain"`
+}
+
+func verdict(rejected bool) string {
+	if rejected {
+		return "rejected"
+	}
+	return "ACCEPTED"
+}
+
+// brokenProof is a valid proof broken in one way, with the Go verifier's verdict and the calldata
+// the Solidity verifier is called with
+type brokenProof struct {
+	name, description string
+	goErr             error
+	calldata          []byte
+
+	// the proof and public witness to save, for verify -expect-fail; nil if they don't decode
+	proof         groth16.Proof
+	publicWitness []fr.Element
+}
+
+// breakProof breaks proof, a valid proof of witness, in several ways and verifies each broken
+// proof with the Go verifier; the mismatched keys case runs a second setup of r1cs
+func breakProof(r1cs frontend.CompiledConstraintSystem, vk groth16.VerifyingKey, witness frontend.Circuit, proof groth16.Proof, publicWitness []fr.Element) ([]brokenProof, error) {
+	var broken []brokenProof
+	add := func(b brokenProof, inputs *ethereum.ProofForSolidity) error {
+		if inputs != nil {
+			calldata, err := inputs.Calldata()
+			if err != nil {
+				return err
+			}
+			b.calldata = calldata
+		}
+		broken = append(broken, b)
+		return nil
+	}
+
+	// tampered proof: Ar and Krs swapped, both still valid curve points
+	raw, err := rawProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	swapped := append(append(append([]byte(nil), raw[192:256]...), raw[64:192]...), raw[0:64]...)
+	tampered, err := proofFromRaw(swapped)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := ethereum.ProofToSolidityInputs(tampered, publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	err = add(brokenProof{name: "tampered-proof", description: "A and C of the proof swapped",
+		goErr: verifyPublic(tampered, vk, publicWitness), proof: tampered, publicWitness: publicWitness}, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// point off the curve: Go refuses to decode it, the pairing precompile reverts
+	offCurve := append([]byte(nil), raw...)
+	offCurve[63] ^= 1
+	_, goErr := proofFromRaw(offCurve)
+	if inputs, err = ethereum.ProofToSolidityInputs(proof, publicWitness); err != nil {
+		return nil, err
+	}
+	inputs.A[1] = new(big.Int).Xor(inputs.A[1], big.NewInt(1))
+	if err := add(brokenProof{name: "point-off-curve", description: "A of the proof moved off the curve", goErr: goErr}, inputs); err != nil {
+		return nil, err
+	}
+
+	if len(publicWitness) != 0 {
+		// wrong public input
+		wrong := append([]fr.Element(nil), publicWitness...)
+		wrong[0].Add(&wrong[0], new(fr.Element).SetOne())
+		if inputs, err = ethereum.ProofToSolidityInputs(proof, wrong); err != nil {
+			return nil, err
+		}
+		err = add(brokenProof{name: "wrong-public-input", description: "first public input incremented",
+			goErr: verifyPublic(proof, vk, wrong), proof: proof, publicWitness: wrong}, inputs)
+		if err != nil {
+			return nil, err
+		}
+
+		// truncated input: the last word of the calldata, or the last public input, is missing
+		if inputs, err = ethereum.ProofToSolidityInputs(proof, publicWitness); err != nil {
+			return nil, err
+		}
+		calldata, err := inputs.Calldata()
+		if err != nil {
+			return nil, err
+		}
+		broken = append(broken, brokenProof{name: "truncated-input", description: "last calldata word and public input dropped",
+			goErr: verifyPublic(proof, vk, publicWitness[:len(publicWitness)-1]), calldata: calldata[:len(calldata)-32]})
+	}
+
+	// mismatched keys: a proof from another setup of the same circuit
+	otherPK, otherVK, err := groth16.Setup(r1cs)
+	if err != nil {
+		return nil, err
+	}
+	otherProof, err := groth16.Prove(r1cs, otherPK, witness)
+	if err != nil {
+		return nil, err
+	}
+	if inputs, err = ethereum.ProofToSolidityInputs(otherProof, publicWitness); err != nil {
+		return nil, err
+	}
+	err = add(brokenProof{name: "mismatched-keys", description: "proof and verifying key from different setups",
+		goErr: verifyPublic(proof, otherVK, publicWitness), proof: otherProof, publicWitness: publicWitness}, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return broken, nil
+}
+
+// verifyPublic verifies proof with the Go verifier
+func verifyPublic(proof groth16.Proof, vk groth16.VerifyingKey, publicWitness []fr.Element) error {
+	return groth16.ReadAndVerify(proof, vk, bytes.NewReader(ethereum.EncodePublicWitness(publicWitness)))
+}
+
+// callRaw calls the verifier with calldata as is; a revert, or a result other than true, is a rejection
+func callRaw(ctx context.Context, backend bind.ContractCaller, address common.Address, calldata []byte) (rejected bool, how string) {
+	out, err := backend.CallContract(ctx, gethereum.CallMsg{To: &address, Data: calldata}, nil)
+	if err != nil {
+		return true, "reverted"
+	}
+	if len(out) != 32 {
+		return true, fmt.Sprintf("returned %d bytes", len(out))
+	}
+	valid := new(big.Int).SetBytes(out).Sign() != 0
+	return !valid, fmt.Sprintf("returned %t", valid)
+}
+
+// rawProof returns the uncompressed encoding of proof: Ar (64 bytes), Bs (128 bytes), Krs (64 bytes)
+func rawProof(proof groth16.Proof) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := proof.WriteRawTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// proofFromRaw decodes a proof, checking that its points are on the curve and in the subgroup
+func proofFromRaw(raw []byte) (groth16.Proof, error) {
+	proof := groth16.NewProof(ecc.BN254)
+	if _, err := proof.ReadFrom(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/testchain"
+)
+
+// negativeTests are the cases of breakProof, with the error of the Go verifier and the result of
+// the Solidity verifier expected for each
+var negativeTests = []struct {
+	name, goErr, chain string
+}{
+	{"tampered-proof", "pairing doesn't match", "returned false"},
+	{"point-off-curve", "invalid point", "reverted"},
+	{"wrong-public-input", "pairing doesn't match", "returned false"},
+	{"truncated-input", "invalid witness size", "reverted"},
+	{"mismatched-keys", "pairing doesn't match", "returned false"},
+}
+
+// negativeSetup returns the verifying key, a valid proof and the broken proofs of the mimc circuit
+func negativeSetup(t *testing.T) (groth16.VerifyingKey, groth16.Proof, []fr.Element, []brokenProof) {
+	t.Helper()
+	c, err := circuits.Get("mimc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness, err := circuits.Example("mimc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicWitness, err := ethereum.PublicWitness(witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken, err := breakProof(r1cs, vk, witness, proof, publicWitness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(broken) != len(negativeTests) {
+		t.Fatalf("got %d broken proofs, expected %d", len(broken), len(negativeTests))
+	}
+	return vk, proof, publicWitness, broken
+}
+
+func TestNegative(t *testing.T) {
+	vk, proof, publicWitness, broken := negativeSetup(t)
+
+	t.Run("go", func(t *testing.T) {
+		if err := verifyPublic(proof, vk, publicWitness); err != nil {
+			t.Fatal("the valid proof is rejected:", err)
+		}
+		for i, tc := range negativeTests {
+			b := broken[i]
+			if b.name != tc.name {
+				t.Fatalf("case %d is %s, expected %s", i, b.name, tc.name)
+			}
+			if b.goErr == nil {
+				t.Errorf("%s: accepted", tc.name)
+			} else if !strings.Contains(b.goErr.Error(), tc.goErr) {
+				t.Errorf("%s: rejected with %q, expected %q", tc.name, b.goErr, tc.goErr)
+			}
+		}
+	})
+
+	t.Run("solidity", func(t *testing.T) {
+		if _, err := exec.LookPath(ethereum.Solc); err != nil {
+			t.Skip("requires solc:", err)
+		}
+		var solidity bytes.Buffer
+		if err := ethereum.ExportSolidity(&solidity, vk); err != nil {
+			t.Fatal(err)
+		}
+		contracts, err := ethereum.CompileSolidity(solidity.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, bytecode, err := ethereum.Artifact(contracts, "Verifier")
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain, err := testchain.New(testchain.Genesis{Accounts: []string{"deployer"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer chain.Close()
+		address, err := chain.DeployRaw("deployer", bytecode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		inputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+		if err != nil {
+			t.Fatal(err)
+		}
+		calldata, err := inputs.Calldata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rejected, how := callRaw(context.Background(), chain, address, calldata); rejected {
+			t.Fatal("the valid proof is rejected:", how)
+		}
+		for i, tc := range negativeTests {
+			rejected, how := callRaw(context.Background(), chain, address, broken[i].calldata)
+			if !rejected || how != tc.chain {
+				t.Errorf("%s: %s, expected %s", tc.name, how, tc.chain)
+			}
+		}
+	})
+}
@@ -0,0 +1,9 @@
+//go:build !embedbundle
+// +build !embedbundle
+
+package main
+
+import "io/fs"
+
+// embedded is nil without the embedbundle tag: artifacts are read from the files written by -init
+var embedded fs.FS
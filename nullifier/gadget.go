@@ -0,0 +1,61 @@
+package nullifier
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/poseidon"
+)
+
+// errUnsupportedCurve is returned by NewGadget for Poseidon outside of BN254
+var errUnsupportedCurve = errors.New("nullifier: poseidon is defined over BN254 only")
+
+// Gadget computes the values of the scheme in a circuit, as the host functions do
+type Gadget struct {
+	cs      *frontend.ConstraintSystem
+	curveID ecc.ID
+	hash    Hash
+}
+
+// NewGadget returns the Gadget of h for the circuit being defined with cs
+func NewGadget(curveID ecc.ID, cs *frontend.ConstraintSystem, h Hash) (*Gadget, error) {
+	switch {
+	case h != MiMC && h != Poseidon:
+		return nil, errUnknownHash
+	case h == Poseidon && curveID != ecc.BN254:
+		return nil, errUnsupportedCurve
+	}
+	return &Gadget{cs: cs, curveID: curveID, hash: h}, nil
+}
+
+// Commitment returns H(nullifier, secret)
+func (g *Gadget) Commitment(nullifier, secret frontend.Variable) frontend.Variable {
+	return g.sum(nullifier, secret)
+}
+
+// NullifierHash returns H(nullifier)
+func (g *Gadget) NullifierHash(nullifier frontend.Variable) frontend.Variable {
+	return g.sum(nullifier)
+}
+
+// Scoped returns H(nullifier, scope)
+func (g *Gadget) Scoped(nullifier, scope frontend.Variable) frontend.Variable {
+	return g.sum(nullifier, scope)
+}
+
+// sum returns H(inputs...), with a fresh hash function
+func (g *Gadget) sum(inputs ...frontend.Variable) frontend.Variable {
+	if g.hash == Poseidon {
+		return poseidon.HashInCircuit(g.cs, inputs...)
+	}
+	// NewMiMC only fails for curves gnark doesn't support, which can't define circuits
+	hFunc, err := mimc.NewMiMC(circuit.Seed, g.curveID, g.cs)
+	if err != nil {
+		panic(err)
+	}
+	hFunc.Write(inputs...)
+	return hFunc.Sum()
+}
@@ -0,0 +1,93 @@
+// Package nullifier computes the commitments and nullifier hashes of the workshop applications
+// (mixer, voting) on the host, and in circuits with Gadget, from a single definition of each:
+//
+//	commitment    = H(nullifier, secret)    published when depositing / registering
+//	nullifierHash = H(nullifier)            revealed once, to prevent double spends
+//	scoped        = H(nullifier, scope)     revealed once per scope, e.g. one vote per election
+//
+// H is MiMC (seeded with circuit.Seed) or the workshop Poseidon, hashing one field element per input.
+// A nullifier must stay secret until it is spent: whoever knows it and the secret can spend the note.
+package nullifier
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/poseidon"
+)
+
+// Hash selects the hash function of the scheme
+type Hash int
+
+const (
+	// MiMC is the MiMC hash of the workshop circuits
+	MiMC Hash = iota
+	// Poseidon is the workshop Poseidon (see package poseidon), cheaper in circuits, BN254 only
+	Poseidon
+)
+
+func (h Hash) String() string {
+	switch h {
+	case MiMC:
+		return "mimc"
+	case Poseidon:
+		return "poseidon"
+	}
+	return fmt.Sprintf("hash(%d)", int(h))
+}
+
+// errUnknownHash is returned for Hash values other than MiMC and Poseidon
+var errUnknownHash = errors.New("nullifier: unknown hash function")
+
+// NewSecret returns a random field element for a nullifier or a secret, read from r (crypto/rand if nil)
+func NewSecret(r io.Reader) (fr.Element, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	var e fr.Element
+	// 31 bytes are always less than the modulus
+	var b [fr.Bytes - 1]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return e, err
+	}
+	e.SetBytes(b[:])
+	return e, nil
+}
+
+// Commitment returns H(nullifier, secret)
+func Commitment(h Hash, nullifier, secret fr.Element) fr.Element {
+	return hash(h, nullifier, secret)
+}
+
+// NullifierHash returns H(nullifier)
+func NullifierHash(h Hash, nullifier fr.Element) fr.Element {
+	return hash(h, nullifier)
+}
+
+// Scoped returns H(nullifier, scope), the nullifier hash of nullifier for scope
+func Scoped(h Hash, nullifier, scope fr.Element) fr.Element {
+	return hash(h, nullifier, scope)
+}
+
+// hash returns H(elements...), one block per element, as Gadget hashes variables
+func hash(h Hash, elements ...fr.Element) fr.Element {
+	switch h {
+	case MiMC:
+		hFunc := mimc.NewMiMC(circuit.Seed)
+		for _, e := range elements {
+			b := e.Bytes()
+			hFunc.Write(b[:])
+		}
+		var res fr.Element
+		res.SetBytes(hFunc.Sum(nil))
+		return res
+	case Poseidon:
+		return poseidon.Hash(elements...)
+	}
+	panic(errUnknownHash)
+}
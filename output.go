@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// outputVersion is the version of the JSON outputs; it is bumped on breaking changes only
+// (adding a field is not one).
+const outputVersion = 1
+
+var fOutput = flag.String("output", "text", "output format: text, or json for one versioned JSON document per result on stdout (logs stay on stderr)")
+
+// output is the envelope of every JSON result
+type output struct {
+	Version int         `json:"version"`
+	Command string      `json:"command"`
+	Result  interface{} `json:"result"`
+}
+
+// jsonOutput returns true if results must be written as JSON
+func jsonOutput() bool {
+	switch *fOutput {
+	case "json":
+		return true
+	case "text":
+		return false
+	}
+	log.Fatalf("unknown output format %q (expected text or json)", *fOutput)
+	return false
+}
+
+// emit writes result as one line of JSON on stdout, with -output json only
+// Text outputs are written by the commands themselves.
+func emit(command string, result interface{}) {
+	if !jsonOutput() {
+		return
+	}
+	assertNoError(json.NewEncoder(os.Stdout).Encode(output{Version: outputVersion, Command: command, Result: result}))
+}
+
+// txResult describes a mined transaction
+type txResult struct {
+	Hash        common.Hash `json:"hash"`
+	BlockNumber uint64      `json:"blockNumber"`
+	Status      uint64      `json:"status"`
+	GasUsed     uint64      `json:"gasUsed"`
+}
+
+func newTxResult(receipt *types.Receipt) *txResult {
+	return &txResult{
+		Hash:        receipt.TxHash,
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		Status:      receipt.Status,
+		GasUsed:     receipt.GasUsed,
+	}
+}
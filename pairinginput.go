@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runPairingInput prints the input of the pairing precompile (0x08) the Solidity verifier sends for
+// a proof, to debug the verifier against raw calls: cast call 0x0000000000000000000000000000000000000008 <input>
+func runPairingInput(args []string) {
+	fs := flag.NewFlagSet("pairing-input", flag.ExitOnError)
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	fProof := fs.String("proof", files.proof, "proof file")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	fOut := fs.String("o", "", "if set, write the binary input to this file instead of printing it in hex")
+	assertNoError(fs.Parse(args))
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+	proof := groth16.NewProof(ecc.BN254)
+	deserialize(proof, *fProof)
+
+	input, err := ethereum.PairingInput(proof, vk, readPublicWitness(*fPublic, *fVK))
+	assertNoError(err)
+	ok, err := ethereum.PairingCheck(input)
+	assertNoError(err)
+	log.Printf("%d bytes for %s, the pairing check returns %v", len(input), ethereum.PairingPrecompile.Hex(), ok)
+
+	if *fOut != "" {
+		assertNoError(ioutil.WriteFile(*fOut, input, 0644))
+		log.Println("pairing input written to", *fOut)
+	} else if !jsonOutput() {
+		fmt.Println(hexutil.Encode(input))
+	}
+	emit("pairing-input", pairingInputResult{Precompile: ethereum.PairingPrecompile.Hex(), Input: hexutil.Encode(input), Valid: ok})
+}
+
+// pairingInputResult is the JSON output of pairing-input
+type pairingInputResult struct {
+	Precompile string `json:"precompile"`
+	Input      string `json:"input"`
+	Valid      bool   `json:"valid"`
+}
@@ -0,0 +1,57 @@
+package poseidon
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// HashInCircuit returns the Poseidon hash of inputs, as Hash computes it on the host
+// The parameters are defined over the BN254 scalar field: only use it in BN254 circuits.
+func HashInCircuit(cs *frontend.ConstraintSystem, inputs ...frontend.Variable) frontend.Variable {
+	var state [Width]frontend.Variable
+	state[0] = cs.Constant(len(inputs))
+	for i := 1; i < Width; i++ {
+		state[i] = cs.Constant(0)
+	}
+
+	for start := 0; start < len(inputs) || start == 0; start += Rate {
+		for i := 0; i < Rate && start+i < len(inputs); i++ {
+			state[1+i] = cs.Add(state[1+i], inputs[start+i])
+		}
+		permuteInCircuit(cs, &state)
+	}
+	return state[1]
+}
+
+func permuteInCircuit(cs *frontend.ConstraintSystem, state *[Width]frontend.Variable) {
+	params := getParams()
+	for r := range params.rcBig {
+		for i := range state {
+			state[i] = cs.Add(state[i], params.rcBig[r][i])
+		}
+		if isFullRound(r) {
+			for i := range state {
+				state[i] = sboxInCircuit(cs, state[i])
+			}
+		} else {
+			state[0] = sboxInCircuit(cs, state[0])
+		}
+
+		// linear layer: multiplications by constants cost no constraint
+		var mixed [Width]frontend.Variable
+		for i := range mixed {
+			terms := make([]interface{}, Width)
+			for j := range state {
+				terms[j] = cs.Mul(state[j], params.mdsBig[i][j])
+			}
+			mixed[i] = cs.Add(terms[0], terms[1], terms[2:]...)
+		}
+		*state = mixed
+	}
+}
+
+// sboxInCircuit computes x^5 with 3 constraints
+func sboxInCircuit(cs *frontend.ConstraintSystem, x frontend.Variable) frontend.Variable {
+	x2 := cs.Mul(x, x)
+	x4 := cs.Mul(x2, x2)
+	return cs.Mul(x4, x)
+}
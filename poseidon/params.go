@@ -0,0 +1,174 @@
+// Package poseidon is a Poseidon hash over the BN254 scalar field, with a host implementation
+// and a gnark gadget computing the same function.
+//
+// The permutation is circomlib's for width 3 (rate 2, capacity 1): x^5 S-box, 8 full and 57
+// partial rounds, round constants and MDS matrix generated by the Grain LFSR of the Poseidon
+// reference implementation (generate_parameters_grain.sage), as circomlib's tables were. The
+// sponge around it is the workshop's (see Hash), so hashes differ from circomlib's poseidon(...).
+package poseidon
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+const (
+	// Width is the size of the permutation state
+	Width = 3
+	// Rate is the number of elements absorbed per permutation
+	Rate = Width - 1
+
+	fullRounds    = 8
+	partialRounds = 57
+)
+
+type params struct {
+	rc  [][Width]fr.Element      // round constants, one row per round
+	mds [Width][Width]fr.Element // mixing layer
+
+	// the same, as constants for the gadget
+	rcBig  [][Width]big.Int
+	mdsBig [Width][Width]big.Int
+}
+
+var (
+	paramsOnce sync.Once
+	p          params
+)
+
+// getParams generates the parameters once: the round constants, then the MDS matrix, from the
+// same Grain LFSR
+func getParams() *params {
+	paramsOnce.Do(func() {
+		g := newGrain()
+
+		nbRounds := fullRounds + partialRounds
+		p.rc = make([][Width]fr.Element, nbRounds)
+		p.rcBig = make([][Width]big.Int, nbRounds)
+		for r := 0; r < nbRounds; r++ {
+			for i := 0; i < Width; i++ {
+				g.element(&p.rc[r][i])
+				p.rc[r][i].ToBigIntRegular(&p.rcBig[r][i])
+			}
+		}
+
+		// Cauchy matrix 1 / (x_i + y_j), the x_i, y_j random, distinct and with non zero sums, as
+		// the first matrix generated for circomlib passed the security checks of the reference
+		var xy [2 * Width]fr.Element
+		for {
+			for i := range xy {
+				g.reduced(&xy[i])
+			}
+			if cauchy(&p.mds, xy[:Width], xy[Width:]) {
+				break
+			}
+		}
+		for i := 0; i < Width; i++ {
+			for j := 0; j < Width; j++ {
+				p.mds[i][j].ToBigIntRegular(&p.mdsBig[i][j])
+			}
+		}
+	})
+	return &p
+}
+
+// cauchy sets m to the Cauchy matrix of xs, ys, or returns false if the 2·Width values are not
+// distinct or a sum is zero
+func cauchy(m *[Width][Width]fr.Element, xs, ys []fr.Element) bool {
+	all := append(append([]fr.Element(nil), xs...), ys...)
+	for i := range all {
+		for j := i + 1; j < len(all); j++ {
+			if all[i].Equal(&all[j]) {
+				return false
+			}
+		}
+	}
+	for i := range xs {
+		for j := range ys {
+			m[i][j].Add(&xs[i], &ys[j])
+			if m[i][j].IsZero() {
+				return false
+			}
+			m[i][j].Inverse(&m[i][j])
+		}
+	}
+	return true
+}
+
+// grain is the Grain LFSR of the Poseidon reference implementation, self-shrinking: of each pair of
+// bits, the second is output if the first is 1
+type grain struct {
+	state [80]byte
+}
+
+// newGrain returns the LFSR initialized for a prime field (1), the x^α S-box (0), fr.Bits bits
+// elements and the instance, then clocked 160 times
+func newGrain() *grain {
+	var g grain
+	bits := g.state[:0]
+	for _, f := range []struct{ value, size int }{
+		{1, 2}, {0, 4}, {fr.Bits, 12}, {Width, 12}, {fullRounds, 10}, {partialRounds, 10},
+	} {
+		for i := f.size - 1; i >= 0; i-- {
+			bits = append(bits, byte(f.value>>i)&1)
+		}
+	}
+	for len(bits) < len(g.state) {
+		bits = append(bits, 1)
+	}
+	for i := 0; i < 160; i++ {
+		g.clock()
+	}
+	return &g
+}
+
+// clock shifts the LFSR, returning the new bit
+func (g *grain) clock() byte {
+	s := &g.state
+	bit := s[62] ^ s[51] ^ s[38] ^ s[23] ^ s[13] ^ s[0]
+	copy(s[:], s[1:])
+	s[len(s)-1] = bit
+	return bit
+}
+
+// bit returns the next output bit
+func (g *grain) bit() byte {
+	for g.clock() == 0 {
+		g.clock()
+	}
+	return g.clock()
+}
+
+// bigInt returns the next fr.Bits bits, big endian
+func (g *grain) bigInt() *big.Int {
+	v := new(big.Int)
+	for i := 0; i < fr.Bits; i++ {
+		v.Lsh(v, 1)
+		v.SetBit(v, 0, uint(g.bit()))
+	}
+	return v
+}
+
+// element sets e to the next value below the modulus, drawing again the larger ones (round
+// constants)
+func (g *grain) element(e *fr.Element) {
+	for {
+		v := g.bigInt()
+		if v.Cmp(fr.Modulus()) < 0 {
+			e.SetBigInt(v)
+			return
+		}
+	}
+}
+
+// reduced sets e to the next value, reduced modulo the modulus (MDS matrix)
+func (g *grain) reduced(e *fr.Element) {
+	e.SetBigInt(g.bigInt())
+}
+
+// isFullRound returns true if round r applies the S-box to the whole state
+func isFullRound(r int) bool {
+	return r < fullRounds/2 || r >= fullRounds/2+partialRounds
+}
@@ -0,0 +1,55 @@
+package poseidon
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Hash returns the Poseidon hash of inputs
+// The capacity element is initialized with the number of inputs, so that inputs of different
+// lengths never collide through zero padding.
+func Hash(inputs ...fr.Element) fr.Element {
+	var state [Width]fr.Element
+	state[0].SetUint64(uint64(len(inputs)))
+
+	for start := 0; start < len(inputs) || start == 0; start += Rate {
+		for i := 0; i < Rate && start+i < len(inputs); i++ {
+			state[1+i].Add(&state[1+i], &inputs[start+i])
+		}
+		permute(&state)
+	}
+	return state[1]
+}
+
+func permute(state *[Width]fr.Element) {
+	params := getParams()
+	for r := range params.rc {
+		for i := range state {
+			state[i].Add(&state[i], &params.rc[r][i])
+		}
+		if isFullRound(r) {
+			for i := range state {
+				sbox(&state[i])
+			}
+		} else {
+			sbox(&state[0])
+		}
+
+		var mixed [Width]fr.Element
+		for i := range mixed {
+			for j := range state {
+				var t fr.Element
+				t.Mul(&params.mds[i][j], &state[j])
+				mixed[i].Add(&mixed[i], &t)
+			}
+		}
+		*state = mixed
+	}
+}
+
+// sbox computes x^5
+func sbox(x *fr.Element) {
+	var x2, x4 fr.Element
+	x2.Square(x)
+	x4.Square(&x2)
+	x.Mul(x, &x4)
+}
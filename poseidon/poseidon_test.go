@@ -0,0 +1,70 @@
+package poseidon
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// circomlib's poseidon([1, 2]): the width 3 permutation of [0, 1, 2], first element
+const circomlibPoseidon12 = "7853200120776062878684798364095072458815029376092732009249414926327459813530"
+
+func TestPermutationKnownAnswer(t *testing.T) {
+	var state [Width]fr.Element
+	state[1].SetUint64(1)
+	state[2].SetUint64(2)
+	permute(&state)
+	if state[0].String() != circomlibPoseidon12 {
+		t.Fatalf("permute([0, 1, 2])[0] = %s, circomlib's poseidon([1, 2]) is %s", state[0].String(), circomlibPoseidon12)
+	}
+}
+
+func TestHashLengths(t *testing.T) {
+	var zero fr.Element
+	one := Hash(zero)
+	two := Hash(zero, zero)
+	if one.Equal(&two) {
+		t.Fatal("Hash(0) = Hash(0, 0): the length doesn't separate the inputs")
+	}
+}
+
+// hashCircuit asserts HashInCircuit(Inputs...) == Hash
+type hashCircuit struct {
+	Inputs [3]frontend.Variable
+	Hash   frontend.Variable `gnark:",public"`
+}
+
+func (c *hashCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(HashInCircuit(cs, c.Inputs[:]...), c.Hash)
+	return nil
+}
+
+func TestHashInCircuit(t *testing.T) {
+	assert := groth16.NewAssert(t)
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &hashCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inputs [3]fr.Element
+	for i := range inputs {
+		inputs[i].SetUint64(uint64(i + 1))
+	}
+	assignment := func(hash fr.Element) *hashCircuit {
+		var witness hashCircuit
+		for i := range inputs {
+			witness.Inputs[i].Assign(inputs[i])
+		}
+		witness.Hash.Assign(hash)
+		return &witness
+	}
+	hash := Hash(inputs[:]...)
+	assert.ProverSucceeded(r1cs, assignment(hash))
+
+	hash.Add(&hash, new(fr.Element).SetOne())
+	assert.ProverFailed(r1cs, assignment(hash))
+}
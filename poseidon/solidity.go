@@ -0,0 +1,91 @@
+package poseidon
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Solidity returns the source of a Poseidon Solidity library with the same parameters, to hash
+// on-chain what Hash and HashInCircuit hash off-chain:
+//
+//	Poseidon.hash2(a, b) == Hash(a, b)
+//
+// The source has no SPDX license identifier nor pragma: it is meant to be appended to a contract source.
+func Solidity() string {
+	params := getParams()
+	data := struct {
+		Modulus       string
+		RC            []string
+		MDS           [Width][Width]string
+		Width         int
+		Rounds        int
+		FullRoundsLow int
+		FullRoundsHi  int
+	}{
+		Modulus:       fr.Modulus().String(),
+		Width:         Width,
+		Rounds:        len(params.rcBig),
+		FullRoundsLow: fullRounds / 2,
+		FullRoundsHi:  fullRounds/2 + partialRounds,
+	}
+	for r := range params.rcBig {
+		for i := range params.rcBig[r] {
+			data.RC = append(data.RC, params.rcBig[r][i].String())
+		}
+	}
+	for i := range params.mdsBig {
+		for j := range params.mdsBig[i] {
+			data.MDS[i][j] = params.mdsBig[i][j].String()
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := solidityTemplate.Execute(&buf, data); err != nil {
+		panic(err) // the template is static
+	}
+	return buf.String()
+}
+
+var solidityTemplate = template.Must(template.New("poseidon").Parse(`
+// Poseidon is generated by the gnark-workshop poseidon package (width 3, x^5 S-box)
+library Poseidon {
+    uint256 internal constant Q = {{.Modulus}};
+
+    function hash2(uint256 a, uint256 b) internal pure returns (uint256) {
+        require(a < Q && b < Q, "poseidon-input-gte-q");
+        uint256[{{.Width}}] memory s = [uint256(2), a, b];
+        uint256[{{len .RC}}] memory rc = [{{range $i, $c := .RC}}{{if $i}}, {{end}}uint256({{$c}}){{end}}];
+        uint256[{{.Width}}][{{.Width}}] memory m = [{{range $i, $row := .MDS}}{{if $i}}, {{end}}[{{range $j, $c := $row}}{{if $j}}, {{end}}uint256({{$c}}){{end}}]{{end}}];
+
+        for (uint256 r = 0; r < {{.Rounds}}; r++) {
+            for (uint256 i = 0; i < {{.Width}}; i++) {
+                s[i] = addmod(s[i], rc[r * {{.Width}} + i], Q);
+            }
+            if (r < {{.FullRoundsLow}} || r >= {{.FullRoundsHi}}) {
+                for (uint256 i = 0; i < {{.Width}}; i++) {
+                    s[i] = sbox(s[i]);
+                }
+            } else {
+                s[0] = sbox(s[0]);
+            }
+
+            uint256[{{.Width}}] memory mixed;
+            for (uint256 i = 0; i < {{.Width}}; i++) {
+                for (uint256 j = 0; j < {{.Width}}; j++) {
+                    mixed[i] = addmod(mixed[i], mulmod(m[i][j], s[j], Q), Q);
+                }
+            }
+            s = mixed;
+        }
+        return s[1];
+    }
+
+    function sbox(uint256 x) private pure returns (uint256) {
+        uint256 x2 = mulmod(x, x, Q);
+        uint256 x4 = mulmod(x2, x2, Q);
+        return mulmod(x4, x, Q);
+    }
+}
+`))
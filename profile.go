@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gbotrel/gnark-workshop/circuits"
+)
+
+// runProfile compiles circuits and prints their size, then with -gadgets the cost of a call of
+// each gadget (see circuits.GadgetProfiles)
+// Circuits are given as arguments, the selected circuit if none is; -all profiles them all.
+func runProfile(args []string) {
+	fs := flag.NewFlagSet("profile", flag.ExitOnError)
+	fAll := fs.Bool("all", false, "profile every registered circuit")
+	fGadgets := fs.Bool("gadgets", false, "also compile each gadget on its own and print the cost of a call")
+	assertNoError(fs.Parse(args))
+
+	names := fs.Args()
+	if *fAll {
+		names = circuits.Names()
+	} else if len(names) == 0 {
+		names = []string{*fCircuit}
+	}
+
+	profiles := make([]*circuits.Profile, len(names))
+	for i, name := range names {
+		p, err := circuits.NewProfile(name)
+		assertNoError(err)
+		profiles[i] = p
+	}
+
+	if !jsonOutput() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "circuit\tconstraints\twires\tpublic\tsecret\tinternal\t")
+		for _, p := range profiles {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t\n", p.Circuit, p.Constraints, p.Wires, p.Public, p.Secret, p.Internal)
+		}
+		assertNoError(w.Flush())
+	}
+	emit("profile", profiles)
+
+	if !*fGadgets {
+		return
+	}
+	gadgets, err := circuits.GadgetProfiles()
+	assertNoError(err)
+	if !jsonOutput() {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "gadget\tconstraints/call\twires/call\t")
+		for _, g := range gadgets {
+			fmt.Fprintf(w, "%s\t%d\t%d\t\n", g.Name, g.Constraints, g.Wires)
+		}
+		assertNoError(w.Flush())
+	}
+	emit("profile-gadgets", gadgets)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gbotrel/gnark-workshop/prover"
+)
+
+// progressLine is the prover.ProgressReporter of the CLI: the phases of a proof and their elapsed
+// time, redrawn on one line of stderr; silent with -output json
+type progressLine struct {
+	// phases are the phases seen so far, in order
+	phases []string
+}
+
+func newProgressLine() *progressLine {
+	return &progressLine{}
+}
+
+// Report implements prover.ProgressReporter
+func (p *progressLine) Report(e *prover.ProveEvent) error {
+	if jsonOutput() {
+		return nil
+	}
+	if len(p.phases) == 0 || p.phases[len(p.phases)-1] != e.Phase {
+		p.phases = append(p.phases, e.Phase)
+	}
+	fmt.Fprintf(os.Stderr, "\r%-32s %6.1fs", strings.Join(p.phases, " > "), float64(e.ElapsedMs)/1000)
+	return nil
+}
+
+// end terminates the line
+func (p *progressLine) end() {
+	if !jsonOutput() && len(p.phases) != 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+}
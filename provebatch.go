@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/prover"
+)
+
+// runProveBatch proves every witness of a JSONL or CSV file for the selected circuit, in parallel
+// with the keys loaded once, and writes a proof and public witness per row, and a manifest
+func runProveBatch(args []string) {
+	fs := flag.NewFlagSet("prove-batch", flag.ExitOnError)
+	fInput := fs.String("input", "", "witnesses, one JSON witness (see circuits.FromJSON) per line, or CSV with a header row naming the circuit fields (.csv)")
+	fOut := fs.String("out", "proofs", "directory of the proofs, public witnesses and manifest")
+	fWorkers := fs.Int("workers", runtime.GOMAXPROCS(0)/4+1, "concurrent proofs (each proof is itself parallel, on -procs cores)")
+	assertNoError(fs.Parse(args))
+	if *fInput == "" {
+		log.Fatal("prove-batch: -input is required")
+	}
+
+	rows, err := readWitnessRows(*fInput)
+	assertNoError(err)
+	keys, err := readKeys(*fCircuit)
+	assertNoError(err)
+	assertNoError(os.MkdirAll(*fOut, 0755))
+	pool := prover.NewPool(keys.R1CS, keys.PK, keys.VK, *fWorkers, *fWorkers)
+	defer pool.Close()
+
+	log.Printf("proving %d witnesses of %s on %d workers", len(rows), *fCircuit, *fWorkers)
+	start := time.Now()
+	results := make([]batchRow, len(rows))
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < *fWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = proveRow(pool, i+1, rows[i], *fOut)
+			}
+		}()
+	}
+	for i := range rows {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	result := proveBatchResult{Circuit: *fCircuit, Input: *fInput, Rows: results, Seconds: elapsed.Seconds()}
+	for _, r := range results {
+		if r.Error == "" {
+			result.Proved++
+		} else {
+			result.Failed++
+			log.Printf("row %d: %s", r.Row, r.Error)
+		}
+	}
+	if elapsed > 0 {
+		result.ProofsPerSecond = float64(result.Proved) / elapsed.Seconds()
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	assertNoError(err)
+	manifest := filepath.Join(*fOut, "manifest.json")
+	assertNoError(ioutil.WriteFile(manifest, append(data, '\n'), 0644))
+
+	log.Printf("%d proofs in %s (%.2f proofs/s), %d failed; manifest in %s",
+		result.Proved, elapsed.Round(time.Millisecond), result.ProofsPerSecond, result.Failed, manifest)
+	emit("prove-batch", result)
+	if result.Failed != 0 {
+		os.Exit(1)
+	}
+}
+
+// proveRow proves the JSON witness of the row-th row (from 1), writing its files in dir
+func proveRow(pool *prover.Pool, row int, witnessJSON []byte, dir string) (r batchRow) {
+	r.Row = row
+	start := time.Now()
+	defer func() { r.ElapsedMs = time.Since(start).Milliseconds() }()
+
+	fail := func(err error) batchRow {
+		r.Error = err.Error()
+		return r
+	}
+	witness, err := circuits.FromJSON(*fCircuit, witnessJSON)
+	if err != nil {
+		return fail(err)
+	}
+	proof, err := pool.Prove(mainCtx, witness)
+	if err != nil {
+		return fail(err)
+	}
+	publicWitness, err := publicWitnessOf(witness)
+	if err != nil {
+		return fail(err)
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("row-%06d", row))
+	r.Proof, r.Public = base+".proof", base+".public"
+	var buf bytes.Buffer
+	if _, err := artifacts.Write(&buf, header, proof); err != nil {
+		return fail(err)
+	}
+	if err := ioutil.WriteFile(r.Proof, buf.Bytes(), 0644); err != nil {
+		return fail(err)
+	}
+	if err := ioutil.WriteFile(r.Public, publicWitness, 0644); err != nil {
+		return fail(err)
+	}
+	return r
+}
+
+func publicWitnessOf(witness frontend.Circuit) ([]byte, error) {
+	publicWitness, err := ethereum.PublicWitness(witness)
+	if err != nil {
+		return nil, err
+	}
+	return ethereum.EncodePublicWitness(publicWitness), nil
+}
+
+// readWitnessRows reads the rows of a prove-batch input file as JSON witnesses; CSV rows become
+// objects keyed by the header row, with string values
+func readWitnessRows(fileName string) ([][]byte, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rows [][]byte
+	if strings.EqualFold(filepath.Ext(fileName), ".csv") {
+		r := csv.NewReader(f)
+		fields, err := r.Read()
+		if err != nil {
+			return nil, fmt.Errorf("%s: header row: %w", fileName, err)
+		}
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				return rows, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fileName, err)
+			}
+			object := make(map[string]string, len(fields))
+			for i, field := range fields {
+				object[strings.TrimSpace(field)] = strings.TrimSpace(record[i])
+			}
+			data, err := json.Marshal(object)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, data)
+		}
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) != 0 {
+			rows = append(rows, append([]byte(nil), line...))
+		}
+	}
+	return rows, scanner.Err()
+}
+
+// proveBatchResult is the manifest, and JSON output, of prove-batch
+type proveBatchResult struct {
+	Circuit         string     `json:"circuit"`
+	Input           string     `json:"input"`
+	Proved          int        `json:"proved"`
+	Failed          int        `json:"failed"`
+	Seconds         float64    `json:"seconds"`
+	ProofsPerSecond float64    `json:"proofsPerSecond"`
+	Rows            []batchRow `json:"rows"`
+}
+
+// batchRow is the outcome of a row of prove-batch
+type batchRow struct {
+	Row       int    `json:"row"`
+	Proof     string `json:"proof,omitempty"`
+	Public    string `json:"public,omitempty"`
+	Error     string `json:"error,omitempty"`
+	ElapsedMs int64  `json:"elapsedMs"`
+}
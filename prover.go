@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	gnarkwitness "github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/metrics"
+	"github.com/gbotrel/gnark-workshop/prover"
+	"google.golang.org/grpc"
+)
+
+// runServeProver serves the gRPC Prover service over the local artifacts of the registered circuits
+func runServeProver(args []string) {
+	fs := flag.NewFlagSet("serve-prover", flag.ExitOnError)
+	fAddr := fs.String("addr", ":9090", "address to listen on")
+	fWorkers := fs.Int("workers", runtime.GOMAXPROCS(0)/4+1, "concurrent proofs per circuit (each proof is itself parallel, on -procs cores)")
+	fQueue := fs.Int("queue", 16, "waiting proof requests per circuit, further requests block")
+	fMetrics := fs.String("metrics-addr", "", "if set, serve Prometheus metrics on http://<metrics-addr>/metrics")
+	fSSE := fs.String("sse-addr", "", "if set, serve Prove as server-sent events on http://<sse-addr>/prove")
+	assertNoError(fs.Parse(args))
+
+	lis, err := net.Listen("tcp", *fAddr)
+	assertNoError(err)
+	s := grpc.NewServer()
+	srv := prover.NewServer(readKeys, *fWorkers, *fQueue)
+	if *fMetrics != "" {
+		phases := metrics.NewPhases()
+		srv.Observe = phases.Observe
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", phases)
+		go func() {
+			log.Fatal(http.ListenAndServe(*fMetrics, mux))
+		}()
+		log.Printf("metrics on http://%s/metrics", *fMetrics)
+	}
+	if *fSSE != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/prove", prover.SSEHandler(srv))
+		go func() {
+			log.Fatal(http.ListenAndServe(*fSSE, mux))
+		}()
+		log.Printf("progress events on http://%s/prove", *fSSE)
+	}
+	prover.RegisterProverServer(s, srv)
+	log.Printf("prover listening on %s", *fAddr)
+	log.Fatal(s.Serve(lis))
+}
+
+// readKeys reads the artifacts written by -init for the circuit registered under name
+func readKeys(name string) (*prover.Keys, error) {
+	if _, err := circuits.Get(name); err != nil {
+		return nil, err
+	}
+	cf := filesOf(name)
+	manifest, err := artifacts.ReadManifest(cf.manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w (run -init -circuit %s on the server)", err, name)
+	}
+	if err := manifest.CheckFeatures(); err != nil {
+		return nil, err
+	}
+	if err := checkSetup(name, manifest, cf); err != nil {
+		return nil, err
+	}
+	keys := &prover.Keys{
+		R1CS: groth16.NewCS(ecc.BN254),
+		PK:   groth16.NewProvingKey(ecc.BN254),
+		VK:   groth16.NewVerifyingKey(ecc.BN254),
+	}
+	for fileName, o := range map[string]interface {
+		ReadFrom(r io.Reader) (int64, error)
+	}{cf.r1cs: keys.R1CS, cf.pk: keys.PK, cf.vk: keys.VK} {
+		f, err := artifacts.Open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		_, err = artifacts.Read(f, header, o)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fileName, err)
+		}
+	}
+	return keys, nil
+}
+
+// runProveRemote proves the example of the selected circuit (or the JSON witness given with
+// -witness) on a remote prover, showing its progress
+func runProveRemote(args []string) {
+	fs := flag.NewFlagSet("prove-remote", flag.ExitOnError)
+	fAddr := fs.String("addr", "localhost:9090", "address of the prover")
+	fWitness := fs.String("witness", "", "JSON witness file (see circuits.FromJSON), the circuit example if not set")
+	fProof := fs.String("proof", files.proof, "file where the proof is written")
+	assertNoError(fs.Parse(args))
+
+	req := &prover.ProveRequest{Circuit: *fCircuit}
+	var witness frontend.Circuit
+	var err error
+	if *fWitness != "" {
+		req.WitnessJSON, err = ioutil.ReadFile(*fWitness)
+		assertNoError(err)
+		witness, err = circuits.FromJSON(*fCircuit, req.WitnessJSON)
+		assertNoError(err)
+	} else {
+		witness, err = circuits.Example(*fCircuit)
+		assertNoError(err)
+		var buf bytes.Buffer
+		_, err = gnarkwitness.WriteFullTo(&buf, ecc.BN254, witness)
+		assertNoError(err)
+		req.Witness = buf.Bytes()
+	}
+
+	client, err := prover.Dial(*fAddr)
+	assertNoError(err)
+	defer client.Close()
+
+	ctx := mainCtx
+	progress := newProgressLine()
+	done, err := client.Prove(ctx, req, func(e *prover.ProveEvent) {
+		progress.Report(e)
+	})
+	progress.end()
+	assertNoError(err)
+	assertNoError(ioutil.WriteFile(*fProof, done.Proof, 0600))
+	log.Printf("proof written to %s (%.1fs)", *fProof, float64(done.ElapsedMs)/1000)
+
+	// check it with the verifying key served by the prover
+	var buf bytes.Buffer
+	_, err = gnarkwitness.WritePublicTo(&buf, ecc.BN254, witness)
+	assertNoError(err)
+	resp, err := client.Verify(ctx, &prover.VerifyRequest{Circuit: *fCircuit, Proof: done.Proof, PublicWitness: buf.Bytes()})
+	assertNoError(err)
+	emit("prove-remote", proveRemoteResult{Circuit: *fCircuit, Proof: *fProof, ElapsedMs: done.ElapsedMs, Valid: resp.Valid})
+	if !resp.Valid {
+		log.Fatal("remote proof is invalid: ", resp.Error)
+	}
+	log.Println("remote proof is valid")
+}
+
+// proveRemoteResult is the JSON output of prove-remote
+type proveRemoteResult struct {
+	Circuit   string `json:"circuit"`
+	Proof     string `json:"proof"`
+	ElapsedMs uint32 `json:"elapsedMs"`
+	Valid     bool   `json:"valid"`
+}
@@ -0,0 +1,132 @@
+package prover
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits"
+)
+
+// FSLoader returns a Loader reading the artifacts of each circuit from fsys, in <circuit>/ with the
+// file names of a store directory (artifacts.R1CSFile, ...): a bundle written by the bundle
+// command, embedded in the binary or mounted in a container
+// The manifest, if present, is checked as -init's: signature, features, circuit and artifact hashes.
+// The bundled R1CS must be the compiled registered circuit: a bundle older than the binary is refused.
+func FSLoader(fsys fs.FS) Loader {
+	header := artifacts.Header{GnarkVersion: artifacts.GnarkVersion(), Curve: ecc.BN254, Backend: backend.GROTH16}
+	return func(name string) (*Keys, error) {
+		var manifest artifacts.Manifest
+		data, err := fs.ReadFile(fsys, path.Join(name, artifacts.ManifestFile))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, err
+		default:
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		if err := manifest.CheckFeatures(); err != nil {
+			return nil, err
+		}
+		trusted, err := artifacts.TrustedKey()
+		if err != nil {
+			return nil, err
+		}
+		if err := manifest.Verify(trusted); err != nil {
+			return nil, err
+		}
+
+		keys := &Keys{
+			R1CS: groth16.NewCS(ecc.BN254),
+			PK:   groth16.NewProvingKey(ecc.BN254),
+			VK:   groth16.NewVerifyingKey(ecc.BN254),
+		}
+		for _, a := range []struct {
+			fileName, hash string
+			o              io.ReaderFrom
+		}{
+			{artifacts.R1CSFile, manifest.Digests[artifacts.R1CSFile], keys.R1CS},
+			{artifacts.PKFile, manifest.PKHash, keys.PK},
+			{artifacts.VKFile, manifest.VKHash, keys.VK},
+		} {
+			fileName := path.Join(name, a.fileName)
+			data, err := fs.ReadFile(fsys, fileName)
+			if err != nil {
+				return nil, fmt.Errorf("%w (bundle the artifacts of %s)", err, name)
+			}
+			if h := sha256.Sum256(data); a.hash != "" && hex.EncodeToString(h[:]) != a.hash {
+				return nil, fmt.Errorf("%s: %w", fileName, artifacts.ErrCorrupted)
+			}
+			r, err := artifacts.Decrypt(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fileName, err)
+			}
+			if _, err := artifacts.Read(r, header, a.o); err != nil {
+				return nil, fmt.Errorf("%s: %w", fileName, err)
+			}
+		}
+
+		// the bundle may be older than the binary: its R1CS and manifest must match the registered circuit
+		circuitHash, err := compiledHash(name)
+		if err != nil {
+			return nil, err
+		}
+		bundledHash, err := artifacts.Hash(keys.R1CS)
+		if err != nil {
+			return nil, err
+		}
+		if bundledHash != circuitHash {
+			return nil, fmt.Errorf("%s: %w", name, artifacts.ErrCircuitMismatch)
+		}
+		if err := manifest.CheckCircuit(circuitHash); err != nil {
+			return nil, err
+		}
+		return keys, nil
+	}
+}
+
+// EmbeddedBundle returns the dir subtree of fsys, the bundle directory embedded in a binary, nil
+// if it holds no circuit: a checkout only has the README placeholder of the bundle directory
+// It panics if dir can't be read, a build error of the embedding package.
+func EmbeddedBundle(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	entries, err := fs.ReadDir(sub, ".")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			return sub
+		}
+	}
+	return nil
+}
+
+// compiledHash compiles the circuit registered under name and returns its hash (see artifacts.Hash)
+func compiledHash(name string) (string, error) {
+	circuit, err := circuits.Get(name)
+	if err != nil {
+		return "", err
+	}
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, circuit)
+	if err != nil {
+		return "", err
+	}
+	return artifacts.Hash(r1cs)
+}
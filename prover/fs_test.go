@@ -0,0 +1,138 @@
+package prover
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits"
+)
+
+// cubeCircuit proves the knowledge of the cube root of a public Y
+type cubeCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubeCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+// squareCircuit is cubeCircuit before an edit: the circuit of a stale bundle
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+const testCircuit = "test-cube"
+
+func init() {
+	circuits.Register(testCircuit, &cubeCircuit{})
+}
+
+// bundle returns the files of a bundle of circuit under testCircuit, and its manifest
+func bundle(t *testing.T, circuit frontend.Circuit) (fstest.MapFS, *artifacts.Manifest) {
+	t.Helper()
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := artifacts.Header{GnarkVersion: artifacts.GnarkVersion(), Curve: ecc.BN254, Backend: backend.GROTH16}
+	fsys := make(fstest.MapFS)
+	for fileName, o := range map[string]io.WriterTo{artifacts.R1CSFile: r1cs, artifacts.PKFile: pk, artifacts.VKFile: vk} {
+		var buf bytes.Buffer
+		if _, err := artifacts.Write(&buf, header, o); err != nil {
+			t.Fatal(err)
+		}
+		fsys[testCircuit+"/"+fileName] = &fstest.MapFile{Data: buf.Bytes()}
+	}
+	circuitHash, err := artifacts.Hash(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fsys, &artifacts.Manifest{Circuit: testCircuit, Curve: ecc.BN254.String(), Backend: backend.GROTH16.String(), CircuitHash: circuitHash}
+}
+
+func withManifest(t *testing.T, fsys fstest.MapFS, manifest *artifacts.Manifest) fstest.MapFS {
+	t.Helper()
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	with := make(fstest.MapFS, len(fsys)+1)
+	for name, f := range fsys {
+		with[name] = f
+	}
+	with[testCircuit+"/"+artifacts.ManifestFile] = &fstest.MapFile{Data: data}
+	return with
+}
+
+func TestFSLoader(t *testing.T) {
+	t.Setenv(artifacts.TrustedKeyEnv, "")
+	current, manifest := bundle(t, &cubeCircuit{})
+	stale, staleManifest := bundle(t, &squareCircuit{})
+	// a manifest recording the registered circuit, shipped with the stale R1CS
+	mixed := withManifest(t, stale, manifest)
+
+	for name, tc := range map[string]struct {
+		fsys     fstest.MapFS
+		expected error
+	}{
+		"bundle":               {fsys: current},
+		"bundle with manifest": {fsys: withManifest(t, current, manifest)},
+		"stale bundle":         {fsys: stale, expected: artifacts.ErrCircuitMismatch},
+		"stale with manifest":  {fsys: withManifest(t, stale, staleManifest), expected: artifacts.ErrCircuitMismatch},
+		"stale R1CS":           {fsys: mixed, expected: artifacts.ErrCircuitMismatch},
+	} {
+		keys, err := FSLoader(tc.fsys)(testCircuit)
+		if !errors.Is(err, tc.expected) {
+			t.Errorf("%s: got %v, expected %v", name, err, tc.expected)
+			continue
+		}
+		if err == nil && (keys.R1CS == nil || keys.PK == nil || keys.VK == nil) {
+			t.Errorf("%s: incomplete keys", name)
+		}
+	}
+
+	if _, err := FSLoader(current)("unregistered"); err == nil {
+		t.Error("loaded an unregistered circuit")
+	}
+}
+
+func TestEmbeddedBundle(t *testing.T) {
+	placeholder := fstest.MapFS{"proverd/bundle/README.md": &fstest.MapFile{Data: []byte("run bundle")}}
+	if b := EmbeddedBundle(placeholder, "proverd/bundle"); b != nil {
+		t.Fatal("the README placeholder is a bundle")
+	}
+
+	bundled := fstest.MapFS{
+		"proverd/bundle/README.md":                      &fstest.MapFile{Data: []byte("run bundle")},
+		"proverd/bundle/" + testCircuit + "/circuit.vk": &fstest.MapFile{Data: []byte("vk")},
+	}
+	b := EmbeddedBundle(bundled, "proverd/bundle")
+	if b == nil {
+		t.Fatal("no bundle")
+	}
+	if data, err := fs.ReadFile(b, testCircuit+"/circuit.vk"); err != nil || string(data) != "vk" {
+		t.Fatalf("read %q, %v", data, err)
+	}
+}
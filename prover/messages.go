@@ -0,0 +1,71 @@
+package prover
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// Codec is the name of the gRPC codec of the service: clients call it with the content-subtype
+// application/grpc+json, and a codec encoding the messages as JSON
+const Codec = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec encodes the messages with encoding/json: []byte fields are base64 strings
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return Codec }
+
+// Phases of a Prove call, in order
+const (
+	PhaseLoad    = "load"    // reading the circuit artifacts, on first use only
+	PhaseWitness = "witness" // decoding the witness
+	PhaseProve   = "prove"   // solving the witness and running the MSMs, sent every second
+	PhaseDone    = "done"
+)
+
+// ProveRequest is the request of Prove, for the circuit registered under Circuit
+// Set one of Witness, the full witness as written by gnark's witness.WriteFullTo, and WitnessJSON,
+// the document read by circuits.FromJSON.
+type ProveRequest struct {
+	Circuit     string          `json:"circuit"`
+	Witness     []byte          `json:"witness,omitempty"`
+	WitnessJSON json.RawMessage `json:"witnessJson,omitempty"`
+}
+
+// ProveEvent is a progress event of Prove: ElapsedMs is the time since the request was received;
+// the last event, in PhaseDone, carries the proof
+type ProveEvent struct {
+	Phase     string `json:"phase"`
+	ElapsedMs uint32 `json:"elapsedMs,omitempty"`
+	Proof     []byte `json:"proof,omitempty"`
+}
+
+// VerifyRequest is the request of Verify; PublicWitness is written by gnark's witness.WritePublicTo
+type VerifyRequest struct {
+	Circuit       string `json:"circuit"`
+	Proof         []byte `json:"proof"`
+	PublicWitness []byte `json:"publicWitness"`
+}
+
+// VerifyResponse is the response of Verify: Error says why an invalid proof is
+type VerifyResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// GetVerifyingKeyRequest is the request of GetVerifyingKey
+type GetVerifyingKeyRequest struct {
+	Circuit string `json:"circuit"`
+}
+
+// VerifyingKey is the response of GetVerifyingKey, the serialized verifying key of Circuit
+type VerifyingKey struct {
+	Circuit string `json:"circuit"`
+	VK      []byte `json:"vk"`
+}
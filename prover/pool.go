@@ -0,0 +1,136 @@
+package prover
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/accel"
+)
+
+// ErrPoolClosed is returned by the Prove calls of a closed Pool
+var ErrPoolClosed = errors.New("prover pool closed")
+
+// Pool proves with keys loaded once, on a fixed number of workers
+// Jobs wait in a bounded queue: when it is full, Prove blocks until a slot frees up or its
+// context is done, so that callers slow down instead of piling up proofs in memory.
+type Pool struct {
+	r1cs frontend.CompiledConstraintSystem
+	pk   groth16.ProvingKey
+	vk   groth16.VerifyingKey
+
+	jobs   chan job
+	closed chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+type job struct {
+	ctx   context.Context
+	prove func() (groth16.Proof, error)
+	// result is buffered, workers never block on a caller that went away
+	result chan jobResult
+}
+
+type jobResult struct {
+	proof groth16.Proof
+	err   error
+}
+
+// NewPool starts workers provers over r1cs and pk, with up to queueSize waiting jobs; vk checks
+// the proofs of accelerators (see accel.Prove)
+func NewPool(r1cs frontend.CompiledConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey, workers, queueSize int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &Pool{
+		r1cs:   r1cs,
+		pk:     pk,
+		vk:     vk,
+		jobs:   make(chan job, queueSize),
+		closed: make(chan struct{}),
+	}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.closed:
+			return
+		case j := <-p.jobs:
+			// select picks at random between closed and jobs: the jobs left when the pool closes fail
+			select {
+			case <-p.closed:
+				j.result <- jobResult{err: ErrPoolClosed}
+				return
+			default:
+			}
+			// skip the jobs cancelled while queued
+			if err := j.ctx.Err(); err != nil {
+				j.result <- jobResult{err: err}
+				continue
+			}
+			proof, err := j.prove()
+			j.result <- jobResult{proof, err}
+		}
+	}
+}
+
+// Prove proves the full assignment witness, on the registered accelerator if any (see accel.Prove)
+// If ctx is done while the proof runs, Prove returns ctx.Err() right away: the proof itself
+// can't be interrupted, the worker completes it and drops it.
+func (p *Pool) Prove(ctx context.Context, witness frontend.Circuit) (groth16.Proof, error) {
+	return p.submit(ctx, func() (groth16.Proof, error) {
+		return accel.Prove(p.r1cs, p.pk, p.vk, witness)
+	})
+}
+
+// ReadAndProve proves the binary full witness read from r (as written by witness.WriteFullTo)
+// r is read by the worker: it must stay valid until ReadAndProve returns. Binary witnesses are
+// always proved on the CPU: accelerators take an assignment.
+func (p *Pool) ReadAndProve(ctx context.Context, r io.Reader) (groth16.Proof, error) {
+	return p.submit(ctx, func() (groth16.Proof, error) {
+		return groth16.ReadAndProve(p.r1cs, p.pk, r)
+	})
+}
+
+func (p *Pool) submit(ctx context.Context, prove func() (groth16.Proof, error)) (groth16.Proof, error) {
+	j := job{ctx: ctx, prove: prove, result: make(chan jobResult, 1)}
+	select {
+	case <-p.closed:
+		return nil, ErrPoolClosed
+	default:
+	}
+	select {
+	case p.jobs <- j:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.closed:
+		return nil, ErrPoolClosed
+	}
+	select {
+	case r := <-j.result:
+		return r.proof, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.closed:
+		return nil, ErrPoolClosed
+	}
+}
+
+// Close stops the workers once their current proof is done; queued jobs fail with ErrPoolClosed
+func (p *Pool) Close() {
+	p.once.Do(func() {
+		close(p.closed)
+	})
+	p.wg.Wait()
+}
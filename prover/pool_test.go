@@ -0,0 +1,241 @@
+package prover
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	gnarkwitness "github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+func TestPoolProve(t *testing.T) {
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &cubeCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPool(r1cs, pk, vk, 2, 1)
+	defer p.Close()
+
+	proof, err := p.Prove(context.Background(), cubeWitness(3, 27))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, cubeWitness(3, 27)); err != nil {
+		t.Fatal(err)
+	}
+	var full bytes.Buffer
+	if _, err := gnarkwitness.WriteFullTo(&full, ecc.BN254, cubeWitness(3, 27)); err != nil {
+		t.Fatal(err)
+	}
+	if proof, err = p.ReadAndProve(context.Background(), &full); err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, cubeWitness(3, 27)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Prove(context.Background(), cubeWitness(3, 28)); err == nil {
+		t.Fatal("proved an unsatisfied witness")
+	}
+}
+
+// blocker is a job proving nothing until released, recording whether it ran
+type blocker struct {
+	started chan struct{}
+	release chan struct{}
+	ran     int32
+}
+
+func newBlocker() *blocker {
+	return &blocker{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (b *blocker) prove() (groth16.Proof, error) {
+	atomic.StoreInt32(&b.ran, 1)
+	close(b.started)
+	<-b.release
+	return nil, nil
+}
+
+// submit runs p.submit(ctx, b.prove) in the background, returning its error on the channel
+func (b *blocker) submit(ctx context.Context, p *Pool) <-chan error {
+	errs := make(chan error, 1)
+	go func() {
+		_, err := p.submit(ctx, b.prove)
+		errs <- err
+	}()
+	return errs
+}
+
+func (b *blocker) waitStarted(t *testing.T) {
+	t.Helper()
+	select {
+	case <-b.started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the job didn't start")
+	}
+}
+
+func TestPoolConcurrency(t *testing.T) {
+	const workers = 3
+	p := NewPool(nil, nil, nil, workers, 10)
+	defer p.Close()
+
+	var running, peak int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 3*workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.submit(context.Background(), func() (groth16.Proof, error) {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					max := atomic.LoadInt32(&peak)
+					if n <= max || atomic.CompareAndSwapInt32(&peak, max, n) {
+						break
+					}
+				}
+				<-release
+				atomic.AddInt32(&running, -1)
+				return nil, nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	// let the workers pick up as many jobs as they can
+	deadline := time.Now().Add(10 * time.Second)
+	for atomic.LoadInt32(&running) < workers && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&running); n != workers {
+		t.Fatalf("%d jobs running, expected %d", n, workers)
+	}
+	close(release)
+	wg.Wait()
+	if peak != workers {
+		t.Fatalf("up to %d jobs ran concurrently, expected %d", peak, workers)
+	}
+}
+
+func TestPoolQueue(t *testing.T) {
+	p := NewPool(nil, nil, nil, 1, 1)
+	defer p.Close()
+
+	running := newBlocker()
+	runningErr := running.submit(context.Background(), p)
+	running.waitStarted(t)
+	queued := newBlocker()
+	close(queued.release)
+	queuedErr := queued.submit(context.Background(), p)
+
+	// the queue is full: the next job waits for a slot until its context is done
+	deadlineCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	blocked := newBlocker()
+	if err := <-blocked.submit(deadlineCtx, p); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, expected a deadline exceeded", err)
+	}
+
+	close(running.release)
+	for _, errs := range []<-chan error{runningErr, queuedErr} {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if atomic.LoadInt32(&blocked.ran) != 0 {
+		t.Fatal("the job that didn't get a slot ran")
+	}
+}
+
+func TestPoolCancel(t *testing.T) {
+	p := NewPool(nil, nil, nil, 1, 1)
+	defer p.Close()
+
+	running := newBlocker()
+	runningCtx, cancelRunning := context.WithCancel(context.Background())
+	runningErr := running.submit(runningCtx, p)
+	running.waitStarted(t)
+
+	queued := newBlocker()
+	queuedCtx, cancelQueued := context.WithCancel(context.Background())
+	queuedErr := queued.submit(queuedCtx, p)
+	// wait for the job to be in the queue
+	for len(p.jobs) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// a job cancelled while queued returns right away, and is dropped
+	cancelQueued()
+	if err := <-queuedErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("queued job: got %v, expected context.Canceled", err)
+	}
+	// a running one returns right away too, its proof is dropped when done
+	cancelRunning()
+	if err := <-runningErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("running job: got %v, expected context.Canceled", err)
+	}
+
+	close(running.release)
+	next := newBlocker()
+	close(next.release)
+	if err := <-next.submit(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&queued.ran) != 0 {
+		t.Fatal("the job cancelled while queued ran")
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	p := NewPool(nil, nil, nil, 1, 1)
+
+	running := newBlocker()
+	runningErr := running.submit(context.Background(), p)
+	running.waitStarted(t)
+	queued := newBlocker()
+	queuedErr := queued.submit(context.Background(), p)
+	for len(p.jobs) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+	for _, errs := range []<-chan error{runningErr, queuedErr} {
+		if err := <-errs; !errors.Is(err, ErrPoolClosed) {
+			t.Fatalf("got %v, expected ErrPoolClosed", err)
+		}
+	}
+	// Close waits for the running proof
+	select {
+	case <-closed:
+		t.Fatal("Close returned before the running proof completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(running.release)
+	<-closed
+
+	if _, err := p.submit(context.Background(), queued.prove); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("got %v after Close, expected ErrPoolClosed", err)
+	}
+	if atomic.LoadInt32(&queued.ran) != 0 {
+		t.Fatal("a queued job ran after Close")
+	}
+}
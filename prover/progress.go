@@ -0,0 +1,65 @@
+package prover
+
+import (
+	"context"
+	"time"
+
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// ProgressInterval is the period of the PhaseProve events while a proof runs
+const ProgressInterval = time.Second
+
+// ProgressReporter is notified of the phases of a proof, e.g. to draw a progress bar or stream them
+// to a client; an error stops the reporting and the wait for the proof
+// gnark solves the witness and runs the A, B and C MSMs inside groth16.Prove, without hooks: they
+// are a single phase, PhaseProve, reported every ProgressInterval while it runs.
+type ProgressReporter interface {
+	Report(e *ProveEvent) error
+}
+
+// ReporterFunc is a function implementing ProgressReporter
+type ReporterFunc func(e *ProveEvent) error
+
+// Report implements ProgressReporter
+func (f ReporterFunc) Report(e *ProveEvent) error {
+	return f(e)
+}
+
+// ProveWithProgress runs prove, reporting PhaseProve to r when it starts and every
+// ProgressInterval until it returns; events carry the time elapsed since the call
+// If ctx is done first, it returns ctx.Err() without waiting for prove, as Pool.Prove.
+func ProveWithProgress(ctx context.Context, r ProgressReporter, prove func() (groth16.Proof, error)) (groth16.Proof, error) {
+	start := time.Now()
+	report := func() error {
+		return r.Report(&ProveEvent{Phase: PhaseProve, ElapsedMs: uint32(time.Since(start) / time.Millisecond)})
+	}
+
+	type result struct {
+		proof groth16.Proof
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		proof, err := prove()
+		done <- result{proof, err}
+	}()
+
+	ticker := time.NewTicker(ProgressInterval)
+	defer ticker.Stop()
+	if err := report(); err != nil {
+		return nil, err
+	}
+	for {
+		select {
+		case <-ticker.C:
+			if err := report(); err != nil {
+				return nil, err
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case r := <-done:
+			return r.proof, r.err
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package prover exposes groth16 proving and verification as a JSON-over-gRPC service (see
+// RegisterProverServer), streaming progress events so that clients can show progress bars on
+// long-running proofs.
+package prover
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Keys are the artifacts of a circuit needed to prove and verify, on BN254 as the rest of the workshop
+type Keys struct {
+	R1CS frontend.CompiledConstraintSystem
+	PK   groth16.ProvingKey
+	VK   groth16.VerifyingKey
+}
+
+// Loader reads the artifacts of the circuit registered under name
+type Loader func(name string) (*Keys, error)
+
+// Server implements ProverServer; artifacts are loaded on first use and kept in memory, with
+// a Pool of workers per circuit
+// A circuit is loaded once, outside the lock: the requests for other circuits don't wait for it.
+type Server struct {
+	load               Loader
+	workers, queueSize int
+
+	// Observe, if set, is notified of each load, prove and verify, e.g. (*metrics.Phases).Observe
+	Observe func(phase, circuit string, d time.Duration, err error)
+
+	// loads shares the load of a circuit between its concurrent first requests
+	loads singleflight.Group
+
+	lock   sync.Mutex
+	keys   map[string]*Keys
+	pools  map[string]*Pool
+	closed bool
+}
+
+// NewServer returns a Server proving the circuits read by load, with workers concurrent proofs
+// and up to queueSize waiting requests per circuit
+func NewServer(load Loader, workers, queueSize int) *Server {
+	return &Server{
+		load:      load,
+		workers:   workers,
+		queueSize: queueSize,
+		keys:      make(map[string]*Keys),
+		pools:     make(map[string]*Pool),
+	}
+}
+
+// Close stops the provers
+func (s *Server) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+	for _, pool := range s.pools {
+		pool.Close()
+	}
+}
+
+// Prove implements ProverServer
+// gnark solves the witness and runs the MSMs inside groth16.Prove, without reporting: the prove
+// phase is sent every second while it runs, see ProveWithProgress.
+func (s *Server) Prove(req *ProveRequest, stream ProveStream) error {
+	start := time.Now()
+	var sendErr error
+	send := func(e *ProveEvent) error {
+		e.ElapsedMs = uint32(time.Since(start) / time.Millisecond)
+		sendErr = stream.Send(e)
+		return sendErr
+	}
+
+	if err := send(&ProveEvent{Phase: PhaseLoad}); err != nil {
+		return err
+	}
+	_, pool, err := s.get(req.Circuit)
+	if err != nil {
+		return err
+	}
+
+	if err := send(&ProveEvent{Phase: PhaseWitness}); err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	prove := func() (groth16.Proof, error) {
+		return pool.ReadAndProve(ctx, bytes.NewReader(req.Witness))
+	}
+	if len(req.WitnessJSON) != 0 {
+		witness, err := circuits.FromJSON(req.Circuit, req.WitnessJSON)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		prove = func() (groth16.Proof, error) {
+			return pool.Prove(ctx, witness)
+		}
+	}
+
+	proof, err := ProveWithProgress(ctx, ReporterFunc(send), func() (groth16.Proof, error) {
+		start := time.Now()
+		proof, err := prove()
+		s.observe("prove", req.Circuit, start, err)
+		return proof, err
+	})
+	switch {
+	case sendErr != nil:
+		return sendErr
+	case ctx.Err() != nil:
+		// the pool drops the job if it is still queued
+		return ctx.Err()
+	case err == ErrPoolClosed:
+		return status.Error(codes.Unavailable, err.Error())
+	case err != nil:
+		return status.Errorf(codes.InvalidArgument, "prove: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return err
+	}
+	return send(&ProveEvent{Phase: PhaseDone, Proof: buf.Bytes()})
+}
+
+// Verify implements ProverServer; an invalid proof is not an error
+func (s *Server) Verify(ctx context.Context, req *VerifyRequest) (*VerifyResponse, error) {
+	keys, _, err := s.get(req.Circuit)
+	if err != nil {
+		return nil, err
+	}
+	// proofs read from files written by the workshop binary have a header
+	proof := groth16.NewProof(ecc.BN254)
+	expected := artifacts.Header{GnarkVersion: artifacts.GnarkVersion(), Curve: ecc.BN254, Backend: backend.GROTH16}
+	if _, err := artifacts.Read(bytes.NewReader(req.Proof), expected, proof); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid proof: %v", err)
+	}
+	start := time.Now()
+	err = groth16.ReadAndVerify(proof, keys.VK, bytes.NewReader(req.PublicWitness))
+	s.observe("verify", req.Circuit, start, err)
+	if err != nil {
+		return &VerifyResponse{Error: err.Error()}, nil
+	}
+	return &VerifyResponse{Valid: true}, nil
+}
+
+// GetVerifyingKey implements ProverServer
+func (s *Server) GetVerifyingKey(ctx context.Context, req *GetVerifyingKeyRequest) (*VerifyingKey, error) {
+	keys, _, err := s.get(req.Circuit)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := keys.VK.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return &VerifyingKey{Circuit: req.Circuit, VK: buf.Bytes()}, nil
+}
+
+// get returns the keys and the pool of the circuit name, loading it on first use
+func (s *Server) get(name string) (*Keys, *Pool, error) {
+	if keys, pool, ok := s.loaded(name); ok {
+		return keys, pool, nil
+	}
+	_, err, _ := s.loads.Do(name, func() (interface{}, error) {
+		// a load that completed since the check above is not shared anymore
+		if _, _, ok := s.loaded(name); ok {
+			return nil, nil
+		}
+		start := time.Now()
+		keys, err := s.load(name)
+		if err != nil {
+			// don't create a series per name sent by clients
+			label := name
+			if _, errGet := circuits.Get(name); errGet != nil {
+				label = "unknown"
+			}
+			s.observe("load", label, start, err)
+			return nil, status.Errorf(codes.NotFound, "circuit %q: %v", name, err)
+		}
+		s.observe("load", name, start, nil)
+
+		pool := NewPool(keys.R1CS, keys.PK, keys.VK, s.workers, s.queueSize)
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if s.closed {
+			pool.Close()
+			return nil, status.Error(codes.Unavailable, ErrPoolClosed.Error())
+		}
+		s.keys[name], s.pools[name] = keys, pool
+		return nil, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	keys, pool, _ := s.loaded(name)
+	return keys, pool, nil
+}
+
+// loaded returns the keys and the pool of the circuit name, if loaded
+func (s *Server) loaded(name string) (*Keys, *Pool, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	keys, ok := s.keys[name]
+	return keys, s.pools[name], ok
+}
+
+func (s *Server) observe(phase, circuit string, start time.Time, err error) {
+	if s.Observe != nil {
+		s.Observe(phase, circuit, time.Since(start), err)
+	}
+}
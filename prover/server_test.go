@@ -0,0 +1,90 @@
+package prover
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestServerLoad checks that a circuit is loaded once for its concurrent first requests, without
+// holding up the requests for the other circuits
+func TestServerLoad(t *testing.T) {
+	var lock sync.Mutex
+	loads := make(map[string]int)
+	release := make(chan struct{})
+	srv := NewServer(func(name string) (*Keys, error) {
+		lock.Lock()
+		loads[name]++
+		lock.Unlock()
+		switch name {
+		case "slow":
+			<-release
+		case "unknown":
+			return nil, errors.New("no artifacts")
+		}
+		return &Keys{}, nil
+	}, 1, 1)
+	defer srv.Close()
+
+	const requests = 8
+	var wg sync.WaitGroup
+	pools := make([]*Pool, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, pool, err := srv.get("slow")
+			if err != nil {
+				t.Error(err)
+			}
+			pools[i] = pool
+		}(i)
+	}
+
+	// the slow load doesn't hold up another circuit
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, _, err := srv.get("fast"); err != nil {
+			t.Error(err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("loading a circuit waits for the load of another")
+	}
+
+	close(release)
+	wg.Wait()
+	for i := range pools {
+		if pools[i] == nil || pools[i] != pools[0] {
+			t.Fatal("the concurrent requests got different pools")
+		}
+	}
+	if _, _, err := srv.get("slow"); err != nil {
+		t.Fatal(err)
+	}
+	if loads["slow"] != 1 || loads["fast"] != 1 {
+		t.Fatalf("loads: %v, expected one per circuit", loads)
+	}
+
+	// failed loads are not cached
+	for i := 0; i < 2; i++ {
+		if _, _, err := srv.get("unknown"); status.Code(err) != codes.NotFound {
+			t.Fatalf("got %v, expected NotFound", err)
+		}
+	}
+	if loads["unknown"] != 2 {
+		t.Fatalf("%d loads of a failing circuit, expected 2", loads["unknown"])
+	}
+
+	srv.Close()
+	if _, _, err := srv.get("after close"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v after Close, expected Unavailable", err)
+	}
+}
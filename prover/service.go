@@ -0,0 +1,159 @@
+package prover
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+)
+
+// serviceName is the gRPC name of the Prover service, which has three methods:
+//
+//	/gnarkworkshop.prover.Prover/Prove            ProveRequest → stream of ProveEvent
+//	/gnarkworkshop.prover.Prover/Verify           VerifyRequest → VerifyResponse
+//	/gnarkworkshop.prover.Prover/GetVerifyingKey  GetVerifyingKeyRequest → VerifyingKey
+//
+// There is no .proto: the messages are the JSON documents of the types of messages.go, sent with
+// the "json" codec (content-subtype application/grpc+json, see Codec).
+const serviceName = "gnarkworkshop.prover.Prover"
+
+// ProverServer is the server API of the Prover service
+type ProverServer interface {
+	Prove(*ProveRequest, ProveStream) error
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	GetVerifyingKey(context.Context, *GetVerifyingKeyRequest) (*VerifyingKey, error)
+}
+
+// ProveStream sends the progress events of a Prove call
+type ProveStream interface {
+	Send(*ProveEvent) error
+	Context() context.Context
+}
+
+// RegisterProverServer registers srv on s
+func RegisterProverServer(s *grpc.Server, srv ProverServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+// serviceDesc describes the Prover service to grpc, in place of generated code
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*ProverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Verify", Handler: verifyHandler},
+		{MethodName: "GetVerifyingKey", Handler: getVerifyingKeyHandler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Prove", Handler: proveHandler, ServerStreams: true},
+	},
+}
+
+func proveHandler(srv interface{}, stream grpc.ServerStream) error {
+	req := new(ProveRequest)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	return srv.(ProverServer).Prove(req, proveStream{stream})
+}
+
+type proveStream struct {
+	grpc.ServerStream
+}
+
+func (s proveStream) Send(e *ProveEvent) error {
+	return s.ServerStream.SendMsg(e)
+}
+
+func verifyHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := new(VerifyRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProverServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	if interceptor == nil {
+		return handler(ctx, req)
+	}
+	return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Verify"}, handler)
+}
+
+func getVerifyingKeyHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := new(GetVerifyingKeyRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProverServer).GetVerifyingKey(ctx, req.(*GetVerifyingKeyRequest))
+	}
+	if interceptor == nil {
+		return handler(ctx, req)
+	}
+	return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/GetVerifyingKey"}, handler)
+}
+
+// Client calls a remote Prover service
+type Client struct {
+	cc *grpc.ClientConn
+}
+
+// Dial connects to the Prover service at target (without TLS: the workshop runs on a local network)
+func Dial(target string) (*Client, error) {
+	cc, err := grpc.Dial(target, grpc.WithInsecure(), grpc.WithDefaultCallOptions(grpc.CallContentSubtype(Codec)))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{cc: cc}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.cc.Close()
+}
+
+// Prove requests a proof, calling progress on each event but the last, which is returned
+func (c *Client) Prove(ctx context.Context, req *ProveRequest, progress func(*ProveEvent)) (*ProveEvent, error) {
+	stream, err := c.cc.NewStream(ctx, &serviceDesc.Streams[0], "/"+serviceName+"/Prove")
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	for {
+		e := new(ProveEvent)
+		if err := stream.RecvMsg(e); err != nil {
+			if err == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if e.Phase == PhaseDone {
+			return e, nil
+		}
+		if progress != nil {
+			progress(e)
+		}
+	}
+}
+
+// Verify checks a proof remotely
+func (c *Client) Verify(ctx context.Context, req *VerifyRequest) (*VerifyResponse, error) {
+	resp := new(VerifyResponse)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/Verify", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetVerifyingKey returns the serialized verifying key of a circuit
+func (c *Client) GetVerifyingKey(ctx context.Context, req *GetVerifyingKeyRequest) (*VerifyingKey, error) {
+	resp := new(VerifyingKey)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/GetVerifyingKey", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,147 @@
+package prover
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	gnarkwitness "github.com/consensys/gnark/backend/witness"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serve starts a Prover service over a bundle of testCircuit and returns a client of it
+func serve(t *testing.T) *Client {
+	t.Helper()
+	t.Setenv(artifacts.TrustedKeyEnv, "")
+	fsys, _ := bundle(t, &cubeCircuit{})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	srv := NewServer(FSLoader(fsys), 1, 1)
+	RegisterProverServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(func() {
+		s.Stop()
+		srv.Close()
+	})
+	client, err := Dial(lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func cubeWitness(x, y int) *cubeCircuit {
+	var w cubeCircuit
+	w.X.Assign(x)
+	w.Y.Assign(y)
+	return &w
+}
+
+func TestService(t *testing.T) {
+	client := serve(t)
+	ctx := context.Background()
+
+	var full, public bytes.Buffer
+	if _, err := gnarkwitness.WriteFullTo(&full, ecc.BN254, cubeWitness(3, 27)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gnarkwitness.WritePublicTo(&public, ecc.BN254, cubeWitness(3, 27)); err != nil {
+		t.Fatal(err)
+	}
+	var wrongPublic bytes.Buffer
+	if _, err := gnarkwitness.WritePublicTo(&wrongPublic, ecc.BN254, cubeWitness(2, 8)); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.GetVerifyingKey(ctx, &GetVerifyingKeyRequest{Circuit: testCircuit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if _, err := vk.ReadFrom(bytes.NewReader(resp.VK)); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, req := range map[string]*ProveRequest{
+		"binary witness": {Circuit: testCircuit, Witness: full.Bytes()},
+		"JSON witness":   {Circuit: testCircuit, WitnessJSON: json.RawMessage(`{"X": 3, "Y": "27"}`)},
+	} {
+		var phases []string
+		done, err := client.Prove(ctx, req, func(e *ProveEvent) { phases = append(phases, e.Phase) })
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(phases) < 2 || phases[0] != PhaseLoad || phases[1] != PhaseWitness {
+			t.Errorf("%s: phases %v", name, phases)
+		}
+		proof := groth16.NewProof(ecc.BN254)
+		if _, err := proof.ReadFrom(bytes.NewReader(done.Proof)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := groth16.Verify(proof, vk, cubeWitness(3, 27)); err != nil {
+			t.Fatalf("%s: invalid proof: %v", name, err)
+		}
+
+		verified, err := client.Verify(ctx, &VerifyRequest{Circuit: testCircuit, Proof: done.Proof, PublicWitness: public.Bytes()})
+		if err != nil || !verified.Valid {
+			t.Fatalf("%s: Verify: %+v, %v", name, verified, err)
+		}
+		verified, err = client.Verify(ctx, &VerifyRequest{Circuit: testCircuit, Proof: done.Proof, PublicWitness: wrongPublic.Bytes()})
+		if err != nil || verified.Valid || verified.Error == "" {
+			t.Fatalf("%s: Verify with another public witness: %+v, %v", name, verified, err)
+		}
+	}
+}
+
+func TestServiceErrors(t *testing.T) {
+	client := serve(t)
+	ctx := context.Background()
+	for name, tc := range map[string]struct {
+		call func() error
+		code codes.Code
+	}{
+		"unknown circuit": {
+			call: func() error {
+				_, err := client.GetVerifyingKey(ctx, &GetVerifyingKeyRequest{Circuit: "unknown"})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		"invalid JSON witness": {
+			call: func() error {
+				_, err := client.Prove(ctx, &ProveRequest{Circuit: testCircuit, WitnessJSON: json.RawMessage(`{"X": 3}`)}, nil)
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		"unsatisfied witness": {
+			call: func() error {
+				_, err := client.Prove(ctx, &ProveRequest{Circuit: testCircuit, WitnessJSON: json.RawMessage(`{"X": 3, "Y": 28}`)}, nil)
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		"invalid proof": {
+			call: func() error {
+				_, err := client.Verify(ctx, &VerifyRequest{Circuit: testCircuit, Proof: []byte("proof")})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+	} {
+		if err := tc.call(); status.Code(err) != tc.code {
+			t.Errorf("%s: got %v, expected %s", name, err, tc.code)
+		}
+	}
+}
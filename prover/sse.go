@@ -0,0 +1,74 @@
+package prover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/status"
+)
+
+// SSEHandler serves Prove over HTTP for browsers and clients without gRPC: a POST of a JSON
+// ProveRequest is answered with a text/event-stream of ProveEvents, one event per message,
+// named after its phase. A failed proof ends the stream with an "error" event, {"error": "..."}.
+//
+//	curl -N -d '{"circuit": "mimc", "witnessJson": {...}}' http://localhost:9092/prove
+func SSEHandler(s *Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST a ProveRequest", http.StatusMethodNotAllowed)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		var req ProveRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		stream := &sseStream{w: w, flusher: flusher, ctx: r.Context()}
+		if err := s.Prove(&req, stream); err != nil && r.Context().Err() == nil {
+			msg := err.Error()
+			if st, ok := status.FromError(err); ok {
+				msg = st.Message()
+			}
+			stream.event("error", struct {
+				Error string `json:"error"`
+			}{msg})
+		}
+	})
+}
+
+// sseStream is the ProveStream of SSEHandler
+type sseStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	ctx     context.Context
+}
+
+func (s *sseStream) Send(e *ProveEvent) error {
+	return s.event(e.Phase, e)
+}
+
+func (s *sseStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *sseStream) event(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", name, data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build embedbundle
+// +build embedbundle
+
+package main
+
+import (
+	"embed"
+
+	"github.com/gbotrel/gnark-workshop/prover"
+)
+
+//go:embed bundle
+var bundleFS embed.FS
+
+// embedded is the bundle directory baked into the binary, nil if nothing was bundled
+var embedded = prover.EmbeddedBundle(bundleFS, "bundle")
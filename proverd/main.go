@@ -0,0 +1,77 @@
+// Command proverd is the slim entrypoint of the proving service, for containers: it serves the
+// gRPC Prover service (see package prover) over a bundle of artifacts written by the bundle
+// command, either mounted (-bundle) or embedded in the binary (built with -tags embedbundle).
+//
+//	go run . bundle -all
+//	go build -tags embedbundle -o proverd ./proverd
+package main
+
+import (
+	"flag"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+
+	_ "github.com/gbotrel/gnark-workshop/circuits/all" // registers the workshop circuits
+	"github.com/gbotrel/gnark-workshop/metrics"
+	"github.com/gbotrel/gnark-workshop/prover"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	fAddr := flag.String("addr", ":9090", "address to listen on")
+	fBundle := flag.String("bundle", "", "bundle directory; the embedded bundle if not set")
+	fWorkers := flag.Int("workers", 0, "concurrent proofs per circuit (each proof is itself parallel, on -procs cores), -procs/4+1 if not set")
+	fProcs := flag.Int("procs", 0, "if set, number of cores the proofs run on, as GOMAXPROCS (pin the container with --cpuset-cpus)")
+	fQueue := flag.Int("queue", 16, "waiting proof requests per circuit, further requests block")
+	fMetrics := flag.String("metrics-addr", "", "if set, serve Prometheus metrics on http://<metrics-addr>/metrics")
+	fSSE := flag.String("sse-addr", "", "if set, serve Prove as server-sent events on http://<sse-addr>/prove")
+	flag.Parse()
+	if *fProcs != 0 {
+		runtime.GOMAXPROCS(*fProcs)
+	}
+	if *fWorkers == 0 {
+		*fWorkers = runtime.GOMAXPROCS(0)/4 + 1
+	}
+
+	var bundle fs.FS
+	switch {
+	case *fBundle != "":
+		bundle = os.DirFS(*fBundle)
+	case embedded != nil:
+		bundle = embedded
+	default:
+		log.Fatal("no embedded bundle (run bundle, then build with -tags embedbundle), set -bundle")
+	}
+
+	lis, err := net.Listen("tcp", *fAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s := grpc.NewServer()
+	srv := prover.NewServer(prover.FSLoader(bundle), *fWorkers, *fQueue)
+	if *fMetrics != "" {
+		phases := metrics.NewPhases()
+		srv.Observe = phases.Observe
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", phases)
+		go func() {
+			log.Fatal(http.ListenAndServe(*fMetrics, mux))
+		}()
+		log.Printf("metrics on http://%s/metrics", *fMetrics)
+	}
+	if *fSSE != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/prove", prover.SSEHandler(srv))
+		go func() {
+			log.Fatal(http.ListenAndServe(*fSSE, mux))
+		}()
+		log.Printf("progress events on http://%s/prove", *fSSE)
+	}
+	prover.RegisterProverServer(s, srv)
+	log.Printf("prover listening on %s", *fAddr)
+	log.Fatal(s.Serve(lis))
+}
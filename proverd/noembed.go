@@ -0,0 +1,9 @@
+//go:build !embedbundle
+// +build !embedbundle
+
+package main
+
+import "io/fs"
+
+// embedded is nil without the embedbundle tag: the bundle is mounted, see -bundle
+var embedded fs.FS
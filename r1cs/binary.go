@@ -0,0 +1,84 @@
+package r1cs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/big"
+)
+
+// circom .r1cs section types
+const (
+	sectionHeader      = 1
+	sectionConstraints = 2
+	sectionWireToLabel = 3
+)
+
+// WriteBinary writes r in circom's .r1cs format: "r1cs", version 1, then the header,
+// constraints and wire to label sections, as little endian integers and field elements
+func (r *R1CS) WriteBinary(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("r1cs")
+	writeUint32(bw, 1) // version
+	writeUint32(bw, 3) // number of sections
+
+	prime, _ := new(big.Int).SetString(r.Prime, 10)
+	var header bytes.Buffer
+	writeUint32(&header, uint32(r.N8))
+	header.Write(r.element(prime))
+	for _, n := range []int{r.NVars, r.NOutputs, r.NPubInputs, r.NPrvInputs} {
+		writeUint32(&header, uint32(n))
+	}
+	writeUint64(&header, uint64(r.NLabels))
+	writeUint32(&header, uint32(len(r.Constraints)))
+	writeSection(bw, sectionHeader, header.Bytes())
+
+	var constraints bytes.Buffer
+	for _, c := range r.Constraints {
+		for _, lc := range c {
+			writeUint32(&constraints, uint32(len(lc)))
+			for _, t := range lc {
+				writeUint32(&constraints, uint32(t.Wire))
+				constraints.Write(r.element(t.Coeff))
+			}
+		}
+	}
+	writeSection(bw, sectionConstraints, constraints.Bytes())
+
+	var labels bytes.Buffer
+	for _, label := range r.Map {
+		writeUint64(&labels, uint64(label))
+	}
+	writeSection(bw, sectionWireToLabel, labels.Bytes())
+
+	return bw.Flush()
+}
+
+// element returns the little endian encoding of v on N8 bytes
+func (r *R1CS) element(v *big.Int) []byte {
+	b := make([]byte, r.N8)
+	v.FillBytes(b)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+func writeSection(w io.Writer, sectionType uint32, data []byte) {
+	writeUint32(w, sectionType)
+	writeUint64(w, uint64(len(data)))
+	w.Write(data)
+}
+
+func writeUint32(w io.Writer, v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	w.Write(b[:])
+}
+
+func writeUint64(w io.Writer, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	w.Write(b[:])
+}
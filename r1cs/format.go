@@ -0,0 +1,74 @@
+package r1cs
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// WireName returns the name of wire, or w<index> for the internal wires
+func (r *R1CS) WireName(wire int) string {
+	if wire < len(r.Names) && r.Names[wire] != "" {
+		return r.Names[wire]
+	}
+	return fmt.Sprintf("w%d", wire)
+}
+
+// IsInput returns true if wire is the constant wire or a public or secret input
+func (r *R1CS) IsInput(wire int) bool {
+	return wire <= r.NPubInputs+r.NPrvInputs
+}
+
+// Format returns the constraint i with wire names, e.g. (Hash - 2·x) · (one) = (w12)
+func (r *R1CS) Format(i int) string {
+	c := r.Constraints[i]
+	return fmt.Sprintf("(%s) · (%s) = (%s)", r.formatLC(c[0]), r.formatLC(c[1]), r.formatLC(c[2]))
+}
+
+// Wires returns the wires of constraint i, in increasing order, without duplicates
+func (r *R1CS) Wires(i int) []int {
+	seen := make(map[int]bool)
+	var wires []int
+	for _, lc := range r.Constraints[i] {
+		for _, t := range lc {
+			if !seen[t.Wire] {
+				seen[t.Wire] = true
+				wires = append(wires, t.Wire)
+			}
+		}
+	}
+	sort.Ints(wires)
+	return wires
+}
+
+func (r *R1CS) formatLC(lc LinearCombination) string {
+	if len(lc) == 0 {
+		return "0"
+	}
+	var sb strings.Builder
+	half := new(big.Int).Rsh(fr.Modulus(), 1)
+	for k, t := range lc {
+		// coefficients above p/2 read better as negative numbers
+		coeff, negative := t.Coeff, false
+		if coeff.Cmp(half) > 0 {
+			coeff, negative = new(big.Int).Sub(fr.Modulus(), coeff), true
+		}
+		switch {
+		case k == 0 && negative:
+			sb.WriteString("-")
+		case k != 0 && negative:
+			sb.WriteString(" - ")
+		case k != 0:
+			sb.WriteString(" + ")
+		}
+		if coeff.Cmp(big.NewInt(1)) != 0 {
+			sb.WriteString(coeff.String())
+			sb.WriteString("·")
+		}
+		sb.WriteString(r.WireName(t.Wire))
+	}
+	return sb.String()
+}
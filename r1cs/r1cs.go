@@ -0,0 +1,166 @@
+// Package r1cs exports compiled BN254 constraint systems in the formats of circom and snarkjs,
+// for external tooling, debugging and constraint audits.
+//
+// Wires are numbered as in gnark's solver: 0 is the constant 1, then the public inputs, the
+// secret inputs and the internal wires. circom numbers its wires the same way (with no outputs),
+// so the exports read as circom constraint systems:
+//
+//   - JSON is the layout of `snarkjs r1cs export json`, with the names of the input wires
+//   - binary is circom's .r1cs format (version 1: header, constraints and wire to label sections)
+//
+// Each constraint is a triple of linear combinations [A, B, C], asserting A·B = C.
+package r1cs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrLayout is returned for constraint systems this package can't read
+var ErrLayout = errors.New("r1cs: unsupported constraint system (expected a bn254 R1CS from gnark v0.5)")
+
+// Term is coeff·wire
+type Term struct {
+	Wire  int
+	Coeff *big.Int
+}
+
+// LinearCombination is a sum of terms; it is encoded in JSON as an object mapping the wire
+// indices to the decimal coefficients, as snarkjs does
+type LinearCombination []Term
+
+// MarshalJSON implements json.Marshaler
+func (lc LinearCombination) MarshalJSON() ([]byte, error) {
+	m := make(map[string]string, len(lc))
+	for _, t := range lc {
+		m[strconv.Itoa(t.Wire)] = t.Coeff.String()
+	}
+	return json.Marshal(m)
+}
+
+// R1CS is an exported constraint system
+type R1CS struct {
+	// N8 is the size of a field element in bytes, and Prime the field modulus in decimal
+	N8    int    `json:"n8"`
+	Prime string `json:"prime"`
+	// NVars is the number of wires; NOutputs is always 0
+	NVars       int `json:"nVars"`
+	NOutputs    int `json:"nOutputs"`
+	NPubInputs  int `json:"nPubInputs"`
+	NPrvInputs  int `json:"nPrvInputs"`
+	NLabels     int `json:"nLabels"`
+	NConstraint int `json:"nConstraints"`
+
+	Constraints []Constraint `json:"constraints"`
+	// Map is the label of each wire (snarkjs' wire to label map); labels are the wire indices
+	Map []int `json:"map"`
+	// Names are the names of the wires: "one", then the circuit variables (see circuits.Wires),
+	// and "" for the internal wires
+	Names []string `json:"names"`
+}
+
+// Constraint is [A, B, C], asserting A·B = C
+type Constraint [3]LinearCombination
+
+// FromCompiled exports ccs; public and secret are the names of the input wires (see circuits.Wires)
+func FromCompiled(ccs frontend.CompiledConstraintSystem, public, secret []string) (*R1CS, error) {
+	nbInternal, nbSecret, nbPublic := ccs.GetNbVariables()
+	// nbPublic counts the constant wire
+	if len(public) != nbPublic-1 || len(secret) != nbSecret {
+		return nil, fmt.Errorf("r1cs: %d public and %d secret names for %d public and %d secret wires",
+			len(public), len(secret), nbPublic-1, nbSecret)
+	}
+	nbWires := nbInternal + nbSecret + nbPublic
+
+	v := reflect.ValueOf(ccs)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, ErrLayout
+	}
+	fConstraints, fCoefficients := v.FieldByName("Constraints"), v.FieldByName("Coefficients")
+	if !fConstraints.IsValid() || !fCoefficients.IsValid() || fConstraints.Kind() != reflect.Slice {
+		return nil, ErrLayout
+	}
+	coefficients, ok := fCoefficients.Interface().([]fr.Element)
+	if !ok {
+		return nil, ErrLayout
+	}
+
+	r := &R1CS{
+		N8:          fr.Bytes,
+		Prime:       fr.Modulus().String(),
+		NVars:       nbWires,
+		NPubInputs:  nbPublic - 1,
+		NPrvInputs:  nbSecret,
+		NLabels:     nbWires,
+		NConstraint: fConstraints.Len(),
+		Constraints: make([]Constraint, fConstraints.Len()),
+		Map:         make([]int, nbWires),
+		Names:       make([]string, nbWires),
+	}
+	for i := range r.Map {
+		r.Map[i] = i
+	}
+	r.Names[0] = "one"
+	copy(r.Names[1:], public)
+	copy(r.Names[nbPublic:], secret)
+
+	for i := range r.Constraints {
+		c := fConstraints.Index(i)
+		for j, name := range []string{"L", "R", "O"} {
+			lc := c.FieldByName(name)
+			if !lc.IsValid() || lc.Kind() != reflect.Slice {
+				return nil, ErrLayout
+			}
+			var err error
+			if r.Constraints[i][j], err = readLinearExpression(lc, coefficients, nbWires); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return r, nil
+}
+
+// readLinearExpression reads a gnark linear expression, a slice of packed terms
+func readLinearExpression(lc reflect.Value, coefficients []fr.Element, nbWires int) (LinearCombination, error) {
+	// wires may appear more than once in a gnark linear expression
+	coeffs := make(map[int]*big.Int, lc.Len())
+	for k := 0; k < lc.Len(); k++ {
+		unpack := lc.Index(k).MethodByName("Unpack")
+		if !unpack.IsValid() {
+			return nil, ErrLayout
+		}
+		out := unpack.Call(nil)
+		if len(out) != 3 || out[0].Kind() != reflect.Int || out[1].Kind() != reflect.Int {
+			return nil, ErrLayout
+		}
+		coeffID, wire := int(out[0].Int()), int(out[1].Int())
+		if coeffID >= len(coefficients) || wire >= nbWires {
+			return nil, ErrLayout
+		}
+		coeff := coefficients[coeffID].ToBigIntRegular(new(big.Int))
+		if prev, ok := coeffs[wire]; ok {
+			coeff.Add(coeff, prev).Mod(coeff, fr.Modulus())
+		}
+		coeffs[wire] = coeff
+	}
+
+	terms := make(LinearCombination, 0, len(coeffs))
+	for wire, coeff := range coeffs {
+		if coeff.Sign() != 0 {
+			terms = append(terms, Term{Wire: wire, Coeff: coeff})
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool { return terms[i].Wire < terms[j].Wire })
+	return terms, nil
+}
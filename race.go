@@ -0,0 +1,245 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/race"
+)
+
+// runRaceStart deploys a Race over the mimc hashes of the facilitator secrets
+// The secrets stay with the facilitator, only their hashes are published on-chain.
+func runRaceStart(args []string) {
+	fs := flag.NewFlagSet("race-start", flag.ExitOnError)
+	fRPCURL := fs.String("rpc-url", "http://localhost:8545", "JSON-RPC endpoint of the node")
+	fSigner := addSignerFlags(fs, "(funded) deployer account")
+	fChainID := fs.Int64("chain-id", 0, "chain ID, queried from the node if not set")
+	fAddress := fs.String("verifier", "", "mimc verifier contract address, read from the deployments file if not set")
+	fDeployments := fs.String("deployments", deploymentsPath, "deployments file")
+	fSecrets := fs.String("secrets", "", "comma separated secrets whose hashes are the race targets")
+	fDuration := fs.Duration("duration", 30*time.Minute, "race duration")
+	assertNoError(fs.Parse(args))
+	if *fCircuit != defaultCircuit {
+		log.Fatalf("race: only the %s circuit can be raced", defaultCircuit)
+	}
+
+	var targets []*big.Int
+	for _, secret := range strings.Split(*fSecrets, ",") {
+		if secret == "" {
+			continue
+		}
+		hash, err := circuit.Hash([]byte(secret))
+		assertNoError(err)
+		targets = append(targets, new(big.Int).SetBytes(hash))
+	}
+	if len(targets) == 0 {
+		log.Fatal("race-start: -secrets is required")
+	}
+
+	signer, closeSigner := fSigner.signer()
+	defer closeSigner()
+	ctx := mainCtx
+	client, chainID := dial(ctx, *fRPCURL, *fChainID)
+	defer client.Close()
+	verifier := verifierAddress(*fAddress, *fDeployments, chainID)
+
+	auth := ethereum.NewTransactor(signer, chainID)
+	auth.Context = ctx
+
+	deadline := time.Now().Add(*fDuration)
+	r, err := race.Deploy(auth, client, verifier, targets, deadline)
+	assertNoError(err)
+	log.Printf("race deployed at %s: %d targets, ends at %s", r.Address.Hex(), len(targets), deadline.Format(time.Kitchen))
+	log.Printf("attendees run: go run . race -rpc-url %s -race %s -secret <guess>", *fRPCURL, r.Address.Hex())
+	emit("race-start", raceStartResult{Race: r.Address, Targets: decimals(targets), Deadline: deadline.Unix()})
+}
+
+// runRace proves the knowledge of a guessed pre-image and claims the matching target
+func runRace(args []string) {
+	fs := flag.NewFlagSet("race", flag.ExitOnError)
+	fRPCURL := fs.String("rpc-url", "http://localhost:8545", "JSON-RPC endpoint of the node")
+	fSigner := addSignerFlags(fs, "(funded) sender")
+	fChainID := fs.Int64("chain-id", 0, "chain ID, queried from the node if not set")
+	fRace := fs.String("race", "", "race contract address")
+	fSecret := fs.String("secret", "", "guessed pre-image of one of the targets (or -secret-stdin, or "+SecretEnv+", kept out of the shell history)")
+	fR1CS := fs.String("r1cs", files.r1cs, "mimc R1CS file (e.g. downloaded with join)")
+	fPK := fs.String("pk", files.pk, "mimc proving key file (e.g. downloaded with join)")
+	assertNoError(fs.Parse(args))
+
+	signer, closeSigner := fSigner.signer()
+	defer closeSigner()
+	ctx := mainCtx
+	client, chainID := dial(ctx, *fRPCURL, *fChainID)
+	defer client.Close()
+	r, err := race.Bind(raceAddress(*fRace), client)
+	assertNoError(err)
+
+	secret := []byte(*fSecret)
+	if *fSecret == "" {
+		var ok bool
+		secret, ok, err = readSecret()
+		assertNoError(err)
+		if !ok {
+			log.Fatal("race: -secret, -secret-stdin or " + SecretEnv + " is required")
+		}
+	}
+	defer wipe(secret)
+
+	// check the guess before spending time proving
+	hash, err := circuit.Hash(secret)
+	assertNoError(err)
+	target := new(big.Int).SetBytes(hash)
+	targets, err := r.Targets(&bind.CallOpts{Context: ctx})
+	assertNoError(err)
+	// constant time, and no early exit: the time taken doesn't tell which target the secret opens
+	found := false
+	for _, t := range targets {
+		found = circuit.IsPreImage(secret, t.FillBytes(make([]byte, len(hash)))) || found
+	}
+	if !found {
+		log.Fatal("wrong guess: its mimc hash is not a target")
+	}
+	solver, err := r.Solver(&bind.CallOpts{Context: ctx}, target)
+	assertNoError(err)
+	if solver != (common.Address{}) {
+		log.Fatalf("too late: target already solved by %s", solver.Hex())
+	}
+
+	r1cs := groth16.NewCS(ecc.BN254)
+	pk := groth16.NewProvingKey(ecc.BN254)
+	deserialize(r1cs, *fR1CS)
+	deserialize(pk, *fPK)
+	witness, err := circuit.NewWitness(secret)
+	assertNoError(err)
+	log.Println("creating proof")
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	assertNoError(err)
+
+	auth := ethereum.NewTransactor(signer, chainID)
+	auth.Context = ctx
+	tx, err := r.Submit(auth, target, proof)
+	assertNoError(err)
+	log.Println("waiting for transaction", tx.Hash().Hex())
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	assertNoError(err)
+	if receipt.Status != 1 {
+		log.Fatal("submission reverted: someone was faster, or the race is over")
+	}
+	log.Printf("target claimed in block %d (gas used: %d)", receipt.BlockNumber, receipt.GasUsed)
+	emit("race", raceResult{Race: r.Address, Target: target.String(), Transaction: newTxResult(receipt)})
+}
+
+// runRaceBoard prints the leaderboard, indexed from the Solved events
+func runRaceBoard(args []string) {
+	fs := flag.NewFlagSet("race-board", flag.ExitOnError)
+	fRPCURL := fs.String("rpc-url", "http://localhost:8545", "JSON-RPC endpoint of the node")
+	fRace := fs.String("race", "", "race contract address")
+	fWatch := fs.Duration("watch", 0, "refresh interval, print once if not set")
+	assertNoError(fs.Parse(args))
+
+	ctx := mainCtx
+	client, _ := dial(ctx, *fRPCURL, 0)
+	defer client.Close()
+	r, err := race.Bind(raceAddress(*fRace), client)
+	assertNoError(err)
+
+	for {
+		targets, err := r.Targets(&bind.CallOpts{Context: ctx})
+		assertNoError(err)
+		deadline, err := r.Deadline(&bind.CallOpts{Context: ctx})
+		assertNoError(err)
+		solutions, err := r.Solutions(&bind.FilterOpts{Context: ctx})
+		assertNoError(err)
+
+		leaderboard := race.Leaderboard(solutions)
+		if jsonOutput() {
+			// one document per refresh
+			emit("race-board", raceBoardResult{
+				Race:        r.Address,
+				Targets:     len(targets),
+				Solved:      len(solutions),
+				Deadline:    deadline.Unix(),
+				Leaderboard: leaderboard,
+			})
+		} else {
+			// clear the terminal between refreshes
+			printLeaderboard(r.Address, len(targets), deadline, leaderboard, *fWatch != 0)
+		}
+
+		if *fWatch == 0 {
+			return
+		}
+		time.Sleep(*fWatch)
+	}
+}
+
+func printLeaderboard(address common.Address, nbTargets int, deadline time.Time, leaderboard []race.Score, clear bool) {
+	if clear {
+		fmt.Print("\033[H\033[2J")
+	}
+	remaining := time.Until(deadline).Round(time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	solved := 0
+	for _, score := range leaderboard {
+		solved += score.Solved
+	}
+	fmt.Printf("race %s: %d/%d targets solved, %s left\n\n", address.Hex(), solved, nbTargets, remaining)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "rank\tsolver\tsolved\tlast block")
+	for i, score := range leaderboard {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\n", i+1, score.Solver.Hex(), score.Solved, score.LastBlock)
+	}
+	assertNoError(w.Flush())
+}
+
+func raceAddress(address string) common.Address {
+	if !common.IsHexAddress(address) {
+		log.Fatalf("invalid race address %q", address)
+	}
+	return common.HexToAddress(address)
+}
+
+// raceStartResult is the JSON output of race-start; targets are decimal strings
+type raceStartResult struct {
+	Race     common.Address `json:"race"`
+	Targets  []string       `json:"targets"`
+	Deadline int64          `json:"deadline"`
+}
+
+// raceResult is the JSON output of race
+type raceResult struct {
+	Race        common.Address `json:"race"`
+	Target      string         `json:"target"`
+	Transaction *txResult      `json:"transaction"`
+}
+
+// raceBoardResult is the JSON output of race-board
+type raceBoardResult struct {
+	Race        common.Address `json:"race"`
+	Targets     int            `json:"targets"`
+	Solved      int            `json:"solved"`
+	Deadline    int64          `json:"deadline"`
+	Leaderboard []race.Score   `json:"leaderboard"`
+}
+
+func decimals(values []*big.Int) []string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = v.String()
+	}
+	return s
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+pragma solidity ^0.8.0;
+
+// IVerifier is the gnark exported verifier of the circuit.Circuit (mimc) circuit
+interface IVerifier {
+    function verifyProof(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c,
+        uint256[1] memory input
+    ) external view returns (bool r);
+}
+
+// Race is a time-boxed proving competition: the facilitator publishes target
+// hashes, the first valid pre-image knowledge proof for a target wins it.
+//
+// Proofs are not bound to msg.sender: a proof seen in the mempool can be
+// replayed by someone else. This is fine for a workshop, not for real stakes.
+contract Race {
+    IVerifier public immutable verifier;
+    uint256 public immutable deadline;
+
+    uint256[] private targets;
+    mapping(uint256 => bool) public isTarget;
+    mapping(uint256 => address) public solver;
+
+    event Started(uint256[] targets, uint256 deadline);
+    event Solved(uint256 indexed target, address indexed solver);
+
+    constructor(IVerifier _verifier, uint256[] memory _targets, uint256 _deadline) {
+        verifier = _verifier;
+        deadline = _deadline;
+        for (uint256 i = 0; i < _targets.length; i++) {
+            require(!isTarget[_targets[i]], "race-duplicate-target");
+            isTarget[_targets[i]] = true;
+            targets.push(_targets[i]);
+        }
+        emit Started(_targets, _deadline);
+    }
+
+    function targetList() external view returns (uint256[] memory) {
+        return targets;
+    }
+
+    // submit claims target with a proof of knowledge of its pre-image
+    function submit(
+        uint256 target,
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c
+    ) external {
+        require(block.timestamp <= deadline, "race-over");
+        require(isTarget[target], "race-unknown-target");
+        require(solver[target] == address(0), "race-already-solved");
+        require(verifier.verifyProof(a, b, c, [target]), "race-invalid-proof");
+
+        solver[target] = msg.sender;
+        emit Solved(target, msg.sender);
+    }
+}
@@ -0,0 +1,183 @@
+// Package race drives the Race contract, a time-boxed proving competition: the facilitator
+// publishes mimc target hashes, attendees race to submit pre-image knowledge proofs, and the
+// leaderboard is indexed from the Solved events.
+package race
+
+import (
+	_ "embed"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+//go:embed Race.sol
+var raceSol string
+
+// raceABI is the ABI of Race.sol, so that attendees can bind a race without solc
+const raceABI = `[
+	{"type":"function","name":"deadline","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"targetList","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256[]"}]},
+	{"type":"function","name":"solver","stateMutability":"view","inputs":[{"name":"","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"submit","stateMutability":"nonpayable","inputs":[{"name":"target","type":"uint256"},{"name":"a","type":"uint256[2]"},{"name":"b","type":"uint256[2][2]"},{"name":"c","type":"uint256[2]"}],"outputs":[]},
+	{"type":"event","name":"Started","anonymous":false,"inputs":[{"name":"targets","type":"uint256[]","indexed":false},{"name":"deadline","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Solved","anonymous":false,"inputs":[{"name":"target","type":"uint256","indexed":true},{"name":"solver","type":"address","indexed":true}]}
+]`
+
+// Race is a handle on a deployed Race contract
+type Race struct {
+	Address  common.Address
+	contract *bind.BoundContract
+}
+
+// Deploy deploys a Race over targets, settling against the mimc verifier deployed at verifier
+// Requires solc in PATH; caller is responsible for committing / mining the transaction.
+func Deploy(auth *bind.TransactOpts, backend bind.ContractBackend, verifier common.Address, targets []*big.Int, deadline time.Time) (*Race, error) {
+	address, contract, err := ethereum.DeployContract(auth, backend, raceSol, "Race", verifier, targets, big.NewInt(deadline.Unix()))
+	if err != nil {
+		return nil, err
+	}
+	return &Race{Address: address, contract: contract}, nil
+}
+
+// Bind returns a handle on the Race deployed at address
+func Bind(address common.Address, backend bind.ContractBackend) (*Race, error) {
+	parsed, err := abi.JSON(strings.NewReader(raceABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Race{Address: address, contract: bind.NewBoundContract(address, parsed, backend, backend, backend)}, nil
+}
+
+// Targets returns the published target hashes
+func (r *Race) Targets(opts *bind.CallOpts) ([]*big.Int, error) {
+	var out []interface{}
+	if err := r.contract.Call(opts, &out, "targetList"); err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new([]*big.Int)).(*[]*big.Int), nil
+}
+
+// Deadline returns the time after which submissions are refused
+func (r *Race) Deadline(opts *bind.CallOpts) (time.Time, error) {
+	var out []interface{}
+	if err := r.contract.Call(opts, &out, "deadline"); err != nil {
+		return time.Time{}, err
+	}
+	deadline := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	return time.Unix(deadline.Int64(), 0), nil
+}
+
+// Solver returns the winner of target, or the zero address if it is still open
+func (r *Race) Solver(opts *bind.CallOpts, target *big.Int) (common.Address, error) {
+	var out []interface{}
+	if err := r.contract.Call(opts, &out, "solver", target); err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// Submit claims target with a proof of knowledge of its mimc pre-image
+func (r *Race) Submit(auth *bind.TransactOpts, target *big.Int, proof groth16.Proof) (*types.Transaction, error) {
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.contract.Transact(auth, "submit", target, solidityInputs.A, solidityInputs.B, solidityInputs.C)
+}
+
+// Solution is a target won by Solver, as indexed from a Solved event
+type Solution struct {
+	Target      *big.Int
+	Solver      common.Address
+	BlockNumber uint64
+	TxHash      common.Hash
+}
+
+// Solutions indexes the Solved events, in chain order
+func (r *Race) Solutions(opts *bind.FilterOpts) ([]Solution, error) {
+	logs, sub, err := r.contract.FilterLogs(opts, "Solved")
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+
+	var solutions []Solution
+	add := func(log types.Log) {
+		// both event arguments are indexed
+		if len(log.Topics) != 3 {
+			return
+		}
+		solutions = append(solutions, Solution{
+			Target:      log.Topics[1].Big(),
+			Solver:      common.BytesToAddress(log.Topics[2].Bytes()),
+			BlockNumber: log.BlockNumber,
+			TxHash:      log.TxHash,
+		})
+	}
+	for {
+		select {
+		case log := <-logs:
+			add(log)
+		case err := <-sub.Err():
+			if err != nil {
+				return nil, err
+			}
+			// the subscription ends once every log is buffered, logs is never closed
+			for {
+				select {
+				case log := <-logs:
+					add(log)
+				default:
+					return solutions, nil
+				}
+			}
+		}
+	}
+}
+
+// Score is the standing of a solver in the leaderboard
+type Score struct {
+	Solver common.Address `json:"solver"`
+	Solved int            `json:"solved"`
+	// LastBlock breaks ties: reaching the same score earlier ranks first
+	LastBlock uint64 `json:"lastBlock"`
+}
+
+// Leaderboard ranks the solvers of solutions by number of targets won
+func Leaderboard(solutions []Solution) []Score {
+	byAddress := make(map[common.Address]*Score)
+	for _, s := range solutions {
+		score, ok := byAddress[s.Solver]
+		if !ok {
+			score = &Score{Solver: s.Solver}
+			byAddress[s.Solver] = score
+		}
+		score.Solved++
+		if s.BlockNumber > score.LastBlock {
+			score.LastBlock = s.BlockNumber
+		}
+	}
+
+	scores := make([]Score, 0, len(byAddress))
+	for _, score := range byAddress {
+		scores = append(scores, *score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Solved != scores[j].Solved {
+			return scores[i].Solved > scores[j].Solved
+		}
+		if scores[i].LastBlock != scores[j].LastBlock {
+			return scores[i].LastBlock < scores[j].LastBlock
+		}
+		return scores[i].Solver.Hex() < scores[j].Solver.Hex()
+	})
+	return scores
+}
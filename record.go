@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os/exec"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runRecord deploys the verifier of the selected circuit on the simulated backend, watches its
+// ProofVerified events, and records the example proof with verifyAndRecord: the event is
+// printed as it is received, then read back with a filter
+func runRecord(args []string) {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	fCount := fs.Int("count", 2, "number of verifyAndRecord transactions")
+	assertNoError(fs.Parse(args))
+	if _, err := exec.LookPath(ethereum.Solc); err != nil {
+		log.Fatal("please install solc", err)
+	}
+
+	r1cs := groth16.NewCS(ecc.BN254)
+	pk := groth16.NewProvingKey(ecc.BN254)
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(r1cs, files.r1cs)
+	deserialize(pk, files.pk)
+	deserialize(vk, files.vk)
+
+	witness, err := circuits.Example(*fCircuit)
+	assertNoError(err)
+	log.Println("creating proof")
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	assertNoError(err)
+	publicWitness, err := ethereum.PublicWitness(witness)
+	assertNoError(err)
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	assertNoError(err)
+
+	// the verifier is compiled from the verifying key: verifiers exported by older versions
+	// don't have verifyAndRecord
+	auth, chain, err := newBackend()
+	assertNoError(err)
+	var solidity bytes.Buffer
+	assertNoError(ethereum.ExportSolidity(&solidity, vk))
+	address, _, err := ethereum.DeployContract(auth, chain, solidity.String(), "Verifier")
+	assertNoError(err)
+	chain.Commit()
+	verifier, err := ethereum.NewVerifier(address, chain, len(publicWitness))
+	assertNoError(err)
+
+	events := make(chan ethereum.ProofVerified)
+	sub, err := verifier.WatchProofVerified(&bind.WatchOpts{Context: mainCtx}, events)
+	assertNoError(err)
+	defer sub.Unsubscribe()
+
+	log.Printf("watching ProofVerified events of %s", address.Hex())
+	for i := 0; i < *fCount; i++ {
+		_, err := verifier.VerifyAndRecord(auth, solidityInputs)
+		assertNoError(err)
+		chain.Commit()
+		select {
+		case e := <-events:
+			log.Printf("ProofVerified: public inputs %s proven by %s (block %d)", e.PublicInputHash.Hex(), e.Prover.Hex(), e.Raw.BlockNumber)
+		case err := <-sub.Err():
+			log.Fatal(err)
+		case <-mainCtx.Done():
+			assertCompleted(mainCtx.Err())
+		}
+	}
+
+	recorded, err := verifier.FilterProofVerified(&bind.FilterOpts{Start: 0, Context: mainCtx})
+	assertNoError(err)
+	expected := ethereum.PublicInputHash(publicWitness)
+	result := recordResult{Verifier: address.Hex(), PublicInputHash: expected.Hex()}
+	for _, e := range recorded {
+		if e.PublicInputHash != expected {
+			log.Fatalf("recorded public input hash %s, expected %s", e.PublicInputHash.Hex(), expected.Hex())
+		}
+		result.Blocks = append(result.Blocks, e.Raw.BlockNumber)
+	}
+	log.Printf("%d verification(s) recorded", len(recorded))
+	emit("record", result)
+}
+
+// recordResult is the JSON output of record: the blocks of the recorded verifications
+type recordResult struct {
+	Verifier        string   `json:"verifier"`
+	PublicInputHash string   `json:"publicInputHash"`
+	Blocks          []uint64 `json:"blocks"`
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/gbotrel/gnark-workshop/recursion"
+)
+
+// runRecursion proves a proof
+// 1. the mimc pre-image circuit is proven on BLS12-377 (inner proof)
+// 2. a BW6-761 circuit verifies the inner proof (outer proof)
+// 3. the outer proof is verified, with the inner verifying key and hash as public inputs
+func runRecursion() {
+	log.Println("compiling inner circuit on BLS12-377 and running groth16.Setup")
+	inner, err := recursion.SetupInner()
+	assertNoError(err)
+	log.Printf("inner circuit: %d constraints", inner.R1CS.GetNbConstraints())
+
+	innerWitness, hash, err := recursion.InnerWitness([]byte("thisIsOurSecret"))
+	assertNoError(err)
+	log.Println("creating inner proof")
+	innerProof, err := groth16.Prove(inner.R1CS, inner.PK, innerWitness)
+	assertNoError(err)
+	assertNoError(groth16.Verify(innerProof, inner.VK, innerWitness))
+
+	log.Println("compiling outer circuit on BW6-761 and running groth16.Setup")
+	outer, err := recursion.SetupOuter()
+	assertNoError(err)
+	log.Printf("outer circuit: %d constraints", outer.R1CS.GetNbConstraints())
+
+	outerWitness, err := recursion.NewWitness(inner.VK, innerProof, hash)
+	assertNoError(err)
+	log.Println("creating outer proof")
+	outerProof, err := groth16.Prove(outer.R1CS, outer.PK, outerWitness)
+	assertNoError(err)
+	assertNoError(groth16.Verify(outerProof, outer.VK, outerWitness))
+	log.Println("outer proof verified: the inner proof is valid")
+}
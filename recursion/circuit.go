@@ -0,0 +1,44 @@
+package recursion
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields"
+	"github.com/consensys/gnark/std/algebra/sw"
+	stdgroth16 "github.com/consensys/gnark/std/groth16"
+)
+
+// ateLoop is the Miller loop parameter of BLS12-377
+const ateLoop = 9586122913090633729
+
+// Circuit defines a proof of a proof
+// the inner proof is a valid BLS12-377 Groth16 proof of the inner circuit (a circuit.Circuit),
+// under the public inner verifying key, for the public hash
+//
+// It is compiled on BW6-761, whose scalar field is the base field of BLS12-377: the inner
+// pairing check is computed with native field operations.
+type Circuit struct {
+	InnerProof stdgroth16.Proof
+	InnerVk    stdgroth16.VerifyingKey `gnark:",public"`
+	Hash       frontend.Variable       `gnark:",public"`
+}
+
+// NewCircuit returns a Circuit definition; the inner circuit has one public input (the hash)
+func NewCircuit() *Circuit {
+	var c Circuit
+	c.InnerVk.G1 = make([]sw.G1Affine, 2)
+	return &c
+}
+
+// Define declares the circuit's constraints
+func (c *Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	if curveID != ecc.BW6_761 {
+		return errOuterCurve
+	}
+	pairingInfo := sw.PairingContext{
+		Extension: fields.GetBLS377ExtensionFp12(cs),
+		AteLoop:   ateLoop,
+	}
+	stdgroth16.Verify(cs, pairingInfo, c.InnerVk, c.InnerProof, []frontend.Variable{c.Hash})
+	return nil
+}
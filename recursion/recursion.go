@@ -0,0 +1,128 @@
+// Package recursion verifies a Groth16 proof inside a Groth16 proof: an inner circuit.Circuit
+// proof on BLS12-377 is checked by an outer Circuit proven on BW6-761, with gnark's
+// std/groth16 verifier gadget (BLS12-377 in BW6-761).
+//
+// The outer proof could in turn be verified on-chain where BW6-761 is supported, or aggregate
+// several inner proofs; here it only shows recursion end to end.
+package recursion
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/mimc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuit"
+)
+
+var errOuterCurve = errors.New("recursion: the outer circuit is defined over BW6-761 only")
+
+// Keys are the compiled circuit and groth16 keys of one layer
+type Keys struct {
+	R1CS frontend.CompiledConstraintSystem
+	PK   groth16.ProvingKey
+	VK   groth16.VerifyingKey
+}
+
+// SetupInner compiles circuit.Circuit on BLS12-377 and runs its (toy) setup
+func SetupInner() (*Keys, error) {
+	return setup(ecc.BLS12_377, &circuit.Circuit{})
+}
+
+// SetupOuter compiles the recursion Circuit on BW6-761 and runs its (toy) setup
+func SetupOuter() (*Keys, error) {
+	return setup(ecc.BW6_761, NewCircuit())
+}
+
+func setup(curveID ecc.ID, c frontend.Circuit) (*Keys, error) {
+	r1cs, err := frontend.Compile(curveID, backend.GROTH16, c)
+	if err != nil {
+		return nil, err
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		return nil, err
+	}
+	return &Keys{R1CS: r1cs, PK: pk, VK: vk}, nil
+}
+
+// InnerWitness returns the circuit.Circuit assignment for secret, hashed with the BLS12-377 MiMC
+// (circuit.Hash is the BN254 one)
+func InnerWitness(secret []byte) (*circuit.Circuit, []byte, error) {
+	if len(secret) > fr.Bytes {
+		return nil, nil, circuit.ErrSecretTooLong
+	}
+	hFunc := mimc.NewMiMC(circuit.Seed)
+	hFunc.Write(secret)
+	hash := hFunc.Sum(nil)
+
+	var witness circuit.Circuit
+	witness.Secret.Assign(secret)
+	witness.Hash.Assign(hash)
+	return &witness, hash, nil
+}
+
+// NewWitness returns the outer Circuit assignment for an inner proof of hash under innerVK
+func NewWitness(innerVK groth16.VerifyingKey, innerProof groth16.Proof, hash []byte) (*Circuit, error) {
+	// G1.Alpha, G1.Beta, G2.Beta, G2.Gamma, G1.Delta, G2.Delta, G1.K
+	var (
+		buf                    bytes.Buffer
+		alpha, g1Beta, g1Delta bls12377.G1Affine
+		beta, gamma, delta     bls12377.G2Affine
+		k                      []bls12377.G1Affine
+	)
+	if _, err := innerVK.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	dec := bls12377.NewDecoder(&buf)
+	for _, v := range []interface{}{&alpha, &g1Beta, &beta, &gamma, &g1Delta, &delta, &k} {
+		if err := dec.Decode(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(k) != 2 {
+		return nil, errors.New("recursion: the inner circuit must have exactly one public input")
+	}
+
+	// Ar, Bs, Krs
+	var (
+		ar, krs bls12377.G1Affine
+		bs      bls12377.G2Affine
+	)
+	buf.Reset()
+	if _, err := innerProof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	dec = bls12377.NewDecoder(&buf)
+	for _, v := range []interface{}{&ar, &bs, &krs} {
+		if err := dec.Decode(v); err != nil {
+			return nil, err
+		}
+	}
+
+	// the gadget takes e(α, β), -γ and -δ
+	e, err := bls12377.Pair([]bls12377.G1Affine{alpha}, []bls12377.G2Affine{beta})
+	if err != nil {
+		return nil, err
+	}
+	gamma.Neg(&gamma)
+	delta.Neg(&delta)
+
+	witness := NewCircuit()
+	witness.InnerProof.Ar.Assign(&ar)
+	witness.InnerProof.Krs.Assign(&krs)
+	witness.InnerProof.Bs.Assign(&bs)
+	witness.InnerVk.E.Assign(&e)
+	witness.InnerVk.G2.GammaNeg.Assign(&gamma)
+	witness.InnerVk.G2.DeltaNeg.Assign(&delta)
+	for i := range k {
+		witness.InnerVk.G1[i].Assign(&k[i])
+	}
+	witness.Hash.Assign(hash)
+	return witness, nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"math/big"
+	"os/exec"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/registry"
+)
+
+// runRegistry shows an application contract consuming the verifier, on the simulated backend:
+// the mimc verifier and a SecretRegistry are deployed, the example pre-image knowledge proof
+// unlocks its hash, and unlocking it again is refused
+func runRegistry(args []string) {
+	fs := flag.NewFlagSet("registry", flag.ExitOnError)
+	assertNoError(fs.Parse(args))
+	if _, err := exec.LookPath(ethereum.Solc); err != nil {
+		log.Fatal("please install solc", err)
+	}
+	vk, proof, hash := proveExample()
+
+	auth, chain, err := newBackend()
+	assertNoError(err)
+	log.Println("deploying verifier and SecretRegistry")
+	var solidity bytes.Buffer
+	assertNoError(vk.ExportSolidity(&solidity))
+	verifier, _, err := ethereum.DeployContract(auth, chain, solidity.String(), "Verifier")
+	assertNoError(err)
+	r, err := registry.Deploy(auth, chain, verifier)
+	assertNoError(err)
+	chain.Commit()
+
+	// unlock, then try again; the gas limit is set as estimating a reverting call fails
+	result := registryResult{Registry: r.Address.Hex(), Hash: hash.String()}
+	opts := *auth
+	opts.GasLimit = 1000000
+	for i, expected := range []uint64{1, 0} {
+		tx, err := r.Unlock(&opts, hash, proof)
+		assertNoError(err)
+		chain.Commit()
+		receipt, err := chain.TransactionReceipt(mainCtx, tx.Hash())
+		assertNoError(err)
+		if receipt.Status != expected {
+			log.Fatalf("unlock %d: status %d, expected %d", i, receipt.Status, expected)
+		}
+		result.Unlocks = append(result.Unlocks, newTxResult(receipt))
+	}
+
+	by, err := r.UnlockedBy(&bind.CallOpts{Context: mainCtx}, hash)
+	assertNoError(err)
+	if by != auth.From {
+		log.Fatal("hash wasn't unlocked by the prover")
+	}
+	log.Printf("hash %s unlocked by %s, second unlock refused", hash, by.Hex())
+	emit("registry", result)
+}
+
+// proveExample sets up the mimc circuit and proves its example assignment, for the application
+// contract demos; hash is the public input
+func proveExample() (groth16.VerifyingKey, groth16.Proof, *big.Int) {
+	circuit, err := circuits.Get(defaultCircuit)
+	assertNoError(err)
+	assignment, err := circuits.Example(defaultCircuit)
+	assertNoError(err)
+
+	log.Println("compiling circuit", defaultCircuit)
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, circuit)
+	assertNoError(err)
+	log.Println("running groth16.Setup")
+	pk, vk, err := groth16.Setup(r1cs)
+	assertNoError(err)
+	log.Println("creating proof")
+	proof, err := groth16.Prove(r1cs, pk, assignment)
+	assertNoError(err)
+	publicWitness, err := ethereum.PublicWitness(assignment)
+	assertNoError(err)
+	return vk, proof, publicWitness[0].ToBigIntRegular(new(big.Int))
+}
+
+// registryResult is the JSON output of registry; the second unlock is the refused one
+type registryResult struct {
+	Registry string      `json:"registry"`
+	Hash     string      `json:"hash"`
+	Unlocks  []*txResult `json:"unlocks"`
+}
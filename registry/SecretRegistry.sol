@@ -0,0 +1,36 @@
+// Compiled after IVerifier.sol, with its SPDX identifier and pragma: see registry.Source.
+//
+// SecretRegistry records which mimc hashes have been unlocked, by whom: unlocking a hash takes a
+// proof of knowledge of its pre-image, checked by the IVerifier of the circuit.Circuit (mimc) circuit.
+//
+// As in Race, proofs are not bound to msg.sender: a proof seen in the mempool can be replayed by
+// someone else, first.
+contract SecretRegistry {
+    IVerifier public immutable verifier;
+
+    mapping(uint256 => address) public unlockedBy;
+
+    event Unlocked(uint256 indexed hash, address indexed by);
+
+    constructor(IVerifier _verifier) {
+        verifier = _verifier;
+    }
+
+    // unlock records msg.sender as the first prover of the knowledge of hash's pre-image
+    function unlock(
+        uint256 hash,
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c
+    ) external {
+        require(unlockedBy[hash] == address(0), "registry-already-unlocked");
+        require(verifier.verifyProof(a, b, c, [hash]), "registry-invalid-proof");
+
+        unlockedBy[hash] = msg.sender;
+        emit Unlocked(hash, msg.sender);
+    }
+
+    function isUnlocked(uint256 hash) external view returns (bool) {
+        return unlockedBy[hash] != address(0);
+    }
+}
@@ -0,0 +1,86 @@
+// Package registry drives SecretRegistry, an example application contract consuming the
+// exported verifier through the IVerifier interface (see ethereum.ExportInterface): it records
+// the mimc hashes whose pre-image knowledge was proven, and who proved it.
+package registry
+
+import (
+	_ "embed"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+//go:embed SecretRegistry.sol
+var registrySol string
+
+// registryABI is the ABI of SecretRegistry.sol, so that it can be bound without solc
+const registryABI = `[
+	{"type":"function","name":"verifier","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"unlockedBy","stateMutability":"view","inputs":[{"name":"","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"isUnlocked","stateMutability":"view","inputs":[{"name":"hash","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"unlock","stateMutability":"nonpayable","inputs":[{"name":"hash","type":"uint256"},{"name":"a","type":"uint256[2]"},{"name":"b","type":"uint256[2][2]"},{"name":"c","type":"uint256[2]"}],"outputs":[]},
+	{"type":"event","name":"Unlocked","anonymous":false,"inputs":[{"name":"hash","type":"uint256","indexed":true},{"name":"by","type":"address","indexed":true}]}
+]`
+
+// Source returns the Solidity source of SecretRegistry, with the IVerifier interface it needs
+func Source() (string, error) {
+	iVerifier, err := ethereum.VerifierInterface(1)
+	if err != nil {
+		return "", err
+	}
+	// a single SPDX identifier and pragma per source
+	return iVerifier + "\n" + registrySol, nil
+}
+
+// SecretRegistry is a handle on a deployed SecretRegistry contract
+type SecretRegistry struct {
+	Address  common.Address
+	contract *bind.BoundContract
+}
+
+// Deploy deploys a SecretRegistry checking proofs with the mimc verifier deployed at verifier
+// Requires solc in PATH; caller is responsible for committing / mining the transaction.
+func Deploy(auth *bind.TransactOpts, backend bind.ContractBackend, verifier common.Address) (*SecretRegistry, error) {
+	source, err := Source()
+	if err != nil {
+		return nil, err
+	}
+	address, contract, err := ethereum.DeployContract(auth, backend, source, "SecretRegistry", verifier)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretRegistry{Address: address, contract: contract}, nil
+}
+
+// Bind returns a handle on the SecretRegistry deployed at address
+func Bind(address common.Address, backend bind.ContractBackend) (*SecretRegistry, error) {
+	parsed, err := abi.JSON(strings.NewReader(registryABI))
+	if err != nil {
+		return nil, err
+	}
+	return &SecretRegistry{Address: address, contract: bind.NewBoundContract(address, parsed, backend, backend, backend)}, nil
+}
+
+// Unlock records the sender of auth as the prover of the knowledge of hash's pre-image
+func (r *SecretRegistry) Unlock(auth *bind.TransactOpts, hash *big.Int, proof groth16.Proof) (*types.Transaction, error) {
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.contract.Transact(auth, "unlock", hash, solidityInputs.A, solidityInputs.B, solidityInputs.C)
+}
+
+// UnlockedBy returns the address that unlocked hash, or the zero address
+func (r *SecretRegistry) UnlockedBy(opts *bind.CallOpts, hash *big.Int) (common.Address, error) {
+	var out []interface{}
+	if err := r.contract.Call(opts, &out, "unlockedBy", hash); err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/metrics"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// bytes read / written by deserialize / serialize
+var ioRead, ioWritten int64
+
+// stageStats is the cost of one stage of the SNARK lifecycle
+// durations are in nanoseconds in JSON outputs
+type stageStats struct {
+	Name         string        `json:"name"`
+	Wall         time.Duration `json:"wall"`
+	CPU          time.Duration `json:"cpu"`
+	PeakRSS      uint64        `json:"peakRss"`   // process high-water mark at the end of the stage
+	Allocated    uint64        `json:"allocated"` // heap bytes allocated during the stage
+	BytesRead    int64         `json:"bytesRead"`
+	BytesWritten int64         `json:"bytesWritten"`
+}
+
+// pipelineReport collects stage costs, printed at the end of a run
+type pipelineReport struct {
+	stages []stageStats
+}
+
+var report pipelineReport
+
+type resourceSnapshot struct {
+	at           time.Time
+	cpu          time.Duration
+	maxRSS       uint64
+	allocated    uint64
+	bytesRead    int64
+	bytesWritten int64
+}
+
+// track starts measuring stage name; calling the returned function ends it
+//
+//	defer report.track("prove")()
+func (r *pipelineReport) track(name string) func() {
+	start := takeSnapshot()
+	return func() {
+		end := takeSnapshot()
+		r.stages = append(r.stages, stageStats{
+			Name:         name,
+			Wall:         end.at.Sub(start.at),
+			CPU:          end.cpu - start.cpu,
+			PeakRSS:      end.maxRSS,
+			Allocated:    end.allocated - start.allocated,
+			BytesRead:    end.bytesRead - start.bytesRead,
+			BytesWritten: end.bytesWritten - start.bytesWritten,
+		})
+	}
+}
+
+// print writes the summary table to stderr
+func (r *pipelineReport) print() {
+	if len(r.stages) == 0 {
+		return
+	}
+	var total stageStats
+	total.Name = "total"
+
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "stage\twall\tcpu\tpeak rss\tallocated\tread\twritten\t")
+	for _, s := range r.stages {
+		printStage(w, s)
+		total.Wall += s.Wall
+		total.CPU += s.CPU
+		total.Allocated += s.Allocated
+		total.BytesRead += s.BytesRead
+		total.BytesWritten += s.BytesWritten
+		if s.PeakRSS > total.PeakRSS {
+			total.PeakRSS = s.PeakRSS
+		}
+	}
+	printStage(w, total)
+	w.Flush()
+}
+
+func printStage(w *tabwriter.Writer, s stageStats) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+		s.Name,
+		s.Wall.Round(time.Millisecond),
+		s.CPU.Round(time.Millisecond),
+		formatBytes(s.PeakRSS),
+		formatBytes(s.Allocated),
+		formatBytes(uint64(s.BytesRead)),
+		formatBytes(uint64(s.BytesWritten)))
+}
+
+func takeSnapshot() resourceSnapshot {
+	sample := []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+	metrics.Read(sample)
+
+	var allocated uint64
+	if sample[0].Value.Kind() == metrics.KindUint64 {
+		allocated = sample[0].Value.Uint64()
+	}
+
+	cpu, maxRSS := rusage()
+	return resourceSnapshot{
+		at:           time.Now(),
+		cpu:          cpu,
+		maxRSS:       maxRSS,
+		allocated:    allocated,
+		bytesRead:    atomic.LoadInt64(&ioRead),
+		bytesWritten: atomic.LoadInt64(&ioWritten),
+	}
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"log"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/rollup"
+)
+
+// runRollup runs the toy rollup on the simulated backend
+// 1. accounts are created in a genesis state, whose root is stored on-chain
+// 2. the operator proves a batch of signed transfers off-chain
+// 3. the Rollup contract moves its root with the batch proof
+func runRollup() {
+	var c rollup.Circuit
+	log.Println("compiling rollup circuit")
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &c)
+	assertNoError(err)
+	log.Printf("rollup circuit: %d constraints", r1cs.GetNbConstraints())
+
+	log.Println("running groth16.Setup")
+	pk, vk, err := groth16.Setup(r1cs)
+	assertNoError(err)
+
+	// genesis: alice, bob and carol own 100 each
+	keys := make([]eddsa.PrivateKey, 3)
+	accounts := make([]rollup.Account, len(keys))
+	for i := range keys {
+		keys[i], err = circuit.GenerateSigningKey(rand.Reader)
+		assertNoError(err)
+		accounts[i] = rollup.Account{PublicKey: keys[i].PublicKey, Balance: 100}
+	}
+	state, err := rollup.NewState(accounts)
+	assertNoError(err)
+
+	auth, chain, err := newBackend()
+	assertNoError(err)
+	log.Println("deploying rollup verifier and rollup contracts on chain")
+	r, err := rollup.Deploy(auth, chain, vk, state.Root())
+	assertNoError(err)
+	chain.Commit()
+
+	// alice sends 30 to bob, bob sends 50 to carol
+	operator := rollup.NewOperator(state, r1cs, pk)
+	for _, t := range []struct {
+		from, to, amount uint64
+	}{{0, 1, 30}, {1, 2, 50}} {
+		transfer := rollup.Transfer{From: t.from, To: t.to, Amount: t.amount, Nonce: state.Accounts[t.from].Nonce}
+		assertNoError(transfer.Sign(keys[t.from]))
+		operator.Add(transfer)
+	}
+
+	log.Println("proving batch")
+	batch, err := operator.Prove()
+	assertNoError(err)
+
+	tx, err := r.SubmitBatch(auth, batch.NewRoot, batch.Proof)
+	assertNoError(err)
+	chain.Commit()
+	receipt, err := chain.TransactionReceipt(context.Background(), tx.Hash())
+	assertNoError(err)
+	if receipt.Status != 1 {
+		log.Fatal("batch submission reverted, but shouldn't have")
+	}
+	log.Printf("submitted batch of %d transfers (gas used: %d)", rollup.BatchSize, receipt.GasUsed)
+
+	root, err := r.StateRoot(nil)
+	assertNoError(err)
+	if root.Cmp(new(big.Int).SetBytes(batch.NewRoot)) != 0 {
+		log.Fatal("on-chain state root doesn't match the operator state")
+	}
+	for i, a := range state.Accounts[:len(keys)] {
+		log.Printf("account %d: balance %d, nonce %d", i, a.Balance, a.Nonce)
+	}
+	log.Println("successfully moved the rollup state root on-chain")
+	emit("rollup", rollupResult{Transfers: rollup.BatchSize, Submit: newTxResult(receipt)})
+}
+
+// rollupResult is the JSON output of -rollup
+type rollupResult struct {
+	Transfers int       `json:"transfers"`
+	Submit    *txResult `json:"submit"`
+}
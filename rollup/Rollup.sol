@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+pragma solidity ^0.8.0;
+
+// IRollupVerifier is the gnark exported verifier of the rollup.Circuit circuit
+interface IRollupVerifier {
+    function verifyProof(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c,
+        uint256[2] memory input
+    ) external view returns (bool r);
+}
+
+// Rollup stores the state root of the toy rollup, and moves it on a valid
+// batch proof from the current root.
+contract Rollup {
+    IRollupVerifier public immutable verifier;
+
+    uint256 public stateRoot;
+    uint256 public batches;
+
+    event BatchSubmitted(uint256 indexed batch, uint256 oldRoot, uint256 newRoot);
+
+    constructor(IRollupVerifier _verifier, uint256 genesisRoot) {
+        verifier = _verifier;
+        stateRoot = genesisRoot;
+    }
+
+    // submitBatch verifies a proof of a batch of transfers from stateRoot to newRoot
+    function submitBatch(
+        uint256 newRoot,
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c
+    ) external {
+        require(verifier.verifyProof(a, b, c, [stateRoot, newRoot]), "rollup-invalid-proof");
+
+        emit BatchSubmitted(batches, stateRoot, newRoot);
+        stateRoot = newRoot;
+        batches++;
+    }
+}
@@ -0,0 +1,125 @@
+package rollup
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/signature/eddsa"
+	"github.com/gbotrel/gnark-workshop/circuit"
+)
+
+const (
+	// Depth is the depth of the state tree (up to 2^Depth accounts)
+	Depth = 4
+	// BatchSize is the number of transfers a batch proof covers
+	BatchSize = 2
+)
+
+// AccountVariables is an account leaf, as hashed in the state tree
+type AccountVariables struct {
+	X, Y    frontend.Variable // public key
+	Balance frontend.Variable
+	Nonce   frontend.Variable
+}
+
+// TransferVariables is one signed transfer with the state it reads; all of it is secret
+type TransferVariables struct {
+	From, To, Amount, Nonce frontend.Variable
+	Signature               eddsa.Signature
+
+	Sender       AccountVariables
+	SenderPath   [Depth]frontend.Variable // in the state before the transfer
+	Receiver     AccountVariables
+	ReceiverPath [Depth]frontend.Variable // in the state after the sender update
+}
+
+// Circuit defines a batch of transfers moving the state root from OldRoot to NewRoot
+// For each transfer: the sender signed it, owns the amount and the nonce matches; both leaves
+// are updated in turn. Transfers are not published: this toy rollup is a validium.
+type Circuit struct {
+	OldRoot   frontend.Variable `gnark:",public"`
+	NewRoot   frontend.Variable `gnark:",public"`
+	Transfers [BatchSize]TransferVariables
+}
+
+// Define declares the circuit's constraints
+func (c *Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	curve, err := twistededwards.NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+
+	root := c.OldRoot
+	for i := range c.Transfers {
+		if root, err = c.Transfers[i].define(curveID, cs, curve, root); err != nil {
+			return err
+		}
+	}
+	cs.AssertIsEqual(root, c.NewRoot)
+
+	return nil
+}
+
+// define asserts the transfer applies to root, and returns the updated root
+func (t *TransferVariables) define(curveID ecc.ID, cs *frontend.ConstraintSystem, curve twistededwards.EdCurve, root frontend.Variable) (frontend.Variable, error) {
+	// the sender signed the transfer
+	msg, err := hashVariables(curveID, cs, t.From, t.To, t.Amount, t.Nonce)
+	if err != nil {
+		return root, err
+	}
+	publicKey := eddsa.PublicKey{A: twistededwards.Point{X: t.Sender.X, Y: t.Sender.Y}, Curve: curve}
+	if err := eddsa.Verify(cs, t.Signature, msg, publicKey); err != nil {
+		return root, err
+	}
+	cs.AssertIsEqual(t.Nonce, t.Sender.Nonce)
+	cs.AssertIsLessOrEqual(t.Amount, t.Sender.Balance)
+
+	// update the sender, the path directions are the bits of its index
+	senderDirections := cs.ToBinary(t.From, Depth)
+	if err := assertLeaf(curveID, cs, t.Sender, t.SenderPath[:], senderDirections, root); err != nil {
+		return root, err
+	}
+	sender := t.Sender
+	sender.Balance = cs.Sub(t.Sender.Balance, t.Amount)
+	sender.Nonce = cs.Add(t.Sender.Nonce, 1)
+	if root, err = leafRoot(curveID, cs, sender, t.SenderPath[:], senderDirections); err != nil {
+		return root, err
+	}
+
+	// then the receiver
+	receiverDirections := cs.ToBinary(t.To, Depth)
+	if err := assertLeaf(curveID, cs, t.Receiver, t.ReceiverPath[:], receiverDirections, root); err != nil {
+		return root, err
+	}
+	receiver := t.Receiver
+	receiver.Balance = cs.Add(t.Receiver.Balance, t.Amount)
+	return leafRoot(curveID, cs, receiver, t.ReceiverPath[:], receiverDirections)
+}
+
+func assertLeaf(curveID ecc.ID, cs *frontend.ConstraintSystem, account AccountVariables, path, directions []frontend.Variable, root frontend.Variable) error {
+	computed, err := leafRoot(curveID, cs, account, path, directions)
+	if err != nil {
+		return err
+	}
+	cs.AssertIsEqual(computed, root)
+	return nil
+}
+
+// leafRoot returns the state root with account at path
+func leafRoot(curveID ecc.ID, cs *frontend.ConstraintSystem, account AccountVariables, path, directions []frontend.Variable) (frontend.Variable, error) {
+	leaf, err := hashVariables(curveID, cs, account.X, account.Y, account.Balance, account.Nonce)
+	if err != nil {
+		return frontend.Variable{}, err
+	}
+	return circuit.MerkleRoot(curveID, cs, leaf, path, directions)
+}
+
+func hashVariables(curveID ecc.ID, cs *frontend.ConstraintSystem, data ...frontend.Variable) (frontend.Variable, error) {
+	hFunc, err := mimc.NewMiMC(circuit.Seed, curveID, cs)
+	if err != nil {
+		return frontend.Variable{}, err
+	}
+	hFunc.Write(data...)
+	return hFunc.Sum(), nil
+}
@@ -0,0 +1,60 @@
+package rollup
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrBatchNotFull is returned when proving with less than BatchSize pending transfers
+var ErrBatchNotFull = errors.New("not enough pending transfers for a batch")
+
+// Operator collects transfers and proves them in batches against its state
+type Operator struct {
+	State   *State
+	r1cs    frontend.CompiledConstraintSystem
+	pk      groth16.ProvingKey
+	pending []Transfer
+}
+
+// Batch is a proven state transition, ready to be submitted on-chain
+type Batch struct {
+	OldRoot, NewRoot []byte
+	Proof            groth16.Proof
+}
+
+// NewOperator returns an operator proving with the rollup.Circuit r1cs and pk
+func NewOperator(state *State, r1cs frontend.CompiledConstraintSystem, pk groth16.ProvingKey) *Operator {
+	return &Operator{State: state, r1cs: r1cs, pk: pk}
+}
+
+// Add queues a signed transfer; it returns true once a batch is full
+func (o *Operator) Add(t Transfer) bool {
+	o.pending = append(o.pending, t)
+	return len(o.pending) >= BatchSize
+}
+
+// Prove applies the next BatchSize pending transfers and proves the transition
+// On error, the state and the pending transfers are left untouched.
+func (o *Operator) Prove() (*Batch, error) {
+	if len(o.pending) < BatchSize {
+		return nil, ErrBatchNotFull
+	}
+	oldRoot, backup := o.State.Root(), o.State.Accounts
+	witness, err := o.State.Apply(o.pending[:BatchSize])
+	if err != nil {
+		return nil, err
+	}
+	proof, err := groth16.Prove(o.r1cs, o.pk, witness)
+	if err != nil {
+		// the circuit and the host state disagree, roll back
+		o.State.Accounts = backup
+		if rerr := o.State.rebuild(); rerr != nil {
+			return nil, rerr
+		}
+		return nil, err
+	}
+	o.pending = o.pending[BatchSize:]
+	return &Batch{OldRoot: oldRoot, NewRoot: o.State.Root(), Proof: proof}, nil
+}
@@ -0,0 +1,62 @@
+package rollup
+
+import (
+	"bytes"
+	_ "embed"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+//go:embed Rollup.sol
+var rollupSol string
+
+// Rollup is a handle on a deployed Rollup contract
+type Rollup struct {
+	Address  common.Address
+	Verifier common.Address
+	contract *bind.BoundContract
+}
+
+// Deploy exports vk (a rollup.Circuit verifying key) to solidity, deploys it, then deploys a
+// Rollup contract starting at genesisRoot.
+// Requires solc in PATH; caller is responsible for committing / mining the transactions.
+func Deploy(auth *bind.TransactOpts, backend bind.ContractBackend, vk groth16.VerifyingKey, genesisRoot []byte) (*Rollup, error) {
+	var buf bytes.Buffer
+	if err := vk.ExportSolidity(&buf); err != nil {
+		return nil, err
+	}
+	verifierAddress, _, err := ethereum.DeployContract(auth, backend, buf.String(), "Verifier")
+	if err != nil {
+		return nil, err
+	}
+
+	address, contract, err := ethereum.DeployContract(auth, backend, rollupSol, "Rollup", verifierAddress, new(big.Int).SetBytes(genesisRoot))
+	if err != nil {
+		return nil, err
+	}
+	return &Rollup{Address: address, Verifier: verifierAddress, contract: contract}, nil
+}
+
+// StateRoot returns the state root stored on-chain
+func (r *Rollup) StateRoot(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	if err := r.contract.Call(opts, &out, "stateRoot"); err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// SubmitBatch moves the on-chain state root to newRoot with a batch proof
+func (r *Rollup) SubmitBatch(auth *bind.TransactOpts, newRoot []byte, proof groth16.Proof) (*types.Transaction, error) {
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.contract.Transact(auth, "submitBatch", new(big.Int).SetBytes(newRoot), solidityInputs.A, solidityInputs.B, solidityInputs.C)
+}
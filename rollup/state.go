@@ -0,0 +1,200 @@
+// Package rollup is a toy zk-rollup: accounts and balances live in a MiMC Merkle tree, an
+// operator proves batches of EdDSA signed transfers off-chain, and the Rollup contract moves
+// its state root on a valid batch proof.
+package rollup
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuit"
+)
+
+var (
+	// ErrInsufficientBalance is returned for transfers of more than the sender balance
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrInvalidNonce is returned for transfers which nonce isn't the sender's
+	ErrInvalidNonce = errors.New("invalid nonce")
+	// ErrInvalidSignature is returned for transfers not signed by the sender
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Account is a rollup account
+type Account struct {
+	PublicKey eddsa.PublicKey
+	Balance   uint64
+	Nonce     uint64
+}
+
+// hash returns mimc(publicKey.X, publicKey.Y, balance, nonce), the account leaf value
+func (a *Account) hash() []byte {
+	var balance, nonce fr.Element
+	balance.SetUint64(a.Balance)
+	nonce.SetUint64(a.Nonce)
+	return hashElements(a.PublicKey.A.X, a.PublicKey.A.Y, balance, nonce)
+}
+
+// Transfer moves Amount from account From to account To
+type Transfer struct {
+	From, To, Amount, Nonce uint64
+	Signature               []byte
+}
+
+// Message returns mimc(from, to, amount, nonce), the message the sender signs
+func (t *Transfer) Message() []byte {
+	var e [4]fr.Element
+	for i, v := range []uint64{t.From, t.To, t.Amount, t.Nonce} {
+		e[i].SetUint64(v)
+	}
+	return hashElements(e[:]...)
+}
+
+// Sign signs the transfer with the sender key
+func (t *Transfer) Sign(key eddsa.PrivateKey) error {
+	sig, err := circuit.Sign(key, t.Message())
+	if err != nil {
+		return err
+	}
+	t.Signature = sig
+	return nil
+}
+
+// State is the rollup state: 2^Depth accounts, unused ones are zero
+type State struct {
+	Accounts [1 << Depth]Account
+	tree     *circuit.MerkleTree
+}
+
+// NewState returns the state holding accounts at indexes 0..len(accounts)-1
+func NewState(accounts []Account) (*State, error) {
+	if len(accounts) > 1<<Depth {
+		return nil, circuit.ErrTooManyLeaves
+	}
+	var s State
+	copy(s.Accounts[:], accounts)
+	return &s, s.rebuild()
+}
+
+// Root returns the state root
+func (s *State) Root() []byte {
+	return s.tree.Root()
+}
+
+func (s *State) rebuild() error {
+	leaves := make([][]byte, len(s.Accounts))
+	for i := range s.Accounts {
+		leaves[i] = s.Accounts[i].hash()
+	}
+	tree, err := circuit.NewMerkleTree(Depth, leaves)
+	if err != nil {
+		return err
+	}
+	s.tree = tree
+	return nil
+}
+
+// Apply checks and applies a batch of BatchSize transfers, and returns the Circuit assignment
+// proving the transition from the previous root to the new one
+// The state is left untouched if a transfer is invalid.
+func (s *State) Apply(transfers []Transfer) (_ *Circuit, err error) {
+	if len(transfers) != BatchSize {
+		return nil, fmt.Errorf("a batch has exactly %d transfers", BatchSize)
+	}
+	backup := s.Accounts
+	defer func() {
+		if err != nil {
+			s.Accounts = backup
+			_ = s.rebuild()
+		}
+	}()
+	var witness Circuit
+	witness.OldRoot.Assign(s.Root())
+
+	for i, t := range transfers {
+		if t.From >= 1<<Depth || t.To >= 1<<Depth {
+			return nil, fmt.Errorf("transfer %d: unknown account", i)
+		}
+		sender := &s.Accounts[t.From]
+		if t.Nonce != sender.Nonce {
+			return nil, fmt.Errorf("transfer %d: %w", i, ErrInvalidNonce)
+		}
+		if t.Amount > sender.Balance {
+			return nil, fmt.Errorf("transfer %d: %w", i, ErrInsufficientBalance)
+		}
+		if ok, err := sender.PublicKey.Verify(t.Signature, t.Message(), newHash()); err != nil || !ok {
+			return nil, fmt.Errorf("transfer %d: %w", i, ErrInvalidSignature)
+		}
+
+		w := &witness.Transfers[i]
+		w.From.Assign(t.From)
+		w.To.Assign(t.To)
+		w.Amount.Assign(t.Amount)
+		w.Nonce.Assign(t.Nonce)
+		if err := circuit.AssignSignature(&w.Signature, t.Signature); err != nil {
+			return nil, fmt.Errorf("transfer %d: %w", i, err)
+		}
+
+		// sender leaf, in the state before the transfer
+		assignAccount(&w.Sender, sender)
+		if err := s.assignPath(w.SenderPath[:], t.From); err != nil {
+			return nil, err
+		}
+		sender.Balance -= t.Amount
+		sender.Nonce++
+		if err := s.rebuild(); err != nil {
+			return nil, err
+		}
+
+		// receiver leaf, in the state after the sender update
+		receiver := &s.Accounts[t.To]
+		assignAccount(&w.Receiver, receiver)
+		if err := s.assignPath(w.ReceiverPath[:], t.To); err != nil {
+			return nil, err
+		}
+		receiver.Balance += t.Amount
+		if err := s.rebuild(); err != nil {
+			return nil, err
+		}
+	}
+
+	witness.NewRoot.Assign(s.Root())
+	return &witness, nil
+}
+
+func (s *State) assignPath(path []frontend.Variable, index uint64) error {
+	siblings, _, err := s.tree.Path(int(index))
+	if err != nil {
+		return err
+	}
+	for i := range path {
+		path[i].Assign(siblings[i])
+	}
+	return nil
+}
+
+func assignAccount(v *AccountVariables, a *Account) {
+	v.X.Assign(a.PublicKey.A.X.ToBigIntRegular(new(big.Int)))
+	v.Y.Assign(a.PublicKey.A.Y.ToBigIntRegular(new(big.Int)))
+	v.Balance.Assign(a.Balance)
+	v.Nonce.Assign(a.Nonce)
+}
+
+// hashElements returns the mimc hash of elements, one block each, as the circuit hashes variables
+func hashElements(elements ...fr.Element) []byte {
+	hFunc := newHash()
+	for _, e := range elements {
+		b := e.Bytes()
+		hFunc.Write(b[:])
+	}
+	return hFunc.Sum(make([]byte, 0, fr.Bytes))
+}
+
+func newHash() hash.Hash {
+	return mimc.NewMiMC(circuit.Seed)
+}
@@ -0,0 +1,71 @@
+package rollup
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/circuit"
+)
+
+func TestOperatorProvesSignedTransfers(t *testing.T) {
+	keys := make([]eddsa.PrivateKey, 2)
+	accounts := make([]Account, len(keys))
+	for i := range keys {
+		key, err := circuit.GenerateSigningKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+		accounts[i] = Account{PublicKey: key.PublicKey, Balance: 100}
+	}
+	state, err := NewState(accounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a transfer signed by another key is refused before proving
+	forged := Transfer{From: 0, To: 1, Amount: 10}
+	if err := forged.Sign(keys[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := state.Apply([]Transfer{forged, forged}); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	operator := NewOperator(state, r1cs, pk)
+	transfers := []Transfer{{From: 0, To: 1, Amount: 10, Nonce: 0}, {From: 1, To: 0, Amount: 5, Nonce: 0}}
+	for i := range transfers {
+		if err := transfers[i].Sign(keys[transfers[i].From]); err != nil {
+			t.Fatal(err)
+		}
+		operator.Add(transfers[i])
+	}
+	batch, err := operator.Prove()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var public Circuit
+	public.OldRoot.Assign(batch.OldRoot)
+	public.NewRoot.Assign(batch.NewRoot)
+	if err := groth16.Verify(batch.Proof, vk, &public); err != nil {
+		t.Fatal(err)
+	}
+	if state.Accounts[0].Balance != 95 || state.Accounts[1].Balance != 105 {
+		t.Fatalf("balances %d, %d, expected 95, 105", state.Accounts[0].Balance, state.Accounts[1].Balance)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runRotateVK deploys the verifier of the current verifying key and points the upgradeable
+// verifier of the selected circuit (deploy -upgradeable) at it, so that applications keep its address
+func runRotateVK(args []string) {
+	fs := flag.NewFlagSet("rotate-vk", flag.ExitOnError)
+	fTxFlags := addTxFlags(fs)
+	fSigner := addSignerFlags(fs, "proxy admin")
+	fDeployments := fs.String("deployments", deploymentsPath, "deployments file to update")
+	fProxy := fs.String("proxy", "", "VerifierProxy address, read from the deployments file if not set")
+	fBin := fs.String("bin", files.verifierBin, "creation bytecode of the new verifier, written by -init")
+	assertNoError(fs.Parse(args))
+
+	signer, closeSigner := fSigner.signer()
+	defer closeSigner()
+
+	ctx := mainCtx
+	client := fTxFlags.dial(ctx)
+	defer client.Close()
+	chainID := client.chainID
+	proxy := verifierAddress(*fProxy, *fDeployments, chainID)
+	previous, err := ethereum.ProxyImplementation(ctx, client, proxy)
+	assertNoError(err)
+	if previous == (common.Address{}) {
+		log.Fatalf("%s is not a VerifierProxy (deploy it with deploy -upgradeable)", proxy.Hex())
+	}
+
+	// the new verifier has no constructor arguments: initcode is the creation bytecode
+	bytecode, err := ethereum.ReadBytecode(*fBin)
+	assertNoError(err)
+	_, tx, err := ethereum.DeployRaw(ctx, client.transactor(ctx, signer), client, bytecode)
+	assertNoError(err)
+	receipt := client.wait(ctx, tx)
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		log.Fatalf("deployment transaction %s reverted", tx.Hash().Hex())
+	}
+	implementation := receipt.ContractAddress
+	log.Printf("new verifier deployed at %s", implementation.Hex())
+
+	tx, err = ethereum.UpgradeProxy(client.transactor(ctx, signer), client, proxy, implementation)
+	assertNoError(err)
+	receipt = client.wait(ctx, tx)
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		log.Fatalf("upgradeTo transaction %s reverted (is %s the proxy admin?)", tx.Hash().Hex(), signer.Address().Hex())
+	}
+	current, err := ethereum.ProxyImplementation(ctx, client, proxy)
+	assertNoError(err)
+	if current != implementation {
+		log.Fatalf("proxy forwards to %s, expected %s", current.Hex(), implementation.Hex())
+	}
+	log.Printf("proxy %s now forwards to %s (was %s)", proxy.Hex(), implementation.Hex(), previous.Hex())
+
+	deployments, err := ethereum.ReadDeployments(*fDeployments)
+	assertNoError(err)
+	deployments.Set(chainID, *fCircuit, ethereum.Deployment{
+		Verifier:       proxy,
+		Implementation: implementation,
+		TxHash:         tx.Hash(),
+		BlockNumber:    receipt.BlockNumber.Uint64(),
+	})
+	assertNoError(deployments.Save(*fDeployments))
+	log.Println("recorded deployment in", *fDeployments)
+
+	emit("rotate-vk", rotateVKResult{
+		ChainID:        chainID.Int64(),
+		Circuit:        *fCircuit,
+		Proxy:          proxy,
+		Previous:       previous,
+		Implementation: implementation,
+		Transaction:    newTxResult(receipt),
+	})
+}
+
+// rotateVKResult is the JSON output of rotate-vk
+type rotateVKResult struct {
+	ChainID        int64          `json:"chainId"`
+	Circuit        string         `json:"circuit"`
+	Proxy          common.Address `json:"proxy"`
+	Previous       common.Address `json:"previous"`
+	Implementation common.Address `json:"implementation"`
+	Transaction    *txResult      `json:"transaction"`
+}
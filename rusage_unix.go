@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// rusage returns the CPU time consumed by the process and its peak resident set size in bytes
+func rusage() (cpu time.Duration, maxRSS uint64) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, 0
+	}
+	cpu = time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+	maxRSS = uint64(ru.Maxrss)
+	if runtime.GOOS != "darwin" {
+		// linux (and BSDs) report kilobytes, darwin bytes
+		maxRSS *= 1024
+	}
+	return
+}
@@ -0,0 +1,8 @@
+package main
+
+import "time"
+
+// rusage is not available on windows; the report only shows wall time, allocations and I/O
+func rusage() (cpu time.Duration, maxRSS uint64) {
+	return 0, 0
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SecretEnv is the environment variable holding the secret the demo proves the knowledge of, if
+// -secret-stdin is not set; it is removed from the environment once read
+const SecretEnv = "GNARK_WORKSHOP_SECRET"
+
+// maxSecretLen bounds the secrets read from stdin, more than any circuit takes
+const maxSecretLen = 4096
+
+// readSecret returns the secret of the user, read from stdin with -secret-stdin (a trailing newline
+// is dropped) or from SecretEnv; ok is false if there is none. The secret is never logged: callers
+// wipe it once the proof is done.
+func readSecret() (secret []byte, ok bool, err error) {
+	if *fSecretStdin {
+		// a single buffer, not grown: no copy of the secret is left behind in a discarded one
+		buf := make([]byte, maxSecretLen+1)
+		n, err := io.ReadFull(os.Stdin, buf)
+		switch {
+		case err == io.EOF || err == io.ErrUnexpectedEOF:
+		case err != nil:
+			wipe(buf)
+			return nil, false, err
+		default:
+			wipe(buf)
+			return nil, false, fmt.Errorf("secret longer than %d bytes", maxSecretLen)
+		}
+		secret = bytes.TrimSuffix(bytes.TrimSuffix(buf[:n], []byte("\n")), []byte("\r"))
+		if len(secret) == 0 {
+			return nil, false, errors.New("-secret-stdin: empty secret")
+		}
+		return secret, true, nil
+	}
+	value, ok := os.LookupEnv(SecretEnv)
+	if !ok {
+		return nil, false, nil
+	}
+	// child processes (solc, anvil) don't inherit it; the string itself can't be wiped
+	if err := os.Unsetenv(SecretEnv); err != nil {
+		return nil, false, err
+	}
+	return []byte(value), true, nil
+}
+
+// wipe overwrites b with zeros
+// Only b is wiped: the copies gnark makes while solving and proving (field elements) are left to
+// the garbage collector.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,156 @@
+// Package session describes a facilitator-run workshop session: the shared chain, the
+// deployed verifiers and the artifacts attendees download to prove against them.
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+)
+
+// Network is the chain the session runs on
+type Network struct {
+	RPCURL  string `json:"rpcUrl"`
+	ChainID int64  `json:"chainId"`
+}
+
+// Artifact is a downloadable file; URL may be relative to the session URL
+type Artifact struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// Circuit is a circuit attendees can prove in the session
+type Circuit struct {
+	Verifier common.Address     `json:"verifier"`
+	Manifest artifacts.Manifest `json:"manifest"`
+	R1CS     Artifact           `json:"r1cs"`
+	PK       Artifact           `json:"pk"`
+	VK       Artifact           `json:"vk"`
+}
+
+// Session is the document served by the facilitator at the session URL
+type Session struct {
+	Name     string             `json:"name"`
+	Network  Network            `json:"network"`
+	Circuits map[string]Circuit `json:"circuits"`
+
+	// URL the session was fetched from, relative artifact URLs are resolved against it
+	base *url.URL
+}
+
+// Fetch downloads the session document at sessionURL
+func Fetch(ctx context.Context, sessionURL string) (*Session, error) {
+	base, err := url.Parse(sessionURL)
+	if err != nil {
+		return nil, err
+	}
+	body, err := get(ctx, base.String())
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var s Session
+	if err := json.NewDecoder(body).Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid session document: %w", err)
+	}
+	s.base = base
+	return &s, nil
+}
+
+// Download fetches the artifact into fileName and checks its digest
+func (s *Session) Download(ctx context.Context, a Artifact, fileName string) error {
+	ref, err := url.Parse(a.URL)
+	if err != nil {
+		return err
+	}
+	if s.base != nil {
+		ref = s.base.ResolveReference(ref)
+	}
+
+	body, err := get(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(fileName), filepath.Base(fileName)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if digest := hex.EncodeToString(h.Sum(nil)); digest != a.SHA256 {
+		return fmt.Errorf("%s: sha256 mismatch (got %s, expected %s)", ref, digest, a.SHA256)
+	}
+	return os.Rename(f.Name(), fileName)
+}
+
+// NewArtifact returns the Artifact served at url for the local file fileName
+func NewArtifact(fileName, url string) (Artifact, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return Artifact{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return Artifact{}, err
+	}
+	return Artifact{URL: url, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// Handler serves the session document at / and /session.json, and the files of dir under /artifacts/
+func Handler(s *Session, dir string) http.Handler {
+	mux := http.NewServeMux()
+	serveSession := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" && r.URL.Path != "/session.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s)
+	}
+	mux.HandleFunc("/", serveSession)
+	mux.Handle("/artifacts/", http.StripPrefix("/artifacts/", http.FileServer(http.Dir(dir))))
+	return mux
+}
+
+func get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"path/filepath"
+
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/ceremony"
+)
+
+// runSignManifest signs the manifest of the current setup of the selected circuit with the ed25519
+// key of -key, generated first with -generate, e.g. for a setup made before signing, or whose keys
+// were encrypted since
+func runSignManifest(args []string) {
+	fs := flag.NewFlagSet("sign-manifest", flag.ExitOnError)
+	fKey := fs.String("key", *fSigningKey, "file of the hex ed25519 seed to sign with")
+	fGenerate := fs.Bool("generate", false, "generate the key first if -key doesn't exist")
+	assertNoError(fs.Parse(args))
+	if *fKey == "" {
+		log.Fatal("sign-manifest: -key is required")
+	}
+	key, err := artifacts.ReadSigningKey(*fKey, *fGenerate)
+	assertNoError(err)
+
+	manifest, err := artifacts.ReadManifest(files.manifest)
+	assertNoError(err)
+	if manifest.Circuit == "" {
+		log.Fatalf("%s has no manifest, run -init first", *fCircuit)
+	}
+	// the digests of the current files: sign what is there, checked first against the keys hashes
+	assertNoError(manifest.CheckKeys(files.pk, files.vk))
+	assertNoError(recordDigests(manifest, files))
+	assertNoError(manifest.Sign(key))
+	assertNoError(manifest.Save(files.manifest))
+	log.Printf("%s signed by %s: set %s to it to refuse other artifacts", files.manifest, manifest.Signature.PublicKey, artifacts.TrustedKeyEnv)
+	emit("sign-manifest", signManifestResult{Circuit: *fCircuit, Manifest: files.manifest, PublicKey: manifest.Signature.PublicKey})
+}
+
+// signManifestResult is the JSON output of sign-manifest
+type signManifestResult struct {
+	Circuit   string `json:"circuit"`
+	Manifest  string `json:"manifest"`
+	PublicKey string `json:"publicKey"`
+}
+
+// digestFiles returns the artifacts of cf recorded in the manifest digests, by name
+func digestFiles(cf circuitFiles) map[string]string {
+	digests := make(map[string]string)
+	for _, fileName := range []string{cf.r1cs, cf.solidity, cf.verifierBin, cf.verifierABI} {
+		digests[filepath.Base(fileName)] = fileName
+	}
+	return digests
+}
+
+// recordDigests sets the digests of the artifacts of cf in manifest, the existing files only
+func recordDigests(manifest *artifacts.Manifest, cf circuitFiles) error {
+	manifest.Digests = make(artifacts.Digests)
+	for name, fileName := range digestFiles(cf) {
+		if _, err := artifacts.FileSize(fileName); err != nil {
+			continue
+		}
+		h, err := artifacts.HashFile(fileName)
+		if err != nil {
+			return err
+		}
+		manifest.Digests[name] = h
+	}
+	return nil
+}
+
+// signManifest signs manifest with the key of -signing-key, if set
+func signManifest(manifest *artifacts.Manifest) error {
+	if *fSigningKey == "" {
+		return nil
+	}
+	key, err := artifacts.ReadSigningKey(*fSigningKey, false)
+	if err != nil {
+		return err
+	}
+	if err := manifest.Sign(key); err != nil {
+		return err
+	}
+	log.Printf("%s manifest signed by %s", *fCircuit, manifest.Signature.PublicKey)
+	return nil
+}
+
+// checkManifest verifies the signature of manifest, made with the key of artifacts.TrustedKeyEnv if
+// set, that its beacon contribution is derived from the beacon, then the digests of the files of cf,
+// by artifact name (see digestFiles)
+func checkManifest(manifest *artifacts.Manifest, cf circuitFiles, names ...string) error {
+	trusted, err := artifacts.TrustedKey()
+	if err != nil {
+		return err
+	}
+	if err := manifest.Verify(trusted); err != nil {
+		return err
+	}
+	if manifest.Beacon != nil {
+		if err := ceremony.CheckBeacon(manifest.Beacon.Value, manifest.Beacon.X1); err != nil {
+			return err
+		}
+	}
+	all := digestFiles(cf)
+	checked := make(map[string]string, len(names))
+	for _, name := range names {
+		checked[name] = all[name]
+	}
+	return manifest.CheckDigests(checked)
+}
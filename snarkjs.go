@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"log"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+	"github.com/gbotrel/gnark-workshop/snarkjs"
+)
+
+// runExportProof converts a proof and its public witness to snarkjs proof.json and public.json
+// to be checked with `snarkjs groth16 verify verification_key.json public.json proof.json`
+func runExportProof(args []string) {
+	fs := flag.NewFlagSet("export-proof", flag.ExitOnError)
+	fVK := fs.String("vk", files.vk, "verifying key file, giving the expected number of public inputs")
+	fProof := fs.String("proof", files.proof, "proof file")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	fProofJSON := fs.String("proof-json", "proof.json", "snarkjs proof file to write")
+	fPublicJSON := fs.String("public-json", "public.json", "snarkjs public inputs file to write")
+	assertNoError(fs.Parse(args))
+
+	proof := groth16.NewProof(ecc.BN254)
+	deserialize(proof, *fProof)
+	snarkjsProof, err := snarkjs.FromProof(proof)
+	assertNoError(err)
+	publicWitness := readPublicWitness(*fPublic, *fVK)
+
+	assertNoError(writeJSON(*fProofJSON, snarkjsProof))
+	assertNoError(writeJSON(*fPublicJSON, snarkjs.FromPublicWitness(publicWitness)))
+	log.Println("snarkjs proof written to", *fProofJSON, "and", *fPublicJSON)
+}
+
+// runImportProof converts snarkjs proof.json and public.json to a proof and public witness
+// that verify (and calldata, submit) read
+func runImportProof(args []string) {
+	fs := flag.NewFlagSet("import-proof", flag.ExitOnError)
+	fProofJSON := fs.String("proof-json", "proof.json", "snarkjs proof file")
+	fPublicJSON := fs.String("public-json", "public.json", "snarkjs public inputs file")
+	fProof := fs.String("proof", files.proof, "proof file to write")
+	fPublic := fs.String("public", files.publicWitness, "public witness file to write")
+	assertNoError(fs.Parse(args))
+
+	var snarkjsProof snarkjs.Proof
+	assertNoError(readJSON(*fProofJSON, &snarkjsProof))
+	proof, err := snarkjsProof.ToProof()
+	if err != nil {
+		log.Fatalf("%s: %v", *fProofJSON, err)
+	}
+	var inputs []string
+	assertNoError(readJSON(*fPublicJSON, &inputs))
+	publicWitness, err := snarkjs.ToPublicWitness(inputs)
+	if err != nil {
+		log.Fatalf("%s: %v", *fPublicJSON, err)
+	}
+
+	serialize(proof, *fProof)
+	assertNoError(ioutil.WriteFile(*fPublic, ethereum.EncodePublicWitness(publicWitness), 0644))
+	log.Println("proof written to", *fProof, "and", *fPublic)
+}
+
+func writeJSON(fileName string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, append(data, '\n'), 0644)
+}
+
+func readJSON(fileName string, v interface{}) error {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
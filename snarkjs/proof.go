@@ -0,0 +1,162 @@
+package snarkjs
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// Proof is the content of a snarkjs proof.json
+type Proof struct {
+	A        G1     `json:"pi_a"`
+	B        G2     `json:"pi_b"`
+	C        G1     `json:"pi_c"`
+	Protocol string `json:"protocol"`
+	Curve    string `json:"curve"`
+}
+
+// FromProof returns the snarkjs proof of proof
+func FromProof(proof groth16.Proof) (*Proof, error) {
+	// Ar, Bs, Krs
+	var (
+		ar, krs bn254.G1Affine
+		bs      bn254.G2Affine
+		buf     bytes.Buffer
+	)
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	dec := bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&ar, &bs, &krs} {
+		if err := dec.Decode(v); err != nil {
+			return nil, err
+		}
+	}
+	return &Proof{
+		A:        fromG1(&ar),
+		B:        fromG2(&bs),
+		C:        fromG1(&krs),
+		Protocol: Protocol,
+		Curve:    Curve,
+	}, nil
+}
+
+// ToProof returns the gnark proof of p
+// The points are checked to be on the curve and in the prime order subgroup.
+func (p *Proof) ToProof() (groth16.Proof, error) {
+	if p.Protocol != Protocol || p.Curve != Curve {
+		return nil, ErrCurve
+	}
+	var (
+		ar, krs bn254.G1Affine
+		bs      bn254.G2Affine
+	)
+	if err := toG1(&ar, p.A); err != nil {
+		return nil, fmt.Errorf("pi_a: %w", err)
+	}
+	if err := toG2(&bs, p.B); err != nil {
+		return nil, fmt.Errorf("pi_b: %w", err)
+	}
+	if err := toG1(&krs, p.C); err != nil {
+		return nil, fmt.Errorf("pi_c: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := bn254.NewEncoder(&buf)
+	for _, v := range []interface{}{&ar, &bs, &krs} {
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+	proof := groth16.NewProof(ecc.BN254)
+	if _, err := proof.ReadFrom(&buf); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// FromPublicWitness returns the content of a snarkjs public.json: the public inputs, as decimal strings
+func FromPublicWitness(publicWitness []fr.Element) []string {
+	inputs := make([]string, len(publicWitness))
+	for i := range publicWitness {
+		inputs[i] = publicWitness[i].ToBigIntRegular(new(big.Int)).String()
+	}
+	return inputs
+}
+
+// ToPublicWitness parses the content of a snarkjs public.json
+func ToPublicWitness(inputs []string) ([]fr.Element, error) {
+	publicWitness := make([]fr.Element, len(inputs))
+	for i, s := range inputs {
+		v, err := parse(s, fr.Modulus())
+		if err != nil {
+			return nil, fmt.Errorf("public input %d: %w", i, err)
+		}
+		publicWitness[i].SetBigInt(v)
+	}
+	return publicWitness, nil
+}
+
+func toG1(p *bn254.G1Affine, c G1) error {
+	if c[2] == "0" {
+		p.X.SetZero()
+		p.Y.SetZero()
+		return nil
+	}
+	if c[2] != "1" {
+		return fmt.Errorf("expected z = 1, got %s", c[2])
+	}
+	for i, e := range []*fp.Element{&p.X, &p.Y} {
+		if err := setDecimal(e, c[i]); err != nil {
+			return err
+		}
+	}
+	if !p.IsOnCurve() || !p.IsInSubGroup() {
+		return fmt.Errorf("point is not in the bn128 group")
+	}
+	return nil
+}
+
+func toG2(p *bn254.G2Affine, c G2) error {
+	if c[2] == [2]string{"0", "0"} {
+		p.X.SetZero()
+		p.Y.SetZero()
+		return nil
+	}
+	if c[2] != [2]string{"1", "0"} {
+		return fmt.Errorf("expected z = [1, 0], got %v", c[2])
+	}
+	for i, e := range []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1} {
+		if err := setDecimal(e, c[i/2][i%2]); err != nil {
+			return err
+		}
+	}
+	if !p.IsOnCurve() || !p.IsInSubGroup() {
+		return fmt.Errorf("point is not in the bn128 group")
+	}
+	return nil
+}
+
+func setDecimal(e *fp.Element, s string) error {
+	v, err := parse(s, fp.Modulus())
+	if err != nil {
+		return err
+	}
+	e.SetBigInt(v)
+	return nil
+}
+
+// parse parses a decimal string smaller than modulus
+func parse(s string, modulus *big.Int) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok || v.Sign() < 0 || v.Cmp(modulus) >= 0 {
+		return nil, fmt.Errorf("invalid field element %q", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,96 @@
+package snarkjs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// generatorProof is a proof.json of the generators: valid points, not a valid proof
+func generatorProof(t testing.TB) []byte {
+	t.Helper()
+	_, _, g1, g2 := bn254.Generators()
+	data, err := json.Marshal(Proof{A: fromG1(&g1), B: fromG2(&g2), C: fromG1(&g1), Protocol: Protocol, Curve: Curve})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestProofRoundTrip(t *testing.T) {
+	var p Proof
+	if err := json.Unmarshal(generatorProof(t), &p); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := p.ToProof()
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := FromProof(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *q != p {
+		t.Fatalf("got %+v back, expected %+v", *q, p)
+	}
+
+	// a point off the curve
+	p.A[1] = "3"
+	if _, err := p.ToProof(); err == nil {
+		t.Fatal("decoded a point off the curve")
+	}
+}
+
+func TestPublicWitnessRoundTrip(t *testing.T) {
+	publicWitness := make([]fr.Element, 2)
+	publicWitness[0].SetUint64(42)
+	publicWitness[1].SetOne()
+	publicWitness[1].Neg(&publicWitness[1])
+
+	inputs := FromPublicWitness(publicWitness)
+	decoded, err := ToPublicWitness(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range publicWitness {
+		if !decoded[i].Equal(&publicWitness[i]) {
+			t.Fatalf("public input %d: got %s, expected %s", i, decoded[i].String(), publicWitness[i].String())
+		}
+	}
+	if _, err := ToPublicWitness([]string{fr.Modulus().String()}); err == nil {
+		t.Fatal("parsed the modulus as a public input")
+	}
+}
+
+// FuzzProof checks that the proof.json and public.json decoders give a valid proof or an error for
+// any input, without panicking, and that a decoded proof encodes back to the same points
+func FuzzProof(f *testing.F) {
+	f.Add(generatorProof(f))
+	f.Add([]byte(`{"pi_a": ["1", "2", "1"], "pi_b": [["0", "0"], ["0", "0"], ["0", "0"]], "pi_c": ["0", "1", "0"], "protocol": "groth16", "curve": "bn128"}`))
+	f.Add([]byte(`["1", "0x2a", "21888242871839275222246405745257275088548364400416034343698204186575808495617"]`))
+	f.Add([]byte(`null`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var p Proof
+		if err := json.Unmarshal(data, &p); err != nil {
+			var inputs []string
+			if err := json.Unmarshal(data, &inputs); err == nil {
+				_, _ = ToPublicWitness(inputs)
+			}
+			return
+		}
+		proof, err := p.ToProof()
+		if err != nil {
+			return
+		}
+		q, err := FromProof(proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := q.ToProof(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+// Package snarkjs converts BN254 groth16 keys and proofs to and from the JSON files of snarkjs
+// (verification_key.json, proof.json, public.json), so that the same keys and proofs can be used
+// with the snarkjs CLI and the JavaScript verifiers built on it.
+//
+// snarkjs writes points in projective coordinates, as decimal strings: G1 points are [x, y, z]
+// and G2 points are [[x.c0, x.c1], [y.c0, y.c1], [z.c0, z.c1]], with the real part (gnark's A0)
+// first. Note the Solidity verifiers take G2 coordinates in the opposite order.
+package snarkjs
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+const (
+	// Protocol is the snarkjs name of groth16
+	Protocol = "groth16"
+	// Curve is the snarkjs name of BN254
+	Curve = "bn128"
+)
+
+// ErrCurve is returned for files of another protocol or curve
+var ErrCurve = errors.New("snarkjs: expected a groth16 key or proof on bn128")
+
+// G1 is a snarkjs G1 point
+type G1 [3]string
+
+// G2 is a snarkjs G2 point
+type G2 [3][2]string
+
+// VerifyingKey is the content of a snarkjs verification_key.json
+type VerifyingKey struct {
+	Protocol string `json:"protocol"`
+	Curve    string `json:"curve"`
+	NPublic  int    `json:"nPublic"`
+	Alpha1   G1     `json:"vk_alpha_1"`
+	Beta2    G2     `json:"vk_beta_2"`
+	Gamma2   G2     `json:"vk_gamma_2"`
+	Delta2   G2     `json:"vk_delta_2"`
+	// AlphaBeta12 is e(α, β), which older snarkjs verifiers read instead of pairing α and β
+	AlphaBeta12 [2][3][2]string `json:"vk_alphabeta_12"`
+	// IC is gnark's K: the first point is the constant term, then one per public input
+	IC []G1 `json:"IC"`
+}
+
+// FromVerifyingKey returns the snarkjs verification key of vk
+func FromVerifyingKey(vk groth16.VerifyingKey) (*VerifyingKey, error) {
+	// G1.Alpha, G1.Beta, G2.Beta, G2.Gamma, G1.Delta, G2.Delta, G1.K
+	var (
+		alpha, g1Beta, g1Delta bn254.G1Affine
+		beta, gamma, delta     bn254.G2Affine
+		k                      []bn254.G1Affine
+		buf                    bytes.Buffer
+	)
+	if _, err := vk.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	dec := bn254.NewDecoder(&buf)
+	for _, v := range []interface{}{&alpha, &g1Beta, &beta, &gamma, &g1Delta, &delta, &k} {
+		if err := dec.Decode(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(k) == 0 {
+		return nil, errors.New("snarkjs: verifying key without K")
+	}
+
+	e, err := bn254.Pair([]bn254.G1Affine{alpha}, []bn254.G2Affine{beta})
+	if err != nil {
+		return nil, err
+	}
+	r := &VerifyingKey{
+		Protocol: Protocol,
+		Curve:    Curve,
+		NPublic:  len(k) - 1,
+		Alpha1:   fromG1(&alpha),
+		Beta2:    fromG2(&beta),
+		Gamma2:   fromG2(&gamma),
+		Delta2:   fromG2(&delta),
+		IC:       make([]G1, len(k)),
+	}
+	// the E6 and E2 types of the tower are internal to gnark-crypto
+	for i, c := range [2][3][2]*fp.Element{
+		{{&e.C0.B0.A0, &e.C0.B0.A1}, {&e.C0.B1.A0, &e.C0.B1.A1}, {&e.C0.B2.A0, &e.C0.B2.A1}},
+		{{&e.C1.B0.A0, &e.C1.B0.A1}, {&e.C1.B1.A0, &e.C1.B1.A1}, {&e.C1.B2.A0, &e.C1.B2.A1}},
+	} {
+		for j, b := range c {
+			r.AlphaBeta12[i][j] = [2]string{decimal(b[0]), decimal(b[1])}
+		}
+	}
+	for i := range k {
+		r.IC[i] = fromG1(&k[i])
+	}
+	return r, nil
+}
+
+func fromG1(p *bn254.G1Affine) G1 {
+	if p.IsInfinity() {
+		return G1{"0", "1", "0"}
+	}
+	return G1{decimal(&p.X), decimal(&p.Y), "1"}
+}
+
+func fromG2(p *bn254.G2Affine) G2 {
+	if p.IsInfinity() {
+		return G2{{"0", "0"}, {"1", "0"}, {"0", "0"}}
+	}
+	return G2{
+		{decimal(&p.X.A0), decimal(&p.X.A1)},
+		{decimal(&p.Y.A0), decimal(&p.Y.A1)},
+		{"1", "0"},
+	}
+}
+
+func decimal(e *fp.Element) string {
+	return e.ToBigIntRegular(new(big.Int)).String()
+}
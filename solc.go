@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/gbotrel/gnark-workshop/solc"
+)
+
+// runSolc downloads a solc release in the cache (see solc.Install), checks its checksum and
+// version, and prints its path, e.g. for ethereum.Solc or scripts
+func runSolc(args []string) {
+	fs := flag.NewFlagSet("solc", flag.ExitOnError)
+	fVersion := fs.String("version", solc.DefaultVersion, "solc release to install")
+	fDir := fs.String("dir", "", "cache directory (default: the user cache directory)")
+	assertNoError(fs.Parse(args))
+
+	path, err := solc.Install(mainCtx, *fVersion, *fDir)
+	assertNoError(err)
+	version, err := solc.Version(mainCtx, path)
+	assertNoError(err)
+	if version != *fVersion {
+		log.Fatalf("%s reports version %s, expected %s", path, version, *fVersion)
+	}
+	log.Printf("solc %s in %s", version, path)
+	if !jsonOutput() {
+		fmt.Println(path)
+	}
+	emit("solc", solcResult{Version: version, Path: path})
+}
+
+// solcResult is the JSON output of solc
+type solcResult struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+}
@@ -0,0 +1,235 @@
+// Package solc pins the Solidity compiler the workshop compiles its contracts with: it downloads a
+// given release from binaries.soliditylang.org, checks it against the published checksum, and
+// caches it. It also checks the compiled contracts against the EVM code size limits, and writes
+// the ABI and bytecode artifacts abigen (or ethereum.GenerateBindings) reads.
+package solc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/compiler"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DefaultVersion is the solc release the verifiers are tested with
+const DefaultVersion = "0.8.9"
+
+// BinariesURL serves the solc releases, under <platform>/list.json
+var BinariesURL = "https://binaries.soliditylang.org"
+
+const (
+	// MaxCodeSize is the maximum size of deployed code (EIP-170)
+	MaxCodeSize = 24576
+	// MaxInitCodeSize is the maximum size of creation code (EIP-3860)
+	MaxInitCodeSize = 2 * MaxCodeSize
+)
+
+var (
+	// ErrUnknownVersion is returned for versions that have no release for the platform
+	ErrUnknownVersion = errors.New("unknown solc version")
+	// ErrChecksum is returned when a downloaded or cached compiler doesn't match its published checksum
+	ErrChecksum = errors.New("solc checksum mismatch")
+	// ErrCodeTooLarge is returned for contracts exceeding MaxCodeSize or MaxInitCodeSize
+	ErrCodeTooLarge = errors.New("contract code too large")
+)
+
+// reVersion matches the version in the output of solc --version
+var reVersion = regexp.MustCompile(`Version: (\d+\.\d+\.\d+)`)
+
+// Platform returns the directory of the releases for this platform on BinariesURL
+func Platform() (string, error) {
+	if runtime.GOARCH != "amd64" {
+		return "", fmt.Errorf("no solc release for %s/%s, install solc and leave -solc-version empty", runtime.GOOS, runtime.GOARCH)
+	}
+	switch runtime.GOOS {
+	case "linux":
+		return "linux-amd64", nil
+	case "darwin":
+		return "macosx-amd64", nil
+	case "windows":
+		return "windows-amd64", nil
+	}
+	return "", fmt.Errorf("no solc release for %s/%s, install solc and leave -solc-version empty", runtime.GOOS, runtime.GOARCH)
+}
+
+// CacheDir returns the directory Install caches the compilers in, by default
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gnark-workshop", "solc"), nil
+}
+
+// build is a release of the list.json of a platform
+type build struct {
+	Path        string `json:"path"`
+	Version     string `json:"version"`
+	LongVersion string `json:"longVersion"`
+	SHA256      string `json:"sha256"`
+}
+
+// Install returns the path of solc version, downloaded in dir (CacheDir if empty) unless it is
+// already there; the binary is checked against the checksum published for the release either way
+func Install(ctx context.Context, version, dir string) (string, error) {
+	platform, err := Platform()
+	if err != nil {
+		return "", err
+	}
+	if dir == "" {
+		if dir, err = CacheDir(); err != nil {
+			return "", err
+		}
+	}
+	release, err := lookup(ctx, platform, version)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, release.Path)
+	if runtime.GOOS == "windows" && !strings.HasSuffix(path, ".exe") {
+		path += ".exe"
+	}
+	if err := checkFile(path, release.SHA256); err == nil {
+		return path, nil
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	tmp := path + ".download"
+	if err := download(ctx, BinariesURL+"/"+platform+"/"+release.Path, tmp); err != nil {
+		return "", err
+	}
+	if err := checkFile(tmp, release.SHA256); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("%s: %w", release.Path, err)
+	}
+	if err := os.Chmod(tmp, 0755); err != nil {
+		return "", err
+	}
+	return path, os.Rename(tmp, path)
+}
+
+// lookup returns the release of version in the list of platform
+func lookup(ctx context.Context, platform, version string) (*build, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, BinariesURL+"/"+platform+"/list.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", req.URL, resp.Status)
+	}
+	var list struct {
+		Builds []build `json:"builds"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("%s: %w", req.URL, err)
+	}
+	for i := range list.Builds {
+		// nightlies have prerelease versions, releases are plain x.y.z
+		if b := &list.Builds[i]; b.Version == version && !strings.Contains(b.LongVersion, "nightly") {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %s for %s", ErrUnknownVersion, version, platform)
+}
+
+func download(ctx context.Context, url, fileName string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// checkFile compares the sha256 of fileName with checksum, 0x prefixed hex
+func checkFile(fileName, checksum string) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != strings.TrimPrefix(checksum, "0x") {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// Version returns the version of the solc binary at path, e.g. 0.8.9
+func Version(ctx context.Context, path string) (string, error) {
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("%s --version: %w", path, err)
+	}
+	m := reVersion.FindSubmatch(out)
+	if m == nil {
+		return "", fmt.Errorf("%s --version: unexpected output %q", path, out)
+	}
+	return string(m[1]), nil
+}
+
+// CheckSize checks the creation and deployed code of the compiled contract name against the EVM limits
+func CheckSize(name string, contract *compiler.Contract) error {
+	if n := len(common.FromHex(contract.RuntimeCode)); n > MaxCodeSize {
+		return fmt.Errorf("%w: %s deployed code is %d bytes, the limit is %d (EIP-170)", ErrCodeTooLarge, name, n, MaxCodeSize)
+	}
+	if n := len(common.FromHex(contract.Code)); n > MaxInitCodeSize {
+		return fmt.Errorf("%w: %s creation code is %d bytes, the limit is %d (EIP-3860)", ErrCodeTooLarge, name, n, MaxInitCodeSize)
+	}
+	return nil
+}
+
+// WriteArtifacts writes the ABI and the creation bytecode of contract to prefix.abi and prefix.bin,
+// as abigen --abi prefix.abi --bin prefix.bin reads them
+func WriteArtifacts(prefix string, contract *compiler.Contract) error {
+	abiJSON, err := json.Marshal(contract.Info.AbiDefinition)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(prefix+".abi", append(abiJSON, '\n'), 0644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(prefix+".bin", []byte(hexutil.Encode(common.FromHex(contract.Code))+"\n"), 0644)
+}
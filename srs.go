@@ -0,0 +1,189 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+	"github.com/gbotrel/gnark-workshop/circuits"
+	"github.com/gbotrel/gnark-workshop/fetch"
+	"github.com/gbotrel/gnark-workshop/srs"
+)
+
+// ptauURL is the .ptau file of power %d of the perpetual powers of tau (Hermez final files, 54
+// contributions and a random beacon)
+const ptauURL = "https://hermez.s3-eu-west-1.amazonaws.com/powersOfTau28_hez_final_%02d.ptau"
+
+// runSRS manages the KZG SRS of PLONK circuits, see package srs
+//
+//	srs fetch -sha256 hex [-size n]   download the .ptau file the selected circuit (or n points) needs, verify it and store its SRS
+//	srs verify [-sha256 hex]          check that an SRS (or .ptau) file is made of powers of one τ
+//	srs info                          print the size of an SRS file and the circuits it fits
+func runSRS(args []string) {
+	fs := flag.NewFlagSet("srs", flag.ExitOnError)
+	fDir := fs.String("dir", defaultSRSDir(), "directory of the SRS files, reused across circuits")
+	fSize := fs.Int("size", 0, "number of G1 points (fetch), from the PLONK constraints of the selected circuit if 0")
+	fURL := fs.String("url", ptauURL, "URL of the .ptau file, %d is its power (fetch)")
+	fSHA256 := fs.String("sha256", "", "expected sha256 of the .ptau file (fetch, required to download it), or of the file (verify)")
+	if len(args) == 0 {
+		log.Fatal("usage: srs fetch|verify|info [-dir dir] [-size n] [-url url] [-sha256 hex] [file]")
+	}
+	step := args[0]
+	assertNoError(fs.Parse(args[1:]))
+
+	switch step {
+	case "fetch":
+		size := *fSize
+		if size == 0 {
+			size = plonkSRSSize(*fCircuit)
+		}
+		fileName, kzgSRS, reused := fetchSRS(*fDir, size, *fURL, *fSHA256)
+		info := srs.NewInfo(kzgSRS)
+		if !jsonOutput() {
+			if reused {
+				fmt.Printf("reusing %s: %d points, fits %d constraints\n", fileName, info.Size, info.MaxConstraints)
+			} else {
+				fmt.Printf("%s: %d points, fits %d constraints\n", fileName, info.Size, info.MaxConstraints)
+			}
+		}
+		emit("srs-fetch", srsResult{File: fileName, Info: info, Reused: reused})
+	case "verify", "info":
+		if fs.NArg() != 1 {
+			log.Fatalf("usage: srs %s file", step)
+		}
+		fileName := fs.Arg(0)
+		if fetch.IsURL(fileName) && !strings.Contains(fileName, "#") && *fSHA256 != "" {
+			fileName += "#sha256=" + strings.ToLower(*fSHA256)
+		}
+		kzgSRS := readSRS(fileName, 0)
+		local, err := fetch.Local(mainCtx, fileName)
+		assertNoError(err)
+		h, err := artifacts.HashFile(local)
+		assertNoError(err)
+		if *fSHA256 != "" && !strings.EqualFold(*fSHA256, h) {
+			log.Fatalf("%s: sha256 is %s, expected %s", fileName, h, *fSHA256)
+		}
+		if step == "verify" {
+			assertNoError(srs.Verify(kzgSRS))
+		}
+		info := srs.NewInfo(kzgSRS)
+		if !jsonOutput() {
+			fmt.Printf("%s: %d points, fits %d constraints\nsha256 %s\n[τ]₂   %s\n", fileName, info.Size, info.MaxConstraints, h, info.Tau2)
+			if step == "verify" {
+				fmt.Println("valid: successive powers of one τ")
+			}
+		}
+		emit("srs-"+step, srsResult{File: fileName, Info: info, SHA256: h})
+	default:
+		log.Fatalf("srs: unknown step %q, expected fetch, verify or info", step)
+	}
+}
+
+// srsResult is the JSON output of srs
+type srsResult struct {
+	File string `json:"file"`
+	srs.Info
+	SHA256 string `json:"sha256,omitempty"`
+	Reused bool   `json:"reused,omitempty"`
+}
+
+// defaultSRSDir is the srs directory next to the downloaded artifacts, shared by the projects of
+// the user
+func defaultSRSDir() string {
+	dir, err := fetch.CacheDir()
+	if err != nil {
+		return "srs"
+	}
+	return filepath.Join(filepath.Dir(dir), "srs")
+}
+
+// plonkSRSSize compiles the circuit for PLONK and returns the size of the SRS it needs
+func plonkSRSSize(name string) int {
+	c, err := circuits.Get(name)
+	assertNoError(err)
+	ccs, err := frontend.Compile(ecc.BN254, backend.PLONK, c)
+	assertNoError(err)
+	_, _, public := ccs.GetNbVariables()
+	size := srs.Size(ccs.GetNbConstraints(), public)
+	log.Printf("%s: %d PLONK constraints, %d public inputs, %d SRS points", name, ccs.GetNbConstraints(), public, size)
+	return size
+}
+
+// fetchSRS returns the SRS file of dir of at least size points, or converts one from the .ptau file
+// of url, downloaded and verified first; reused is set if the file was there
+func fetchSRS(dir string, size int, url, sha256 string) (fileName string, kzgSRS *kzg.SRS, reused bool) {
+	if fileName := findSRS(dir, size); fileName != "" {
+		return fileName, readSRS(fileName, 0), true
+	}
+	power := srs.Power(size)
+	if strings.Contains(url, "%") {
+		url = fmt.Sprintf(url, power)
+	}
+	switch {
+	case fetch.IsURL(url) && sha256 == "":
+		log.Fatalf("srs fetch: -sha256 is required to download %s, the published sha256 of the .ptau file", url)
+	case fetch.IsURL(url):
+		url += "#sha256=" + strings.ToLower(sha256)
+	case sha256 == "":
+		log.Printf("no -sha256 given: %s is only checked to be an SRS, not to be the ceremony's", url)
+	default:
+		h, err := artifacts.HashFile(url)
+		assertNoError(err)
+		if !strings.EqualFold(h, sha256) {
+			log.Fatalf("%s: sha256 is %s, expected %s", url, h, sha256)
+		}
+	}
+	kzgSRS = readSRS(url, size)
+	log.Printf("verifying %d points", size)
+	assertNoError(srs.Verify(kzgSRS))
+
+	assertNoError(os.MkdirAll(dir, 0755))
+	fileName = filepath.Join(dir, fmt.Sprintf("bn254_%d.srs", size))
+	assertNoError(srs.WriteFile(fileName, kzgSRS))
+	return fileName, kzgSRS, false
+}
+
+// findSRS returns the smallest SRS file of dir of at least size points, named by fetchSRS
+func findSRS(dir string, size int) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "bn254_*.srs"))
+	best, bestSize := "", 0
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), "bn254_"), ".srs"))
+		if err != nil || n < size || (best != "" && n >= bestSize) {
+			continue
+		}
+		best, bestSize = m, n
+	}
+	return best
+}
+
+// readSRS reads an SRS file, or the first size points of a .ptau file, all of them if size is 0;
+// fileName may be a URL (see fetch.Local)
+func readSRS(fileName string, size int) *kzg.SRS {
+	local, err := fetch.Local(mainCtx, fileName)
+	assertNoError(err)
+	if !strings.HasSuffix(local, ".ptau") && !strings.HasSuffix(strings.SplitN(fileName, "#", 2)[0], ".ptau") {
+		kzgSRS, err := srs.ReadFile(local)
+		assertNoError(err)
+		if size != 0 {
+			kzgSRS, err = srs.Truncate(kzgSRS, size)
+			assertNoError(err)
+		}
+		return kzgSRS
+	}
+	f, err := os.Open(local)
+	assertNoError(err)
+	defer f.Close()
+	kzgSRS, err := srs.ReadPtau(f, size)
+	assertNoError(err)
+	return kzgSRS
+}
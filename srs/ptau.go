@@ -0,0 +1,157 @@
+package srs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// the sections of a .ptau file snarkjs writes
+const (
+	ptauHeader = 1
+	ptauTauG1  = 2
+	ptauTauG2  = 3
+)
+
+// fpSize is the size of a coordinate of a .ptau point
+const fpSize = fp.Limbs * 8
+
+type ptauSection struct {
+	offset, size int64
+}
+
+// ReadPtau reads the first size powers of τ of a snarkjs .ptau file on BN254 (bn128), all of them
+// if size is 0, e.g. of the perpetual powers of tau ceremony, a phase 1 file prepared or not: only
+// its tauG1 and tauG2 sections are read, the points seeked to, so a large file costs only the
+// points used.
+// The points are not checked, see Verify.
+func ReadPtau(r io.ReadSeeker, size int) (*kzg.SRS, error) {
+	var header [12]byte
+	if err := readFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != "ptau" {
+		return nil, fmt.Errorf("ptau: not a .ptau file")
+	}
+	nbSections := binary.LittleEndian.Uint32(header[8:])
+
+	// the sections, their order is not fixed
+	sections := make(map[uint32]ptauSection)
+	offset := int64(len(header))
+	for i := uint32(0); i < nbSections; i++ {
+		var sectionHeader [12]byte
+		if err := readFull(r, sectionHeader[:]); err != nil {
+			return nil, err
+		}
+		section := ptauSection{offset: offset + 12, size: int64(binary.LittleEndian.Uint64(sectionHeader[4:]))}
+		sections[binary.LittleEndian.Uint32(sectionHeader[:4])] = section
+		offset = section.offset + section.size
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range []uint32{ptauHeader, ptauTauG1, ptauTauG2} {
+		if _, ok := sections[s]; !ok {
+			return nil, fmt.Errorf("ptau: section %d missing", s)
+		}
+	}
+
+	// header: n8, q, power, ceremony power
+	if _, err := r.Seek(sections[ptauHeader].offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var n8 [4]byte
+	if err := readFull(r, n8[:]); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(n8[:]) != fpSize {
+		return nil, fmt.Errorf("ptau: %d bytes field elements, not a bn128 file", binary.LittleEndian.Uint32(n8[:]))
+	}
+	q := make([]byte, fpSize)
+	if err := readFull(r, q); err != nil {
+		return nil, err
+	}
+	if new(big.Int).SetBytes(reverse(q)).Cmp(fp.Modulus()) != 0 {
+		return nil, fmt.Errorf("ptau: not a bn128 file")
+	}
+	var power [4]byte
+	if err := readFull(r, power[:]); err != nil {
+		return nil, err
+	}
+	available := 1<<(binary.LittleEndian.Uint32(power[:])+1) - 1
+	if size == 0 {
+		size = available
+	}
+	if size > available {
+		return nil, fmt.Errorf("ptau: %d points needed, the file has %d (power %d)", size, available, binary.LittleEndian.Uint32(power[:]))
+	}
+
+	srs := &kzg.SRS{G1: make([]bn254.G1Affine, size)}
+	if sections[ptauTauG1].size < int64(size)*2*fpSize || sections[ptauTauG2].size < 2*4*fpSize {
+		return nil, fmt.Errorf("ptau: truncated file")
+	}
+	if _, err := r.Seek(sections[ptauTauG1].offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 4*fpSize)
+	for i := range srs.G1 {
+		if err := readFull(r, buf[:2*fpSize]); err != nil {
+			return nil, err
+		}
+		if err := setMontgomery(buf, &srs.G1[i].X, &srs.G1[i].Y); err != nil {
+			return nil, fmt.Errorf("ptau: tauG1[%d]: %w", i, err)
+		}
+	}
+	if _, err := r.Seek(sections[ptauTauG2].offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	for i := range srs.G2 {
+		if err := readFull(r, buf); err != nil {
+			return nil, err
+		}
+		p := &srs.G2[i]
+		if err := setMontgomery(buf, &p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1); err != nil {
+			return nil, fmt.Errorf("ptau: tauG2[%d]: %w", i, err)
+		}
+	}
+	return srs, nil
+}
+
+// setMontgomery sets the elements from the little endian coordinates of buf, in Montgomery form as
+// snarkjs writes them, fp.Element's own representation
+func setMontgomery(buf []byte, elements ...*fp.Element) error {
+	modulus := fp.Modulus().Bytes()
+	for i, e := range elements {
+		b := buf[i*fpSize : (i+1)*fpSize]
+		if bytes.Compare(reverse(append([]byte(nil), b...)), modulus) >= 0 {
+			return fmt.Errorf("coordinate not reduced")
+		}
+		for j := range e {
+			e[j] = binary.LittleEndian.Uint64(b[j*8:])
+		}
+	}
+	return nil
+}
+
+// reverse reverses b in place, little to big endian
+func reverse(b []byte) []byte {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// readFull is io.ReadFull with io.ErrUnexpectedEOF for a truncated file
+func readFull(r io.Reader, buf []byte) error {
+	_, err := io.ReadFull(r, buf)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
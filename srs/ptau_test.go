@@ -0,0 +1,142 @@
+package srs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// ptauFile is a .ptau file of power, as snarkjs writes it: tauG1 holds the points of srs, and
+// tauG2 [1]₂, [τ]₂ repeated; its sections are in the order of sections, which may list others
+type ptauFile struct {
+	srs      *kzg.SRS
+	power    uint32
+	n8       uint32
+	sections []uint32
+}
+
+func (p ptauFile) bytes() []byte {
+	le := func(elements ...*fp.Element) []byte {
+		b := make([]byte, 0, len(elements)*fpSize)
+		for _, e := range elements {
+			for _, limb := range e {
+				b = append(b, make([]byte, 8)...)
+				binary.LittleEndian.PutUint64(b[len(b)-8:], limb)
+			}
+		}
+		return b
+	}
+	content := map[uint32][]byte{}
+	var header bytes.Buffer
+	_ = binary.Write(&header, binary.LittleEndian, p.n8)
+	header.Write(reverse(fp.Modulus().FillBytes(make([]byte, fpSize))))
+	_ = binary.Write(&header, binary.LittleEndian, p.power)
+	_ = binary.Write(&header, binary.LittleEndian, uint32(28))
+	content[ptauHeader] = header.Bytes()
+	for i := range p.srs.G1 {
+		content[ptauTauG1] = append(content[ptauTauG1], le(&p.srs.G1[i].X, &p.srs.G1[i].Y)...)
+	}
+	for i := 0; i < 1<<p.power; i++ {
+		g2 := &p.srs.G2[i%2]
+		content[ptauTauG2] = append(content[ptauTauG2], le(&g2.X.A0, &g2.X.A1, &g2.Y.A0, &g2.Y.A1)...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("ptau")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(1))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(p.sections)))
+	for _, s := range p.sections {
+		data, ok := content[s]
+		if !ok {
+			data = []byte("another section")
+		}
+		_ = binary.Write(&buf, binary.LittleEndian, s)
+		_ = binary.Write(&buf, binary.LittleEndian, uint64(len(data)))
+		buf.Write(data)
+	}
+	return buf.Bytes()
+}
+
+func newPtau(t *testing.T) ptauFile {
+	// power 2: 7 points
+	return ptauFile{srs: newSRS(t, 7, 12345), power: 2, n8: fpSize, sections: []uint32{ptauHeader, ptauTauG1, ptauTauG2}}
+}
+
+func TestReadPtau(t *testing.T) {
+	p := newPtau(t)
+	for name, tc := range map[string]struct {
+		sections []uint32
+		size     int
+	}{
+		"all points": {sections: p.sections},
+		"3 points":   {sections: p.sections, size: 3},
+		// the sections are seeked to: their order is not fixed, and others are skipped
+		"sections out of order": {sections: []uint32{ptauTauG2, 7, ptauHeader, 4, ptauTauG1}},
+	} {
+		file := p
+		file.sections = tc.sections
+		srs, err := ReadPtau(bytes.NewReader(file.bytes()), tc.size)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		expected := len(p.srs.G1)
+		if tc.size != 0 {
+			expected = tc.size
+		}
+		if len(srs.G1) != expected {
+			t.Errorf("%s: %d points, expected %d", name, len(srs.G1), expected)
+			continue
+		}
+		for i := range srs.G1 {
+			if !srs.G1[i].Equal(&p.srs.G1[i]) {
+				t.Errorf("%s: [τ^%d]₁ differs", name, i)
+			}
+		}
+		if !srs.G2[0].Equal(&p.srs.G2[0]) || !srs.G2[1].Equal(&p.srs.G2[1]) {
+			t.Errorf("%s: G2 points differ", name)
+		}
+		if err := Verify(srs); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestReadPtauInvalid(t *testing.T) {
+	valid := newPtau(t)
+	file := valid.bytes()
+	// the file header, then the header section (n8, q, power, ceremony power) and the header of tauG1
+	const tauG1Offset = 12 + 12 + 4 + fpSize + 4 + 4 + 12
+	for name, tc := range map[string]struct {
+		data []byte
+		size int
+		// expected, if set, is the expected error, any error otherwise
+		expected error
+	}{
+		"too many points":   {data: file, size: 8},
+		"not a ptau file":   {data: append([]byte("zkey"), file[4:]...)},
+		"empty":             {data: nil, expected: io.ErrUnexpectedEOF},
+		"truncated":         {data: file[:tauG1Offset+100], expected: io.ErrUnexpectedEOF},
+		"missing tauG2":     {data: ptauFile{srs: valid.srs, power: 2, n8: fpSize, sections: []uint32{ptauHeader, ptauTauG1}}.bytes()},
+		"another field":     {data: ptauFile{srs: valid.srs, power: 2, n8: 48, sections: valid.sections}.bytes()},
+		"missing G1 points": {data: ptauFile{srs: &kzg.SRS{G1: valid.srs.G1[:5], G2: valid.srs.G2}, power: 2, n8: fpSize, sections: valid.sections}.bytes()},
+		"not reduced": {
+			data: func() []byte {
+				data := append([]byte(nil), file...)
+				// the last byte of the first coordinate of tauG1, the most significant
+				data[tauG1Offset+fpSize-1] = 0xff
+				return data
+			}(),
+		},
+	} {
+		_, err := ReadPtau(bytes.NewReader(tc.data), tc.size)
+		if err == nil || tc.expected != nil && !errors.Is(err, tc.expected) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Package srs manages the universal structured reference strings of KZG commitments on BN254, as
+// PLONK uses them: the powers [τⁱ]₁ and [1]₂, [τ]₂ of a secret τ nobody must know.
+//
+// Generating an SRS locally (kzg.NewSRS with a known τ) is only fit for tests: whoever knows τ can
+// forge proofs. Instead, SRS are converted from the output of a public ceremony, the snarkjs .ptau
+// files of the perpetual powers of tau (see ReadPtau), and checked to be powers of the same τ (see
+// Verify) before they are used.
+package srs
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// ErrInvalidSRS is returned when an SRS is not made of the successive powers of one τ
+var ErrInvalidSRS = errors.New("srs: invalid SRS")
+
+// Size returns the number of G1 points a PLONK circuit of constraints constraints (of the sparse
+// R1CS) and public public inputs needs: the size of its evaluation domain, a power of 2, plus 3 for
+// the blinding of the quotient
+func Size(constraints, public int) int {
+	return Domain(constraints, public) + 3
+}
+
+// Domain returns the size of the evaluation domain of a PLONK circuit, the public inputs taking
+// a row each
+func Domain(constraints, public int) int {
+	n := constraints + public
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len(uint(n-1))
+}
+
+// Power returns the power of 2 of the smallest .ptau file holding size G1 points: a file of power
+// p has 2^(p+1) - 1 of them
+func Power(size int) int {
+	p := 0
+	for 1<<(p+1)-1 < size {
+		p++
+	}
+	return p
+}
+
+// Info describes an SRS
+type Info struct {
+	// Size is the number of G1 points, the largest Size a circuit can need
+	Size int `json:"size"`
+	// MaxConstraints is the number of constraints and public inputs of the largest circuit it fits
+	MaxConstraints int `json:"maxConstraints"`
+	// Tau2 is [τ]₂, compressed and hex encoded, which identifies the ceremony
+	Tau2 string `json:"tau2"`
+}
+
+// NewInfo returns the Info of srs
+func NewInfo(srs *kzg.SRS) Info {
+	info := Info{Size: len(srs.G1)}
+	if domain := len(srs.G1) - 3; domain > 0 {
+		// the largest power of 2 that fits
+		info.MaxConstraints = 1 << (bits.Len(uint(domain)) - 1)
+	}
+	tau2 := srs.G2[1].Bytes()
+	info.Tau2 = fmt.Sprintf("%x", tau2[:])
+	return info
+}
+
+// Truncate returns the first size G1 points of srs, still an SRS of the same τ
+func Truncate(srs *kzg.SRS, size int) (*kzg.SRS, error) {
+	if size > len(srs.G1) {
+		return nil, fmt.Errorf("srs: %d points needed, the SRS has %d", size, len(srs.G1))
+	}
+	return &kzg.SRS{G1: srs.G1[:size], G2: srs.G2}, nil
+}
+
+// Verify checks that srs starts with the generators and is made of the powers of one τ ∉ {0, 1}:
+// the points are in their groups, and e([τⁱ⁺¹]₁, [1]₂) = e([τⁱ]₁, [τ]₂) for all i, checked at once on
+// a random linear combination. It doesn't tell whether τ is known to someone, which only the
+// ceremony transcript does.
+func Verify(srs *kzg.SRS) error {
+	if len(srs.G1) < 2 {
+		return fmt.Errorf("%w: %d G1 points", ErrInvalidSRS, len(srs.G1))
+	}
+	_, _, g1, g2 := bn254.Generators()
+	if !srs.G1[0].Equal(&g1) || !srs.G2[0].Equal(&g2) {
+		return fmt.Errorf("%w: doesn't start with the generators", ErrInvalidSRS)
+	}
+	if srs.G2[1].Equal(&g2) || srs.G2[1].IsInfinity() {
+		return fmt.Errorf("%w: τ is 0 or 1", ErrInvalidSRS)
+	}
+	if !srs.G2[1].IsOnCurve() || !srs.G2[1].IsInSubGroup() {
+		return fmt.Errorf("%w: [τ]₂ is not in G2", ErrInvalidSRS)
+	}
+	for i := range srs.G1 {
+		if !srs.G1[i].IsOnCurve() || !srs.G1[i].IsInSubGroup() {
+			return fmt.Errorf("%w: [τ^%d]₁ is not in G1", ErrInvalidSRS, i)
+		}
+	}
+
+	// fresh randomness on every call: Σrᵢ[τⁱ⁺¹]₁ = τ·Σrᵢ[τⁱ]₁ only holds for all r if every power is right
+	n := len(srs.G1) - 1
+	r := make([]fr.Element, n)
+	for i := range r {
+		if _, err := r[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+	var a, b bn254.G1Affine
+	if _, err := a.MultiExp(srs.G1[:n], r, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if _, err := b.MultiExp(srs.G1[1:], r, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	a.Neg(&a)
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{b, a}, []bn254.G2Affine{srs.G2[0], srs.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the points are not successive powers of τ", ErrInvalidSRS)
+	}
+	return nil
+}
+
+// ReadFile reads the SRS of fileName, written by WriteFile
+func ReadFile(fileName string) (*kzg.SRS, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var srs kzg.SRS
+	if _, err := srs.ReadFrom(f); err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return &srs, nil
+}
+
+// WriteFile writes srs to fileName, in gnark-crypto's format
+func WriteFile(fileName string, srs *kzg.SRS) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := srs.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,125 @@
+package srs
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// newSRS returns an SRS of size points with a known τ, fit for tests only
+func newSRS(t *testing.T, size int, tau int64) *kzg.SRS {
+	t.Helper()
+	srs, err := kzg.NewSRS(uint64(size), big.NewInt(tau))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srs
+}
+
+func TestSize(t *testing.T) {
+	for _, tc := range []struct {
+		constraints, public, domain, size, power int
+	}{
+		{0, 0, 1, 4, 2},
+		{1, 1, 2, 5, 2},
+		{3, 1, 4, 7, 2},
+		{4, 1, 8, 11, 3},
+		{1000, 2, 1024, 1027, 10},
+		{1 << 20, 0, 1 << 20, 1<<20 + 3, 20},
+	} {
+		if d := Domain(tc.constraints, tc.public); d != tc.domain {
+			t.Errorf("Domain(%d, %d) = %d, expected %d", tc.constraints, tc.public, d, tc.domain)
+		}
+		size := Size(tc.constraints, tc.public)
+		if size != tc.size {
+			t.Errorf("Size(%d, %d) = %d, expected %d", tc.constraints, tc.public, size, tc.size)
+		}
+		if p := Power(size); p != tc.power || 1<<(p+1)-1 < size || (p > 0 && 1<<p-1 >= size) {
+			t.Errorf("Power(%d) = %d, expected %d", size, p, tc.power)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	if err := Verify(newSRS(t, 16, 12345)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, g1, g2 := bn254.Generators()
+	var two big.Int
+	two.SetUint64(2)
+	for name, tamper := range map[string]func(srs *kzg.SRS){
+		"G1 point":       func(srs *kzg.SRS) { srs.G1[5].ScalarMultiplication(&srs.G1[5], &two) },
+		"last G1 point":  func(srs *kzg.SRS) { srs.G1[15].ScalarMultiplication(&srs.G1[15], &two) },
+		"swapped powers": func(srs *kzg.SRS) { srs.G1[3], srs.G1[4] = srs.G1[4], srs.G1[3] },
+		"another τ":      func(srs *kzg.SRS) { srs.G2[1] = newSRS(t, 2, 54321).G2[1] },
+		"G1 generator":   func(srs *kzg.SRS) { srs.G1[0] = srs.G1[1] },
+		"G2 generator":   func(srs *kzg.SRS) { srs.G2[0] = srs.G2[1] },
+		"τ = 1":          func(srs *kzg.SRS) { *srs = *newSRS(t, 16, 1) },
+		"τ = 0":          func(srs *kzg.SRS) { srs.G2[1].X.SetZero(); srs.G2[1].Y.SetZero() },
+		"off curve":      func(srs *kzg.SRS) { srs.G1[2].Y.Add(&srs.G1[2].Y, &g1.Y) },
+		"too short":      func(srs *kzg.SRS) { srs.G1 = srs.G1[:1] },
+		"[τ]₂ off curve": func(srs *kzg.SRS) { srs.G2[1].Y.Add(&srs.G2[1].Y, &g2.Y) },
+	} {
+		srs := newSRS(t, 16, 12345)
+		tamper(srs)
+		if err := Verify(srs); !errors.Is(err, ErrInvalidSRS) {
+			t.Errorf("%s: got %v, expected ErrInvalidSRS", name, err)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	srs := newSRS(t, 16, 12345)
+	truncated, err := Truncate(srs, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(truncated.G1) != 7 {
+		t.Fatalf("%d points", len(truncated.G1))
+	}
+	if err := Verify(truncated); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Truncate(srs, 17); err == nil {
+		t.Fatal("truncated to more points than the SRS has")
+	}
+}
+
+func TestInfo(t *testing.T) {
+	srs := newSRS(t, Size(1000, 2), 12345)
+	info := NewInfo(srs)
+	if info.Size != 1027 || info.MaxConstraints != 1024 {
+		t.Fatalf("size %d, %d constraints", info.Size, info.MaxConstraints)
+	}
+	if Size(info.MaxConstraints, 0) > info.Size || Size(info.MaxConstraints+1, 0) <= info.Size {
+		t.Fatalf("%d constraints is not the largest circuit fitting %d points", info.MaxConstraints, info.Size)
+	}
+	if NewInfo(newSRS(t, 1027, 54321)).Tau2 == info.Tau2 {
+		t.Fatal("two ceremonies have the same [τ]₂")
+	}
+}
+
+func TestReadWriteFile(t *testing.T) {
+	srs := newSRS(t, 16, 12345)
+	fileName := filepath.Join(t.TempDir(), "bn254.srs")
+	if err := WriteFile(fileName, srs); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if NewInfo(read) != NewInfo(srs) {
+		t.Fatal("the SRS changed")
+	}
+	for i := range srs.G1 {
+		if !read.G1[i].Equal(&srs.G1[i]) {
+			t.Fatalf("[τ^%d]₁ changed", i)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package stateproof
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gbotrel/gnark-workshop/circuit"
+)
+
+// ErrNotAFieldElement is returned for values that don't fit in a BN254 field element: they must be
+// split in halves instead
+var ErrNotAFieldElement = errors.New("value doesn't fit in a field element")
+
+// Inputs are the witness inputs of a storage value: 256-bit words are split in their big-endian
+// 128-bit halves (as circuit.Keccak256PublicInputs), which always fit in a field element
+// Value is the value as a single field element, nil if it doesn't fit in one.
+type Inputs struct {
+	Block         uint64         `json:"block"`
+	Address       common.Address `json:"address"`
+	StateRootHi   *hexutil.Big   `json:"stateRootHi"`
+	StateRootLo   *hexutil.Big   `json:"stateRootLo"`
+	StorageRootHi *hexutil.Big   `json:"storageRootHi"`
+	StorageRootLo *hexutil.Big   `json:"storageRootLo"`
+	SlotHi        *hexutil.Big   `json:"slotHi"`
+	SlotLo        *hexutil.Big   `json:"slotLo"`
+	Value         *hexutil.Big   `json:"value,omitempty"`
+	ValueHi       *hexutil.Big   `json:"valueHi"`
+	ValueLo       *hexutil.Big   `json:"valueLo"`
+}
+
+// NewInputs returns the inputs of the i-th storage value of p, proven in block of state root
+// stateRoot; p must have been verified (see AccountProof.Verify)
+func NewInputs(block uint64, stateRoot common.Hash, p *AccountProof, i int) Inputs {
+	sp := &p.StorageProof[i]
+	value := common.BigToHash(sp.Value.ToInt())
+	in := Inputs{Block: block, Address: p.Address}
+	in.StateRootHi, in.StateRootLo = Halves(stateRoot)
+	in.StorageRootHi, in.StorageRootLo = Halves(p.StorageHash)
+	in.SlotHi, in.SlotLo = Halves(sp.Slot())
+	in.ValueHi, in.ValueLo = Halves(value)
+	if v, err := FieldElement(sp.Value.ToInt()); err == nil {
+		in.Value = (*hexutil.Big)(v)
+	}
+	return in
+}
+
+// Halves splits h in its big-endian 128-bit halves
+func Halves(h common.Hash) (hi, lo *hexutil.Big) {
+	return (*hexutil.Big)(new(big.Int).SetBytes(h[:16])), (*hexutil.Big)(new(big.Int).SetBytes(h[16:]))
+}
+
+// FieldElement returns v if it is a BN254 field element, ErrNotAFieldElement otherwise
+func FieldElement(v *big.Int) (*big.Int, error) {
+	if v.Sign() < 0 || v.Cmp(fr.Modulus()) >= 0 {
+		return nil, ErrNotAFieldElement
+	}
+	return new(big.Int).Set(v), nil
+}
+
+// MappingSlot returns the storage slot of mapping[key], for a Solidity mapping declared at slot
+func MappingSlot(key common.Hash, slot uint64) common.Hash {
+	return crypto.Keccak256Hash(circuit.MappingSlotPreimage(key, slot))
+}
+
+// MappingSlotWitness returns the keccak256 circuit assignment proving the knowledge of the key of
+// the mapping slot MappingSlot(key, slot): the public digest is the slot of the storage proof
+func MappingSlotWitness(key common.Hash, slot uint64) (*circuit.Keccak256Circuit, error) {
+	return circuit.NewKeccak256Witness(circuit.MappingSlotPreimage(key, slot))
+}
@@ -0,0 +1,96 @@
+package stateproof
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestHalves(t *testing.T) {
+	h := common.HexToHash("0x0102030405060708090a0b0c0d0e0f10ffeeddccbbaa99887766554433221100")
+	hi, lo := Halves(h)
+	if hi.ToInt().Text(16) != "102030405060708090a0b0c0d0e0f10" || lo.ToInt().Text(16) != "ffeeddccbbaa99887766554433221100" {
+		t.Fatalf("halves %s %s", hi, lo)
+	}
+	// the halves always fit in a field element
+	for _, half := range []*big.Int{hi.ToInt(), lo.ToInt()} {
+		if _, err := FieldElement(half); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFieldElement(t *testing.T) {
+	max := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	for name, tc := range map[string]struct {
+		v        *big.Int
+		expected error
+	}{
+		"zero":          {v: big.NewInt(0)},
+		"modulus - 1":   {v: max},
+		"modulus":       {v: fr.Modulus(), expected: ErrNotAFieldElement},
+		"256-bit value": {v: new(big.Int).Lsh(big.NewInt(1), 255), expected: ErrNotAFieldElement},
+		"negative":      {v: big.NewInt(-1), expected: ErrNotAFieldElement},
+	} {
+		v, err := FieldElement(tc.v)
+		if !errors.Is(err, tc.expected) {
+			t.Errorf("%s: got %v, expected %v", name, err, tc.expected)
+			continue
+		}
+		if err == nil && (v.Cmp(tc.v) != 0 || v == tc.v) {
+			t.Errorf("%s: got %v, expected a copy of %v", name, v, tc.v)
+		}
+	}
+}
+
+func TestMappingSlot(t *testing.T) {
+	// keccak256(abi.encode(key, slot)), as Solidity computes mapping(key => ...) at slot
+	key := common.HexToHash("0xbeef")
+	expected := crypto.Keccak256Hash(key.Bytes(), common.BigToHash(big.NewInt(2)).Bytes())
+	if got := MappingSlot(key, 2); got != expected {
+		t.Fatalf("got %s, expected %s", got.Hex(), expected.Hex())
+	}
+	if MappingSlot(key, 3) == expected {
+		t.Fatal("the slot of the mapping is ignored")
+	}
+}
+
+func TestNewInputs(t *testing.T) {
+	db, root := newState(t)
+	p := proofOf(t, db, contract, slots...)
+	if err := p.Verify(root); err != nil {
+		t.Fatal(err)
+	}
+
+	in := NewInputs(12, root, p, 0)
+	stateHi, stateLo := Halves(root)
+	storageHi, storageLo := Halves(p.StorageHash)
+	switch {
+	case in.Block != 12 || in.Address != contract:
+		t.Fatalf("block %d, address %s", in.Block, in.Address.Hex())
+	case in.StateRootHi.ToInt().Cmp(stateHi.ToInt()) != 0 || in.StateRootLo.ToInt().Cmp(stateLo.ToInt()) != 0:
+		t.Fatal("state root halves")
+	case in.StorageRootHi.ToInt().Cmp(storageHi.ToInt()) != 0 || in.StorageRootLo.ToInt().Cmp(storageLo.ToInt()) != 0:
+		t.Fatal("storage root halves")
+	case in.SlotHi.ToInt().Sign() != 0 || in.SlotLo.ToInt().Sign() != 0:
+		t.Fatal("slot halves")
+	case in.Value == nil || in.Value.ToInt().Int64() != 0x2a:
+		t.Fatalf("value %v", in.Value)
+	case in.ValueHi.ToInt().Sign() != 0 || in.ValueLo.ToInt().Int64() != 0x2a:
+		t.Fatal("value halves")
+	}
+
+	// a value larger than a field element is only given in halves
+	in = NewInputs(12, root, p, 1)
+	if in.Value != nil {
+		t.Fatalf("value %v doesn't fit in a field element", in.Value)
+	}
+	ones := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	if in.ValueHi.ToInt().Cmp(ones) != 0 || in.ValueLo.ToInt().Cmp(ones) != 0 {
+		t.Fatalf("value halves %s %s", in.ValueHi, in.ValueLo)
+	}
+}
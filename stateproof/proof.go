@@ -0,0 +1,154 @@
+// Package stateproof feeds circuits with Ethereum state: it fetches an account and storage slots
+// with their Merkle-Patricia proofs (eth_getProof), checks the proofs on the host against the state
+// root of a block, decoding the RLP of the trie nodes, accounts and values, and converts the
+// values into witness inputs.
+//
+// The proofs are checked on the host, not in a circuit: a circuit using the values proves a
+// statement about the values, and the verifier must check them against the chain (e.g. the state
+// root against blockhash) itself.
+package stateproof
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// ErrInvalidProof is returned when a proof doesn't match the state root, or the values it proves
+var ErrInvalidProof = errors.New("invalid state proof")
+
+// AccountProof is the eth_getProof response: an account, the storage slots asked for, and their proofs
+type AccountProof struct {
+	Address      common.Address  `json:"address"`
+	Balance      *hexutil.Big    `json:"balance"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	StorageProof []StorageProof  `json:"storageProof"`
+}
+
+// StorageProof is the value of a storage slot, and its proof from the storage root of the account
+type StorageProof struct {
+	// Key is the slot, as asked for: nodes may return it unpadded, see Slot
+	Key   string          `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// Slot returns the storage slot of p
+func (p *StorageProof) Slot() common.Hash {
+	return common.HexToHash(p.Key)
+}
+
+// account is the RLP encoding of an account in the state trie
+type account struct {
+	Nonce    uint64
+	Balance  *big.Int
+	Root     common.Hash
+	CodeHash []byte
+}
+
+// Get fetches the account at address and its storage slots, with their proofs, in the state of
+// block (the latest block if nil)
+func Get(ctx context.Context, client *rpc.Client, address common.Address, slots []common.Hash, block *big.Int) (*AccountProof, error) {
+	blockNumber := "latest"
+	if block != nil {
+		blockNumber = hexutil.EncodeBig(block)
+	}
+	keys := make([]string, len(slots))
+	for i, slot := range slots {
+		keys[i] = slot.Hex()
+	}
+	var p AccountProof
+	if err := client.CallContext(ctx, &p, "eth_getProof", address, keys, blockNumber); err != nil {
+		return nil, fmt.Errorf("eth_getProof: %w", err)
+	}
+	if len(p.StorageProof) != len(slots) {
+		return nil, fmt.Errorf("eth_getProof returned %d storage proofs for %d slots", len(p.StorageProof), len(slots))
+	}
+	return &p, nil
+}
+
+// Verify checks the account proof of p against stateRoot, then each storage proof against the
+// storage root of the account
+// Absent accounts and slots are proven by proofs of their absence: their values must be zero.
+func (p *AccountProof) Verify(stateRoot common.Hash) error {
+	value, err := verifyProof(stateRoot, p.Address.Bytes(), p.AccountProof)
+	if err != nil {
+		return fmt.Errorf("%w: account %s: %v", ErrInvalidProof, p.Address.Hex(), err)
+	}
+	if value == nil {
+		// nodes differ on the storage and code hashes of absent accounts, their values are zero
+		return p.verifyAbsent()
+	}
+	var acc account
+	if err := rlp.DecodeBytes(value, &acc); err != nil {
+		return fmt.Errorf("%w: account %s: %v", ErrInvalidProof, p.Address.Hex(), err)
+	}
+	switch {
+	case acc.Nonce != uint64(p.Nonce):
+		return fmt.Errorf("%w: account %s: nonce %d, proven %d", ErrInvalidProof, p.Address.Hex(), p.Nonce, acc.Nonce)
+	case p.Balance == nil || acc.Balance.Cmp(p.Balance.ToInt()) != 0:
+		return fmt.Errorf("%w: account %s: balance %v, proven %s", ErrInvalidProof, p.Address.Hex(), p.Balance, acc.Balance)
+	case acc.Root != p.StorageHash:
+		return fmt.Errorf("%w: account %s: storage root %s, proven %s", ErrInvalidProof, p.Address.Hex(), p.StorageHash.Hex(), acc.Root.Hex())
+	case !bytes.Equal(acc.CodeHash, p.CodeHash.Bytes()):
+		return fmt.Errorf("%w: account %s: code hash %s, proven %x", ErrInvalidProof, p.Address.Hex(), p.CodeHash.Hex(), acc.CodeHash)
+	}
+
+	for i := range p.StorageProof {
+		sp := &p.StorageProof[i]
+		value, err := verifyProof(p.StorageHash, sp.Slot().Bytes(), sp.Proof)
+		if err != nil {
+			return fmt.Errorf("%w: slot %s: %v", ErrInvalidProof, sp.Slot().Hex(), err)
+		}
+		// values are stored as RLP strings, without leading zeros; absent slots are zero
+		var proven []byte
+		if value != nil {
+			if err := rlp.DecodeBytes(value, &proven); err != nil {
+				return fmt.Errorf("%w: slot %s: %v", ErrInvalidProof, sp.Slot().Hex(), err)
+			}
+		}
+		if sp.Value == nil || new(big.Int).SetBytes(proven).Cmp(sp.Value.ToInt()) != 0 {
+			return fmt.Errorf("%w: slot %s: value %v, proven 0x%x", ErrInvalidProof, sp.Slot().Hex(), sp.Value, proven)
+		}
+	}
+	return nil
+}
+
+// verifyAbsent checks the values of an absent account: its nonce, balance and slots are zero
+func (p *AccountProof) verifyAbsent() error {
+	if p.Nonce != 0 || p.Balance == nil || p.Balance.ToInt().Sign() != 0 {
+		return fmt.Errorf("%w: account %s is absent, but has a nonce or a balance", ErrInvalidProof, p.Address.Hex())
+	}
+	for i := range p.StorageProof {
+		if sp := &p.StorageProof[i]; sp.Value == nil || sp.Value.ToInt().Sign() != 0 {
+			return fmt.Errorf("%w: slot %s of absent account %s is not zero", ErrInvalidProof, sp.Slot().Hex(), p.Address.Hex())
+		}
+	}
+	return nil
+}
+
+// verifyProof returns the value of key in the secure trie of root, as proven by the trie nodes of
+// proof, nil if proof proves its absence
+// Secure tries are keyed by keccak256(key), and their nodes by the keccak256 of their RLP encoding.
+func verifyProof(root common.Hash, key []byte, proof []hexutil.Bytes) ([]byte, error) {
+	nodes := memorydb.New()
+	for _, node := range proof {
+		if err := nodes.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+	return trie.VerifyProof(root, crypto.Keccak256(key), nodes)
+}
@@ -0,0 +1,243 @@
+package stateproof
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	contract = common.HexToAddress("0x00000000000000000000000000000000000c0de0")
+	absent   = common.HexToAddress("0x000000000000000000000000000000000000dead")
+	slots    = []common.Hash{common.HexToHash("0x00"), common.HexToHash("0x01"), MappingSlot(common.HexToHash("0xbeef"), 2)}
+	// emptySlot is a slot of contract that was never written
+	emptySlot = common.HexToHash("0x07")
+)
+
+// newState returns a committed state with contract and a few other accounts, and its root
+func newState(t *testing.T) (*state.StateDB, common.Hash) {
+	t.Helper()
+	db, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := byte(1); i <= 16; i++ {
+		db.AddBalance(common.Address{i}, big.NewInt(int64(i)*1e9))
+	}
+	db.SetNonce(contract, 1)
+	db.AddBalance(contract, big.NewInt(42))
+	db.SetCode(contract, []byte{0x60, 0x00})
+	db.SetState(contract, slots[0], common.HexToHash("0x2a"))
+	// a 256-bit value, larger than a field element
+	db.SetState(contract, slots[1], common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	db.SetState(contract, slots[2], common.HexToHash("0x1234"))
+	root, err := db.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Database().TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if db, err = state.New(root, db.Database(), nil); err != nil {
+		t.Fatal(err)
+	}
+	return db, root
+}
+
+// proofOf returns the eth_getProof response for address and slots in db
+func proofOf(t *testing.T, db *state.StateDB, address common.Address, slots ...common.Hash) *AccountProof {
+	t.Helper()
+	accountProof, err := db.GetProof(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &AccountProof{
+		Address:  address,
+		Balance:  (*hexutil.Big)(db.GetBalance(address)),
+		Nonce:    hexutil.Uint64(db.GetNonce(address)),
+		CodeHash: db.GetCodeHash(address),
+	}
+	if tr := db.StorageTrie(address); tr != nil {
+		p.StorageHash = tr.Hash()
+	}
+	for _, node := range accountProof {
+		p.AccountProof = append(p.AccountProof, node)
+	}
+	for _, slot := range slots {
+		sp := StorageProof{Key: slot.Hex(), Value: (*hexutil.Big)(db.GetState(address, slot).Big())}
+		// as nodes do, absent accounts have no storage proofs
+		if db.Exist(address) {
+			proof, err := db.GetStorageProof(address, slot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, node := range proof {
+				sp.Proof = append(sp.Proof, node)
+			}
+		}
+		p.StorageProof = append(p.StorageProof, sp)
+	}
+	return p
+}
+
+func TestVerify(t *testing.T) {
+	db, root := newState(t)
+
+	for name, tc := range map[string]struct {
+		proof    func() *AccountProof
+		root     common.Hash
+		expected error
+	}{
+		"slots":        {proof: func() *AccountProof { return proofOf(t, db, contract, slots...) }},
+		"empty slot":   {proof: func() *AccountProof { return proofOf(t, db, contract, emptySlot) }},
+		"no slot":      {proof: func() *AccountProof { return proofOf(t, db, contract) }},
+		"absent":       {proof: func() *AccountProof { return proofOf(t, db, absent, slots[0]) }},
+		"another root": {proof: func() *AccountProof { return proofOf(t, db, contract, slots...) }, root: common.Hash{1}, expected: ErrInvalidProof},
+		"value": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, contract, slots...)
+				p.StorageProof[2].Value = (*hexutil.Big)(big.NewInt(0x1235))
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		"empty slot not zero": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, contract, emptySlot)
+				p.StorageProof[0].Value = (*hexutil.Big)(big.NewInt(1))
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		"proof of another slot": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, contract, slots[0], slots[2])
+				p.StorageProof[0].Proof = p.StorageProof[1].Proof
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		"balance": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, contract)
+				p.Balance = (*hexutil.Big)(big.NewInt(43))
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		"nonce": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, contract)
+				p.Nonce++
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		"code hash": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, contract)
+				p.CodeHash = common.Hash{1}
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		// the storage proofs are checked against the proven storage root, not the one returned
+		"storage root": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, contract, slots[0])
+				p.StorageHash = common.Hash{1}
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		"tampered node": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, contract)
+				last := p.AccountProof[len(p.AccountProof)-1]
+				last[len(last)-1] ^= 1
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		"absent with a balance": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, absent)
+				p.Balance = (*hexutil.Big)(big.NewInt(1))
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		"absent with a value": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, absent, slots[0])
+				p.StorageProof[0].Value = (*hexutil.Big)(big.NewInt(1))
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+		"missing value": {
+			proof: func() *AccountProof {
+				p := proofOf(t, db, contract, slots[0])
+				p.StorageProof[0].Value = nil
+				return p
+			},
+			expected: ErrInvalidProof,
+		},
+	} {
+		stateRoot := root
+		if tc.root != (common.Hash{}) {
+			stateRoot = tc.root
+		}
+		if err := tc.proof().Verify(stateRoot); !errors.Is(err, tc.expected) {
+			t.Errorf("%s: got %v, expected %v", name, err, tc.expected)
+		}
+	}
+}
+
+// ethService serves eth_getProof with proof, recording the arguments
+type ethService struct {
+	proof *AccountProof
+	keys  []string
+	block string
+}
+
+func (s *ethService) GetProof(address common.Address, keys []string, block string) (*AccountProof, error) {
+	s.keys, s.block = keys, block
+	return s.proof, nil
+}
+
+func TestGet(t *testing.T) {
+	db, root := newState(t)
+	service := &ethService{proof: proofOf(t, db, contract, slots...)}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	p, err := Get(context.Background(), client, contract, slots, big.NewInt(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.block != "0xc" || len(service.keys) != len(slots) || service.keys[2] != slots[2].Hex() {
+		t.Fatalf("eth_getProof called with %v at %s", service.keys, service.block)
+	}
+	if err := p.Verify(root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(context.Background(), client, contract, slots, nil); err != nil || service.block != "latest" {
+		t.Fatalf("latest block: %s, %v", service.block, err)
+	}
+	if _, err := Get(context.Background(), client, contract, slots[:2], nil); err == nil {
+		t.Fatal("accepted 3 storage proofs for 2 slots")
+	}
+}
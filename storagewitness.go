@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gbotrel/gnark-workshop/circuit"
+	"github.com/gbotrel/gnark-workshop/stateproof"
+)
+
+// runStorageWitness reads a storage slot of a contract with its proof (eth_getProof), checks the
+// proof against the state root of the block, and prints the value as witness inputs
+// With -mapping-key, the slot is the one of mapping[key], and -out writes the keccak256 circuit
+// witness proving the knowledge of the key of the slot.
+func runStorageWitness(args []string) {
+	fs := flag.NewFlagSet("storage-witness", flag.ExitOnError)
+	fNode := addNodeFlags(fs)
+	fAddress := fs.String("address", "", "contract address")
+	fSlot := fs.Uint64("slot", 0, "storage slot, or slot of the mapping declaration with -mapping-key")
+	fKey := fs.String("mapping-key", "", "if set, 32-byte hex key: read mapping[key], the mapping being declared at -slot")
+	fBlock := fs.Int64("block", -1, "block number, the latest block if negative")
+	fOut := fs.String("out", "", "with -mapping-key, JSON witness file of the keccak256 circuit for the slot")
+	assertNoError(fs.Parse(args))
+	if !common.IsHexAddress(*fAddress) {
+		log.Fatalf("-address %q is not an address", *fAddress)
+	}
+	if *fOut != "" && *fKey == "" {
+		log.Fatal("-out requires -mapping-key")
+	}
+
+	slot := common.BigToHash(new(big.Int).SetUint64(*fSlot))
+	var key common.Hash
+	if *fKey != "" {
+		key = common.HexToHash(*fKey)
+		slot = stateproof.MappingSlot(key, *fSlot)
+	}
+
+	ctx := mainCtx
+	client := fNode.dial(ctx)
+	defer client.Close()
+	var number *big.Int
+	if *fBlock >= 0 {
+		number = big.NewInt(*fBlock)
+	}
+	header, err := client.HeaderByNumber(ctx, number)
+	assertNoError(err)
+
+	address := common.HexToAddress(*fAddress)
+	proof, err := stateproof.Get(ctx, client.rpc, address, []common.Hash{slot}, header.Number)
+	assertNoError(err)
+	assertNoError(proof.Verify(header.Root))
+	log.Printf("slot %s of %s at block %d: %s, proof checked against state root %s",
+		slot.Hex(), address.Hex(), header.Number, proof.StorageProof[0].Value, header.Root.Hex())
+
+	result := storageWitnessResult{Slot: slot, Inputs: stateproof.NewInputs(header.Number.Uint64(), header.Root, proof, 0)}
+	if *fOut != "" {
+		// the JSON form of stateproof.MappingSlotWitness, see circuits.FromJSON
+		preimage := circuit.MappingSlotPreimage(key, *fSlot)
+		secret := make([]int, len(preimage))
+		for i, b := range preimage {
+			secret[i] = int(b)
+		}
+		hi, lo := circuit.Keccak256PublicInputs(slot)
+		assertNoError(writeJSON(*fOut, map[string]interface{}{
+			"Secret":   secret,
+			"DigestHi": hexutil.Encode(hi),
+			"DigestLo": hexutil.Encode(lo),
+		}))
+		log.Printf("keccak256 witness written to %s (go run . -circuit keccak256 debug-witness -witness %s)", *fOut, *fOut)
+	}
+	if !jsonOutput() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		assertNoError(enc.Encode(result.Inputs))
+	}
+	emit("storage-witness", result)
+}
+
+// storageWitnessResult is the JSON output of storage-witness
+type storageWitnessResult struct {
+	Slot   common.Hash       `json:"slot"`
+	Inputs stateproof.Inputs `json:"inputs"`
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	gethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runSubmit calls verifyProof of an already deployed verifier with a serialized proof
+// The view call result is always printed; with -tx, the call is also sent as a transaction
+// (the verifier doesn't change state, but it shows the real gas cost of a verification).
+func runSubmit(args []string) {
+	fs := flag.NewFlagSet("submit", flag.ExitOnError)
+	fTxFlags := addTxFlags(fs)
+	fAddress := fs.String("address", "", "verifier contract address, read from the deployments file if not set")
+	fDeployments := fs.String("deployments", deploymentsPath, "deployments file")
+	fProof := fs.String("proof", files.proof, "proof file")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	fVK := fs.String("vk", files.vk, "verifying key file, giving the expected number of public inputs")
+	fTx := fs.Bool("tx", false, "also send the call as a transaction")
+	fSigner := addSignerFlags(fs, "sender (with -tx)")
+	assertNoError(fs.Parse(args))
+
+	// build the calldata
+	proof := groth16.NewProof(ecc.BN254)
+	deserialize(proof, *fProof)
+	publicWitness := readPublicWitness(*fPublic, *fVK)
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	assertNoError(err)
+	calldata, err := solidityInputs.Calldata()
+	assertNoError(err)
+
+	ctx := mainCtx
+	client := fTxFlags.dial(ctx)
+	defer client.Close()
+	chainID := client.chainID
+	address := verifierAddress(*fAddress, *fDeployments, chainID)
+
+	// view call
+	msg := gethereum.CallMsg{To: &address, Data: calldata}
+	var signer ethereum.Signer
+	if *fTx {
+		var closeSigner func()
+		signer, closeSigner = fSigner.signer()
+		defer closeSigner()
+		msg.From = signer.Address()
+	}
+	out, err := client.CallContract(ctx, msg, nil)
+	assertNoError(err)
+	gas, err := client.EstimateGas(ctx, msg)
+	assertNoError(err)
+	valid := len(out) == 32 && new(big.Int).SetBytes(out).Sign() != 0
+	log.Printf("verifyProof on %s returned %t (estimated gas: %d)", address.Hex(), valid, gas)
+
+	result := submitResult{ChainID: chainID.Int64(), Verifier: address, Valid: valid, EstimatedGas: gas}
+	if *fTx {
+		receipt := client.sendCall(ctx, signer, address, calldata, gas)
+		log.Printf("transaction mined in block %d (status: %d, gas used: %d)", receipt.BlockNumber, receipt.Status, receipt.GasUsed)
+		result.Transaction = newTxResult(receipt)
+	}
+	emit("submit", result)
+
+	if !valid {
+		os.Exit(1)
+	}
+}
+
+// submitResult is the JSON output of submit
+type submitResult struct {
+	ChainID      int64          `json:"chainId"`
+	Verifier     common.Address `json:"verifier"`
+	Valid        bool           `json:"valid"`
+	EstimatedGas uint64         `json:"estimatedGas"`
+	Transaction  *txResult      `json:"transaction,omitempty"`
+}
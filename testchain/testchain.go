@@ -0,0 +1,162 @@
+// Package testchain sets up a geth simulated backend with named, funded accounts, so that demos and
+// tests can deploy verifiers and send proofs as different provers and callers.
+//
+//	chain, err := testchain.New(testchain.Genesis{Accounts: []string{"deployer", "alice", "bob"}})
+//	address, err := chain.DeployRaw("deployer", initcode)
+//	tx, err := app.Unlock(chain.Transactor("alice"), ...)
+//	receipt, err := chain.Mine(tx)
+package testchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// DefaultGasLimit is the block gas limit, enough for the verifiers of the workshop circuits
+const DefaultGasLimit uint64 = 8000029
+
+// DefaultBalance is the genesis balance of the accounts, 1000 ether
+var DefaultBalance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+
+// ErrUnknownAccount is returned for account names not in the genesis
+var ErrUnknownAccount = errors.New("testchain: unknown account")
+
+// Genesis configures the accounts of a new chain
+type Genesis struct {
+	// Accounts are the names of the funded accounts
+	Accounts []string
+	// Balances override DefaultBalance per account name
+	Balances map[string]*big.Int
+	// GasLimit is the block gas limit, DefaultGasLimit if 0
+	GasLimit uint64
+}
+
+// Account is an account of the chain; its key is derived from its name, so that addresses are
+// the same from one run to the other (and anyone can spend from them: test chains only)
+type Account struct {
+	Name    string
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+}
+
+// Chain is a simulated backend with named accounts; it implements ethereum.Backend
+type Chain struct {
+	*backends.SimulatedBackend
+	ChainID *big.Int
+
+	accounts map[string]*Account
+}
+
+// New returns a chain whose genesis funds the accounts of g
+func New(g Genesis) (*Chain, error) {
+	if g.GasLimit == 0 {
+		g.GasLimit = DefaultGasLimit
+	}
+	c := &Chain{accounts: make(map[string]*Account, len(g.Accounts))}
+	alloc := make(core.GenesisAlloc, len(g.Accounts))
+	for _, name := range g.Accounts {
+		if _, ok := c.accounts[name]; ok {
+			return nil, fmt.Errorf("testchain: duplicate account %q", name)
+		}
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte("testchain:" + name)))
+		if err != nil {
+			return nil, err
+		}
+		a := &Account{Name: name, Key: key, Address: crypto.PubkeyToAddress(key.PublicKey)}
+		c.accounts[name] = a
+
+		balance := DefaultBalance
+		if b, ok := g.Balances[name]; ok {
+			balance = b
+		}
+		alloc[a.Address] = core.GenesisAccount{Balance: balance}
+	}
+	for name := range g.Balances {
+		if _, ok := c.accounts[name]; !ok {
+			return nil, fmt.Errorf("%w %q in balances", ErrUnknownAccount, name)
+		}
+	}
+
+	c.SimulatedBackend = backends.NewSimulatedBackend(alloc, g.GasLimit)
+	c.ChainID = c.Blockchain().Config().ChainID
+	return c, nil
+}
+
+// Account returns the account called name
+func (c *Chain) Account(name string) (*Account, error) {
+	a, ok := c.accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownAccount, name)
+	}
+	return a, nil
+}
+
+// Transactor returns the options of the transactions sent by the account called name; it panics
+// if there is no such account, as tests can't go on without it
+func (c *Chain) Transactor(name string) *bind.TransactOpts {
+	a, err := c.Account(name)
+	if err != nil {
+		panic(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(a.Key, c.ChainID)
+	if err != nil {
+		panic(err)
+	}
+	return auth
+}
+
+// CallOpts returns the options of the calls made as the account called name, for contracts
+// checking msg.sender in view functions
+func (c *Chain) CallOpts(name string) *bind.CallOpts {
+	a, err := c.Account(name)
+	if err != nil {
+		panic(err)
+	}
+	return &bind.CallOpts{From: a.Address}
+}
+
+// Mine commits the pending transactions and returns the receipt of tx
+func (c *Chain) Mine(tx *types.Transaction) (*types.Receipt, error) {
+	c.Commit()
+	return c.TransactionReceipt(context.Background(), tx.Hash())
+}
+
+// Advance mines an empty block d after the latest one, e.g. to get past a deadline
+func (c *Chain) Advance(d time.Duration) error {
+	if err := c.AdjustTime(d); err != nil {
+		return err
+	}
+	c.Commit()
+	return nil
+}
+
+// DeployRaw deploys initcode from the account called name, mines it and returns the contract address
+func (c *Chain) DeployRaw(name string, initcode []byte) (common.Address, error) {
+	if _, err := c.Account(name); err != nil {
+		return common.Address{}, err
+	}
+	address, tx, err := ethereum.DeployRaw(context.Background(), c.Transactor(name), c, initcode)
+	if err != nil {
+		return common.Address{}, err
+	}
+	receipt, err := c.Mine(tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return common.Address{}, fmt.Errorf("testchain: deployment %s reverted", tx.Hash().Hex())
+	}
+	return address, nil
+}
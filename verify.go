@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/gbotrel/gnark-workshop/artifacts"
+)
+
+// runVerify checks a serialized proof against a verifying key and a public witness
+// It only needs these three files: no proving key, no R1CS, no blockchain.
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	fProof := fs.String("proof", files.proof, "proof file")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	fExpectFail := fs.Bool("expect-fail", false, "succeed only if the proof is rejected, e.g. a proof written by negative")
+	fVersion := fs.Int("version", 0, "verify with the verifying key of this version of the circuit (see migrate) instead of -vk")
+	assertNoError(fs.Parse(args))
+	if *fVersion != 0 {
+		*fVK = filepath.Join(store.Dir(versionOf(*fVersion).ID), artifacts.VKFile)
+	}
+
+	if *fVK == files.vk {
+		// the current setup: proofs of the circuit as it is now don't verify with a stale key
+		assertNoError(checkCurrentVK())
+	}
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+
+	log.Println("verifying proof", *fProof)
+	err := verifyProofFile(vk, *fProof, *fPublic)
+	result := verifyResult{Proof: *fProof, Valid: err == nil, ExpectFail: *fExpectFail}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	emit("verify", result)
+	switch {
+	case *fExpectFail && err == nil:
+		log.Fatal("proof is valid, but was expected to be rejected")
+	case *fExpectFail:
+		log.Printf("proof is rejected, as expected: %v", err)
+	case err != nil:
+		log.Fatal("proof is invalid: ", err)
+	default:
+		log.Println("proof is valid")
+	}
+}
+
+// checkCurrentVK checks the signature of the manifest of the current setup (see checkManifest), then
+// returns artifacts.ErrCircuitMismatch if the selected circuit changed since the setup of its current
+// verifying key, or artifacts.ErrCorrupted if the key file changed; artifacts without manifest, or
+// predating circuit hashes, pass
+func checkCurrentVK() error {
+	manifest, err := artifacts.ReadManifest(files.manifest)
+	if err != nil {
+		return err
+	}
+	if err := checkManifest(manifest, files); err != nil {
+		return err
+	}
+	if manifest.CircuitHash == "" {
+		return nil
+	}
+	if err := checkCircuit(*fCircuit, manifest); err != nil {
+		return err
+	}
+	return manifest.CheckKeys("", files.vk)
+}
+
+// verifyProofFile verifies the proof in proofFile; a proof that can't be decoded (e.g. with a point
+// off the curve) is invalid
+func verifyProofFile(vk groth16.VerifyingKey, proofFile, publicFile string) error {
+	f, err := os.Open(proofFile)
+	assertNoError(err)
+	defer f.Close()
+	proof := groth16.NewProof(ecc.BN254)
+	if _, err := artifacts.Read(f, header, proof); err != nil {
+		return fmt.Errorf("decode proof: %w", err)
+	}
+
+	publicWitness, err := os.Open(publicFile)
+	assertNoError(err)
+	defer publicWitness.Close()
+	return groth16.ReadAndVerify(proof, vk, publicWitness)
+}
+
+// verifyResult is the JSON output of verify
+type verifyResult struct {
+	Proof      string `json:"proof"`
+	Valid      bool   `json:"valid"`
+	ExpectFail bool   `json:"expectFail,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
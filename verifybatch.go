@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/gbotrel/gnark-workshop/batch"
+)
+
+// runVerifyBatch checks serialized proofs of the same circuit with a single pairing check
+func runVerifyBatch(args []string) {
+	fs := flag.NewFlagSet("verify-batch", flag.ExitOnError)
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	fProofs := fs.String("proofs", files.proof, "comma separated proof files")
+	fPublics := fs.String("public", files.publicWitness, "comma separated public witness files, in the order of the proofs")
+	assertNoError(fs.Parse(args))
+
+	proofFiles, publicFiles := strings.Split(*fProofs, ","), strings.Split(*fPublics, ",")
+	if len(proofFiles) != len(publicFiles) {
+		log.Fatalf("verify-batch: %d proofs but %d public witnesses", len(proofFiles), len(publicFiles))
+	}
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+	b, err := batch.New(vk)
+	assertNoError(err)
+	for i := range proofFiles {
+		proof := groth16.NewProof(ecc.BN254)
+		deserialize(proof, proofFiles[i])
+		assertNoError(b.Add(proof, readPublicWitness(publicFiles[i], *fVK)))
+	}
+
+	log.Printf("verifying %d proofs", b.Len())
+	err = b.Verify()
+	result := verifyBatchResult{Proofs: proofFiles, Valid: err == nil}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	emit("verify-batch", result)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("all proofs are valid")
+}
+
+// verifyBatchResult is the JSON output of verify-batch
+type verifyBatchResult struct {
+	Proofs []string `json:"proofs"`
+	Valid  bool     `json:"valid"`
+	Error  string   `json:"error,omitempty"`
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	gethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runVerifyOnChain checks a proof against a deployed verifier with a read-only eth_call: no key, no
+// transaction, no verifying key, so that anyone can confirm a proof matches the contract on chain
+func runVerifyOnChain(args []string) {
+	fs := flag.NewFlagSet("verify-onchain", flag.ExitOnError)
+	fNodeFlags := addNodeFlags(fs)
+	fAddress := fs.String("address", "", "verifier contract address, read from the deployments file if not set")
+	fDeployments := fs.String("deployments", deploymentsPath, "deployments file")
+	fProof := fs.String("proof", files.proof, "proof file")
+	fPublic := fs.String("public", files.publicWitness, "public witness file")
+	fBlock := fs.Int64("block", 0, "block number to call the verifier at, the latest block if 0")
+	fVersion := fs.Int("version", 0, "call the verifier of this version of the circuit (see migrate) instead of -address")
+	assertNoError(fs.Parse(args))
+
+	proof := groth16.NewProof(ecc.BN254)
+	deserialize(proof, *fProof)
+	data, err := ioutil.ReadFile(*fPublic)
+	assertNoError(err)
+	publicWitness, err := ethereum.DecodePublicWitness(data)
+	assertNoError(err)
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	assertNoError(err)
+	calldata, err := solidityInputs.Calldata()
+	assertNoError(err)
+
+	ctx := mainCtx
+	client := fNodeFlags.dial(ctx)
+	defer client.Close()
+	if *fVersion != 0 {
+		v := versionOf(*fVersion)
+		if *fAddress = v.Verifiers[client.chainID.String()]; *fAddress == "" {
+			log.Fatalf("no verifier of %s version %d recorded on chain %s", *fCircuit, v.Version, client.chainID)
+		}
+	}
+	address := verifierAddress(*fAddress, *fDeployments, client.chainID)
+	var block *big.Int
+	if *fBlock != 0 {
+		block = big.NewInt(*fBlock)
+	}
+
+	code, err := client.CodeAt(ctx, address, block)
+	assertNoError(err)
+	if len(code) == 0 {
+		log.Fatalf("no contract at %s on chain %s", address.Hex(), client.chainID)
+	}
+
+	// a revert is a rejection: the verifier expects another number of public inputs, or a point
+	// is not on the curve
+	result := verifyOnChainResult{ChainID: client.chainID.Int64(), Verifier: address, PublicInputs: len(publicWitness), Block: *fBlock}
+	out, err := client.CallContract(ctx, gethereum.CallMsg{To: &address, Data: calldata}, block)
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+		log.Printf("verifyProof on %s reverted: %v", address.Hex(), err)
+	default:
+		result.Valid = len(out) == 32 && new(big.Int).SetBytes(out).Sign() != 0
+		log.Printf("verifyProof on %s returned %t", address.Hex(), result.Valid)
+	}
+	emit("verify-onchain", result)
+
+	if !result.Valid {
+		os.Exit(1)
+	}
+}
+
+// verifyOnChainResult is the JSON output of verify-onchain
+type verifyOnChainResult struct {
+	ChainID      int64          `json:"chainId"`
+	Verifier     common.Address `json:"verifier"`
+	Block        int64          `json:"block,omitempty"`
+	PublicInputs int            `json:"publicInputs"`
+	Valid        bool           `json:"valid"`
+	Error        string         `json:"error,omitempty"`
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+// runVKFingerprint prints the canonical hash of a verifying key (see ethereum.VKFingerprint), to
+// compare keys without comparing files
+func runVKFingerprint(args []string) {
+	fs := flag.NewFlagSet("vk-fingerprint", flag.ExitOnError)
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	assertNoError(fs.Parse(args))
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+	fingerprint, err := ethereum.VKFingerprint(vk)
+	assertNoError(err)
+	n, err := ethereum.NbPublicInputs(vk)
+	assertNoError(err)
+	if !jsonOutput() {
+		fmt.Println(fingerprint.Hex())
+	}
+	emit("vk-fingerprint", vkFingerprintResult{VK: *fVK, Fingerprint: fingerprint, NbPublicInputs: n})
+}
+
+// vkFingerprintResult is the JSON output of vk-fingerprint
+type vkFingerprintResult struct {
+	VK             string      `json:"vk"`
+	Fingerprint    common.Hash `json:"fingerprint"`
+	NbPublicInputs int         `json:"nbPublicInputs"`
+}
+
+// runCheckDeployment reads the code of a deployed verifier (the implementation, behind a
+// VerifierProxy) and checks that it embeds the points of the local verifying key, and, if the
+// creation bytecode of the setup is at hand, that it is the code it deploys: "deployed the verifier
+// of another key" mistakes are caught before any proof is sent to it
+func runCheckDeployment(args []string) {
+	fs := flag.NewFlagSet("check-deployment", flag.ExitOnError)
+	fNodeFlags := addNodeFlags(fs)
+	fAddress := fs.String("address", "", "verifier (or proxy) address, read from the deployments file if not set")
+	fDeployments := fs.String("deployments", deploymentsPath, "deployments file")
+	fVK := fs.String("vk", files.vk, "verifying key file")
+	fBin := fs.String("bin", files.verifierBin, "creation bytecode file, the exact code is compared if it exists")
+	fBlock := fs.Int64("block", 0, "block number to read the code at, the latest block if 0")
+	assertNoError(fs.Parse(args))
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	deserialize(vk, *fVK)
+	fingerprint, err := ethereum.VKFingerprint(vk)
+	assertNoError(err)
+
+	ctx := mainCtx
+	client := fNodeFlags.dial(ctx)
+	defer client.Close()
+	address := verifierAddress(*fAddress, *fDeployments, client.chainID)
+	var block *big.Int
+	if *fBlock != 0 {
+		block = big.NewInt(*fBlock)
+	}
+	result := checkDeploymentResult{ChainID: client.chainID.Int64(), Verifier: address, Fingerprint: fingerprint}
+
+	// behind a proxy, the code is the implementation's
+	codeAddress := address
+	if implementation, err := ethereum.ProxyImplementation(ctx, client, address); err == nil && implementation != (common.Address{}) {
+		log.Printf("%s is a proxy to %s", address.Hex(), implementation.Hex())
+		result.Implementation, codeAddress = &implementation, implementation
+	}
+	code, err := client.CodeAt(ctx, codeAddress, block)
+	assertNoError(err)
+	if len(code) == 0 {
+		log.Fatalf("no contract at %s on chain %s", codeAddress.Hex(), client.chainID)
+	}
+
+	result.Missing, err = ethereum.MissingVKConstants(code, vk)
+	assertNoError(err)
+	result.OK = len(result.Missing) == 0
+	if bytecode, err := ethereum.ReadBytecode(*fBin); err == nil {
+		matches := ethereum.RuntimeCodeMatches(code, bytecode)
+		result.BytecodeMatches = &matches
+		result.OK = result.OK && matches
+	}
+
+	switch {
+	case result.OK:
+		log.Printf("%s verifies with the verifying key %s", codeAddress.Hex(), fingerprint.Hex())
+	case len(result.Missing) != 0:
+		log.Printf("%s is not a verifier of %s: its code lacks %v", codeAddress.Hex(), fingerprint.Hex(), result.Missing)
+	default:
+		log.Printf("%s embeds the verifying key %s, but is not the code of %s (another compiler or verifier variant)", codeAddress.Hex(), fingerprint.Hex(), *fBin)
+	}
+	emit("check-deployment", result)
+	if !result.OK {
+		os.Exit(1)
+	}
+}
+
+// checkDeploymentResult is the JSON output of check-deployment
+type checkDeploymentResult struct {
+	ChainID        int64           `json:"chainId"`
+	Verifier       common.Address  `json:"verifier"`
+	Implementation *common.Address `json:"implementation,omitempty"`
+	Fingerprint    common.Hash     `json:"fingerprint"`
+	// Missing are the verifying key constants the code doesn't embed
+	Missing []string `json:"missing,omitempty"`
+	// BytecodeMatches is set if the creation bytecode was compared
+	BytecodeMatches *bool `json:"bytecodeMatches,omitempty"`
+	OK              bool  `json:"ok"`
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"log"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/mixer"
+	"github.com/gbotrel/gnark-workshop/voting"
+)
+
+// runVoting runs an election on the simulated backend
+// 1. the organizer commits the voters in a tree and deploys a Voting for its root
+// 2. each voter proves and casts their vote, sent by the organizer's account (as a relayer would)
+// 3. a second vote of a voter is refused, then the election is closed and its tallies checked
+func runVoting() {
+	var c voting.Circuit
+	log.Println("compiling vote circuit")
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &c)
+	assertNoError(err)
+
+	log.Println("running groth16.Setup")
+	pk, vk, err := groth16.Setup(r1cs)
+	assertNoError(err)
+
+	// registration: the organizer only gets the commitments
+	choices := []int{1, 0, 1}
+	var voters []*voting.Voter
+	var tree mixer.Tree
+	for range choices {
+		voter, err := voting.NewVoter(nil)
+		assertNoError(err)
+		voters = append(voters, voter)
+		tree.Leaves = append(tree.Leaves, voter.Commitment())
+	}
+	var election fr.Element
+	election.SetUint64(1)
+
+	auth, chain, err := newBackend()
+	assertNoError(err)
+	ctx := mainCtx
+
+	log.Printf("deploying vote verifier and voting contracts for %d voters", len(voters))
+	v, err := voting.Deploy(auth, chain, vk, tree.Root(), election, 2)
+	assertNoError(err)
+	chain.Commit()
+
+	// vote, then vote again with the first voter; the gas limit is set as estimating a reverting call fails
+	result := votingResult{}
+	opts := *auth
+	opts.GasLimit = 2000000
+	vote := func(voter *voting.Voter, choice int, expected uint64) {
+		witness, err := voting.Witness(&tree, voter, election, choice)
+		assertNoError(err)
+		proof, err := groth16.Prove(r1cs, pk, witness)
+		assertNoError(err)
+		tx, err := v.Vote(&opts, proof, voter.NullifierHash(election), choice)
+		assertNoError(err)
+		chain.Commit()
+		receipt, err := chain.TransactionReceipt(ctx, tx.Hash())
+		assertNoError(err)
+		if receipt.Status != expected {
+			log.Fatalf("vote %d: status %d, expected %d", len(result.Votes), receipt.Status, expected)
+		}
+		result.Votes = append(result.Votes, newTxResult(receipt))
+	}
+	for i, voter := range voters {
+		log.Printf("voter %d votes %d", i, choices[i])
+		vote(voter, choices[i], 1)
+	}
+	log.Println("voter 0 votes again")
+	vote(voters[0], 0, 0)
+
+	tx, err := v.Close(auth)
+	assertNoError(err)
+	chain.Commit()
+	receipt, err := chain.TransactionReceipt(ctx, tx.Hash())
+	assertNoError(err)
+	if receipt.Status != 1 {
+		log.Fatal("close reverted, but shouldn't have")
+	}
+
+	tallies, err := v.Results(nil)
+	assertNoError(err)
+	expected := make([]int64, len(tallies))
+	for _, choice := range choices {
+		expected[choice]++
+	}
+	for i, tally := range tallies {
+		if tally.Int64() != expected[i] {
+			log.Fatalf("choice %d: %s votes, expected %d", i, tally, expected[i])
+		}
+		result.Tallies = append(result.Tallies, tally.Int64())
+	}
+	log.Printf("election closed, tallies %v, double vote refused", result.Tallies)
+	emit("voting", result)
+}
+
+// votingResult is the JSON output of -voting; the last vote is the refused double vote
+type votingResult struct {
+	Votes   []*txResult `json:"votes"`
+	Tallies []int64     `json:"tallies"`
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+pragma solidity ^0.8.0;
+
+// IVoteVerifier is the gnark exported verifier of the voting.Circuit circuit
+interface IVoteVerifier {
+    function verifyProof(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c,
+        uint256[4] memory input
+    ) external view returns (bool r);
+}
+
+// Voting runs an election among the voters committed in a Merkle tree of root voters: each voter
+// casts one vote, with a proof that they own a leaf of the tree and that nullifierHash is the
+// nullifier of their leaf for this election. Votes are public, voters are not: nothing links a
+// vote to a leaf, and a second vote of the same voter is refused by its nullifier.
+contract Voting {
+    // CHOICES is the number of choices the circuit accepts, as voting.Choices
+    uint256 public constant CHOICES = 4;
+
+    IVoteVerifier public immutable verifier;
+    uint256 public immutable voters;
+    uint256 public immutable election;
+    address public immutable organizer;
+
+    uint256[] private tallies;
+    bool public closed;
+    mapping(uint256 => bool) public nullifierSpent;
+
+    event Voted(uint256 indexed nullifierHash, uint256 choice);
+    event Closed(uint256[] tallies);
+
+    constructor(IVoteVerifier _verifier, uint256 _voters, uint256 _election, uint256 choices) {
+        require(choices >= 2 && choices <= CHOICES, "voting-invalid-choices");
+        verifier = _verifier;
+        voters = _voters;
+        election = _election;
+        organizer = msg.sender;
+        tallies = new uint256[](choices);
+    }
+
+    function vote(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c,
+        uint256 nullifierHash,
+        uint256 choice
+    ) external {
+        require(!closed, "voting-closed");
+        require(choice < tallies.length, "voting-invalid-choice");
+        require(!nullifierSpent[nullifierHash], "voting-already-voted");
+        require(verifier.verifyProof(a, b, c, [voters, election, nullifierHash, choice]), "voting-invalid-proof");
+
+        nullifierSpent[nullifierHash] = true;
+        tallies[choice]++;
+        emit Voted(nullifierHash, choice);
+    }
+
+    function close() external {
+        require(msg.sender == organizer, "voting-not-organizer");
+        require(!closed, "voting-closed");
+        closed = true;
+        emit Closed(tallies);
+    }
+
+    function results() external view returns (uint256[] memory) {
+        return tallies;
+    }
+}
@@ -0,0 +1,61 @@
+package voting
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/gbotrel/gnark-workshop/mixer"
+	"github.com/gbotrel/gnark-workshop/nullifier"
+	"github.com/gbotrel/gnark-workshop/poseidon"
+)
+
+// Depth is the depth of the voters tree (a mixer.Tree): up to 2^Depth voters
+const Depth = mixer.Depth
+
+// Choices is the number of choices a vote can take, at most: 0 to Choices-1, as CHOICES in Voting.sol
+const Choices = 4
+
+// Circuit defines a vote
+// poseidon(nullifier, secret) is a leaf of the public voters root, nullifierHash = poseidon(nullifier,
+// election) and the vote is a valid choice. Election scopes the nullifier: the same voters can vote
+// in other elections without their votes being linked.
+type Circuit struct {
+	Voters        frontend.Variable `gnark:",public"`
+	Election      frontend.Variable `gnark:",public"`
+	NullifierHash frontend.Variable `gnark:",public"`
+	Vote          frontend.Variable `gnark:",public"`
+
+	Nullifier frontend.Variable
+	Secret    frontend.Variable
+	Index     frontend.Variable
+	Path      [Depth]frontend.Variable
+}
+
+// Define declares the circuit's constraints
+func (c *Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	// the workshop poseidon is defined over BN254 only
+	notes, err := nullifier.NewGadget(curveID, cs, nullifier.Poseidon)
+	if err != nil {
+		return err
+	}
+
+	// the vote is one of Choices
+	cs.ToBinary(c.Vote, choicesBits)
+
+	// nullifierHash = poseidon(nullifier, election)
+	cs.AssertIsEqual(notes.Scoped(c.Nullifier, c.Election), c.NullifierHash)
+
+	// poseidon(nullifier, secret) is a leaf of voters, at index
+	node := notes.Commitment(c.Nullifier, c.Secret)
+	directions := cs.ToBinary(c.Index, Depth)
+	for i := range c.Path {
+		left := cs.Select(directions[i], c.Path[i], node)
+		right := cs.Select(directions[i], node, c.Path[i])
+		node = poseidon.HashInCircuit(cs, left, right)
+	}
+	cs.AssertIsEqual(node, c.Voters)
+
+	return nil
+}
+
+// choicesBits is the number of bits of a vote, Choices being a power of 2
+const choicesBits = 2
@@ -0,0 +1,89 @@
+package voting
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/gbotrel/gnark-workshop/mixer"
+	"github.com/gbotrel/gnark-workshop/nullifier"
+)
+
+var (
+	// ErrUnknownVoter is returned when proving the vote of a voter who isn't in the voters tree
+	ErrUnknownVoter = errors.New("voter not found in the voters tree")
+
+	// ErrInvalidChoice is returned for votes out of [0, Choices)
+	ErrInvalidChoice = errors.New("invalid choice")
+)
+
+// Voter is what a voter keeps to vote: the organizer only learns its commitment
+type Voter struct {
+	Nullifier fr.Element
+	Secret    fr.Element
+}
+
+// NewVoter returns a voter with random nullifier and secret, read from r (crypto/rand if nil)
+func NewVoter(r io.Reader) (*Voter, error) {
+	var v Voter
+	for _, e := range []*fr.Element{&v.Nullifier, &v.Secret} {
+		var err error
+		if *e, err = nullifier.NewSecret(r); err != nil {
+			return nil, err
+		}
+	}
+	return &v, nil
+}
+
+// Commitment returns poseidon(nullifier, secret), the leaf of the voter in the voters tree
+func (v *Voter) Commitment() fr.Element {
+	return nullifier.Commitment(nullifier.Poseidon, v.Nullifier, v.Secret)
+}
+
+// NullifierHash returns poseidon(nullifier, election), revealed with the vote of the voter in election
+func (v *Voter) NullifierHash(election fr.Element) fr.Element {
+	return nullifier.Scoped(nullifier.Poseidon, v.Nullifier, election)
+}
+
+// Witness returns the assignment of the vote of voter for choice in election, voters being the
+// tree of the commitments of the voters
+func Witness(voters *mixer.Tree, voter *Voter, election fr.Element, choice int) (*Circuit, error) {
+	if choice < 0 || choice >= Choices {
+		return nil, ErrInvalidChoice
+	}
+	commitment := voter.Commitment()
+	index := -1
+	for i := range voters.Leaves {
+		if voters.Leaves[i].Equal(&commitment) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, ErrUnknownVoter
+	}
+	path, err := voters.Path(index)
+	if err != nil {
+		return nil, err
+	}
+
+	var witness Circuit
+	root := voters.Root()
+	nullifierHash := voter.NullifierHash(election)
+	witness.Voters.Assign(toBig(root))
+	witness.Election.Assign(toBig(election))
+	witness.NullifierHash.Assign(toBig(nullifierHash))
+	witness.Vote.Assign(choice)
+	witness.Nullifier.Assign(toBig(voter.Nullifier))
+	witness.Secret.Assign(toBig(voter.Secret))
+	witness.Index.Assign(index)
+	for i := range path {
+		witness.Path[i].Assign(toBig(path[i]))
+	}
+	return &witness, nil
+}
+
+func toBig(e fr.Element) *big.Int {
+	return e.ToBigIntRegular(new(big.Int))
+}
@@ -0,0 +1,75 @@
+// Package voting is a private voting example: the organizer commits the voters in a Merkle tree,
+// and each voter casts one public vote per election, with a proof that they are in the tree and
+// the nullifier of their leaf for the election, which keeps them from voting twice without
+// revealing who they are.
+//
+// Commitments and nullifiers come from package nullifier, the tree is a mixer.Tree.
+package voting
+
+import (
+	"bytes"
+	_ "embed"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+//go:embed Voting.sol
+var votingSol string
+
+// Voting is a handle on a deployed Voting contract
+type Voting struct {
+	Address  common.Address
+	Verifier common.Address
+	Election fr.Element
+	contract *bind.BoundContract
+}
+
+// Deploy exports vk (a voting.Circuit verifying key) to solidity, deploys it, then deploys a Voting
+// for election among the voters of the tree of root voters, between choices choices.
+// Requires solc in PATH; caller is responsible for committing / mining the transactions.
+func Deploy(auth *bind.TransactOpts, backend bind.ContractBackend, vk groth16.VerifyingKey, voters, election fr.Element, choices int) (*Voting, error) {
+	var buf bytes.Buffer
+	if err := vk.ExportSolidity(&buf); err != nil {
+		return nil, err
+	}
+	verifierAddress, _, err := ethereum.DeployContract(auth, backend, buf.String(), "Verifier")
+	if err != nil {
+		return nil, err
+	}
+
+	address, contract, err := ethereum.DeployContract(auth, backend, votingSol, "Voting", verifierAddress, toBig(voters), toBig(election), big.NewInt(int64(choices)))
+	if err != nil {
+		return nil, err
+	}
+	return &Voting{Address: address, Verifier: verifierAddress, Election: election, contract: contract}, nil
+}
+
+// Vote casts the vote proven by proof; anyone can send it, the proof is bound to the choice
+func (v *Voting) Vote(auth *bind.TransactOpts, proof groth16.Proof, nullifierHash fr.Element, choice int) (*types.Transaction, error) {
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, nil)
+	if err != nil {
+		return nil, err
+	}
+	return v.contract.Transact(auth, "vote", solidityInputs.A, solidityInputs.B, solidityInputs.C, toBig(nullifierHash), big.NewInt(int64(choice)))
+}
+
+// Close ends the election; auth must be the organizer, the deployer
+func (v *Voting) Close(auth *bind.TransactOpts) (*types.Transaction, error) {
+	return v.contract.Transact(auth, "close")
+}
+
+// Results returns the number of votes for each choice
+func (v *Voting) Results(opts *bind.CallOpts) ([]*big.Int, error) {
+	var out []interface{}
+	if err := v.contract.Call(opts, &out, "results"); err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new([]*big.Int)).(*[]*big.Int), nil
+}
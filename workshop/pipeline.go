@@ -0,0 +1,185 @@
+// Package workshop exposes the workshop flow as a library: compile a circuit, run its setup,
+// prove, verify in Go, export and deploy its Solidity verifier, and verify on-chain, without
+// shelling out to the workshop binary.
+//
+//	p := workshop.New(&circuit.Circuit{})
+//	if err := p.Compile(ctx); err != nil { ... }
+//	if err := p.Setup(ctx); err != nil { ... }
+//	proof, err := p.Prove(ctx, assignment)
+//	err = p.VerifyLocal(ctx, proof, assignment)
+//	_, _, err = p.DeployVerifier(auth, backend) // mine the transaction, then
+//	valid, err := p.VerifyOnChain(nil, proof, assignment)
+package workshop
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gbotrel/gnark-workshop/ethereum"
+)
+
+var (
+	// ErrNotCompiled is returned by the steps needing the constraint system before Compile
+	ErrNotCompiled = errors.New("workshop: circuit not compiled")
+	// ErrNoKeys is returned by the steps needing the groth16 keys before Setup
+	ErrNoKeys = errors.New("workshop: no keys, run Setup first")
+	// ErrNotDeployed is returned by VerifyOnChain before DeployVerifier
+	ErrNotDeployed = errors.New("workshop: verifier not deployed")
+)
+
+// Pipeline runs the workshop flow for one circuit, on BN254 (the curve of the EVM precompiles)
+// Each step stores its result in the Pipeline; the exported fields may also be set directly,
+// e.g. with keys deserialized from files, to skip the steps that produced them.
+// The steps return ctx.Err() as soon as ctx is done, see Run; on-chain steps take their
+// context from auth and opts.
+type Pipeline struct {
+	Circuit frontend.Circuit
+
+	R1CS frontend.CompiledConstraintSystem
+	PK   groth16.ProvingKey
+	VK   groth16.VerifyingKey
+
+	// Verifier is the address of the deployed Solidity verifier
+	Verifier common.Address
+	backend  bind.ContractBackend
+}
+
+// New returns a Pipeline for the circuit definition c
+func New(c frontend.Circuit) *Pipeline {
+	return &Pipeline{Circuit: c}
+}
+
+// Compile compiles the circuit to a R1CS
+func (p *Pipeline) Compile(ctx context.Context) error {
+	var r1cs frontend.CompiledConstraintSystem
+	err := Run(ctx, func() (err error) {
+		r1cs, err = frontend.Compile(ecc.BN254, backend.GROTH16, p.Circuit)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	p.R1CS = r1cs
+	return nil
+}
+
+// Setup runs the groth16 (toy, single party) trusted setup
+func (p *Pipeline) Setup(ctx context.Context) error {
+	if p.R1CS == nil {
+		return ErrNotCompiled
+	}
+	var (
+		pk groth16.ProvingKey
+		vk groth16.VerifyingKey
+	)
+	err := Run(ctx, func() (err error) {
+		pk, vk, err = groth16.Setup(p.R1CS)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	p.PK, p.VK = pk, vk
+	return nil
+}
+
+// Prove creates a proof for the full assignment
+func (p *Pipeline) Prove(ctx context.Context, assignment frontend.Circuit) (groth16.Proof, error) {
+	if p.R1CS == nil {
+		return nil, ErrNotCompiled
+	}
+	if p.PK == nil {
+		return nil, ErrNoKeys
+	}
+	var proof groth16.Proof
+	err := Run(ctx, func() (err error) {
+		proof, err = groth16.Prove(p.R1CS, p.PK, assignment)
+		return
+	})
+	return proof, err
+}
+
+// VerifyLocal verifies proof in Go; assignment may be full or public only
+func (p *Pipeline) VerifyLocal(ctx context.Context, proof groth16.Proof, assignment frontend.Circuit) error {
+	if p.VK == nil {
+		return ErrNoKeys
+	}
+	return Run(ctx, func() error {
+		return groth16.Verify(proof, p.VK, assignment)
+	})
+}
+
+// ExportSolidity writes the Solidity verifier of the verifying key
+func (p *Pipeline) ExportSolidity(w io.Writer) error {
+	if p.VK == nil {
+		return ErrNoKeys
+	}
+	return ethereum.ExportSolidity(w, p.VK)
+}
+
+// DeployVerifier compiles the Solidity verifier and sends its creation transaction
+// Requires solc in PATH; caller is responsible for committing / mining the transaction.
+func (p *Pipeline) DeployVerifier(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+	var solidity bytes.Buffer
+	if err := p.ExportSolidity(&solidity); err != nil {
+		return common.Address{}, nil, err
+	}
+	contracts, err := ethereum.CompileSolidity(solidity.String())
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	_, bytecode, err := ethereum.Artifact(contracts, "Verifier")
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	ctx := auth.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	address, tx, err := ethereum.DeployRaw(ctx, auth, backend, bytecode)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	p.Verifier, p.backend = address, backend
+	return address, tx, nil
+}
+
+// UseVerifier binds a verifier deployed beforehand, instead of DeployVerifier
+func (p *Pipeline) UseVerifier(address common.Address, backend bind.ContractBackend) {
+	p.Verifier, p.backend = address, backend
+}
+
+// VerifyOnChain calls verifyProof on the deployed verifier, with the public inputs of assignment
+func (p *Pipeline) VerifyOnChain(opts *bind.CallOpts, proof groth16.Proof, assignment frontend.Circuit) (bool, error) {
+	if p.backend == nil {
+		return false, ErrNotDeployed
+	}
+	if p.VK == nil {
+		return false, ErrNoKeys
+	}
+	publicWitness, err := ethereum.PublicWitness(assignment)
+	if err != nil {
+		return false, err
+	}
+	if err := ethereum.CheckPublicWitness(p.VK, publicWitness); err != nil {
+		return false, err
+	}
+	solidityInputs, err := ethereum.ProofToSolidityInputs(proof, publicWitness)
+	if err != nil {
+		return false, err
+	}
+	verifier, err := ethereum.NewVerifier(p.Verifier, p.backend, len(publicWitness))
+	if err != nil {
+		return false, err
+	}
+	return verifier.VerifyProof(opts, solidityInputs)
+}
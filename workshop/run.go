@@ -0,0 +1,22 @@
+package workshop
+
+import "context"
+
+// Run runs f, returning ctx.Err() as soon as ctx is done
+// gnark computations can't be interrupted: f keeps running in the background and its result is
+// dropped, but the caller is free to exit or move on.
+func Run(ctx context.Context, f func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- f()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}